@@ -6,10 +6,13 @@ import (
 	"VetiCare/services"
 	"VetiCare/validators"
 	"encoding/json"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -25,12 +28,18 @@ func (ac *AppointmentController) RegisterRoutes(r *mux.Router, authMiddleware fu
 	r.Handle("/api/appointments", authMiddleware(http.HandlerFunc(ac.CreateAppointment))).Methods("POST")
 	r.Handle("/api/appointments", authMiddleware(http.HandlerFunc(ac.GetAllAppointments))).Methods("GET")
 	r.Handle("/api/appointments/active", authMiddleware(http.HandlerFunc(ac.GetActiveAppointments))).Methods("GET")
+	r.Handle("/api/appointments/walk-in", authMiddleware(http.HandlerFunc(ac.CreateWalkIn))).Methods("POST")
 	r.Handle("/api/appointments/{id}/status/{status_id}", authMiddleware(http.HandlerFunc(ac.UpdateStatus))).Methods("PATCH")
+	r.Handle("/api/appointments/{id}/reschedule", authMiddleware(http.HandlerFunc(ac.RescheduleAppointment))).Methods("POST")
+	r.Handle("/api/appointments/{id}/reschedules", authMiddleware(http.HandlerFunc(ac.GetRescheduleHistory))).Methods("GET")
+	r.Handle("/api/appointments/{id}/approve", authMiddleware(http.HandlerFunc(ac.ApproveAppointment))).Methods("PATCH")
+	r.Handle("/api/appointments/{id}/deposit", authMiddleware(http.HandlerFunc(ac.RecordDeposit))).Methods("PATCH")
 	r.Handle("/api/appointments/{id}", authMiddleware(http.HandlerFunc(ac.GetAppointmentByID))).Methods("GET")
 	r.Handle("/api/appointments/{id}", authMiddleware(http.HandlerFunc(ac.UpdateAppointment))).Methods("PUT")
 	r.Handle("/api/appointments/{id}", authMiddleware(http.HandlerFunc(ac.DeleteAppointment))).Methods("DELETE")
 	r.Handle("/api/appointments/user/{user_id}", authMiddleware(http.HandlerFunc(ac.GetAppointmentsByUser))).Methods("GET")
 	r.Handle("/api/appointments/pet/{pet_id}/history", authMiddleware(http.HandlerFunc(ac.GetMedicalHistoryByPet))).Methods("GET")
+	r.Handle("/api/queue/today", authMiddleware(http.HandlerFunc(ac.GetTodayQueue))).Methods("GET")
 
 	// DASHBOARD ROUTES
 	r.Handle("/api/dashboard/appointments/attended", authMiddleware(http.HandlerFunc(ac.GetCountAttendedAppointments))).Methods("GET")
@@ -40,6 +49,11 @@ func (ac *AppointmentController) RegisterRoutes(r *mux.Router, authMiddleware fu
 	r.Handle("/api/dashboard/appointments/monthly_last6months", authMiddleware(http.HandlerFunc(ac.GetAppointmentsByMonthLast6Months))).Methods("GET")
 }
 
+func (ac *AppointmentController) RegisterAdminRoutes(r *mux.Router, adminMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/vets/{id}/reassignments/preview", adminMiddleware(http.HandlerFunc(ac.PreviewReassignments))).Methods("POST")
+	r.Handle("/api/vets/{id}/reassignments", adminMiddleware(http.HandlerFunc(ac.ApplyReassignments))).Methods("POST")
+}
+
 func (ac *AppointmentController) CreateAppointment(w http.ResponseWriter, r *http.Request) {
 	var app dto.AppointmentDTO
 	if err := json.NewDecoder(r.Body).Decode(&app); err != nil {
@@ -47,22 +61,55 @@ func (ac *AppointmentController) CreateAppointment(w http.ResponseWriter, r *htt
 		return
 	}
 
-	duplicate, err := ac.Service.ExistsAppointmentForPet(app.Date, app.Time)
+	if err := validators.ValidateUUIDOptional(app.VetID); err != nil {
+		http.Error(w, validators.ErrInvalidVetID.Error(), http.StatusBadRequest)
+		return
+	}
+	appointment := entities.Appointment{
+		PetID:      uuid.MustParse(app.PetID),
+		Date:       app.Date,
+		Time:       app.Time,
+		ClinicID:   app.ClinicID,
+		RoomID:     app.RoomID,
+		ResourceID: app.ResourceID,
+	}
+	if app.VetID != nil && *app.VetID != "" {
+		vetID := uuid.MustParse(*app.VetID)
+		appointment.VetID = &vetID
+	}
+	if err := ac.Service.CreateAppointment(&appointment); err != nil {
+		http.Error(w, "Error creando cita: "+err.Error(), appointmentErrorStatus(err))
+		return
+	}
+	completeApp, err := ac.Service.GetAppointmentByID(appointment.ID.String())
 	if err != nil {
-		http.Error(w, "Error validando duplicidad: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Error obteniendo cita creada: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(dto.NewAppointmentDTO(completeApp))
+}
+
+func (ac *AppointmentController) CreateWalkIn(w http.ResponseWriter, r *http.Request) {
+	var input dto.WalkInDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
 		return
 	}
-	if duplicate {
-		http.Error(w, "Ya existe una cita registrada para esa fecha y hora", http.StatusBadRequest)
+	if err := validators.ValidateWalkInDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 	appointment := entities.Appointment{
-		PetID: uuid.MustParse(app.PetID),
-		Date:  app.Date,
-		Time:  app.Time,
+		PetID:    uuid.MustParse(input.PetID),
+		ClinicID: input.ClinicID,
+		Reason:   input.Reason,
 	}
-	if err := ac.Service.CreateAppointment(&appointment); err != nil {
-		http.Error(w, "Error creando cita: "+err.Error(), http.StatusInternalServerError)
+	if input.VetID != nil && *input.VetID != "" {
+		vetID := uuid.MustParse(*input.VetID)
+		appointment.VetID = &vetID
+	}
+	if err := ac.Service.CreateWalkIn(&appointment); err != nil {
+		http.Error(w, "Error registrando paciente sin cita: "+err.Error(), appointmentErrorStatus(err))
 		return
 	}
 	completeApp, err := ac.Service.GetAppointmentByID(appointment.ID.String())
@@ -70,22 +117,140 @@ func (ac *AppointmentController) CreateAppointment(w http.ResponseWriter, r *htt
 		http.Error(w, "Error obteniendo cita creada: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(dto.NewAppointmentDTO(completeApp))
 }
 
-func (ac *AppointmentController) GetAllAppointments(w http.ResponseWriter, _ *http.Request) {
-	list, err := ac.Service.GetAllAppointments()
+func (ac *AppointmentController) GetTodayQueue(w http.ResponseWriter, r *http.Request) {
+	clinicID, err := parseClinicID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	now := time.Now()
+	queues, err := ac.Service.GetTodayQueue(now, clinicID)
+	if err != nil {
+		http.Error(w, "Error obteniendo la cola del día: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToQueueDTO(now, queues))
+}
+
+// GetAllAppointments busca citas con filtros opcionales. Filtros admitidos:
+// date_from y date_to (DD-MM-YYYY), vet_id, owner_id, pet_id, species_id,
+// status_id (uno o varios separados por coma), clinic_id, diagnosis_id y q
+// (texto en el motivo).
+// La paginación usa page y page_size (por defecto 20, máximo 100).
+// La respuesta sigue siendo un arreglo y el total va en X-Total-Count. sort
+// acepta date, created_at, updated_at o status, con prefijo "-" para orden
+// descendente.
+func (ac *AppointmentController) GetAllAppointments(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAppointmentFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.Normalize()
+	list, total, err := ac.Service.SearchAppointments(filter)
 	if err != nil {
 		http.Error(w, "Error al obtener citas: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	var dtos []dto.AppointmentDTO
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	w.Header().Set("X-Page", strconv.Itoa(filter.Page))
+	w.Header().Set("X-Page-Size", strconv.Itoa(filter.PageSize))
+	dtos := []dto.AppointmentDTO{}
 	for _, app := range list {
 		dtos = append(dtos, dto.NewAppointmentDTO(&app))
 	}
 	json.NewEncoder(w).Encode(dtos)
 }
 
+func parseAppointmentFilter(q url.Values) (entities.AppointmentFilter, error) {
+	filter := entities.AppointmentFilter{
+		VetID:   q.Get("vet_id"),
+		OwnerID: q.Get("owner_id"),
+		PetID:   q.Get("pet_id"),
+		Query:   strings.TrimSpace(q.Get("q")),
+	}
+
+	for _, id := range []string{filter.VetID, filter.OwnerID, filter.PetID} {
+		if id != "" {
+			if err := validators.ValidateUUIDRequired(id); err != nil {
+				return filter, err
+			}
+		}
+	}
+	if v := q.Get("date_from"); v != "" {
+		date, err := time.Parse("02-01-2006", v)
+		if err != nil {
+			return filter, errors.New("date_from inválido, use formato DD-MM-YYYY")
+		}
+		filter.DateFrom = &date
+	}
+	if v := q.Get("date_to"); v != "" {
+		date, err := time.Parse("02-01-2006", v)
+		if err != nil {
+			return filter, errors.New("date_to inválido, use formato DD-MM-YYYY")
+		}
+		filter.DateTo = &date
+	}
+	if v := q.Get("clinic_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, errors.New("clinic_id inválido")
+		}
+		filter.ClinicID = &id
+	}
+	if v := q.Get("species_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, errors.New("species_id inválido")
+		}
+		filter.SpeciesID = id
+	}
+	if v := q.Get("diagnosis_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id < 1 {
+			return filter, errors.New("diagnosis_id inválido")
+		}
+		filter.DiagnosisID = id
+	}
+	if v := q.Get("status_id"); v != "" {
+		for _, part := range strings.Split(v, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || validators.ValidateStatusID(id) != nil {
+				return filter, errors.New("status_id inválido")
+			}
+			filter.StatusIDs = append(filter.StatusIDs, id)
+		}
+	}
+	if v := q.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return filter, errors.New("page debe ser un número mayor que cero")
+		}
+		filter.Page = page
+	}
+	if v := q.Get("page_size"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size < 1 {
+			return filter, errors.New("page_size debe ser un número mayor que cero")
+		}
+		filter.PageSize = size
+	}
+	if v := q.Get("sort"); v != "" {
+		filter.SortDesc = strings.HasPrefix(v, "-")
+		filter.SortBy = strings.TrimPrefix(v, "-")
+		switch filter.SortBy {
+		case "date", "created_at", "updated_at", "status":
+		default:
+			return filter, errors.New("sort inválido, use date, created_at, updated_at o status")
+		}
+	}
+	return filter, nil
+}
+
 func (ac *AppointmentController) GetAppointmentByID(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 
@@ -106,7 +271,11 @@ func (ac *AppointmentController) GetAppointmentByID(w http.ResponseWriter, r *ht
 func (ac *AppointmentController) GetActiveAppointments(w http.ResponseWriter, r *http.Request) {
 	dateStr := r.URL.Query().Get("date")
 	var apps []entities.Appointment
-	var err error
+	clinicID, err := parseClinicID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	if dateStr != "" {
 		date, errParse := time.Parse("02-01-2006", dateStr)
@@ -114,9 +283,9 @@ func (ac *AppointmentController) GetActiveAppointments(w http.ResponseWriter, r
 			http.Error(w, "Fecha inválida, use formato DD-MM-YYYY", http.StatusBadRequest)
 			return
 		}
-		apps, err = ac.Service.GetAppointmentsByStatusAndDate(date)
+		apps, err = ac.Service.GetAppointmentsByStatusAndDate(date, clinicID)
 	} else {
-		apps, err = ac.Service.GetAppointmentsByStatus(1)
+		apps, err = ac.Service.GetAppointmentsByStatus(1, clinicID)
 	}
 
 	if err != nil {
@@ -134,25 +303,40 @@ func (ac *AppointmentController) GetActiveAppointments(w http.ResponseWriter, r
 
 func (ac *AppointmentController) GetMedicalHistoryByPet(w http.ResponseWriter, r *http.Request) {
 	petID := mux.Vars(r)["pet_id"]
+	diagnosisID := 0
+	if v := r.URL.Query().Get("diagnosis_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id < 1 {
+			http.Error(w, "diagnosis_id inválido", http.StatusBadRequest)
+			return
+		}
+		diagnosisID = id
+	}
 
-	apps, err := ac.Service.GetMedicalHistoryByPetID(petID)
+	history, err := ac.Service.GetMedicalHistoryByPetID(r.Header.Get("User-ID"), petID, diagnosisID)
 	if err != nil {
-		http.Error(w, "Error obteniendo historial médico: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Error obteniendo historial médico: "+err.Error(), appointmentErrorStatus(err))
 		return
 	}
 
-	var dtos []dto.AppointmentDTO
-	for _, app := range apps {
-		dtos = append(dtos, dto.NewAppointmentDTO(&app))
+	// La respuesta sigue siendo la lista de citas; los medicamentos activos y
+	// los adjuntos solo se incluyen si el cliente los pide con full=true.
+	if r.URL.Query().Get("full") == "true" {
+		json.NewEncoder(w).Encode(dto.ToMedicalHistoryDTO(petID, history))
+		return
 	}
-
-	json.NewEncoder(w).Encode(dtos)
+	json.NewEncoder(w).Encode(dto.ToAppointmentDTOs(history.Appointments))
 }
 
 func (ac *AppointmentController) GetAppointmentsByUser(w http.ResponseWriter, r *http.Request) {
 	userID := mux.Vars(r)["user_id"]
+	clinicID, err := parseClinicID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	apps, err := ac.Service.GetByUserID(userID)
+	apps, err := ac.Service.GetByUserID(userID, clinicID)
 	if err != nil {
 		http.Error(w, "Error obteniendo citas: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -236,9 +420,28 @@ func (ac *AppointmentController) UpdateAppointment(w http.ResponseWriter, r *htt
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	// El estado solo cambia con su propia ruta, que valida la transición y
+	// actualiza la nota clínica, el plan de tratamiento y las inasistencias.
+	// Se acepta el estado actual porque el cuerpo completo de la cita lo trae.
+	if _, ok := fields["status_id"]; ok {
+		current, err := ac.Service.GetAppointmentByID(id)
+		if err != nil {
+			http.Error(w, "Error al obtener cita: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if current == nil {
+			http.Error(w, "Cita no encontrada", http.StatusNotFound)
+			return
+		}
+		if current.StatusID != appDTO.StatusID {
+			http.Error(w, "El estado de la cita no se cambia con esta ruta; use PATCH /api/appointments/{id}/status/{status_id}", http.StatusBadRequest)
+			return
+		}
+		delete(fields, "status_id")
+	}
 
 	if err := ac.Service.UpdateAppointment(id, fields); err != nil {
-		http.Error(w, "Error al actualizar cita: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Error al actualizar cita: "+err.Error(), appointmentErrorStatus(err))
 		return
 	}
 
@@ -265,14 +468,14 @@ func (ac *AppointmentController) UpdateStatus(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	if statusID < 1 || statusID > 3 {
-		http.Error(w, "status_id debe ser 1, 2 o 3", http.StatusBadRequest)
+	if err := validators.ValidateStatusID(statusID); err != nil {
+		http.Error(w, "status_id debe ser un valor entre 1 y 6", http.StatusBadRequest)
 		return
 	}
 
-	err = ac.Service.UpdateStatus(appointmentID, statusID)
+	err = ac.Service.UpdateStatus(r.Header.Get("User-ID"), appointmentID, statusID)
 	if err != nil {
-		http.Error(w, "Error actualizando estado: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Error actualizando estado: "+err.Error(), appointmentErrorStatus(err))
 		return
 	}
 
@@ -285,18 +488,176 @@ func (ac *AppointmentController) UpdateStatus(w http.ResponseWriter, r *http.Req
 	json.NewEncoder(w).Encode(dto.NewAppointmentDTO(updatedApp))
 }
 
+func (ac *AppointmentController) RescheduleAppointment(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	var input dto.RescheduleAppointmentDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateRescheduleDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var requestedBy *uuid.UUID
+	if parsed, err := uuid.Parse(r.Header.Get("User-ID")); err == nil {
+		requestedBy = &parsed
+	}
+
+	app, err := ac.Service.Reschedule(id, input.Date, input.Time, input.Reason, requestedBy)
+	if err != nil {
+		http.Error(w, "Error al reprogramar cita: "+err.Error(), appointmentErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.NewAppointmentDTO(app))
+}
+
+func (ac *AppointmentController) GetRescheduleHistory(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	list, err := ac.Service.GetReschedules(id)
+	if err != nil {
+		http.Error(w, "Error obteniendo historial de reprogramaciones: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dtos := []dto.AppointmentRescheduleDTO{}
+	for _, item := range list {
+		dtos = append(dtos, dto.ToAppointmentRescheduleDTO(&item))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (ac *AppointmentController) ApproveAppointment(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	app, err := ac.Service.Approve(r.Header.Get("User-ID"), id)
+	if err != nil {
+		http.Error(w, "Error al aprobar cita: "+err.Error(), appointmentErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.NewAppointmentDTO(app))
+}
+
+func (ac *AppointmentController) RecordDeposit(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	app, err := ac.Service.RecordDeposit(r.Header.Get("User-ID"), id)
+	if err != nil {
+		http.Error(w, "Error al registrar depósito: "+err.Error(), appointmentErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.NewAppointmentDTO(app))
+}
+
+func decodeReassignment(w http.ResponseWriter, r *http.Request) (string, dto.ReassignmentInputDTO, time.Time, time.Time, bool) {
+	var input dto.ReassignmentInputDTO
+	vetID := mux.Vars(r)["id"]
+	if err := validators.ValidateUUIDRequired(vetID); err != nil {
+		http.Error(w, validators.ErrInvalidVetID.Error(), http.StatusBadRequest)
+		return "", input, time.Time{}, time.Time{}, false
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return "", input, time.Time{}, time.Time{}, false
+	}
+	if err := validators.ValidateReassignmentDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", input, time.Time{}, time.Time{}, false
+	}
+	from, _ := time.ParseInLocation("02-01-2006", input.From, time.Local)
+	to, _ := time.ParseInLocation("02-01-2006", input.To, time.Local)
+	return vetID, input, from, to, true
+}
+
+func (ac *AppointmentController) PreviewReassignments(w http.ResponseWriter, r *http.Request) {
+	vetID, input, from, to, ok := decodeReassignment(w, r)
+	if !ok {
+		return
+	}
+	proposals, err := ac.Service.ProposeReassignments(vetID, from, to)
+	if err != nil {
+		http.Error(w, "Error al calcular reasignaciones: "+err.Error(), appointmentErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToReassignmentResultDTO(vetID, input, false, proposals))
+}
+
+func (ac *AppointmentController) ApplyReassignments(w http.ResponseWriter, r *http.Request) {
+	vetID, input, from, to, ok := decodeReassignment(w, r)
+	if !ok {
+		return
+	}
+	if input.ProposalHash == "" {
+		http.Error(w, validators.ErrReassignHashRequired.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.Reason == "" {
+		input.Reason = "Veterinario no disponible"
+	}
+	proposals, err := ac.Service.ApplyReassignments(vetID, from, to, input.Reason, input.ProposalHash)
+	if err != nil {
+		http.Error(w, "Error al aplicar reasignaciones: "+err.Error(), appointmentErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToReassignmentResultDTO(vetID, input, true, proposals))
+}
+
+func appointmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrAppointmentNotFound), errors.Is(err, services.ErrVetNotFound), errors.Is(err, services.ErrPetNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrPetAccessDenied), errors.Is(err, services.ErrStaffOnly):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrAppointmentSlotTaken),
+		errors.Is(err, services.ErrRoomFull),
+		errors.Is(err, services.ErrResourceUnavailable):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrAppointmentNotScheduled),
+		errors.Is(err, services.ErrAppointmentNotCancelable),
+		errors.Is(err, services.ErrAppointmentClinicNeeded),
+		errors.Is(err, services.ErrAppointmentSameSlot),
+		errors.Is(err, services.ErrRescheduleCutoff),
+		errors.Is(err, services.ErrRescheduleLimitReached),
+		errors.Is(err, services.ErrRescheduleDateTimeInPast),
+		errors.Is(err, services.ErrApprovalNotRequired),
+		errors.Is(err, services.ErrApprovalPending),
+		errors.Is(err, services.ErrDepositNotRequired),
+		errors.Is(err, services.ErrDepositPending),
+		errors.Is(err, services.ErrInvalidStatusTransition),
+		errors.Is(err, services.ErrCheckInNotToday),
+		errors.Is(err, services.ErrClinicClosed),
+		errors.Is(err, services.ErrVetOnTimeOff),
+		errors.Is(err, services.ErrVetNotInClinic),
+		errors.Is(err, services.ErrRoomNotFound),
+		errors.Is(err, services.ErrRoomNotInClinic),
+		errors.Is(err, services.ErrResourceNotFound),
+		errors.Is(err, services.ErrResourceNotInClinic),
+		errors.Is(err, services.ErrImplausibleVital):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrAppointmentRecordLocked), errors.Is(err, services.ErrReassignmentChanged),
+		errors.Is(err, services.ErrAppointmentStatusChanged):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvalidDateRange):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 func (ac *AppointmentController) DeleteAppointment(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 	msg, err := ac.Service.DeleteAppointment(id)
 	if err != nil {
-		http.Error(w, "Error al eliminar cita: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Error al eliminar cita: "+err.Error(), appointmentErrorStatus(err))
 		return
 	}
 	json.NewEncoder(w).Encode(map[string]string{"message": msg})
 }
 
 func (ac *AppointmentController) GetCountAttendedAppointments(w http.ResponseWriter, r *http.Request) {
-	count, err := ac.Service.CountAppointmentsByStatus(2) // Status 2 = atendidas
+	clinicID, err := parseClinicID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	count, err := ac.Service.CountAppointmentsByStatus(2, clinicID) // Status 2 = atendidas
 	if err != nil {
 		http.Error(w, "Error obteniendo citas atendidas: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -305,7 +666,12 @@ func (ac *AppointmentController) GetCountAttendedAppointments(w http.ResponseWri
 }
 
 func (ac *AppointmentController) GetCountPendingAppointments(w http.ResponseWriter, r *http.Request) {
-	count, err := ac.Service.CountAppointmentsByStatus(1) // Status 1 = pendientes
+	clinicID, err := parseClinicID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	count, err := ac.Service.CountAppointmentsByStatus(1, clinicID) // Status 1 = pendientes
 	if err != nil {
 		http.Error(w, "Error obteniendo citas pendientes: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -314,7 +680,12 @@ func (ac *AppointmentController) GetCountPendingAppointments(w http.ResponseWrit
 }
 
 func (ac *AppointmentController) GetTotalVets(w http.ResponseWriter, r *http.Request) {
-	count, err := ac.Service.CountVets()
+	clinicID, err := parseClinicID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	count, err := ac.Service.CountVets(clinicID)
 	if err != nil {
 		http.Error(w, "Error obteniendo veterinarios: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -323,7 +694,12 @@ func (ac *AppointmentController) GetTotalVets(w http.ResponseWriter, r *http.Req
 }
 
 func (ac *AppointmentController) GetTopVets(w http.ResponseWriter, r *http.Request) {
-	vets, err := ac.Service.GetVetsWithMostAppointments(5)
+	clinicID, err := parseClinicID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	vets, err := ac.Service.GetVetsWithMostAppointments(5, clinicID)
 	if err != nil {
 		http.Error(w, "Error obteniendo veterinarios con más citas: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -332,7 +708,12 @@ func (ac *AppointmentController) GetTopVets(w http.ResponseWriter, r *http.Reque
 }
 
 func (ac *AppointmentController) GetAppointmentsByMonthLast6Months(w http.ResponseWriter, r *http.Request) {
-	results, err := ac.Service.CountAttendedByMonthLast6Months()
+	clinicID, err := parseClinicID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	results, err := ac.Service.CountAttendedByMonthLast6Months(clinicID)
 	if err != nil {
 		http.Error(w, "Error obteniendo citas por mes: "+err.Error(), http.StatusInternalServerError)
 		return
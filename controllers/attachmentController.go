@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/utils"
+	"VetiCare/validators"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+type AttachmentController struct {
+	Service *services.AttachmentService
+}
+
+func NewAttachmentController(service *services.AttachmentService) *AttachmentController {
+	return &AttachmentController{Service: service}
+}
+
+func (ac *AttachmentController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/pets/{id}/attachments", authMiddleware(http.HandlerFunc(ac.Upload))).Methods("POST")
+	r.Handle("/api/pets/{id}/attachments", authMiddleware(http.HandlerFunc(ac.GetByPet))).Methods("GET")
+	r.Handle("/api/appointments/{id}/attachments", authMiddleware(http.HandlerFunc(ac.GetByAppointment))).Methods("GET")
+	r.Handle("/api/attachments/{id}/file", authMiddleware(http.HandlerFunc(ac.Download))).Methods("GET")
+	r.Handle("/api/attachments/{id}", authMiddleware(http.HandlerFunc(ac.Delete))).Methods("DELETE")
+}
+
+// Upload recibe un formulario multipart con el archivo en el campo file y,
+// opcionalmente, description y appointment_id. El tamaño máximo se configura
+// con ATTACHMENT_MAX_MB.
+func (ac *AttachmentController) Upload(w http.ResponseWriter, r *http.Request) {
+	petID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, validators.ErrInvalidPetID.Error(), http.StatusBadRequest)
+		return
+	}
+	maxBytes := int64(utils.GetEnvInt("ATTACHMENT_MAX_MB", 10)) << 20
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+1<<20)
+	if err := r.ParseMultipartForm(8 << 20); err != nil {
+		http.Error(w, validators.ErrAttachmentTooLarge.Error()+" o el formulario es inválido", http.StatusRequestEntityTooLarge)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, validators.ErrInvalidAttachmentFile.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	description := r.FormValue("description")
+	var appointmentID *string
+	if v := r.FormValue("appointment_id"); v != "" {
+		appointmentID = &v
+	}
+	if err := validators.ValidateAttachmentUpload(header.Filename, description, appointmentID, header.Size, maxBytes); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, validators.ErrAttachmentTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	// El tipo se detecta con los primeros bytes del archivo; el que declara el
+	// cliente no es confiable.
+	reader := bufio.NewReader(file)
+	head, _ := reader.Peek(512)
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+
+	attachment := entities.Attachment{
+		PetID:       petID,
+		FileName:    header.Filename,
+		ContentType: contentType,
+		Description: description,
+	}
+	if uploader, err := uuid.Parse(r.Header.Get("User-ID")); err == nil {
+		attachment.UploadedByID = uploader
+	}
+	if appointmentID != nil {
+		id := uuid.MustParse(*appointmentID)
+		attachment.AppointmentID = &id
+	}
+
+	created, err := ac.Service.Upload(r.Header.Get("User-ID"), &attachment, reader)
+	if err != nil {
+		http.Error(w, "Error al guardar adjunto: "+err.Error(), attachmentErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToAttachmentDTO(created))
+}
+
+func (ac *AttachmentController) GetByPet(w http.ResponseWriter, r *http.Request) {
+	list, err := ac.Service.GetByPetID(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener adjuntos: "+err.Error(), attachmentErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToAttachmentDTOs(list))
+}
+
+func (ac *AttachmentController) GetByAppointment(w http.ResponseWriter, r *http.Request) {
+	list, err := ac.Service.GetByAppointmentID(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener adjuntos: "+err.Error(), attachmentErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToAttachmentDTOs(list))
+}
+
+func (ac *AttachmentController) Download(w http.ResponseWriter, r *http.Request) {
+	attachment, content, err := ac.Service.Open(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al descargar adjunto: "+err.Error(), attachmentErrorStatus(err))
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	io.Copy(w, content)
+}
+
+func (ac *AttachmentController) Delete(w http.ResponseWriter, r *http.Request) {
+	msg, err := ac.Service.Delete(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al eliminar adjunto: "+err.Error(), attachmentErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+func attachmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrAttachmentNotFound),
+		errors.Is(err, services.ErrPetNotFound),
+		errors.Is(err, services.ErrAppointmentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrPetAccessDenied), errors.Is(err, services.ErrStaffOnly):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrAttachmentTypeNotAllowed):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, services.ErrAppointmentPetMismatch):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
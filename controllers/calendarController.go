@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"time"
+)
+
+type CalendarController struct {
+	Service *services.CalendarService
+}
+
+func NewCalendarController(service *services.CalendarService) *CalendarController {
+	return &CalendarController{Service: service}
+}
+
+func (cc *CalendarController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/vets/{id}/time-off", authMiddleware(http.HandlerFunc(cc.GetTimeOffByVet))).Methods("GET")
+	r.Handle("/api/vets/{id}/availability", authMiddleware(http.HandlerFunc(cc.GetVetAvailability))).Methods("GET")
+	r.Handle("/api/clinic/closures", authMiddleware(http.HandlerFunc(cc.GetClosures))).Methods("GET")
+}
+
+func (cc *CalendarController) RegisterAdminRoutes(r *mux.Router, adminMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/vets/{id}/time-off", adminMiddleware(http.HandlerFunc(cc.CreateTimeOff))).Methods("POST")
+	r.Handle("/api/time-off/{id}/affected", adminMiddleware(http.HandlerFunc(cc.GetAffectedByTimeOff))).Methods("GET")
+	r.Handle("/api/time-off/{id}", adminMiddleware(http.HandlerFunc(cc.DeleteTimeOff))).Methods("DELETE")
+	r.Handle("/api/clinic/closures", adminMiddleware(http.HandlerFunc(cc.CreateClosure))).Methods("POST")
+	r.Handle("/api/clinic/closures/{id}", adminMiddleware(http.HandlerFunc(cc.DeleteClosure))).Methods("DELETE")
+}
+
+func (cc *CalendarController) CreateTimeOff(w http.ResponseWriter, r *http.Request) {
+	vetID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, validators.ErrInvalidVetID.Error(), http.StatusBadRequest)
+		return
+	}
+	var input dto.TimeOffInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateTimeOffDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	start, _ := time.Parse("02-01-2006", input.StartDate)
+	end, _ := time.Parse("02-01-2006", input.EndDate)
+
+	timeOff := entities.VetTimeOff{
+		VetID:     vetID,
+		StartDate: start,
+		EndDate:   end,
+		Reason:    input.Reason,
+	}
+	affected, err := cc.Service.AddTimeOff(&timeOff)
+	if err != nil {
+		http.Error(w, "Error registrando ausencia: "+err.Error(), calendarErrorStatus(err))
+		return
+	}
+	created, err := cc.Service.GetTimeOffByID(timeOff.ID.String())
+	if err != nil || created == nil {
+		http.Error(w, "Error obteniendo ausencia creada", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"time_off":              dto.ToVetTimeOffDTO(created),
+		"affected_appointments": dto.ToAppointmentDTOs(affected),
+	})
+}
+
+func (cc *CalendarController) GetTimeOffByVet(w http.ResponseWriter, r *http.Request) {
+	vetID := mux.Vars(r)["id"]
+	list, err := cc.Service.GetTimeOffByVet(vetID)
+	if err != nil {
+		http.Error(w, "Error obteniendo ausencias: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dtos := []dto.VetTimeOffDTO{}
+	for _, item := range list {
+		dtos = append(dtos, dto.ToVetTimeOffDTO(&item))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (cc *CalendarController) GetAffectedByTimeOff(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	affected, err := cc.Service.GetAffectedByTimeOff(id)
+	if err != nil {
+		http.Error(w, "Error obteniendo citas afectadas: "+err.Error(), calendarErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToAppointmentDTOs(affected))
+}
+
+func (cc *CalendarController) DeleteTimeOff(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := cc.Service.DeleteTimeOff(id); err != nil {
+		http.Error(w, "Error al eliminar ausencia: "+err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": "Ausencia eliminada correctamente"})
+}
+
+func (cc *CalendarController) CreateClosure(w http.ResponseWriter, r *http.Request) {
+	var input dto.ClinicClosureInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateClinicClosureDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	date, _ := time.Parse("02-01-2006", input.Date)
+	closure := entities.ClinicClosure{Date: date, Description: input.Description}
+	affected, err := cc.Service.AddClosure(&closure)
+	if err != nil {
+		http.Error(w, "Error registrando cierre, verifique que la fecha no esté registrada", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"closure":               dto.ToClinicClosureDTO(&closure),
+		"affected_appointments": dto.ToAppointmentDTOs(affected),
+	})
+}
+
+func (cc *CalendarController) GetClosures(w http.ResponseWriter, r *http.Request) {
+	var from, to *time.Time
+	if v := r.URL.Query().Get("from"); v != "" {
+		date, err := time.Parse("02-01-2006", v)
+		if err != nil {
+			http.Error(w, "Fecha inválida, use formato DD-MM-YYYY", http.StatusBadRequest)
+			return
+		}
+		from = &date
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		date, err := time.Parse("02-01-2006", v)
+		if err != nil {
+			http.Error(w, "Fecha inválida, use formato DD-MM-YYYY", http.StatusBadRequest)
+			return
+		}
+		to = &date
+	}
+	list, err := cc.Service.GetClosures(from, to)
+	if err != nil {
+		http.Error(w, "Error obteniendo días de cierre: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dtos := []dto.ClinicClosureDTO{}
+	for _, item := range list {
+		dtos = append(dtos, dto.ToClinicClosureDTO(&item))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (cc *CalendarController) DeleteClosure(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := cc.Service.DeleteClosure(id); err != nil {
+		http.Error(w, "Error al eliminar día de cierre: "+err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": "Día de cierre eliminado correctamente"})
+}
+
+func (cc *CalendarController) GetVetAvailability(w http.ResponseWriter, r *http.Request) {
+	vetID := mux.Vars(r)["id"]
+	if err := validators.ValidateUUIDRequired(vetID); err != nil {
+		http.Error(w, validators.ErrInvalidVetID.Error(), http.StatusBadRequest)
+		return
+	}
+	dateStr := r.URL.Query().Get("date")
+	date, err := time.ParseInLocation("02-01-2006", dateStr, time.Local)
+	if err != nil {
+		http.Error(w, "Fecha inválida, use formato DD-MM-YYYY", http.StatusBadRequest)
+		return
+	}
+	clinicID, err := parseClinicID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	slots, err := cc.Service.GetVetAvailability(vetID, date, clinicID)
+	if err != nil {
+		http.Error(w, "Error obteniendo disponibilidad: "+err.Error(), calendarErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToAvailabilityDTO(vetID, dateStr, slots))
+}
+
+func calendarErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrVetNotFound), errors.Is(err, services.ErrTimeOffNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidDateRange):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,323 @@
+package controllers
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+type ClinicController struct {
+	Service *services.ClinicService
+}
+
+func NewClinicController(service *services.ClinicService) *ClinicController {
+	return &ClinicController{Service: service}
+}
+
+func (cc *ClinicController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/clinics", authMiddleware(http.HandlerFunc(cc.GetAll))).Methods("GET")
+	r.Handle("/api/clinics/{id}", authMiddleware(http.HandlerFunc(cc.GetByID))).Methods("GET")
+	r.Handle("/api/clinics/{id}/rooms", authMiddleware(http.HandlerFunc(cc.GetRooms))).Methods("GET")
+	r.Handle("/api/clinics/{id}/resources", authMiddleware(http.HandlerFunc(cc.GetResources))).Methods("GET")
+	r.Handle("/api/clinics/{id}/vets", authMiddleware(http.HandlerFunc(cc.GetVets))).Methods("GET")
+}
+
+func (cc *ClinicController) RegisterAdminRoutes(r *mux.Router, adminMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/clinics", adminMiddleware(http.HandlerFunc(cc.Create))).Methods("POST")
+	r.Handle("/api/clinics/{id}", adminMiddleware(http.HandlerFunc(cc.Update))).Methods("PUT")
+	r.Handle("/api/clinics/{id}", adminMiddleware(http.HandlerFunc(cc.Delete))).Methods("DELETE")
+	r.Handle("/api/clinics/{id}/rooms", adminMiddleware(http.HandlerFunc(cc.CreateRoom))).Methods("POST")
+	r.Handle("/api/rooms/{id}", adminMiddleware(http.HandlerFunc(cc.UpdateRoom))).Methods("PUT")
+	r.Handle("/api/clinics/{id}/resources", adminMiddleware(http.HandlerFunc(cc.CreateResource))).Methods("POST")
+	r.Handle("/api/resources/{id}", adminMiddleware(http.HandlerFunc(cc.UpdateResource))).Methods("PUT")
+	r.Handle("/api/vets/{id}/clinics", adminMiddleware(http.HandlerFunc(cc.SetVetClinics))).Methods("PUT")
+}
+
+// parseClinicID lee el filtro opcional clinic_id de la consulta.
+func parseClinicID(r *http.Request) (*int, error) {
+	value := r.URL.Query().Get("clinic_id")
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil || id < 1 {
+		return nil, errors.New("clinic_id inválido")
+	}
+	return &id, nil
+}
+
+func (cc *ClinicController) GetAll(w http.ResponseWriter, _ *http.Request) {
+	list, err := cc.Service.GetAll()
+	if err != nil {
+		http.Error(w, "Error al obtener clínicas: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dtos := []dto.ClinicDTO{}
+	for _, c := range list {
+		dtos = append(dtos, dto.ToClinicDTO(&c))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (cc *ClinicController) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	clinic, err := cc.Service.GetByID(id)
+	if err != nil {
+		http.Error(w, "Error al obtener clínica: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if clinic == nil {
+		http.Error(w, "Clínica no encontrada", http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToClinicDTO(clinic))
+}
+
+func (cc *ClinicController) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.ClinicDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateClinicName(input.Name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateClinicAddress(input.Address); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.Phone != "" {
+		if err := validators.ValidatePhone(input.Phone); err != nil {
+			http.Error(w, validators.ErrInvalidPhoneUser.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	clinic := entities.Clinic{Name: input.Name, Address: input.Address, Phone: input.Phone, StatusID: 1}
+	if err := cc.Service.Create(&clinic); err != nil {
+		http.Error(w, "Error al crear clínica, verifique que el nombre no esté en uso", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToClinicDTO(&clinic))
+}
+
+func (cc *ClinicController) Update(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var fields map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if name, ok := fields["name"].(string); ok {
+		if err := validators.ValidateClinicName(name); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if address, ok := fields["address"].(string); ok {
+		if err := validators.ValidateClinicAddress(address); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if phone, ok := fields["phone"].(string); ok {
+		if err := validators.ValidatePhone(phone); err != nil {
+			http.Error(w, validators.ErrInvalidPhoneUser.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := cc.Service.Update(id, fields); err != nil {
+		http.Error(w, "Error al actualizar clínica: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": "Clínica actualizada correctamente"})
+}
+
+func (cc *ClinicController) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	msg, err := cc.Service.Delete(id)
+	if err != nil {
+		http.Error(w, "Error al cambiar estado de la clínica: "+err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+func (cc *ClinicController) GetRooms(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	list, err := cc.Service.GetRooms(id)
+	if err != nil {
+		http.Error(w, "Error al obtener salas: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dtos := []dto.RoomDTO{}
+	for _, room := range list {
+		dtos = append(dtos, dto.ToRoomDTO(&room))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (cc *ClinicController) CreateRoom(w http.ResponseWriter, r *http.Request) {
+	clinicID, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var input dto.RoomDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if input.Capacity == 0 {
+		input.Capacity = 1
+	}
+	if err := validators.ValidateRoomName(input.Name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateCapacity(input.Capacity); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	room := entities.Room{ClinicID: clinicID, Name: input.Name, Capacity: input.Capacity, StatusID: 1}
+	if err := cc.Service.CreateRoom(&room); err != nil {
+		http.Error(w, "Error al crear sala: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToRoomDTO(&room))
+}
+
+func (cc *ClinicController) UpdateRoom(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	fields, ok := decodeCapacityFields(w, r, "capacity")
+	if !ok {
+		return
+	}
+	if err := cc.Service.UpdateRoom(id, fields); err != nil {
+		http.Error(w, "Error al actualizar sala: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	room, err := cc.Service.GetRoomByID(id)
+	if err != nil || room == nil {
+		http.Error(w, "Sala no encontrada", http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToRoomDTO(room))
+}
+
+func (cc *ClinicController) GetResources(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	list, err := cc.Service.GetResources(id)
+	if err != nil {
+		http.Error(w, "Error al obtener equipos: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dtos := []dto.ResourceDTO{}
+	for _, resource := range list {
+		dtos = append(dtos, dto.ToResourceDTO(&resource))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (cc *ClinicController) CreateResource(w http.ResponseWriter, r *http.Request) {
+	clinicID, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var input dto.ResourceDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if input.Quantity == 0 {
+		input.Quantity = 1
+	}
+	if err := validators.ValidateRoomName(input.Name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateCapacity(input.Quantity); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resource := entities.Resource{ClinicID: clinicID, Name: input.Name, Quantity: input.Quantity, StatusID: 1}
+	if err := cc.Service.CreateResource(&resource); err != nil {
+		http.Error(w, "Error al crear equipo: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToResourceDTO(&resource))
+}
+
+func (cc *ClinicController) UpdateResource(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	fields, ok := decodeCapacityFields(w, r, "quantity")
+	if !ok {
+		return
+	}
+	if err := cc.Service.UpdateResource(id, fields); err != nil {
+		http.Error(w, "Error al actualizar equipo: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resource, err := cc.Service.GetResourceByID(id)
+	if err != nil || resource == nil {
+		http.Error(w, "Equipo no encontrado", http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToResourceDTO(resource))
+}
+
+func (cc *ClinicController) GetVets(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	vets, err := cc.Service.GetVets(id)
+	if err != nil {
+		http.Error(w, "Error al obtener veterinarios: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dtos := []dto.UserDTO{}
+	for _, vet := range vets {
+		dtos = append(dtos, dto.ToUserDTO(&vet))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (cc *ClinicController) SetVetClinics(w http.ResponseWriter, r *http.Request) {
+	vetID := mux.Vars(r)["id"]
+	if err := validators.ValidateUUIDRequired(vetID); err != nil {
+		http.Error(w, validators.ErrInvalidVetID.Error(), http.StatusBadRequest)
+		return
+	}
+	var input dto.VetClinicsDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := cc.Service.SetVetClinics(vetID, input.ClinicIDs); err != nil {
+		http.Error(w, "Error al asignar clínicas: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": "Clínicas asignadas correctamente"})
+}
+
+func decodeCapacityFields(w http.ResponseWriter, r *http.Request, capacityKey string) (map[string]interface{}, bool) {
+	var fields map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return nil, false
+	}
+	delete(fields, "clinic_id")
+	if name, ok := fields["name"].(string); ok {
+		if err := validators.ValidateRoomName(name); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	if capacity, ok := fields[capacityKey].(float64); ok {
+		if err := validators.ValidateCapacity(int(capacity)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	return fields, true
+}
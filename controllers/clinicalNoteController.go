@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+type ClinicalNoteController struct {
+	Service *services.ClinicalNoteService
+}
+
+func NewClinicalNoteController(service *services.ClinicalNoteService) *ClinicalNoteController {
+	return &ClinicalNoteController{Service: service}
+}
+
+func (nc *ClinicalNoteController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/appointments/{id}/clinical-note", authMiddleware(http.HandlerFunc(nc.GetByAppointment))).Methods("GET")
+	r.Handle("/api/appointments/{id}/clinical-note", authMiddleware(http.HandlerFunc(nc.SaveDraft))).Methods("PUT")
+	r.Handle("/api/appointments/{id}/clinical-note/finalize", authMiddleware(http.HandlerFunc(nc.Finalize))).Methods("POST")
+	r.Handle("/api/clinical-note-templates", authMiddleware(http.HandlerFunc(nc.GetTemplates))).Methods("GET")
+	r.Handle("/api/clinical-note-templates", authMiddleware(http.HandlerFunc(nc.CreateTemplate))).Methods("POST")
+	r.Handle("/api/clinical-note-templates/{id}", authMiddleware(http.HandlerFunc(nc.UpdateTemplate))).Methods("PUT")
+	r.Handle("/api/clinical-note-templates/{id}", authMiddleware(http.HandlerFunc(nc.DeleteTemplate))).Methods("DELETE")
+}
+
+// RegisterAdminRoutes expone la gestión de las plantillas compartidas por
+// toda la clínica. Se descarta cualquier User-ID recibido para que la
+// plantilla no quede a nombre de un veterinario.
+func (nc *ClinicalNoteController) RegisterAdminRoutes(r *mux.Router, adminMiddleware func(http.Handler) http.Handler) {
+	shared := func(h http.HandlerFunc) http.Handler {
+		return adminMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Del("User-ID")
+			h(w, r)
+		}))
+	}
+	r.Handle("/api/clinical-note-templates/shared", shared(nc.CreateTemplate)).Methods("POST")
+	r.Handle("/api/clinical-note-templates/shared/{id}", shared(nc.UpdateTemplate)).Methods("PUT")
+	r.Handle("/api/clinical-note-templates/shared/{id}", shared(nc.DeleteTemplate)).Methods("DELETE")
+}
+
+func (nc *ClinicalNoteController) GetByAppointment(w http.ResponseWriter, r *http.Request) {
+	note, err := nc.Service.GetByAppointmentID(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener nota clínica: "+err.Error(), clinicalNoteErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToClinicalNoteDTO(note))
+}
+
+// SaveDraft guarda el borrador de la nota redactada por el veterinario
+// autenticado. Cada llamada reemplaza el contenido completo del borrador.
+func (nc *ClinicalNoteController) SaveDraft(w http.ResponseWriter, r *http.Request) {
+	appointmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de cita inválido", http.StatusBadRequest)
+		return
+	}
+	vetID, err := uuid.Parse(r.Header.Get("User-ID"))
+	if err != nil {
+		http.Error(w, services.ErrClinicalNoteAuthorInvalid.Error(), http.StatusForbidden)
+		return
+	}
+	var input dto.ClinicalNoteInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateClinicalNoteInputDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	note := entities.ClinicalNote{
+		VetID:          vetID,
+		TemplateID:     input.TemplateID,
+		Subjective:     input.Subjective,
+		Objective:      input.Objective,
+		Assessment:     input.Assessment,
+		Plan:           input.Plan,
+		DiagnosisCodes: dto.JoinDiagnosisCodes(input.DiagnosisCodes),
+	}
+	saved, err := nc.Service.SaveDraft(appointmentID, note)
+	if err != nil {
+		http.Error(w, "Error al guardar nota clínica: "+err.Error(), clinicalNoteErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToClinicalNoteDTO(saved))
+}
+
+func (nc *ClinicalNoteController) Finalize(w http.ResponseWriter, r *http.Request) {
+	vetID, err := uuid.Parse(r.Header.Get("User-ID"))
+	if err != nil {
+		http.Error(w, services.ErrClinicalNoteAuthorInvalid.Error(), http.StatusForbidden)
+		return
+	}
+	note, err := nc.Service.Finalize(mux.Vars(r)["id"], vetID)
+	if err != nil {
+		http.Error(w, "Error al finalizar nota clínica: "+err.Error(), clinicalNoteErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToClinicalNoteDTO(note))
+}
+
+func (nc *ClinicalNoteController) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	list, err := nc.Service.GetTemplates(r.Header.Get("User-ID"), r.URL.Query().Get("all") != "true")
+	if err != nil {
+		http.Error(w, "Error al obtener plantillas: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dtos := []dto.ClinicalNoteTemplateDTO{}
+	for _, t := range list {
+		dtos = append(dtos, dto.ToClinicalNoteTemplateDTO(&t))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+// templateEditor identifica a quien edita la plantilla. En las rutas de
+// administración no hay User-ID y se devuelve nil, que corresponde a las
+// plantillas compartidas.
+func templateEditor(r *http.Request) *uuid.UUID {
+	if parsed, err := uuid.Parse(r.Header.Get("User-ID")); err == nil {
+		return &parsed
+	}
+	return nil
+}
+
+func (nc *ClinicalNoteController) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var input dto.ClinicalNoteTemplateDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateClinicalNoteTemplateDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	template := entities.ClinicalNoteTemplate{
+		VetID:      templateEditor(r),
+		Name:       input.Name,
+		Subjective: input.Subjective,
+		Objective:  input.Objective,
+		Assessment: input.Assessment,
+		Plan:       input.Plan,
+		StatusID:   1,
+	}
+	created, err := nc.Service.CreateTemplate(&template)
+	if err != nil {
+		http.Error(w, "Error al crear plantilla: "+err.Error(), clinicalNoteErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToClinicalNoteTemplateDTO(created))
+}
+
+func (nc *ClinicalNoteController) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var input dto.ClinicalNoteTemplateDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateClinicalNoteTemplateDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fields := map[string]interface{}{
+		"name":       input.Name,
+		"subjective": input.Subjective,
+		"objective":  input.Objective,
+		"assessment": input.Assessment,
+		"plan":       input.Plan,
+	}
+	updated, err := nc.Service.UpdateTemplate(id, templateEditor(r), fields)
+	if err != nil {
+		http.Error(w, "Error al actualizar plantilla: "+err.Error(), clinicalNoteErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToClinicalNoteTemplateDTO(updated))
+}
+
+func (nc *ClinicalNoteController) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	msg, err := nc.Service.DeleteTemplate(id, templateEditor(r))
+	if err != nil {
+		http.Error(w, "Error al cambiar estado de la plantilla: "+err.Error(), clinicalNoteErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+func clinicalNoteErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrClinicalNoteNotFound),
+		errors.Is(err, services.ErrAppointmentNotFound),
+		errors.Is(err, services.ErrNoteTemplateNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrClinicalNoteAuthorInvalid),
+		errors.Is(err, services.ErrNoteTemplateNotOwned),
+		errors.Is(err, services.ErrPetAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrClinicalNoteFinalized):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrClinicalNoteNotAllowed):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,247 @@
+package controllers
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type DiagnosisController struct {
+	Service *services.DiagnosisService
+}
+
+func NewDiagnosisController(service *services.DiagnosisService) *DiagnosisController {
+	return &DiagnosisController{Service: service}
+}
+
+func (dc *DiagnosisController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/diagnoses", authMiddleware(http.HandlerFunc(dc.GetDiagnoses))).Methods("GET")
+	r.Handle("/api/diagnoses/{id}", authMiddleware(http.HandlerFunc(dc.GetDiagnosisByID))).Methods("GET")
+	r.Handle("/api/appointments/{id}/diagnoses", authMiddleware(http.HandlerFunc(dc.GetAppointmentDiagnoses))).Methods("GET")
+	r.Handle("/api/appointments/{id}/diagnoses", authMiddleware(http.HandlerFunc(dc.SetAppointmentDiagnoses))).Methods("PUT")
+	r.Handle("/api/dashboard/diagnoses", authMiddleware(http.HandlerFunc(dc.GetReport))).Methods("GET")
+}
+
+func (dc *DiagnosisController) RegisterAdminRoutes(r *mux.Router, adminMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/diagnoses", adminMiddleware(http.HandlerFunc(dc.CreateDiagnosis))).Methods("POST")
+	r.Handle("/api/diagnoses/{id}", adminMiddleware(http.HandlerFunc(dc.UpdateDiagnosis))).Methods("PUT")
+	r.Handle("/api/diagnoses/{id}", adminMiddleware(http.HandlerFunc(dc.DeleteDiagnosis))).Methods("DELETE")
+}
+
+// GetDiagnoses lista el catálogo. Admite species_id para ver solo los que
+// aplican a la especie, q para buscar por código o nombre y all=true para
+// incluir los inactivos.
+func (dc *DiagnosisController) GetDiagnoses(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	speciesID := 0
+	if v := q.Get("species_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "species_id inválido", http.StatusBadRequest)
+			return
+		}
+		speciesID = id
+	}
+	list, err := dc.Service.GetDiagnoses(q.Get("all") != "true", speciesID, strings.TrimSpace(q.Get("q")))
+	if err != nil {
+		http.Error(w, "Error al obtener diagnósticos: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToDiagnosisDTOs(list))
+}
+
+func (dc *DiagnosisController) GetDiagnosisByID(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	d, err := dc.Service.GetDiagnosisByID(id)
+	if err != nil {
+		http.Error(w, "Error al obtener diagnóstico: "+err.Error(), diagnosisErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToDiagnosisDTO(d))
+}
+
+func decodeDiagnosis(w http.ResponseWriter, r *http.Request) (*entities.Diagnosis, bool) {
+	var input dto.DiagnosisDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return nil, false
+	}
+	input.Code = strings.ToUpper(strings.TrimSpace(input.Code))
+	input.Name = strings.TrimSpace(input.Name)
+	if err := validators.ValidateDiagnosisDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return &entities.Diagnosis{
+		Code:      input.Code,
+		Name:      input.Name,
+		Category:  strings.TrimSpace(input.Category),
+		SpeciesID: input.SpeciesID,
+		StatusID:  1,
+	}, true
+}
+
+func (dc *DiagnosisController) CreateDiagnosis(w http.ResponseWriter, r *http.Request) {
+	d, ok := decodeDiagnosis(w, r)
+	if !ok {
+		return
+	}
+	created, err := dc.Service.CreateDiagnosis(d)
+	if err != nil {
+		if errors.Is(err, services.ErrSpeciesNotFound) {
+			http.Error(w, "Error al crear diagnóstico: "+err.Error(), diagnosisErrorStatus(err))
+			return
+		}
+		http.Error(w, "Error al crear diagnóstico, verifique que el código no esté en uso", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToDiagnosisDTO(created))
+}
+
+func (dc *DiagnosisController) UpdateDiagnosis(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	d, ok := decodeDiagnosis(w, r)
+	if !ok {
+		return
+	}
+	updated, err := dc.Service.UpdateDiagnosis(id, d)
+	if err != nil {
+		if errors.Is(err, services.ErrDiagnosisNotFound) || errors.Is(err, services.ErrSpeciesNotFound) {
+			http.Error(w, "Error al actualizar diagnóstico: "+err.Error(), diagnosisErrorStatus(err))
+			return
+		}
+		http.Error(w, "Error al actualizar diagnóstico, verifique que el código no esté en uso", http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToDiagnosisDTO(updated))
+}
+
+func (dc *DiagnosisController) DeleteDiagnosis(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	msg, err := dc.Service.DeleteDiagnosis(id)
+	if err != nil {
+		http.Error(w, "Error al cambiar estado del diagnóstico: "+err.Error(), diagnosisErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+func (dc *DiagnosisController) GetAppointmentDiagnoses(w http.ResponseWriter, r *http.Request) {
+	list, err := dc.Service.GetAppointmentDiagnoses(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener diagnósticos de la cita: "+err.Error(), diagnosisErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToAppointmentDiagnosisDTOs(list))
+}
+
+// SetAppointmentDiagnoses reemplaza la lista completa de diagnósticos de la
+// cita; una lista vacía los elimina.
+func (dc *DiagnosisController) SetAppointmentDiagnoses(w http.ResponseWriter, r *http.Request) {
+	appointmentID := mux.Vars(r)["id"]
+	if err := validators.ValidateUUIDRequired(appointmentID); err != nil {
+		http.Error(w, "ID de cita inválido", http.StatusBadRequest)
+		return
+	}
+	var input dto.AppointmentDiagnosesInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateAppointmentDiagnosesInputDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	list := []entities.AppointmentDiagnosis{}
+	for _, in := range input.Diagnoses {
+		list = append(list, entities.AppointmentDiagnosis{
+			DiagnosisID: in.DiagnosisID,
+			IsPrimary:   in.IsPrimary,
+			Notes:       strings.TrimSpace(in.Notes),
+		})
+	}
+	saved, err := dc.Service.SetAppointmentDiagnoses(r.Header.Get("User-ID"), appointmentID, list)
+	if err != nil {
+		http.Error(w, "Error al registrar diagnósticos: "+err.Error(), diagnosisErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToAppointmentDiagnosisDTOs(saved))
+}
+
+// GetReport cuenta los diagnósticos de citas finalizadas. Admite date_from y
+// date_to (DD-MM-YYYY), species_id, clinic_id y primary_only=true para
+// contar solo los diagnósticos principales.
+func (dc *DiagnosisController) GetReport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := entities.DiagnosisReportFilter{PrimaryOnly: q.Get("primary_only") == "true"}
+	if v := q.Get("date_from"); v != "" {
+		date, err := time.Parse("02-01-2006", v)
+		if err != nil {
+			http.Error(w, "date_from inválido, use formato DD-MM-YYYY", http.StatusBadRequest)
+			return
+		}
+		filter.DateFrom = &date
+	}
+	if v := q.Get("date_to"); v != "" {
+		date, err := time.Parse("02-01-2006", v)
+		if err != nil {
+			http.Error(w, "date_to inválido, use formato DD-MM-YYYY", http.StatusBadRequest)
+			return
+		}
+		filter.DateTo = &date
+	}
+	if v := q.Get("species_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "species_id inválido", http.StatusBadRequest)
+			return
+		}
+		filter.SpeciesID = id
+	}
+	clinicID, err := parseClinicID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.ClinicID = clinicID
+
+	results, err := dc.Service.Report(r.Header.Get("User-ID"), filter)
+	if err != nil {
+		http.Error(w, "Error obteniendo reporte de diagnósticos: "+err.Error(), diagnosisErrorStatus(err))
+		return
+	}
+	if results == nil {
+		results = []entities.DiagnosisCount{}
+	}
+	json.NewEncoder(w).Encode(results)
+}
+
+func diagnosisErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrDiagnosisNotFound),
+		errors.Is(err, services.ErrAppointmentNotFound),
+		errors.Is(err, services.ErrSpeciesNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrPetAccessDenied), errors.Is(err, services.ErrStaffOnly):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrAppointmentRecordLocked):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrDiagnosisSpecies),
+		errors.Is(err, services.ErrDiagnosisDuplicated),
+		errors.Is(err, services.ErrDiagnosisPrimary),
+		errors.Is(err, services.ErrDiagnosisNotAllowed):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrInvalidDateRange):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
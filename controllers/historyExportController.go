@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"VetiCare/services"
+	"errors"
+	"github.com/gorilla/mux"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type HistoryExportController struct {
+	Service *services.HistoryExportService
+}
+
+func NewHistoryExportController(service *services.HistoryExportService) *HistoryExportController {
+	return &HistoryExportController{Service: service}
+}
+
+func (hc *HistoryExportController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/pets/{id}/history.pdf", authMiddleware(http.HandlerFunc(hc.ExportPDF))).Methods("GET")
+}
+
+func (hc *HistoryExportController) ExportPDF(w http.ResponseWriter, r *http.Request) {
+	content, pet, err := hc.Service.ExportPDF(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al generar historial: "+err.Error(), historyExportErrorStatus(err))
+		return
+	}
+	fileName := "historial-" + pet.Name + "-" + time.Now().Format("2006-01-02") + ".pdf"
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Write(content)
+}
+
+func historyExportErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPetNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrStaffOnly):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,397 @@
+package controllers
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// internalLaboratory es el nombre con el que se registran las órdenes que se
+// procesan en la clínica.
+const internalLaboratory = "Laboratorio interno"
+
+type LabController struct {
+	Service *services.LabService
+}
+
+func NewLabController(service *services.LabService) *LabController {
+	return &LabController{Service: service}
+}
+
+func (lc *LabController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/lab-tests", authMiddleware(http.HandlerFunc(lc.GetTests))).Methods("GET")
+	r.Handle("/api/lab-tests/{id}", authMiddleware(http.HandlerFunc(lc.GetTestByID))).Methods("GET")
+	r.Handle("/api/appointments/{id}/lab-orders", authMiddleware(http.HandlerFunc(lc.CreateOrder))).Methods("POST")
+	r.Handle("/api/appointments/{id}/lab-orders", authMiddleware(http.HandlerFunc(lc.GetOrdersByAppointment))).Methods("GET")
+	r.Handle("/api/pets/{id}/lab-orders", authMiddleware(http.HandlerFunc(lc.GetOrdersByPet))).Methods("GET")
+	r.Handle("/api/lab-orders/pending", authMiddleware(http.HandlerFunc(lc.GetOpenOrders))).Methods("GET")
+	r.Handle("/api/lab-orders/{id}", authMiddleware(http.HandlerFunc(lc.GetOrderByID))).Methods("GET")
+	r.Handle("/api/lab-orders/{id}/status/{status_id}", authMiddleware(http.HandlerFunc(lc.UpdateOrderStatus))).Methods("PATCH")
+	r.Handle("/api/lab-orders/{id}/results", authMiddleware(http.HandlerFunc(lc.RecordResults))).Methods("PUT")
+}
+
+func (lc *LabController) RegisterAdminRoutes(r *mux.Router, adminMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/lab-tests", adminMiddleware(http.HandlerFunc(lc.CreateTest))).Methods("POST")
+	r.Handle("/api/lab-tests/{id}", adminMiddleware(http.HandlerFunc(lc.UpdateTest))).Methods("PUT")
+	r.Handle("/api/lab-tests/{id}", adminMiddleware(http.HandlerFunc(lc.DeleteTest))).Methods("DELETE")
+	r.Handle("/api/lab-tests/{id}/analytes", adminMiddleware(http.HandlerFunc(lc.CreateAnalyte))).Methods("POST")
+	r.Handle("/api/lab-analytes/{id}", adminMiddleware(http.HandlerFunc(lc.UpdateAnalyte))).Methods("PUT")
+	r.Handle("/api/lab-analytes/{id}", adminMiddleware(http.HandlerFunc(lc.DeleteAnalyte))).Methods("DELETE")
+	r.Handle("/api/lab-analytes/{id}/ranges/{species_id}", adminMiddleware(http.HandlerFunc(lc.SetReferenceRange))).Methods("PUT")
+	r.Handle("/api/lab-analytes/{id}/ranges/{species_id}", adminMiddleware(http.HandlerFunc(lc.DeleteReferenceRange))).Methods("DELETE")
+}
+
+func (lc *LabController) GetTests(w http.ResponseWriter, r *http.Request) {
+	list, err := lc.Service.GetTests(r.URL.Query().Get("all") != "true")
+	if err != nil {
+		http.Error(w, "Error al obtener pruebas de laboratorio: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToLabTestDTOs(list))
+}
+
+func (lc *LabController) GetTestByID(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	test, err := lc.Service.GetTestByID(id)
+	if err != nil {
+		http.Error(w, "Error al obtener prueba de laboratorio: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToLabTestDTO(test))
+}
+
+// CreateTest registra la prueba con sus analitos y, opcionalmente, los rangos
+// de referencia de cada analito por especie.
+func (lc *LabController) CreateTest(w http.ResponseWriter, r *http.Request) {
+	var input dto.LabTestDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateLabTestDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	test := entities.LabTest{
+		Name:       input.Name,
+		Code:       input.Code,
+		SampleType: input.SampleType,
+		StatusID:   1,
+	}
+	for i, a := range input.Analytes {
+		analyte := toLabAnalyte(a)
+		if analyte.SortOrder == 0 {
+			analyte.SortOrder = i + 1
+		}
+		test.Analytes = append(test.Analytes, analyte)
+	}
+	created, err := lc.Service.CreateTest(&test)
+	if err != nil {
+		if errors.Is(err, services.ErrSpeciesNotFound) {
+			http.Error(w, "Error al crear prueba de laboratorio: "+err.Error(), labErrorStatus(err))
+			return
+		}
+		http.Error(w, "Error al crear prueba de laboratorio, verifique que el nombre no esté en uso", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToLabTestDTO(created))
+}
+
+func (lc *LabController) UpdateTest(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var input dto.LabTestDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	input.Analytes = nil
+	if err := validators.ValidateLabTestDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fields := map[string]interface{}{
+		"name":        input.Name,
+		"code":        input.Code,
+		"sample_type": input.SampleType,
+	}
+	updated, err := lc.Service.UpdateTest(id, fields)
+	if err != nil {
+		http.Error(w, "Error al actualizar prueba de laboratorio: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToLabTestDTO(updated))
+}
+
+func (lc *LabController) DeleteTest(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	msg, err := lc.Service.DeleteTest(id)
+	if err != nil {
+		http.Error(w, "Error al cambiar estado de la prueba de laboratorio: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+func (lc *LabController) CreateAnalyte(w http.ResponseWriter, r *http.Request) {
+	testID, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var input dto.LabAnalyteDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateLabAnalyteDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	analyte := toLabAnalyte(input)
+	analyte.LabTestID = testID
+	created, err := lc.Service.CreateAnalyte(&analyte)
+	if err != nil {
+		http.Error(w, "Error al crear analito: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToLabAnalyteDTO(created))
+}
+
+// UpdateAnalyte cambia el nombre, la unidad o el orden del analito; los
+// rangos se gestionan por especie en su propia ruta.
+func (lc *LabController) UpdateAnalyte(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var input dto.LabAnalyteDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	input.Ranges = nil
+	if err := validators.ValidateLabAnalyteDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fields := map[string]interface{}{
+		"name":       input.Name,
+		"unit":       input.Unit,
+		"sort_order": input.SortOrder,
+	}
+	updated, err := lc.Service.UpdateAnalyte(id, fields)
+	if err != nil {
+		http.Error(w, "Error al actualizar analito: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToLabAnalyteDTO(updated))
+}
+
+func (lc *LabController) DeleteAnalyte(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	msg, err := lc.Service.DeleteAnalyte(id)
+	if err != nil {
+		http.Error(w, "Error al cambiar estado del analito: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+func (lc *LabController) SetReferenceRange(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	analyteID, _ := strconv.Atoi(vars["id"])
+	speciesID, _ := strconv.Atoi(vars["species_id"])
+	var input dto.LabReferenceRangeDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	input.SpeciesID = speciesID
+	if err := validators.ValidateLabReferenceRangeDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rng := entities.LabReferenceRange{AnalyteID: analyteID, SpeciesID: speciesID, Min: input.Min, Max: input.Max}
+	analyte, err := lc.Service.SetReferenceRange(&rng)
+	if err != nil {
+		http.Error(w, "Error al guardar rango de referencia: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToLabAnalyteDTO(analyte))
+}
+
+func (lc *LabController) DeleteReferenceRange(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	analyteID, _ := strconv.Atoi(vars["id"])
+	speciesID, _ := strconv.Atoi(vars["species_id"])
+	if err := lc.Service.DeleteReferenceRange(analyteID, speciesID); err != nil {
+		http.Error(w, "Error al eliminar rango de referencia: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": "Rango de referencia eliminado correctamente"})
+}
+
+func (lc *LabController) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	appointmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de cita inválido", http.StatusBadRequest)
+		return
+	}
+	var input dto.LabOrderInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	input.Laboratory = strings.TrimSpace(input.Laboratory)
+	if err := validators.ValidateLabOrderInputDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.Laboratory == "" {
+		input.Laboratory = internalLaboratory
+	}
+	order := entities.LabOrder{
+		AppointmentID: appointmentID,
+		LabTestID:     input.LabTestID,
+		Laboratory:    input.Laboratory,
+		External:      input.External,
+		Notes:         input.Notes,
+	}
+	created, err := lc.Service.CreateOrder(r.Header.Get("User-ID"), &order)
+	if err != nil {
+		http.Error(w, "Error al crear orden de laboratorio: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToLabOrderDTO(created))
+}
+
+func (lc *LabController) GetOrdersByAppointment(w http.ResponseWriter, r *http.Request) {
+	list, err := lc.Service.GetOrdersByAppointmentID(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener órdenes de laboratorio: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToLabOrderDTOs(list))
+}
+
+// GetOrdersByPet admite ?status_id= para filtrar, por ejemplo, solo las
+// órdenes pendientes.
+func (lc *LabController) GetOrdersByPet(w http.ResponseWriter, r *http.Request) {
+	var statusID *int
+	if v := r.URL.Query().Get("status_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "status_id inválido", http.StatusBadRequest)
+			return
+		}
+		statusID = &id
+	}
+	list, err := lc.Service.GetOrdersByPetID(r.Header.Get("User-ID"), mux.Vars(r)["id"], statusID)
+	if err != nil {
+		http.Error(w, "Error al obtener órdenes de laboratorio: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToLabOrderDTOs(list))
+}
+
+func (lc *LabController) GetOpenOrders(w http.ResponseWriter, r *http.Request) {
+	list, err := lc.Service.GetOpenOrders(r.Header.Get("User-ID"))
+	if err != nil {
+		http.Error(w, "Error al obtener órdenes pendientes: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToLabOrderDTOs(list))
+}
+
+func (lc *LabController) GetOrderByID(w http.ResponseWriter, r *http.Request) {
+	order, err := lc.Service.GetOrderByID(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener orden de laboratorio: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToLabOrderDTO(order))
+}
+
+func (lc *LabController) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	statusID, err := strconv.Atoi(vars["status_id"])
+	if err != nil {
+		http.Error(w, "status_id inválido", http.StatusBadRequest)
+		return
+	}
+	order, err := lc.Service.UpdateOrderStatus(r.Header.Get("User-ID"), vars["id"], statusID)
+	if err != nil {
+		http.Error(w, "Error actualizando estado de la orden: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToLabOrderDTO(order))
+}
+
+// RecordResults registra los valores recibidos del laboratorio y completa la
+// orden. La respuesta trae cada resultado marcado con su rango de referencia.
+func (lc *LabController) RecordResults(w http.ResponseWriter, r *http.Request) {
+	var input dto.LabResultsInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateLabResultsInputDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	results := []entities.LabResult{}
+	for _, in := range input.Results {
+		results = append(results, entities.LabResult{
+			AnalyteID: in.AnalyteID,
+			Value:     in.Value,
+			TextValue: strings.TrimSpace(in.TextValue),
+		})
+	}
+	order, err := lc.Service.RecordResults(r.Header.Get("User-ID"), mux.Vars(r)["id"], results, input.Notes)
+	if err != nil {
+		http.Error(w, "Error al registrar resultados: "+err.Error(), labErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToLabOrderDTO(order))
+}
+
+func toLabAnalyte(in dto.LabAnalyteDTO) entities.LabAnalyte {
+	analyte := entities.LabAnalyte{
+		Name:      in.Name,
+		Unit:      in.Unit,
+		SortOrder: in.SortOrder,
+		StatusID:  1,
+	}
+	for _, rng := range in.Ranges {
+		analyte.Ranges = append(analyte.Ranges, entities.LabReferenceRange{SpeciesID: rng.SpeciesID, Min: rng.Min, Max: rng.Max})
+	}
+	return analyte
+}
+
+func labErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrLabTestNotFound),
+		errors.Is(err, services.ErrLabAnalyteNotFound),
+		errors.Is(err, services.ErrLabRangeNotFound),
+		errors.Is(err, services.ErrLabOrderNotFound),
+		errors.Is(err, services.ErrAppointmentNotFound),
+		errors.Is(err, services.ErrPetNotFound),
+		errors.Is(err, services.ErrSpeciesNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrPetAccessDenied), errors.Is(err, services.ErrStaffOnly):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrLabOrderClosed), errors.Is(err, services.ErrLabOrderStatus):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrLabOrderNotAllowed),
+		errors.Is(err, services.ErrLabResultsEmpty),
+		errors.Is(err, services.ErrLabResultAnalyte),
+		errors.Is(err, services.ErrLabResultDuplicated),
+		errors.Is(err, services.ErrLabResultValueRequired):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
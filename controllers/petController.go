@@ -4,8 +4,10 @@ import (
 	"VetiCare/entities"
 	"VetiCare/entities/dto"
 	"VetiCare/services"
+	"VetiCare/utils"
 	"VetiCare/validators"
 	"encoding/json"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"net/http"
@@ -25,6 +27,7 @@ func (pc *PetController) RegisterRoutes(r *mux.Router, authMiddleware func(http.
 	r.Handle("/api/pets", authMiddleware(http.HandlerFunc(pc.GetAllPets))).Methods("GET")
 	r.Handle("/api/pets/active", authMiddleware(http.HandlerFunc(pc.GetActivePets))).Methods("GET")
 	r.Handle("/api/pets/owner/{owner_id}", authMiddleware(http.HandlerFunc(pc.GetPetsByOwner))).Methods("GET")
+	r.Handle("/api/pets/microchip/{number}", authMiddleware(http.HandlerFunc(pc.LookupMicrochip))).Methods("GET")
 	r.Handle("/api/pets/{id}", authMiddleware(http.HandlerFunc(pc.GetPetByID))).Methods("GET")
 	r.Handle("/api/pets/{id}", authMiddleware(http.HandlerFunc(pc.UpdatePet))).Methods("PUT")
 	r.Handle("/api/pets/{id}", authMiddleware(http.HandlerFunc(pc.DeletePet))).Methods("DELETE")
@@ -36,6 +39,13 @@ func (pc *PetController) CreatePet(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "JSON inválido", http.StatusBadRequest)
 		return
 	}
+	if petDTO.Microchip != nil {
+		number := utils.NormalizeMicrochip(*petDTO.Microchip)
+		petDTO.Microchip = &number
+		if number == "" {
+			petDTO.Microchip = nil
+		}
+	}
 	if err := validators.ValidatePetDTO(petDTO); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -46,13 +56,19 @@ func (pc *PetController) CreatePet(w http.ResponseWriter, r *http.Request) {
 		SpeciesID: petDTO.SpeciesID,
 		BirthDate: petDTO.BirthDate,
 		Breed:     petDTO.Breed,
+		BreedID:   petDTO.BreedID,
 		StatusID:  petDTO.StatusID,
+
+		Microchip:            petDTO.Microchip,
+		MicrochipImplantedAt: petDTO.MicrochipImplantedAt,
+		MicrochipLocation:    petDTO.MicrochipLocation,
 	}
-	if err := pc.Service.CreatePet(&pet); err != nil {
-		http.Error(w, "Error creando mascota: "+err.Error(), http.StatusInternalServerError)
+	requesterID := r.Header.Get("User-ID")
+	if err := pc.Service.CreatePet(requesterID, &pet); err != nil {
+		http.Error(w, "Error creando mascota: "+err.Error(), petErrorStatus(err))
 		return
 	}
-	completePet, err := pc.Service.GetPetByID(pet.ID.String())
+	completePet, err := pc.Service.GetPetByID(requesterID, pet.ID.String())
 	if err != nil {
 		http.Error(w, "Error obteniendo mascota creada: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -61,10 +77,10 @@ func (pc *PetController) CreatePet(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(dto.ToPetDTO(completePet))
 }
 
-func (pc *PetController) GetAllPets(w http.ResponseWriter, _ *http.Request) {
-	pets, err := pc.Service.GetAllPets()
+func (pc *PetController) GetAllPets(w http.ResponseWriter, r *http.Request) {
+	pets, err := pc.Service.GetAllPets(r.Header.Get("User-ID"))
 	if err != nil {
-		http.Error(w, "Error al obtener mascotas: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Error al obtener mascotas: "+err.Error(), petErrorStatus(err))
 		return
 	}
 	var dtos []dto.PetDTO
@@ -74,10 +90,10 @@ func (pc *PetController) GetAllPets(w http.ResponseWriter, _ *http.Request) {
 	json.NewEncoder(w).Encode(dtos)
 }
 
-func (pc *PetController) GetActivePets(w http.ResponseWriter, _ *http.Request) {
-	pets, err := pc.Service.GetActivePets()
+func (pc *PetController) GetActivePets(w http.ResponseWriter, r *http.Request) {
+	pets, err := pc.Service.GetActivePets(r.Header.Get("User-ID"))
 	if err != nil {
-		http.Error(w, "Error obteniendo mascotas activas: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Error obteniendo mascotas activas: "+err.Error(), petErrorStatus(err))
 		return
 	}
 	var dtos []dto.PetDTO
@@ -89,9 +105,9 @@ func (pc *PetController) GetActivePets(w http.ResponseWriter, _ *http.Request) {
 
 func (pc *PetController) GetPetsByOwner(w http.ResponseWriter, r *http.Request) {
 	ownerID := mux.Vars(r)["owner_id"]
-	pets, err := pc.Service.GetPetsByOwner(ownerID)
+	pets, err := pc.Service.GetPetsByOwner(r.Header.Get("User-ID"), ownerID)
 	if err != nil {
-		http.Error(w, "Error obteniendo mascotas por dueño: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Error obteniendo mascotas por dueño: "+err.Error(), petErrorStatus(err))
 		return
 	}
 	var dtos []dto.PetDTO
@@ -103,13 +119,9 @@ func (pc *PetController) GetPetsByOwner(w http.ResponseWriter, r *http.Request)
 
 func (pc *PetController) GetPetByID(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
-	pet, err := pc.Service.GetPetByID(id)
+	pet, err := pc.Service.GetPetByID(r.Header.Get("User-ID"), id)
 	if err != nil {
-		http.Error(w, "Error al obtener mascota: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-	if pet == nil {
-		http.Error(w, "Mascota no encontrada", http.StatusNotFound)
+		http.Error(w, "Error al obtener mascota: "+err.Error(), petErrorStatus(err))
 		return
 	}
 	json.NewEncoder(w).Encode(dto.ToPetDTO(pet))
@@ -122,6 +134,9 @@ func (pc *PetController) UpdatePet(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "JSON inválido", http.StatusBadRequest)
 		return
 	}
+	// La foto solo se cambia con su propia ruta, que genera los archivos.
+	delete(fields, "photo_version")
+	delete(fields, "photo_updated_at")
 
 	if name, ok := fields["name"].(string); ok {
 		if err := validators.ValidatePetName(name); err != nil {
@@ -151,6 +166,41 @@ func (pc *PetController) UpdatePet(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		fields["birth_date"] = birthDate
+	}
+	if microchip, ok := fields["microchip"]; ok && microchip != nil {
+		number, isString := microchip.(string)
+		if !isString {
+			http.Error(w, validators.ErrInvalidMicrochip.Error(), http.StatusBadRequest)
+			return
+		}
+		number = utils.NormalizeMicrochip(number)
+		if number == "" {
+			fields["microchip"] = nil
+		} else if err := validators.ValidateMicrochip(number); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else {
+			fields["microchip"] = number
+		}
+	}
+	if implantedStr, ok := fields["microchip_implanted_at"].(string); ok {
+		implantedAt, err := time.Parse(time.RFC3339, implantedStr)
+		if err != nil {
+			http.Error(w, "Formato de fecha de implante del microchip inválido", http.StatusBadRequest)
+			return
+		}
+		if err := validators.ValidateMicrochipImplantedAt(&implantedAt, nil); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fields["microchip_implanted_at"] = implantedAt
+	}
+	if location, ok := fields["microchip_location"].(string); ok {
+		if err := validators.ValidateMaxLen(location, 100, validators.ErrInvalidMicrochipLocation); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 	}
 	if breed, ok := fields["breed"].(string); ok {
 		if err := validators.ValidatePetBreed(&breed); err != nil {
@@ -158,6 +208,13 @@ func (pc *PetController) UpdatePet(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if breedID, ok := fields["breed_id"]; ok && breedID != nil {
+		id, isNumber := breedID.(float64)
+		if !isNumber || id <= 0 || id != float64(int(id)) {
+			http.Error(w, validators.ErrInvalidBreedID.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 	if statusID, ok := fields["status_id"].(float64); ok {
 		if err := validators.ValidatePetStatusID(int(statusID)); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
@@ -165,28 +222,55 @@ func (pc *PetController) UpdatePet(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if err := pc.Service.UpdatePet(id, fields); err != nil {
-		http.Error(w, "Error al actualizar mascota: "+err.Error(), http.StatusInternalServerError)
+	requesterID := r.Header.Get("User-ID")
+	if err := pc.Service.UpdatePet(requesterID, id, fields); err != nil {
+		http.Error(w, "Error al actualizar mascota: "+err.Error(), petErrorStatus(err))
 		return
 	}
-	pet, err := pc.Service.GetPetByID(id)
+	pet, err := pc.Service.GetPetByID(requesterID, id)
 	if err != nil {
-		http.Error(w, "Error al obtener mascota actualizada: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Error al obtener mascota actualizada: "+err.Error(), petErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToPetDTO(pet))
+}
+
+// LookupMicrochip devuelve la mascota y el contacto del dueño a partir del
+// número leído en el chip. Acepta el número con espacios o guiones.
+func (pc *PetController) LookupMicrochip(w http.ResponseWriter, r *http.Request) {
+	number := utils.NormalizeMicrochip(mux.Vars(r)["number"])
+	if err := validators.ValidateMicrochip(number); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if pet == nil {
-		http.Error(w, "Mascota no encontrada", http.StatusNotFound)
+	pet, err := pc.Service.LookupMicrochip(r.Header.Get("User-ID"), number)
+	if err != nil {
+		http.Error(w, "Error al buscar microchip: "+err.Error(), petErrorStatus(err))
 		return
 	}
-	json.NewEncoder(w).Encode(dto.ToPetDTO(pet))
+	json.NewEncoder(w).Encode(dto.ToMicrochipLookupDTO(pet))
 }
 
 func (pc *PetController) DeletePet(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
-	msg, err := pc.Service.DeletePet(id)
+	msg, err := pc.Service.DeletePet(r.Header.Get("User-ID"), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), petErrorStatus(err))
 		return
 	}
 	json.NewEncoder(w).Encode(map[string]string{"message": msg})
 }
+
+func petErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPetNotFound), errors.Is(err, services.ErrBreedNotFound), errors.Is(err, services.ErrMicrochipNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrBreedSpeciesMismatch), errors.Is(err, services.ErrBreedInactive), errors.Is(err, services.ErrMicrochipImplant):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrMicrochipInUse):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrStaffOnly), errors.Is(err, services.ErrPetAccessDenied):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,304 @@
+package controllers
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"time"
+)
+
+type PetHealthController struct {
+	Service *services.PetHealthService
+}
+
+func NewPetHealthController(service *services.PetHealthService) *PetHealthController {
+	return &PetHealthController{Service: service}
+}
+
+func (hc *PetHealthController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/pets/{id}/allergies", authMiddleware(http.HandlerFunc(hc.GetAllergies))).Methods("GET")
+	r.Handle("/api/pets/{id}/allergies", authMiddleware(http.HandlerFunc(hc.CreateAllergy))).Methods("POST")
+	r.Handle("/api/pets/{id}/allergies/{record_id}", authMiddleware(http.HandlerFunc(hc.UpdateAllergy))).Methods("PUT")
+	r.Handle("/api/pets/{id}/allergies/{record_id}", authMiddleware(http.HandlerFunc(hc.DeleteAllergy))).Methods("DELETE")
+	r.Handle("/api/pets/{id}/conditions", authMiddleware(http.HandlerFunc(hc.GetConditions))).Methods("GET")
+	r.Handle("/api/pets/{id}/conditions", authMiddleware(http.HandlerFunc(hc.CreateCondition))).Methods("POST")
+	r.Handle("/api/pets/{id}/conditions/{record_id}", authMiddleware(http.HandlerFunc(hc.UpdateCondition))).Methods("PUT")
+	r.Handle("/api/pets/{id}/conditions/{record_id}", authMiddleware(http.HandlerFunc(hc.DeleteCondition))).Methods("DELETE")
+	r.Handle("/api/pets/{id}/behavior-alerts", authMiddleware(http.HandlerFunc(hc.GetBehaviorAlerts))).Methods("GET")
+	r.Handle("/api/pets/{id}/behavior-alerts", authMiddleware(http.HandlerFunc(hc.CreateBehaviorAlert))).Methods("POST")
+	r.Handle("/api/pets/{id}/behavior-alerts/{record_id}", authMiddleware(http.HandlerFunc(hc.UpdateBehaviorAlert))).Methods("PUT")
+	r.Handle("/api/pets/{id}/behavior-alerts/{record_id}", authMiddleware(http.HandlerFunc(hc.DeleteBehaviorAlert))).Methods("DELETE")
+}
+
+// recordedBy devuelve el usuario autenticado que registra el dato, si lo hay.
+func recordedBy(r *http.Request) *uuid.UUID {
+	if parsed, err := uuid.Parse(r.Header.Get("User-ID")); err == nil {
+		return &parsed
+	}
+	return nil
+}
+
+func (hc *PetHealthController) GetAllergies(w http.ResponseWriter, r *http.Request) {
+	list, err := hc.Service.GetAllergies(r.Header.Get("User-ID"), mux.Vars(r)["id"], r.URL.Query().Get("all") != "true")
+	if err != nil {
+		http.Error(w, "Error al obtener alergias: "+err.Error(), petHealthErrorStatus(err))
+		return
+	}
+	dtos := []dto.PetAllergyDTO{}
+	for _, a := range list {
+		dtos = append(dtos, dto.ToPetAllergyDTO(&a))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (hc *PetHealthController) CreateAllergy(w http.ResponseWriter, r *http.Request) {
+	petID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, validators.ErrInvalidPetID.Error(), http.StatusBadRequest)
+		return
+	}
+	var input dto.PetAllergyDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePetAllergyDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	allergy := entities.PetAllergy{
+		PetID:        petID,
+		Agent:        input.Agent,
+		Reaction:     input.Reaction,
+		Severity:     input.Severity,
+		Notes:        input.Notes,
+		RecordedByID: recordedBy(r),
+	}
+	created, err := hc.Service.CreateAllergy(r.Header.Get("User-ID"), &allergy)
+	if err != nil {
+		http.Error(w, "Error al registrar alergia: "+err.Error(), petHealthErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToPetAllergyDTO(created))
+}
+
+func (hc *PetHealthController) UpdateAllergy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	var input dto.PetAllergyDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePetAllergyDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fields := map[string]interface{}{
+		"agent":    input.Agent,
+		"reaction": input.Reaction,
+		"severity": input.Severity,
+		"notes":    input.Notes,
+	}
+	updated, err := hc.Service.UpdateAllergy(r.Header.Get("User-ID"), vars["id"], vars["record_id"], fields)
+	if err != nil {
+		http.Error(w, "Error al actualizar alergia: "+err.Error(), petHealthErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToPetAllergyDTO(updated))
+}
+
+func (hc *PetHealthController) DeleteAllergy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	msg, err := hc.Service.DeleteAllergy(r.Header.Get("User-ID"), vars["id"], vars["record_id"])
+	if err != nil {
+		http.Error(w, "Error al cambiar estado de la alergia: "+err.Error(), petHealthErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+func (hc *PetHealthController) GetConditions(w http.ResponseWriter, r *http.Request) {
+	list, err := hc.Service.GetConditions(r.Header.Get("User-ID"), mux.Vars(r)["id"], r.URL.Query().Get("all") != "true")
+	if err != nil {
+		http.Error(w, "Error al obtener condiciones crónicas: "+err.Error(), petHealthErrorStatus(err))
+		return
+	}
+	dtos := []dto.PetConditionDTO{}
+	for _, c := range list {
+		dtos = append(dtos, dto.ToPetConditionDTO(&c))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func parseDiagnosedOn(value *string) *time.Time {
+	if value == nil || *value == "" {
+		return nil
+	}
+	t, _ := time.ParseInLocation("02-01-2006", *value, time.Local)
+	return &t
+}
+
+func (hc *PetHealthController) CreateCondition(w http.ResponseWriter, r *http.Request) {
+	petID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, validators.ErrInvalidPetID.Error(), http.StatusBadRequest)
+		return
+	}
+	var input dto.PetConditionDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePetConditionDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	condition := entities.PetCondition{
+		PetID:        petID,
+		Name:         input.Name,
+		DiagnosedOn:  parseDiagnosedOn(input.DiagnosedOn),
+		Critical:     input.Critical,
+		Notes:        input.Notes,
+		RecordedByID: recordedBy(r),
+	}
+	created, err := hc.Service.CreateCondition(r.Header.Get("User-ID"), &condition)
+	if err != nil {
+		http.Error(w, "Error al registrar condición crónica: "+err.Error(), petHealthErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToPetConditionDTO(created))
+}
+
+func (hc *PetHealthController) UpdateCondition(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	var input dto.PetConditionDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePetConditionDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fields := map[string]interface{}{
+		"name":         input.Name,
+		"diagnosed_on": parseDiagnosedOn(input.DiagnosedOn),
+		"critical":     input.Critical,
+		"notes":        input.Notes,
+	}
+	updated, err := hc.Service.UpdateCondition(r.Header.Get("User-ID"), vars["id"], vars["record_id"], fields)
+	if err != nil {
+		http.Error(w, "Error al actualizar condición crónica: "+err.Error(), petHealthErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToPetConditionDTO(updated))
+}
+
+func (hc *PetHealthController) DeleteCondition(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	msg, err := hc.Service.DeleteCondition(r.Header.Get("User-ID"), vars["id"], vars["record_id"])
+	if err != nil {
+		http.Error(w, "Error al cambiar estado de la condición crónica: "+err.Error(), petHealthErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+func (hc *PetHealthController) GetBehaviorAlerts(w http.ResponseWriter, r *http.Request) {
+	list, err := hc.Service.GetBehaviorAlerts(r.Header.Get("User-ID"), mux.Vars(r)["id"], r.URL.Query().Get("all") != "true")
+	if err != nil {
+		http.Error(w, "Error al obtener avisos de conducta: "+err.Error(), petHealthErrorStatus(err))
+		return
+	}
+	dtos := []dto.PetBehaviorAlertDTO{}
+	for _, b := range list {
+		dtos = append(dtos, dto.ToPetBehaviorAlertDTO(&b))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+// CreateBehaviorAlert registra un aviso de conducta; si no se indica critical
+// se considera crítico, porque su propósito es advertir al personal.
+func (hc *PetHealthController) CreateBehaviorAlert(w http.ResponseWriter, r *http.Request) {
+	petID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, validators.ErrInvalidPetID.Error(), http.StatusBadRequest)
+		return
+	}
+	var input dto.PetBehaviorAlertDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePetBehaviorAlertDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	alert := entities.PetBehaviorAlert{
+		PetID:        petID,
+		Description:  input.Description,
+		Critical:     input.Critical == nil || *input.Critical,
+		RecordedByID: recordedBy(r),
+	}
+	created, err := hc.Service.CreateBehaviorAlert(r.Header.Get("User-ID"), &alert)
+	if err != nil {
+		http.Error(w, "Error al registrar aviso de conducta: "+err.Error(), petHealthErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToPetBehaviorAlertDTO(created))
+}
+
+func (hc *PetHealthController) UpdateBehaviorAlert(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	var input dto.PetBehaviorAlertDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePetBehaviorAlertDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fields := map[string]interface{}{
+		"description": input.Description,
+		"critical":    input.Critical == nil || *input.Critical,
+	}
+	updated, err := hc.Service.UpdateBehaviorAlert(r.Header.Get("User-ID"), vars["id"], vars["record_id"], fields)
+	if err != nil {
+		http.Error(w, "Error al actualizar aviso de conducta: "+err.Error(), petHealthErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToPetBehaviorAlertDTO(updated))
+}
+
+func (hc *PetHealthController) DeleteBehaviorAlert(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	msg, err := hc.Service.DeleteBehaviorAlert(r.Header.Get("User-ID"), vars["id"], vars["record_id"])
+	if err != nil {
+		http.Error(w, "Error al cambiar estado del aviso de conducta: "+err.Error(), petHealthErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+func petHealthErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPetNotFound),
+		errors.Is(err, services.ErrAllergyNotFound),
+		errors.Is(err, services.ErrConditionNotFound),
+		errors.Is(err, services.ErrBehaviorAlertNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrPetAccessDenied), errors.Is(err, services.ErrStaffOnly):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
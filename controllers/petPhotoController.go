@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/utils"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type PetPhotoController struct {
+	Service *services.PetPhotoService
+}
+
+func NewPetPhotoController(service *services.PetPhotoService) *PetPhotoController {
+	return &PetPhotoController{Service: service}
+}
+
+func (pc *PetPhotoController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/pets/{id}/photo", authMiddleware(http.HandlerFunc(pc.Upload))).Methods("PUT")
+	r.Handle("/api/pets/{id}/photo", authMiddleware(http.HandlerFunc(pc.Get))).Methods("GET")
+	r.Handle("/api/pets/{id}/photo", authMiddleware(http.HandlerFunc(pc.Delete))).Methods("DELETE")
+}
+
+// Upload recibe un formulario multipart con la foto en el campo photo. El
+// tamaño máximo se configura con PET_PHOTO_MAX_MB.
+func (pc *PetPhotoController) Upload(w http.ResponseWriter, r *http.Request) {
+	maxBytes := int64(utils.GetEnvInt("PET_PHOTO_MAX_MB", 5)) << 20
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+1<<20)
+	if err := r.ParseMultipartForm(8 << 20); err != nil {
+		http.Error(w, validators.ErrPetPhotoTooLarge.Error()+" o el formulario es inválido", http.StatusRequestEntityTooLarge)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("photo")
+	if err != nil {
+		http.Error(w, validators.ErrInvalidPetPhotoFile.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	if header.Size > maxBytes {
+		http.Error(w, validators.ErrPetPhotoTooLarge.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, validators.ErrInvalidPetPhotoFile.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pet, err := pc.Service.Upload(r.Header.Get("User-ID"), mux.Vars(r)["id"], data)
+	if err != nil {
+		http.Error(w, "Error al guardar foto: "+err.Error(), petPhotoErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToPetDTO(pet))
+}
+
+// Get sirve la foto (o la miniatura con ?size=thumb). Si la URL trae la
+// versión actual en ?v, el navegador puede guardarla en caché sin
+// revalidarla: una foto nueva siempre tiene otra URL.
+func (pc *PetPhotoController) Get(w http.ResponseWriter, r *http.Request) {
+	thumbnail := r.URL.Query().Get("size") == "thumb"
+	pet, content, err := pc.Service.Open(r.Header.Get("User-ID"), mux.Vars(r)["id"], thumbnail)
+	if err != nil {
+		http.Error(w, "Error al obtener foto: "+err.Error(), petPhotoErrorStatus(err))
+		return
+	}
+	defer content.Close()
+
+	etag := `"` + pet.PhotoVersion
+	if thumbnail {
+		etag += "-thumb"
+	}
+	etag += `"`
+	w.Header().Set("ETag", etag)
+	if r.URL.Query().Get("v") == pet.PhotoVersion {
+		w.Header().Set("Cache-Control", "private, max-age=31536000, immutable")
+	} else {
+		w.Header().Set("Cache-Control", "private, no-cache")
+	}
+	if pet.PhotoUpdatedAt != nil {
+		w.Header().Set("Last-Modified", pet.PhotoUpdatedAt.UTC().Format(http.TimeFormat))
+	}
+	if strings.Contains(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	io.Copy(w, content)
+}
+
+func (pc *PetPhotoController) Delete(w http.ResponseWriter, r *http.Request) {
+	pet, err := pc.Service.Delete(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al eliminar foto: "+err.Error(), petPhotoErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToPetDTO(pet))
+}
+
+func petPhotoErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPetNotFound), errors.Is(err, services.ErrPetPhotoNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrPetAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, utils.ErrImageFormat):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, utils.ErrImageTooLarge):
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusInternalServerError
+}
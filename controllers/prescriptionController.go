@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strings"
+)
+
+type PrescriptionController struct {
+	Service *services.PrescriptionService
+}
+
+func NewPrescriptionController(service *services.PrescriptionService) *PrescriptionController {
+	return &PrescriptionController{Service: service}
+}
+
+func (pc *PrescriptionController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/appointments/{id}/prescriptions", authMiddleware(http.HandlerFunc(pc.Create))).Methods("POST")
+	r.Handle("/api/appointments/{id}/prescriptions", authMiddleware(http.HandlerFunc(pc.GetByAppointment))).Methods("GET")
+	r.Handle("/api/pets/{id}/prescriptions", authMiddleware(http.HandlerFunc(pc.GetByPet))).Methods("GET")
+	r.Handle("/api/prescriptions/{id}", authMiddleware(http.HandlerFunc(pc.GetByID))).Methods("GET")
+	r.Handle("/api/prescriptions/{id}/void", authMiddleware(http.HandlerFunc(pc.Void))).Methods("PATCH")
+}
+
+// Create emite la receta firmada por el veterinario autenticado.
+func (pc *PrescriptionController) Create(w http.ResponseWriter, r *http.Request) {
+	appointmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de cita inválido", http.StatusBadRequest)
+		return
+	}
+	vetID, err := uuid.Parse(r.Header.Get("User-ID"))
+	if err != nil {
+		http.Error(w, services.ErrPrescriptionSignerInvalid.Error(), http.StatusForbidden)
+		return
+	}
+	var input dto.PrescriptionInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePrescriptionInputDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	prescription := entities.Prescription{
+		AppointmentID: appointmentID,
+		VetID:         vetID,
+		Notes:         input.Notes,
+	}
+	for _, item := range input.Items {
+		item.Route = strings.ToLower(strings.TrimSpace(item.Route))
+		prescription.Items = append(prescription.Items, dto.ToPrescriptionItem(item))
+	}
+
+	created, err := pc.Service.Create(&prescription)
+	if err != nil {
+		http.Error(w, "Error al emitir receta: "+err.Error(), prescriptionErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToPrescriptionDTO(created))
+}
+
+func (pc *PrescriptionController) GetByAppointment(w http.ResponseWriter, r *http.Request) {
+	list, err := pc.Service.GetByAppointmentID(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener recetas: "+err.Error(), prescriptionErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToPrescriptionDTOs(list))
+}
+
+func (pc *PrescriptionController) GetByPet(w http.ResponseWriter, r *http.Request) {
+	list, err := pc.Service.GetByPetID(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener recetas: "+err.Error(), prescriptionErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToPrescriptionDTOs(list))
+}
+
+func (pc *PrescriptionController) GetByID(w http.ResponseWriter, r *http.Request) {
+	p, err := pc.Service.GetByID(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener receta: "+err.Error(), prescriptionErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToPrescriptionDTO(p))
+}
+
+func (pc *PrescriptionController) Void(w http.ResponseWriter, r *http.Request) {
+	vetID, err := uuid.Parse(r.Header.Get("User-ID"))
+	if err != nil {
+		http.Error(w, services.ErrPrescriptionSignerInvalid.Error(), http.StatusForbidden)
+		return
+	}
+	var input dto.VoidPrescriptionDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateVoidPrescriptionDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	p, err := pc.Service.Void(mux.Vars(r)["id"], vetID, input.Reason)
+	if err != nil {
+		http.Error(w, "Error al anular receta: "+err.Error(), prescriptionErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToPrescriptionDTO(p))
+}
+
+func prescriptionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrAppointmentNotFound), errors.Is(err, services.ErrPrescriptionNotFound), errors.Is(err, services.ErrPetNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrPrescriptionSignerInvalid), errors.Is(err, services.ErrPetAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrPrescriptionVoided), errors.Is(err, services.ErrPrescriptionNotAllowed):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
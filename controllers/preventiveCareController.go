@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/utils"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type PreventiveCareController struct {
+	Service *services.PreventiveCareService
+}
+
+func NewPreventiveCareController(service *services.PreventiveCareService) *PreventiveCareController {
+	return &PreventiveCareController{Service: service}
+}
+
+func (pc *PreventiveCareController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/pets/{id}/dewormings", authMiddleware(http.HandlerFunc(pc.GetDewormings))).Methods("GET")
+	r.Handle("/api/pets/{id}/dewormings", authMiddleware(http.HandlerFunc(pc.RecordDeworming))).Methods("POST")
+	r.Handle("/api/species/{id}/protocols", authMiddleware(http.HandlerFunc(pc.GetProtocols))).Methods("GET")
+	r.Handle("/api/reminders/worklist", authMiddleware(http.HandlerFunc(pc.GetWorklist))).Methods("GET")
+}
+
+func (pc *PreventiveCareController) RegisterAdminRoutes(r *mux.Router, adminMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/species/{id}/protocols", adminMiddleware(http.HandlerFunc(pc.CreateProtocol))).Methods("POST")
+	r.Handle("/api/protocols/{id}", adminMiddleware(http.HandlerFunc(pc.UpdateProtocol))).Methods("PUT")
+	r.Handle("/api/protocols/{id}", adminMiddleware(http.HandlerFunc(pc.DeleteProtocol))).Methods("DELETE")
+	r.Handle("/api/reminders/run", adminMiddleware(http.HandlerFunc(pc.RunReminders))).Methods("POST")
+}
+
+func (pc *PreventiveCareController) GetDewormings(w http.ResponseWriter, r *http.Request) {
+	list, err := pc.Service.GetDewormingsByPet(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener desparasitaciones: "+err.Error(), preventiveCareErrorStatus(err))
+		return
+	}
+	dtos := []dto.DewormingDTO{}
+	for _, d := range list {
+		dtos = append(dtos, dto.ToDewormingDTO(&d))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (pc *PreventiveCareController) RecordDeworming(w http.ResponseWriter, r *http.Request) {
+	petID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, validators.ErrInvalidPetID.Error(), http.StatusBadRequest)
+		return
+	}
+	var input dto.DewormingInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateDewormingInputDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	given, _ := time.ParseInLocation("02-01-2006", input.DateGiven, time.Local)
+	deworming := entities.Deworming{
+		PetID:     petID,
+		Product:   input.Product,
+		Dose:      input.Dose,
+		WeightKg:  input.WeightKg,
+		DateGiven: given,
+		Notes:     input.Notes,
+	}
+	if input.NextDueDate != "" {
+		next, _ := time.ParseInLocation("02-01-2006", input.NextDueDate, time.Local)
+		deworming.NextDueDate = &next
+	}
+	if input.AppointmentID != nil && *input.AppointmentID != "" {
+		id := uuid.MustParse(*input.AppointmentID)
+		deworming.AppointmentID = &id
+	}
+	if input.AdministeredByID != nil && *input.AdministeredByID != "" {
+		id := uuid.MustParse(*input.AdministeredByID)
+		deworming.AdministeredByID = &id
+	}
+
+	created, err := pc.Service.RecordDeworming(r.Header.Get("User-ID"), &deworming)
+	if err != nil {
+		http.Error(w, "Error al registrar desparasitación: "+err.Error(), preventiveCareErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToDewormingDTO(created))
+}
+
+func (pc *PreventiveCareController) GetProtocols(w http.ResponseWriter, r *http.Request) {
+	speciesID, _ := strconv.Atoi(mux.Vars(r)["id"])
+	list, err := pc.Service.GetProtocols(&speciesID, r.URL.Query().Get("all") != "true")
+	if err != nil {
+		http.Error(w, "Error al obtener protocolos: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dtos := []dto.PreventiveProtocolDTO{}
+	for _, p := range list {
+		dtos = append(dtos, dto.ToPreventiveProtocolDTO(&p))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (pc *PreventiveCareController) CreateProtocol(w http.ResponseWriter, r *http.Request) {
+	speciesID, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var input dto.PreventiveProtocolDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePreventiveProtocolDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	protocol := entities.PreventiveProtocol{
+		SpeciesID:    speciesID,
+		Name:         input.Name,
+		CareType:     input.CareType,
+		VaccineID:    input.VaccineID,
+		MinAgeWeeks:  input.MinAgeWeeks,
+		MaxAgeWeeks:  input.MaxAgeWeeks,
+		IntervalDays: input.IntervalDays,
+		StatusID:     1,
+	}
+	created, err := pc.Service.CreateProtocol(&protocol)
+	if err != nil {
+		http.Error(w, "Error al crear protocolo: "+err.Error(), preventiveCareErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToPreventiveProtocolDTO(created))
+}
+
+func (pc *PreventiveCareController) UpdateProtocol(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var fields map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	delete(fields, "id")
+	delete(fields, "species_id")
+	if name, ok := fields["name"].(string); ok && (name == "" || len(name) > 100) {
+		http.Error(w, validators.ErrInvalidProtocolName.Error(), http.StatusBadRequest)
+		return
+	}
+	if interval, ok := fields["interval_days"].(float64); ok && interval <= 0 {
+		http.Error(w, validators.ErrInvalidProtocolInterval.Error(), http.StatusBadRequest)
+		return
+	}
+	if minAge, ok := fields["min_age_weeks"].(float64); ok && minAge < 0 {
+		http.Error(w, validators.ErrInvalidProtocolAges.Error(), http.StatusBadRequest)
+		return
+	}
+	updated, err := pc.Service.UpdateProtocol(id, fields)
+	if err != nil {
+		http.Error(w, "Error al actualizar protocolo: "+err.Error(), preventiveCareErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToPreventiveProtocolDTO(updated))
+}
+
+func (pc *PreventiveCareController) DeleteProtocol(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	msg, err := pc.Service.DeleteProtocol(id)
+	if err != nil {
+		http.Error(w, "Error al cambiar estado del protocolo: "+err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+// GetWorklist devuelve al personal los cuidados preventivos que vencen en los
+// próximos días (days, por defecto REMINDER_WINDOW_DAYS) junto con el último
+// recordatorio enviado a cada dueño.
+func (pc *PreventiveCareController) GetWorklist(w http.ResponseWriter, r *http.Request) {
+	days := utils.GetEnvInt("REMINDER_WINDOW_DAYS", 14)
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > 365 {
+			http.Error(w, "days debe ser un número entre 0 y 365", http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+	now := time.Now()
+	items, err := pc.Service.GetWorklist(r.Header.Get("User-ID"), now, days)
+	if err != nil {
+		http.Error(w, "Error al obtener cuidados pendientes: "+err.Error(), preventiveCareErrorStatus(err))
+		return
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	json.NewEncoder(w).Encode(dto.ToCareDueItemDTOs(items, today))
+}
+
+func (pc *PreventiveCareController) RunReminders(w http.ResponseWriter, _ *http.Request) {
+	count, err := pc.Service.SendDueReminders(time.Now())
+	if err != nil {
+		http.Error(w, "Error al enviar recordatorios: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]int{"reminders_sent": count})
+}
+
+func preventiveCareErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPetNotFound), errors.Is(err, services.ErrProtocolNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidProtocolType),
+		errors.Is(err, services.ErrProtocolNeedsVaccine),
+		errors.Is(err, services.ErrDewormingDateFuture),
+		errors.Is(err, services.ErrNextDueBeforeGiven),
+		errors.Is(err, services.ErrAdministeringVetInvalid):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrPetAccessDenied), errors.Is(err, services.ErrStaffOnly):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type RecordAmendmentController struct {
+	Service *services.RecordAmendmentService
+}
+
+func NewRecordAmendmentController(service *services.RecordAmendmentService) *RecordAmendmentController {
+	return &RecordAmendmentController{Service: service}
+}
+
+func (ac *RecordAmendmentController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/appointments/{id}/amendments", authMiddleware(http.HandlerFunc(ac.Amend))).Methods("POST")
+	r.Handle("/api/appointments/{id}/versions", authMiddleware(http.HandlerFunc(ac.GetVersions))).Methods("GET")
+	r.Handle("/api/appointments/{id}/versions/{version}", authMiddleware(http.HandlerFunc(ac.GetVersion))).Methods("GET")
+}
+
+// Amend corrige los datos clínicos de una cita finalizada. Solo se envían los
+// campos que cambian junto con amendment_reason.
+func (ac *RecordAmendmentController) Amend(w http.ResponseWriter, r *http.Request) {
+	var input dto.AppointmentAmendmentInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateAppointmentAmendmentInputDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, err := ac.Service.Amend(r.Header.Get("User-ID"), mux.Vars(r)["id"], strings.TrimSpace(input.AmendmentReason), input.Changes())
+	if err != nil {
+		http.Error(w, "Error al enmendar cita: "+err.Error(), recordAmendmentErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToAppointmentRecordVersionDTO(version))
+}
+
+func (ac *RecordAmendmentController) GetVersions(w http.ResponseWriter, r *http.Request) {
+	versions, err := ac.Service.GetVersions(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener versiones: "+err.Error(), recordAmendmentErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToAppointmentRecordVersionDTOs(versions))
+}
+
+func (ac *RecordAmendmentController) GetVersion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	number, err := strconv.Atoi(vars["version"])
+	if err != nil || number < 1 {
+		http.Error(w, "Versión inválida", http.StatusBadRequest)
+		return
+	}
+	version, err := ac.Service.GetVersion(r.Header.Get("User-ID"), vars["id"], number)
+	if err != nil {
+		http.Error(w, "Error al obtener versión: "+err.Error(), recordAmendmentErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToAppointmentRecordVersionDTO(version))
+}
+
+func recordAmendmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrAppointmentNotFound), errors.Is(err, services.ErrRecordVersionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrPetAccessDenied), errors.Is(err, services.ErrStaffOnly):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrAmendmentConflict):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrAmendmentNotFinished),
+		errors.Is(err, services.ErrAmendmentNoChanges),
+		errors.Is(err, services.ErrAmendmentNoClinicalNote),
+		errors.Is(err, services.ErrImplausibleVital):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
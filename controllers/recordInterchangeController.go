@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// maxRecordImportBytes limita el tamaño del Bundle que se acepta al importar.
+const maxRecordImportBytes = 20 << 20
+
+type RecordInterchangeController struct {
+	Service *services.RecordInterchangeService
+}
+
+func NewRecordInterchangeController(service *services.RecordInterchangeService) *RecordInterchangeController {
+	return &RecordInterchangeController{Service: service}
+}
+
+func (rc *RecordInterchangeController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/pets/import", authMiddleware(http.HandlerFunc(rc.Import))).Methods("POST")
+	r.Handle("/api/pets/{id}/record.json", authMiddleware(http.HandlerFunc(rc.Export))).Methods("GET")
+}
+
+func (rc *RecordInterchangeController) Export(w http.ResponseWriter, r *http.Request) {
+	export, err := rc.Service.Export(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al exportar historial: "+err.Error(), recordInterchangeErrorStatus(err))
+		return
+	}
+	now := time.Now()
+	fileName := "historial-" + export.Pet.Name + "-" + now.Format("2006-01-02") + ".json"
+	w.Header().Set("Content-Type", "application/fhir+json")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	w.Header().Set("Cache-Control", "private, no-store")
+	json.NewEncoder(w).Encode(dto.ToFHIRBundle(export, now))
+}
+
+// Import recibe el Bundle en el cuerpo. Con ?dry_run=true se valida y se
+// informa qué se crearía y qué se omitiría sin guardar nada.
+func (rc *RecordInterchangeController) Import(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRecordImportBytes)
+	var bundle dto.FHIRBundle
+	if err := json.NewDecoder(r.Body).Decode(&bundle); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	record, err := dto.ParseFHIRBundle(bundle)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateFHIRRecord(record); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	result, err := rc.Service.Import(r.Header.Get("User-ID"), record, dryRun)
+	if err != nil {
+		http.Error(w, "Error al importar historial: "+err.Error(), recordInterchangeErrorStatus(err))
+		return
+	}
+	if !dryRun {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(dto.ToRecordImportResultDTO(result))
+}
+
+func recordInterchangeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPetNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrStaffOnly):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrImportOwnerEmailUsed), errors.Is(err, services.ErrImportMicrochipUsed):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrImportSpeciesUnknown), errors.Is(err, services.ErrImportOwnerEmail):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
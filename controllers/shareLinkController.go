@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/utils"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultShareLinkHours = 72
+
+type ShareLinkController struct {
+	Service *services.ShareLinkService
+}
+
+func NewShareLinkController(service *services.ShareLinkService) *ShareLinkController {
+	return &ShareLinkController{Service: service}
+}
+
+func (sc *ShareLinkController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/pets/{id}/share-links", authMiddleware(http.HandlerFunc(sc.CreateLink))).Methods("POST")
+	r.Handle("/api/pets/{id}/share-links", authMiddleware(http.HandlerFunc(sc.GetLinksByPet))).Methods("GET")
+	r.Handle("/api/share-links/{id}", authMiddleware(http.HandlerFunc(sc.RevokeLink))).Methods("DELETE")
+	r.Handle("/api/share-links/{id}/accesses", authMiddleware(http.HandlerFunc(sc.GetAccessLog))).Methods("GET")
+}
+
+// RegisterPublicRoutes expone la vista del enlace compartido, que se abre sin
+// cuenta. El PIN viaja en la cabecera X-Share-PIN para que no quede en los
+// registros de URL.
+func (sc *ShareLinkController) RegisterPublicRoutes(r *mux.Router) {
+	r.HandleFunc("/api/shared/{token}", sc.OpenSharedRecord).Methods("GET")
+}
+
+// CreateLink genera el enlace. Si no se indica expires_in_hours vence a las
+// 72 horas.
+func (sc *ShareLinkController) CreateLink(w http.ResponseWriter, r *http.Request) {
+	petID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(petID); err != nil {
+		http.Error(w, "ID de mascota inválido", http.StatusBadRequest)
+		return
+	}
+	var input dto.ShareLinkInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	input.Scope = strings.ToLower(strings.TrimSpace(input.Scope))
+	input.Recipient = strings.TrimSpace(input.Recipient)
+	if err := validators.ValidateShareLinkInputDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	hours := input.ExpiresInHours
+	if hours == 0 {
+		hours = defaultShareLinkHours
+	}
+	link := entities.ShareLink{
+		Scope:     input.Scope,
+		Recipient: input.Recipient,
+		ExpiresAt: time.Now().Add(time.Duration(hours) * time.Hour),
+	}
+	created, err := sc.Service.CreateLink(r.Header.Get("User-ID"), petID, &link, input.Pin)
+	if err != nil {
+		http.Error(w, "Error al crear enlace compartido: "+err.Error(), shareLinkErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToShareLinkDTO(created))
+}
+
+func (sc *ShareLinkController) GetLinksByPet(w http.ResponseWriter, r *http.Request) {
+	list, err := sc.Service.GetLinksByPet(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener enlaces compartidos: "+err.Error(), shareLinkErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToShareLinkDTOs(list))
+}
+
+func (sc *ShareLinkController) RevokeLink(w http.ResponseWriter, r *http.Request) {
+	link, err := sc.Service.RevokeLink(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al revocar enlace compartido: "+err.Error(), shareLinkErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToShareLinkDTO(link))
+}
+
+func (sc *ShareLinkController) GetAccessLog(w http.ResponseWriter, r *http.Request) {
+	list, err := sc.Service.GetAccessLog(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener accesos: "+err.Error(), shareLinkErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToShareLinkAccessDTOs(list))
+}
+
+func (sc *ShareLinkController) OpenSharedRecord(w http.ResponseWriter, r *http.Request) {
+	record, err := sc.Service.OpenSharedRecord(
+		mux.Vars(r)["token"],
+		strings.TrimSpace(r.Header.Get("X-Share-PIN")),
+		clientIP(r),
+		r.UserAgent(),
+	)
+	if err != nil {
+		http.Error(w, "Error al abrir enlace compartido: "+err.Error(), shareLinkErrorStatus(err))
+		return
+	}
+	w.Header().Set("Cache-Control", "private, no-store")
+	json.NewEncoder(w).Encode(dto.ToSharedRecordDTO(record))
+}
+
+// clientIP devuelve la dirección de la conexión. Solo si esa dirección es uno
+// de los proxies de TRUSTED_PROXIES se toma X-Forwarded-For, recorriéndolo
+// desde el final hasta la primera dirección que no sea un proxy de confianza;
+// el cliente puede escribir lo que quiera al inicio de la cabecera.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	trusted := utils.TrustedProxies()
+	if !trusted[host] {
+		return host
+	}
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !trusted[hop] {
+			return hop
+		}
+		host = hop
+	}
+	return host
+}
+
+func shareLinkErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrShareLinkNotFound), errors.Is(err, services.ErrPetNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrShareLinkExpired):
+		return http.StatusGone
+	case errors.Is(err, services.ErrShareLinkPinRequired), errors.Is(err, services.ErrShareLinkPinInvalid):
+		return http.StatusUnauthorized
+	case errors.Is(err, services.ErrShareLinkLocked),
+		errors.Is(err, services.ErrPetAccessDenied),
+		errors.Is(err, services.ErrStaffOnly):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
@@ -2,8 +2,11 @@ package controllers
 
 import (
 	"VetiCare/entities"
+	"VetiCare/entities/dto"
 	"VetiCare/services"
+	"VetiCare/validators"
 	"encoding/json"
+	"errors"
 	"github.com/gorilla/mux"
 	"net/http"
 	"strconv"
@@ -23,6 +26,18 @@ func (sc *SpeciesController) RegisterRoutes(r *mux.Router, mw func(http.Handler)
 	r.Handle("/api/species", mw(http.HandlerFunc(sc.Create))).Methods("POST")
 	r.Handle("/api/species/{id}", mw(http.HandlerFunc(sc.Update))).Methods("PUT")
 	r.Handle("/api/species/{id}", mw(http.HandlerFunc(sc.Delete))).Methods("DELETE")
+	r.Handle("/api/species/{id}/reference-ranges", mw(http.HandlerFunc(sc.GetReferenceRanges))).Methods("GET")
+	r.Handle("/api/species/{id}/breeds", mw(http.HandlerFunc(sc.GetBreeds))).Methods("GET")
+}
+
+func (sc *SpeciesController) RegisterAdminRoutes(r *mux.Router, adminMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/species/{id}/reference-ranges", adminMiddleware(http.HandlerFunc(sc.CreateReferenceRange))).Methods("POST")
+	r.Handle("/api/reference-ranges/{id}", adminMiddleware(http.HandlerFunc(sc.UpdateReferenceRange))).Methods("PUT")
+	r.Handle("/api/reference-ranges/{id}", adminMiddleware(http.HandlerFunc(sc.DeleteReferenceRange))).Methods("DELETE")
+	r.Handle("/api/species/{id}/breeds", adminMiddleware(http.HandlerFunc(sc.CreateBreed))).Methods("POST")
+	r.Handle("/api/breeds/migrate-legacy", adminMiddleware(http.HandlerFunc(sc.MigrateLegacyBreeds))).Methods("POST")
+	r.Handle("/api/breeds/{id}", adminMiddleware(http.HandlerFunc(sc.UpdateBreed))).Methods("PUT")
+	r.Handle("/api/breeds/{id}", adminMiddleware(http.HandlerFunc(sc.DeleteBreed))).Methods("DELETE")
 }
 
 func (sc *SpeciesController) GetAll(w http.ResponseWriter, r *http.Request) {
@@ -83,3 +98,192 @@ func (sc *SpeciesController) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 	json.NewEncoder(w).Encode(map[string]string{"message": "Especie eliminada correctamente"})
 }
+
+func (sc *SpeciesController) GetReferenceRanges(w http.ResponseWriter, r *http.Request) {
+	speciesID, _ := strconv.Atoi(mux.Vars(r)["id"])
+	list, err := sc.Service.GetReferenceRanges(speciesID, r.URL.Query().Get("all") != "true")
+	if err != nil {
+		http.Error(w, "Error al obtener rangos de referencia: "+err.Error(), speciesErrorStatus(err))
+		return
+	}
+	dtos := []dto.VitalReferenceRangeDTO{}
+	for _, vr := range list {
+		dtos = append(dtos, dto.ToVitalReferenceRangeDTO(&vr))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (sc *SpeciesController) CreateReferenceRange(w http.ResponseWriter, r *http.Request) {
+	speciesID, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var input dto.VitalReferenceRangeDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateVitalReferenceRangeDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	vr := entities.VitalReferenceRange{
+		SpeciesID:    speciesID,
+		Vital:        input.Vital,
+		LifeStage:    input.LifeStage,
+		MinAgeWeeks:  input.MinAgeWeeks,
+		MaxAgeWeeks:  input.MaxAgeWeeks,
+		Min:          input.Min,
+		Max:          input.Max,
+		PlausibleMin: input.PlausibleMin,
+		PlausibleMax: input.PlausibleMax,
+		StatusID:     1,
+	}
+	created, err := sc.Service.CreateReferenceRange(&vr)
+	if err != nil {
+		http.Error(w, "Error al crear rango de referencia: "+err.Error(), speciesErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToVitalReferenceRangeDTO(created))
+}
+
+func (sc *SpeciesController) UpdateReferenceRange(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var fields map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	delete(fields, "id")
+	delete(fields, "species_id")
+	if stage, ok := fields["life_stage"].(string); ok && len(stage) > 50 {
+		http.Error(w, validators.ErrInvalidLifeStage.Error(), http.StatusBadRequest)
+		return
+	}
+	if minAge, ok := fields["min_age_weeks"].(float64); ok && minAge < 0 {
+		http.Error(w, validators.ErrInvalidRangeAges.Error(), http.StatusBadRequest)
+		return
+	}
+	updated, err := sc.Service.UpdateReferenceRange(id, fields)
+	if err != nil {
+		http.Error(w, "Error al actualizar rango de referencia: "+err.Error(), speciesErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToVitalReferenceRangeDTO(updated))
+}
+
+func (sc *SpeciesController) DeleteReferenceRange(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	msg, err := sc.Service.DeleteReferenceRange(id)
+	if err != nil {
+		http.Error(w, "Error al cambiar estado del rango de referencia: "+err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+func (sc *SpeciesController) GetBreeds(w http.ResponseWriter, r *http.Request) {
+	speciesID, _ := strconv.Atoi(mux.Vars(r)["id"])
+	list, err := sc.Service.GetBreeds(speciesID, r.URL.Query().Get("all") != "true")
+	if err != nil {
+		http.Error(w, "Error al obtener razas: "+err.Error(), speciesErrorStatus(err))
+		return
+	}
+	dtos := []dto.BreedDTO{}
+	for _, b := range list {
+		dtos = append(dtos, dto.ToBreedDTO(&b))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (sc *SpeciesController) CreateBreed(w http.ResponseWriter, r *http.Request) {
+	speciesID, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var input dto.BreedDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateBreedDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	breed := entities.Breed{
+		SpeciesID: speciesID,
+		Name:      input.Name,
+		Aliases:   input.Aliases,
+		IsMixed:   input.IsMixed,
+		StatusID:  1,
+	}
+	created, err := sc.Service.CreateBreed(&breed)
+	if err != nil {
+		if status := speciesErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, "Error al crear raza: "+err.Error(), status)
+			return
+		}
+		http.Error(w, "Error al crear raza, verifique que el nombre no esté en uso en la especie", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToBreedDTO(created))
+}
+
+func (sc *SpeciesController) UpdateBreed(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var fields map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	delete(fields, "id")
+	delete(fields, "species_id")
+	if name, ok := fields["name"].(string); ok {
+		if err := validators.ValidateBreedName(name); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if aliases, ok := fields["aliases"].(string); ok {
+		if err := validators.ValidateMaxLen(aliases, 300, validators.ErrInvalidBreedAliases); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	updated, err := sc.Service.UpdateBreed(id, fields)
+	if err != nil {
+		http.Error(w, "Error al actualizar raza: "+err.Error(), speciesErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToBreedDTO(updated))
+}
+
+func (sc *SpeciesController) DeleteBreed(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	msg, err := sc.Service.DeleteBreed(id)
+	if err != nil {
+		http.Error(w, "Error al cambiar estado de la raza: "+err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+// MigrateLegacyBreeds asocia la raza escrita como texto libre con el
+// catálogo. Con ?dry_run=true solo informa lo que haría.
+func (sc *SpeciesController) MigrateLegacyBreeds(w http.ResponseWriter, r *http.Request) {
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	result, err := sc.Service.MigrateLegacyBreeds(dryRun)
+	if err != nil {
+		http.Error(w, "Error al migrar razas: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToLegacyBreedResultDTO(result, dryRun))
+}
+
+func speciesErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrSpeciesNotFound), errors.Is(err, services.ErrReferenceRangeNotFound), errors.Is(err, services.ErrBreedNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidVital), errors.Is(err, services.ErrInvalidReferenceRange):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrMixedBreedExists):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,279 @@
+package controllers
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TreatmentPlanController struct {
+	Service *services.TreatmentPlanService
+}
+
+func NewTreatmentPlanController(service *services.TreatmentPlanService) *TreatmentPlanController {
+	return &TreatmentPlanController{Service: service}
+}
+
+func (tc *TreatmentPlanController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/pets/{id}/treatment-plans", authMiddleware(http.HandlerFunc(tc.CreatePlan))).Methods("POST")
+	r.Handle("/api/pets/{id}/treatment-plans", authMiddleware(http.HandlerFunc(tc.GetPlansByPet))).Methods("GET")
+	r.Handle("/api/treatment-plans/overdue", authMiddleware(http.HandlerFunc(tc.GetOverdueSteps))).Methods("GET")
+	r.Handle("/api/treatment-plans/{id}", authMiddleware(http.HandlerFunc(tc.GetPlanByID))).Methods("GET")
+	r.Handle("/api/treatment-plans/{id}", authMiddleware(http.HandlerFunc(tc.UpdatePlan))).Methods("PUT")
+	r.Handle("/api/treatment-plans/{id}", authMiddleware(http.HandlerFunc(tc.CancelPlan))).Methods("DELETE")
+	r.Handle("/api/treatment-plans/{id}/steps", authMiddleware(http.HandlerFunc(tc.AddStep))).Methods("POST")
+	r.Handle("/api/treatment-plans/{id}/steps/{step_id}/skip", authMiddleware(http.HandlerFunc(tc.SkipStep))).Methods("PATCH")
+	r.Handle("/api/treatment-plans/{id}/steps/{step_id}/appointment", authMiddleware(http.HandlerFunc(tc.LinkAppointment))).Methods("PUT")
+	r.Handle("/api/treatment-plans/{id}/follow-ups", authMiddleware(http.HandlerFunc(tc.SuggestFollowUps))).Methods("GET")
+	r.Handle("/api/treatment-plans/{id}/follow-ups", authMiddleware(http.HandlerFunc(tc.ScheduleFollowUps))).Methods("POST")
+}
+
+func toTreatmentPlanStep(in dto.TreatmentPlanStepInputDTO) entities.TreatmentPlanStep {
+	return entities.TreatmentPlanStep{
+		Procedure:     strings.TrimSpace(in.Procedure),
+		Notes:         strings.TrimSpace(in.Notes),
+		IntervalDays:  in.IntervalDays,
+		EstimatedCost: in.EstimatedCost,
+	}
+}
+
+func parseOptionalVetID(id *string) *uuid.UUID {
+	if id == nil || *id == "" {
+		return nil
+	}
+	vetID := uuid.MustParse(*id)
+	return &vetID
+}
+
+// CreatePlan registra el plan con sus pasos. Si no se indica start_date el
+// plan empieza hoy.
+func (tc *TreatmentPlanController) CreatePlan(w http.ResponseWriter, r *http.Request) {
+	petID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "ID de mascota inválido", http.StatusBadRequest)
+		return
+	}
+	var input dto.TreatmentPlanInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	input.Title = strings.TrimSpace(input.Title)
+	if err := validators.ValidateTreatmentPlanInputDTO(input, true); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if input.StartDate != "" {
+		start, _ = time.ParseInLocation("02-01-2006", input.StartDate, time.Local)
+	}
+	plan := entities.TreatmentPlan{
+		PetID:       petID,
+		VetID:       parseOptionalVetID(input.VetID),
+		ClinicID:    input.ClinicID,
+		Title:       input.Title,
+		Description: strings.TrimSpace(input.Description),
+		StartDate:   start,
+	}
+	for _, s := range input.Steps {
+		plan.Steps = append(plan.Steps, toTreatmentPlanStep(s))
+	}
+	created, err := tc.Service.CreatePlan(r.Header.Get("User-ID"), &plan)
+	if err != nil {
+		http.Error(w, "Error al crear plan de tratamiento: "+err.Error(), treatmentPlanErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToTreatmentPlanDTO(created))
+}
+
+func (tc *TreatmentPlanController) GetPlansByPet(w http.ResponseWriter, r *http.Request) {
+	var statusID *int
+	if v := r.URL.Query().Get("status_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "status_id inválido", http.StatusBadRequest)
+			return
+		}
+		statusID = &id
+	}
+	list, err := tc.Service.GetPlansByPetID(r.Header.Get("User-ID"), mux.Vars(r)["id"], statusID)
+	if err != nil {
+		http.Error(w, "Error al obtener planes de tratamiento: "+err.Error(), treatmentPlanErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToTreatmentPlanDTOs(list))
+}
+
+func (tc *TreatmentPlanController) GetPlanByID(w http.ResponseWriter, r *http.Request) {
+	plan, err := tc.Service.GetPlanByID(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener plan de tratamiento: "+err.Error(), treatmentPlanErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToTreatmentPlanDTO(plan))
+}
+
+// UpdatePlan cambia el título, la descripción y el veterinario responsable.
+// Los pasos se agregan u omiten con sus propias rutas.
+func (tc *TreatmentPlanController) UpdatePlan(w http.ResponseWriter, r *http.Request) {
+	var input dto.TreatmentPlanInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	input.Title = strings.TrimSpace(input.Title)
+	if err := validators.ValidateTreatmentPlanInputDTO(input, false); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fields := map[string]interface{}{
+		"title":       input.Title,
+		"description": strings.TrimSpace(input.Description),
+		"clinic_id":   input.ClinicID,
+	}
+	plan, err := tc.Service.UpdatePlan(r.Header.Get("User-ID"), mux.Vars(r)["id"], fields, parseOptionalVetID(input.VetID))
+	if err != nil {
+		http.Error(w, "Error al actualizar plan de tratamiento: "+err.Error(), treatmentPlanErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToTreatmentPlanDTO(plan))
+}
+
+func (tc *TreatmentPlanController) CancelPlan(w http.ResponseWriter, r *http.Request) {
+	plan, err := tc.Service.CancelPlan(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al cancelar plan de tratamiento: "+err.Error(), treatmentPlanErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToTreatmentPlanDTO(plan))
+}
+
+func (tc *TreatmentPlanController) AddStep(w http.ResponseWriter, r *http.Request) {
+	var input dto.TreatmentPlanStepInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	input.Procedure = strings.TrimSpace(input.Procedure)
+	if err := validators.ValidateTreatmentPlanStepInputDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	step := toTreatmentPlanStep(input)
+	plan, err := tc.Service.AddStep(r.Header.Get("User-ID"), mux.Vars(r)["id"], &step)
+	if err != nil {
+		http.Error(w, "Error al agregar paso al plan: "+err.Error(), treatmentPlanErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToTreatmentPlanDTO(plan))
+}
+
+func (tc *TreatmentPlanController) SkipStep(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	plan, err := tc.Service.SkipStep(r.Header.Get("User-ID"), vars["id"], vars["step_id"])
+	if err != nil {
+		http.Error(w, "Error al omitir paso del plan: "+err.Error(), treatmentPlanErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToTreatmentPlanDTO(plan))
+}
+
+func (tc *TreatmentPlanController) LinkAppointment(w http.ResponseWriter, r *http.Request) {
+	var input dto.TreatmentStepAppointmentInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateUUIDRequired(input.AppointmentID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	vars := mux.Vars(r)
+	plan, err := tc.Service.LinkAppointment(r.Header.Get("User-ID"), vars["id"], vars["step_id"], input.AppointmentID)
+	if err != nil {
+		http.Error(w, "Error al vincular cita al paso: "+err.Error(), treatmentPlanErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToTreatmentPlanDTO(plan))
+}
+
+// SuggestFollowUps muestra, sin agendar nada, el turno propuesto para cada
+// paso pendiente. Admite step_id (uno o varios separados por coma).
+func (tc *TreatmentPlanController) SuggestFollowUps(w http.ResponseWriter, r *http.Request) {
+	var stepIDs []string
+	if v := r.URL.Query().Get("step_id"); v != "" {
+		stepIDs = strings.Split(v, ",")
+	}
+	proposals, _, err := tc.Service.SuggestFollowUps(r.Header.Get("User-ID"), mux.Vars(r)["id"], stepIDs)
+	if err != nil {
+		http.Error(w, "Error al sugerir citas de seguimiento: "+err.Error(), treatmentPlanErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToTreatmentFollowUpDTOs(proposals))
+}
+
+// ScheduleFollowUps agenda las citas sugeridas. El cuerpo es opcional y
+// puede limitar los pasos con step_ids.
+func (tc *TreatmentPlanController) ScheduleFollowUps(w http.ResponseWriter, r *http.Request) {
+	var input dto.TreatmentFollowUpInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	results, err := tc.Service.ScheduleFollowUps(r.Header.Get("User-ID"), mux.Vars(r)["id"], input.StepIDs)
+	if err != nil {
+		http.Error(w, "Error al agendar citas de seguimiento: "+err.Error(), treatmentPlanErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToTreatmentFollowUpDTOs(results))
+}
+
+func (tc *TreatmentPlanController) GetOverdueSteps(w http.ResponseWriter, r *http.Request) {
+	clinicID, err := parseClinicID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	list, err := tc.Service.GetOverdueSteps(r.Header.Get("User-ID"), clinicID)
+	if err != nil {
+		http.Error(w, "Error al obtener pasos vencidos: "+err.Error(), treatmentPlanErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToOverdueTreatmentStepDTOs(list, time.Now()))
+}
+
+func treatmentPlanErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrTreatmentPlanNotFound),
+		errors.Is(err, services.ErrTreatmentStepNotFound),
+		errors.Is(err, services.ErrPetNotFound),
+		errors.Is(err, services.ErrAppointmentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrPetAccessDenied), errors.Is(err, services.ErrStaffOnly):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrTreatmentPlanClosed),
+		errors.Is(err, services.ErrTreatmentStepClosed),
+		errors.Is(err, services.ErrTreatmentStepScheduled),
+		errors.Is(err, services.ErrTreatmentAppointmentInUse):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrTreatmentAppointmentPet),
+		errors.Is(err, services.ErrTreatmentAppointmentState),
+		errors.Is(err, services.ErrTreatmentVetInvalid),
+		errors.Is(err, services.ErrVetNotInClinic):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
@@ -181,7 +181,7 @@ func (uc *UserController) GetAllUsers(w http.ResponseWriter, _ *http.Request) {
 }
 
 func (uc *UserController) GetOwners(w http.ResponseWriter, _ *http.Request) {
-	users, err := uc.Service.GetUsersByRole(1) // Dueños
+	users, err := uc.Service.GetUsersByRole(1, nil) // Dueños
 	if err != nil {
 		http.Error(w, "Error al obtener dueños: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -193,8 +193,13 @@ func (uc *UserController) GetOwners(w http.ResponseWriter, _ *http.Request) {
 	json.NewEncoder(w).Encode(dtos)
 }
 
-func (uc *UserController) GetVets(w http.ResponseWriter, _ *http.Request) {
-	users, err := uc.Service.GetUsersByRole(2) // Veterinarios
+func (uc *UserController) GetVets(w http.ResponseWriter, r *http.Request) {
+	clinicID, err := parseClinicID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	users, err := uc.Service.GetUsersByRole(2, clinicID) // Veterinarios
 	if err != nil {
 		http.Error(w, "Error al obtener veterinarios: "+err.Error(), http.StatusInternalServerError)
 		return
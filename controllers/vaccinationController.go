@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type VaccinationController struct {
+	Service *services.VaccinationService
+}
+
+func NewVaccinationController(service *services.VaccinationService) *VaccinationController {
+	return &VaccinationController{Service: service}
+}
+
+func (vc *VaccinationController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/vaccines", authMiddleware(http.HandlerFunc(vc.GetVaccines))).Methods("GET")
+	r.Handle("/api/vaccinations/due", authMiddleware(http.HandlerFunc(vc.GetDue))).Methods("GET")
+	r.Handle("/api/pets/{id}/vaccinations", authMiddleware(http.HandlerFunc(vc.GetByPet))).Methods("GET")
+	r.Handle("/api/pets/{id}/vaccinations", authMiddleware(http.HandlerFunc(vc.RecordVaccination))).Methods("POST")
+}
+
+func (vc *VaccinationController) RegisterAdminRoutes(r *mux.Router, adminMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/vaccines", adminMiddleware(http.HandlerFunc(vc.CreateVaccine))).Methods("POST")
+	r.Handle("/api/vaccines/{id}", adminMiddleware(http.HandlerFunc(vc.UpdateVaccine))).Methods("PUT")
+	r.Handle("/api/vaccines/{id}", adminMiddleware(http.HandlerFunc(vc.DeleteVaccine))).Methods("DELETE")
+	r.Handle("/api/vaccinations/migrate-legacy", adminMiddleware(http.HandlerFunc(vc.MigrateLegacy))).Methods("POST")
+}
+
+func (vc *VaccinationController) GetVaccines(w http.ResponseWriter, r *http.Request) {
+	list, err := vc.Service.GetVaccines(r.URL.Query().Get("all") != "true")
+	if err != nil {
+		http.Error(w, "Error al obtener vacunas: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dtos := []dto.VaccineDTO{}
+	for _, v := range list {
+		dtos = append(dtos, dto.ToVaccineDTO(&v))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (vc *VaccinationController) CreateVaccine(w http.ResponseWriter, r *http.Request) {
+	var input dto.VaccineDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if input.DefaultIntervalDays == 0 {
+		input.DefaultIntervalDays = 365
+	}
+	if err := validators.ValidateVaccineDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	vaccine := entities.Vaccine{
+		Name:                input.Name,
+		SpeciesID:           input.SpeciesID,
+		Aliases:             input.Aliases,
+		DefaultIntervalDays: input.DefaultIntervalDays,
+		StatusID:            1,
+	}
+	if err := vc.Service.CreateVaccine(&vaccine); err != nil {
+		http.Error(w, "Error al crear vacuna, verifique que el nombre no esté en uso", http.StatusBadRequest)
+		return
+	}
+	created, err := vc.Service.GetVaccineByID(vaccine.ID)
+	if err != nil || created == nil {
+		http.Error(w, "Error obteniendo vacuna creada", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToVaccineDTO(created))
+}
+
+func (vc *VaccinationController) UpdateVaccine(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var fields map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	delete(fields, "id")
+	if name, ok := fields["name"].(string); ok {
+		if err := validators.ValidateVaccineName(name); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if interval, ok := fields["default_interval_days"].(float64); ok && interval < 0 {
+		http.Error(w, validators.ErrInvalidVaccineInterval.Error(), http.StatusBadRequest)
+		return
+	}
+	if aliases, ok := fields["aliases"].(string); ok {
+		if err := validators.ValidateMaxLen(aliases, 300, validators.ErrInvalidVaccineAliases); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := vc.Service.UpdateVaccine(id, fields); err != nil {
+		http.Error(w, "Error al actualizar vacuna: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": "Vacuna actualizada correctamente"})
+}
+
+func (vc *VaccinationController) DeleteVaccine(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	msg, err := vc.Service.DeleteVaccine(id)
+	if err != nil {
+		http.Error(w, "Error al cambiar estado de la vacuna: "+err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+func (vc *VaccinationController) GetByPet(w http.ResponseWriter, r *http.Request) {
+	petID := mux.Vars(r)["id"]
+	if err := validators.ValidateUUIDRequired(petID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	list, err := vc.Service.GetByPetID(r.Header.Get("User-ID"), petID)
+	if err != nil {
+		http.Error(w, "Error al obtener vacunas de la mascota: "+err.Error(), vaccinationErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToVaccinationDTOs(list))
+}
+
+func (vc *VaccinationController) RecordVaccination(w http.ResponseWriter, r *http.Request) {
+	petID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, validators.ErrInvalidPetID.Error(), http.StatusBadRequest)
+		return
+	}
+	var input dto.VaccinationInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateVaccinationInputDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	given, _ := time.ParseInLocation("02-01-2006", input.DateGiven, time.Local)
+	vaccination := entities.Vaccination{
+		PetID:        petID,
+		VaccineID:    input.VaccineID,
+		Manufacturer: input.Manufacturer,
+		LotNumber:    input.LotNumber,
+		DoseNumber:   input.DoseNumber,
+		DateGiven:    given,
+		Notes:        input.Notes,
+	}
+	if input.NextDueDate != "" {
+		next, _ := time.ParseInLocation("02-01-2006", input.NextDueDate, time.Local)
+		vaccination.NextDueDate = &next
+	}
+	if input.AppointmentID != nil && *input.AppointmentID != "" {
+		id := uuid.MustParse(*input.AppointmentID)
+		vaccination.AppointmentID = &id
+	}
+	if input.AdministeredByID != nil && *input.AdministeredByID != "" {
+		id := uuid.MustParse(*input.AdministeredByID)
+		vaccination.AdministeredByID = &id
+	}
+
+	created, err := vc.Service.RecordVaccination(r.Header.Get("User-ID"), &vaccination)
+	if err != nil {
+		http.Error(w, "Error al registrar vacuna: "+err.Error(), vaccinationErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.ToVaccinationDTO(created))
+}
+
+// GetDue lista las mascotas con una vacuna por vencer entre from y to
+// (DD-MM-YYYY). Por defecto cubre los próximos 30 días.
+func (vc *VaccinationController) GetDue(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	today := time.Now()
+	filter := entities.VaccinationDueFilter{
+		From: time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local),
+	}
+	filter.To = filter.From.AddDate(0, 0, 30)
+	if v := q.Get("from"); v != "" {
+		from, err := time.ParseInLocation("02-01-2006", v, time.Local)
+		if err != nil {
+			http.Error(w, "from inválido, use formato DD-MM-YYYY", http.StatusBadRequest)
+			return
+		}
+		filter.From = from
+	}
+	if v := q.Get("to"); v != "" {
+		to, err := time.ParseInLocation("02-01-2006", v, time.Local)
+		if err != nil {
+			http.Error(w, "to inválido, use formato DD-MM-YYYY", http.StatusBadRequest)
+			return
+		}
+		filter.To = to
+	}
+	if v := q.Get("vaccine_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "vaccine_id inválido", http.StatusBadRequest)
+			return
+		}
+		filter.VaccineID = &id
+	}
+	if v := q.Get("species_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "species_id inválido", http.StatusBadRequest)
+			return
+		}
+		filter.SpeciesID = &id
+	}
+
+	list, err := vc.Service.GetDue(r.Header.Get("User-ID"), filter)
+	if err != nil {
+		http.Error(w, "Error al obtener vacunas por vencer: "+err.Error(), vaccinationErrorStatus(err))
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToVaccinationDTOs(list))
+}
+
+func (vc *VaccinationController) MigrateLegacy(w http.ResponseWriter, _ *http.Request) {
+	result, err := vc.Service.MigrateLegacy()
+	if err != nil {
+		http.Error(w, "Error al migrar vacunas: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(dto.ToLegacyVaccinationResultDTO(result))
+}
+
+func vaccinationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPetNotFound),
+		errors.Is(err, services.ErrAppointmentNotFound),
+		errors.Is(err, services.ErrVaccineNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrVaccineSpeciesMismatch),
+		errors.Is(err, services.ErrAppointmentPetMismatch),
+		errors.Is(err, services.ErrVaccinationDateInFuture),
+		errors.Is(err, services.ErrNextDueBeforeGiven),
+		errors.Is(err, services.ErrAdministeringVetInvalid):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrInvalidDateRange):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrPetAccessDenied), errors.Is(err, services.ErrStaffOnly):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"VetiCare/services"
+	"VetiCare/validators"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"time"
+)
+
+type VitalSignController struct {
+	Service *services.VitalSignService
+}
+
+func NewVitalSignController(service *services.VitalSignService) *VitalSignController {
+	return &VitalSignController{Service: service}
+}
+
+func (vc *VitalSignController) RegisterRoutes(r *mux.Router, authMiddleware func(http.Handler) http.Handler) {
+	r.Handle("/api/pets/{id}/vitals", authMiddleware(http.HandlerFunc(vc.GetSeries))).Methods("GET")
+	r.Handle("/api/pets/{id}/vitals", authMiddleware(http.HandlerFunc(vc.RecordVitals))).Methods("POST")
+}
+
+// GetSeries devuelve la serie de signos vitales de la mascota ordenada por
+// fecha, con el cambio porcentual de cada signo respecto de la toma anterior.
+func (vc *VitalSignController) GetSeries(w http.ResponseWriter, r *http.Request) {
+	points, err := vc.Service.GetSeries(r.Header.Get("User-ID"), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Error al obtener signos vitales: "+err.Error(), vitalSignErrorStatus(err))
+		return
+	}
+	dtos := []dto.VitalPointDTO{}
+	for _, p := range points {
+		dtos = append(dtos, dto.ToVitalPointDTO(&p))
+	}
+	json.NewEncoder(w).Encode(dtos)
+}
+
+func (vc *VitalSignController) RecordVitals(w http.ResponseWriter, r *http.Request) {
+	petID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, validators.ErrInvalidPetID.Error(), http.StatusBadRequest)
+		return
+	}
+	var input dto.VitalSignInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateVitalSignInputDTO(input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vital := entities.VitalSign{
+		PetID:              petID,
+		WeightKg:           input.WeightKg,
+		Temperature:        input.Temperature,
+		HeartRate:          input.HeartRate,
+		RespiratoryRate:    input.RespiratoryRate,
+		BodyConditionScore: input.BodyConditionScore,
+		Notes:              input.Notes,
+	}
+	if input.RecordedAt != "" {
+		vital.RecordedAt, _ = time.ParseInLocation(validators.VitalRecordedAtLayout, input.RecordedAt, time.Local)
+	}
+	if input.AppointmentID != nil && *input.AppointmentID != "" {
+		id := uuid.MustParse(*input.AppointmentID)
+		vital.AppointmentID = &id
+	}
+	if parsed, err := uuid.Parse(r.Header.Get("User-ID")); err == nil {
+		vital.RecordedByID = &parsed
+	}
+
+	created, err := vc.Service.Record(r.Header.Get("User-ID"), &vital)
+	if err != nil {
+		http.Error(w, "Error al registrar signos vitales: "+err.Error(), vitalSignErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func vitalSignErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPetNotFound), errors.Is(err, services.ErrAppointmentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrVitalsEmpty),
+		errors.Is(err, services.ErrVitalsDateInFuture),
+		errors.Is(err, services.ErrAppointmentPetMismatch),
+		errors.Is(err, services.ErrImplausibleVital):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrPetAccessDenied), errors.Is(err, services.ErrStaffOnly):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrAppointmentRecordLocked):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
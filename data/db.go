@@ -39,7 +39,13 @@ func RunPostgresDB() error {
 }
 
 func runMigrations(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := backfillAppointmentClinics(db); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(
+		&entities.Clinic{},
+		&entities.Room{},
+		&entities.Resource{},
 		&entities.User{},
 		&entities.Admin{},
 		&entities.Pet{},
@@ -47,7 +53,85 @@ func runMigrations(db *gorm.DB) error {
 		&entities.AdminType{},
 		&entities.UserRole{},
 		&entities.Species{},
-	)
+		&entities.Breed{},
+		&entities.AppointmentReschedule{},
+		&entities.VetTimeOff{},
+		&entities.ClinicClosure{},
+		&entities.Vaccine{},
+		&entities.Vaccination{},
+		&entities.Prescription{},
+		&entities.PrescriptionItem{},
+		&entities.Deworming{},
+		&entities.PreventiveProtocol{},
+		&entities.CareReminder{},
+		&entities.VitalSign{},
+		&entities.VitalReferenceRange{},
+		&entities.PetAllergy{},
+		&entities.PetCondition{},
+		&entities.PetBehaviorAlert{},
+		&entities.ClinicalNote{},
+		&entities.ClinicalNoteTemplate{},
+		&entities.Attachment{},
+		&entities.AppointmentRecordVersion{},
+		&entities.LabTest{},
+		&entities.LabAnalyte{},
+		&entities.LabReferenceRange{},
+		&entities.LabOrder{},
+		&entities.LabResult{},
+		&entities.Diagnosis{},
+		&entities.AppointmentDiagnosis{},
+		&entities.TreatmentPlan{},
+		&entities.TreatmentPlanStep{},
+		&entities.ShareLink{},
+		&entities.ShareLinkAccess{},
+	); err != nil {
+		return err
+	}
+	return backfillVetClinics(db)
+}
+
+// backfillAppointmentClinics asigna la clínica principal a las citas creadas
+// antes de que la clínica fuera obligatoria, para que AutoMigrate pueda
+// marcar la columna como NOT NULL. Si la base es anterior a las clínicas, la
+// columna se agrega primero sin la restricción. En una base nueva no hace
+// nada.
+func backfillAppointmentClinics(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&entities.Appointment{}) {
+		return nil
+	}
+	if err := ensureMainClinic(db); err != nil {
+		return err
+	}
+	if !db.Migrator().HasColumn(&entities.Appointment{}, "ClinicID") {
+		if err := db.Exec("ALTER TABLE appointments ADD COLUMN clinic_id bigint").Error; err != nil {
+			return err
+		}
+	}
+	return db.Model(&entities.Appointment{}).
+		Where("clinic_id IS NULL").
+		Update("clinic_id", entities.MainClinicID).Error
+}
+
+// backfillVetClinics asigna la clínica principal a los veterinarios activos
+// que no tienen ninguna; sin ella no pasarían la validación de las citas.
+func backfillVetClinics(db *gorm.DB) error {
+	if err := ensureMainClinic(db); err != nil {
+		return err
+	}
+	return db.Exec(`INSERT INTO vet_clinics (user_id, clinic_id)
+		SELECT users.id, ? FROM users
+		WHERE users.role_id = ? AND users.status_id = ?
+			AND NOT EXISTS (SELECT 1 FROM vet_clinics vc WHERE vc.user_id = users.id)`,
+		entities.MainClinicID, 2, 1).Error
+}
+
+// ensureMainClinic crea la clínica principal si todavía no existe.
+func ensureMainClinic(db *gorm.DB) error {
+	if err := db.AutoMigrate(&entities.Clinic{}); err != nil {
+		return err
+	}
+	clinic := entities.Clinic{ID: entities.MainClinicID, Name: "Clínica principal"}
+	return db.Where("id = ?", clinic.ID).FirstOrCreate(&clinic).Error
 }
 
 func seedCatalogs(db *gorm.DB) error {
@@ -97,5 +181,250 @@ func seedCatalogs(db *gorm.DB) error {
 		}
 	}
 
+	// Breeds
+	breeds := []entities.Breed{
+		{ID: 1, SpeciesID: 1, Name: "Mestizo / Otra", Aliases: "mestizo,mestiza,criollo,criolla,zaguate,aguacatero,callejero,cruce,cruzado,mezcla,sin raza,otra,otro", IsMixed: true},
+		{ID: 2, SpeciesID: 1, Name: "Labrador Retriever", Aliases: "labrador,lab"},
+		{ID: 3, SpeciesID: 1, Name: "Golden Retriever", Aliases: "golden"},
+		{ID: 4, SpeciesID: 1, Name: "Pastor Alemán", Aliases: "german shepherd,ovejero alemán"},
+		{ID: 5, SpeciesID: 1, Name: "Chihuahua"},
+		{ID: 6, SpeciesID: 1, Name: "Poodle", Aliases: "caniche,french poodle"},
+		{ID: 7, SpeciesID: 1, Name: "Schnauzer", Aliases: "schnauzer miniatura,mini schnauzer"},
+		{ID: 8, SpeciesID: 1, Name: "Shih Tzu", Aliases: "shitzu"},
+		{ID: 9, SpeciesID: 1, Name: "Pitbull", Aliases: "pit bull,american pit bull terrier"},
+		{ID: 10, SpeciesID: 1, Name: "Rottweiler"},
+		{ID: 11, SpeciesID: 1, Name: "Husky Siberiano", Aliases: "husky,siberian husky"},
+		{ID: 12, SpeciesID: 1, Name: "Beagle"},
+		{ID: 13, SpeciesID: 1, Name: "Bulldog Francés", Aliases: "french bulldog,frenchie"},
+		{ID: 14, SpeciesID: 1, Name: "Pug", Aliases: "carlino"},
+		{ID: 15, SpeciesID: 1, Name: "Yorkshire Terrier", Aliases: "yorkshire,yorkie"},
+		{ID: 16, SpeciesID: 1, Name: "Dóberman", Aliases: "doberman pinscher"},
+		{ID: 17, SpeciesID: 1, Name: "Boxer"},
+		{ID: 18, SpeciesID: 1, Name: "Dachshund", Aliases: "salchicha,teckel"},
+		{ID: 19, SpeciesID: 1, Name: "Pomerania", Aliases: "pomeranian,lulú de pomerania"},
+		{ID: 20, SpeciesID: 1, Name: "Cocker Spaniel", Aliases: "cocker"},
+		{ID: 21, SpeciesID: 2, Name: "Mestizo / Otra", Aliases: "mestizo,mestiza,criollo,criolla,doméstico,domestico,común,comun,europeo común,cruce,mezcla,sin raza,otra,otro", IsMixed: true},
+		{ID: 22, SpeciesID: 2, Name: "Siamés", Aliases: "siamese"},
+		{ID: 23, SpeciesID: 2, Name: "Persa", Aliases: "persian"},
+		{ID: 24, SpeciesID: 2, Name: "Angora", Aliases: "angora turco"},
+		{ID: 25, SpeciesID: 2, Name: "Maine Coon"},
+		{ID: 26, SpeciesID: 2, Name: "Bengalí", Aliases: "bengal"},
+		{ID: 27, SpeciesID: 2, Name: "Ragdoll"},
+		{ID: 28, SpeciesID: 2, Name: "Británico de Pelo Corto", Aliases: "british shorthair,británico"},
+		{ID: 29, SpeciesID: 2, Name: "Esfinge", Aliases: "sphynx,sphinx"},
+		{ID: 30, SpeciesID: 3, Name: "Mestizo / Otra", Aliases: "mestizo,cruce,sin raza,otra,otro", IsMixed: true},
+		{ID: 31, SpeciesID: 3, Name: "Periquito", Aliases: "periquito australiano,budgie"},
+		{ID: 32, SpeciesID: 3, Name: "Canario"},
+		{ID: 33, SpeciesID: 3, Name: "Cacatúa Ninfa", Aliases: "ninfa,carolina,cockatiel"},
+		{ID: 34, SpeciesID: 3, Name: "Agapornis", Aliases: "inseparable,lovebird"},
+		{ID: 35, SpeciesID: 3, Name: "Loro", Aliases: "perico,lora,cotorra"},
+		{ID: 36, SpeciesID: 3, Name: "Guacamaya", Aliases: "guacamayo,ara"},
+	}
+	for _, b := range breeds {
+		var existing entities.Breed
+		result := db.First(&existing, "id = ?", b.ID)
+		if result.Error != nil && errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			if err := db.Create(&b).Error; err != nil {
+				log.Printf("Error insertando Breed %v: %v\n", b, err)
+			}
+		}
+	}
+	syncSequence(db, "breeds")
+
+	// Clinics
+	clinics := []entities.Clinic{
+		{ID: entities.MainClinicID, Name: "Clínica principal"},
+	}
+	for _, c := range clinics {
+		var existing entities.Clinic
+		result := db.First(&existing, "id = ?", c.ID)
+		if result.Error != nil && errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			if err := db.Create(&c).Error; err != nil {
+				log.Printf("Error insertando Clinic %v: %v\n", c, err)
+			}
+		}
+	}
+	syncSequence(db, "clinics")
+
+	// Vaccines
+	dog, cat := 1, 2
+	vaccines := []entities.Vaccine{
+		{ID: 1, Name: "Rabia", Aliases: "antirrábica,antirrabica,rabies", DefaultIntervalDays: 365},
+		{ID: 2, Name: "Polivalente canina", SpeciesID: &dog, Aliases: "polivalente,quíntuple,quintuple,séxtuple,sextuple,dhpp,moquillo,parvovirus,parvo", DefaultIntervalDays: 365},
+		{ID: 3, Name: "Leptospirosis", SpeciesID: &dog, Aliases: "lepto", DefaultIntervalDays: 365},
+		{ID: 4, Name: "Bordetella", SpeciesID: &dog, Aliases: "tos de las perreras,tos de perrera,kennel", DefaultIntervalDays: 365},
+		{ID: 5, Name: "Triple felina", SpeciesID: &cat, Aliases: "fvrcp,trivalente felina,triple", DefaultIntervalDays: 365},
+		{ID: 6, Name: "Leucemia felina", SpeciesID: &cat, Aliases: "felv,leucemia", DefaultIntervalDays: 365},
+	}
+	for _, v := range vaccines {
+		var existing entities.Vaccine
+		result := db.First(&existing, "id = ?", v.ID)
+		if result.Error != nil && errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			if err := db.Create(&v).Error; err != nil {
+				log.Printf("Error insertando Vaccine %v: %v\n", v, err)
+			}
+		}
+	}
+	syncSequence(db, "vaccines")
+
+	// PreventiveProtocols
+	rabies, dogCombo, catCombo := 1, 2, 5
+	twelveWeeks := 12
+	protocols := []entities.PreventiveProtocol{
+		{ID: 1, SpeciesID: 1, Name: "Polivalente canina", CareType: entities.CareTypeVaccination, VaccineID: &dogCombo, MinAgeWeeks: 6, IntervalDays: 365},
+		{ID: 2, SpeciesID: 1, Name: "Rabia canina", CareType: entities.CareTypeVaccination, VaccineID: &rabies, MinAgeWeeks: 12, IntervalDays: 365},
+		{ID: 3, SpeciesID: 1, Name: "Desparasitación de cachorro", CareType: entities.CareTypeDeworming, MinAgeWeeks: 2, MaxAgeWeeks: &twelveWeeks, IntervalDays: 15},
+		{ID: 4, SpeciesID: 1, Name: "Desparasitación de adulto", CareType: entities.CareTypeDeworming, MinAgeWeeks: 12, IntervalDays: 90},
+		{ID: 5, SpeciesID: 2, Name: "Triple felina", CareType: entities.CareTypeVaccination, VaccineID: &catCombo, MinAgeWeeks: 8, IntervalDays: 365},
+		{ID: 6, SpeciesID: 2, Name: "Rabia felina", CareType: entities.CareTypeVaccination, VaccineID: &rabies, MinAgeWeeks: 12, IntervalDays: 365},
+		{ID: 7, SpeciesID: 2, Name: "Desparasitación de gatito", CareType: entities.CareTypeDeworming, MinAgeWeeks: 2, MaxAgeWeeks: &twelveWeeks, IntervalDays: 15},
+		{ID: 8, SpeciesID: 2, Name: "Desparasitación de adulto", CareType: entities.CareTypeDeworming, MinAgeWeeks: 12, IntervalDays: 90},
+	}
+	for _, p := range protocols {
+		var existing entities.PreventiveProtocol
+		result := db.First(&existing, "id = ?", p.ID)
+		if result.Error != nil && errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			if err := db.Create(&p).Error; err != nil {
+				log.Printf("Error insertando PreventiveProtocol %v: %v\n", p, err)
+			}
+		}
+	}
+	syncSequence(db, "preventive_protocols")
+
+	// VitalReferenceRanges
+	oneYear := 52
+	referenceRanges := []entities.VitalReferenceRange{
+		{ID: 1, SpeciesID: 1, Vital: entities.VitalTemperature, Min: 37.5, Max: 39.2, PlausibleMin: 30, PlausibleMax: 44},
+		{ID: 2, SpeciesID: 1, Vital: entities.VitalWeight, Min: 1, Max: 90, PlausibleMin: 0.1, PlausibleMax: 120},
+		{ID: 3, SpeciesID: 1, Vital: entities.VitalHeartRate, Min: 60, Max: 140, PlausibleMin: 20, PlausibleMax: 350},
+		{ID: 4, SpeciesID: 1, Vital: entities.VitalHeartRate, LifeStage: "Cachorro", MaxAgeWeeks: &oneYear, Min: 120, Max: 220, PlausibleMin: 20, PlausibleMax: 350},
+		{ID: 5, SpeciesID: 1, Vital: entities.VitalRespiratoryRate, Min: 10, Max: 30, PlausibleMin: 4, PlausibleMax: 200},
+		{ID: 6, SpeciesID: 2, Vital: entities.VitalTemperature, Min: 38, Max: 39.2, PlausibleMin: 30, PlausibleMax: 44},
+		{ID: 7, SpeciesID: 2, Vital: entities.VitalWeight, Min: 2, Max: 8, PlausibleMin: 0.05, PlausibleMax: 20},
+		{ID: 8, SpeciesID: 2, Vital: entities.VitalHeartRate, Min: 140, Max: 220, PlausibleMin: 40, PlausibleMax: 350},
+		{ID: 9, SpeciesID: 2, Vital: entities.VitalWeight, LifeStage: "Gatito", MaxAgeWeeks: &oneYear, Min: 0.1, Max: 5, PlausibleMin: 0.05, PlausibleMax: 20},
+		{ID: 10, SpeciesID: 2, Vital: entities.VitalRespiratoryRate, Min: 20, Max: 30, PlausibleMin: 4, PlausibleMax: 200},
+	}
+	for _, vr := range referenceRanges {
+		var existing entities.VitalReferenceRange
+		result := db.First(&existing, "id = ?", vr.ID)
+		if result.Error != nil && errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			if err := db.Create(&vr).Error; err != nil {
+				log.Printf("Error insertando VitalReferenceRange %v: %v\n", vr, err)
+			}
+		}
+	}
+	syncSequence(db, "vital_reference_ranges")
+
+	// LabTests
+	labTests := []entities.LabTest{
+		{ID: 1, Name: "Hemograma", Code: "HEM", SampleType: "Sangre con EDTA", StatusID: 1},
+		{ID: 2, Name: "Química sanguínea", Code: "QS", SampleType: "Suero", StatusID: 1},
+	}
+	for _, lt := range labTests {
+		var existing entities.LabTest
+		result := db.First(&existing, "id = ?", lt.ID)
+		if result.Error != nil && errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			if err := db.Create(&lt).Error; err != nil {
+				log.Printf("Error insertando LabTest %v: %v\n", lt, err)
+			}
+		}
+	}
+	syncSequence(db, "lab_tests")
+
+	// LabAnalytes
+	labAnalytes := []entities.LabAnalyte{
+		{ID: 1, LabTestID: 1, Name: "Hematocrito", Unit: "%", SortOrder: 1, StatusID: 1},
+		{ID: 2, LabTestID: 1, Name: "Hemoglobina", Unit: "g/dL", SortOrder: 2, StatusID: 1},
+		{ID: 3, LabTestID: 1, Name: "Leucocitos", Unit: "x10^3/µL", SortOrder: 3, StatusID: 1},
+		{ID: 4, LabTestID: 1, Name: "Plaquetas", Unit: "x10^3/µL", SortOrder: 4, StatusID: 1},
+		{ID: 5, LabTestID: 2, Name: "Glucosa", Unit: "mg/dL", SortOrder: 1, StatusID: 1},
+		{ID: 6, LabTestID: 2, Name: "Creatinina", Unit: "mg/dL", SortOrder: 2, StatusID: 1},
+		{ID: 7, LabTestID: 2, Name: "BUN", Unit: "mg/dL", SortOrder: 3, StatusID: 1},
+		{ID: 8, LabTestID: 2, Name: "ALT", Unit: "U/L", SortOrder: 4, StatusID: 1},
+	}
+	for _, la := range labAnalytes {
+		var existing entities.LabAnalyte
+		result := db.First(&existing, "id = ?", la.ID)
+		if result.Error != nil && errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			if err := db.Create(&la).Error; err != nil {
+				log.Printf("Error insertando LabAnalyte %v: %v\n", la, err)
+			}
+		}
+	}
+	syncSequence(db, "lab_analytes")
+
+	// LabReferenceRanges
+	labRanges := []entities.LabReferenceRange{
+		{ID: 1, AnalyteID: 1, SpeciesID: 1, Min: 37, Max: 55},
+		{ID: 2, AnalyteID: 2, SpeciesID: 1, Min: 12, Max: 18},
+		{ID: 3, AnalyteID: 3, SpeciesID: 1, Min: 6, Max: 17},
+		{ID: 4, AnalyteID: 4, SpeciesID: 1, Min: 200, Max: 500},
+		{ID: 5, AnalyteID: 5, SpeciesID: 1, Min: 70, Max: 143},
+		{ID: 6, AnalyteID: 6, SpeciesID: 1, Min: 0.5, Max: 1.8},
+		{ID: 7, AnalyteID: 7, SpeciesID: 1, Min: 7, Max: 27},
+		{ID: 8, AnalyteID: 8, SpeciesID: 1, Min: 10, Max: 125},
+		{ID: 9, AnalyteID: 1, SpeciesID: 2, Min: 30, Max: 45},
+		{ID: 10, AnalyteID: 2, SpeciesID: 2, Min: 9, Max: 15},
+		{ID: 11, AnalyteID: 3, SpeciesID: 2, Min: 5.5, Max: 19.5},
+		{ID: 12, AnalyteID: 4, SpeciesID: 2, Min: 300, Max: 800},
+		{ID: 13, AnalyteID: 5, SpeciesID: 2, Min: 71, Max: 159},
+		{ID: 14, AnalyteID: 6, SpeciesID: 2, Min: 0.8, Max: 2.4},
+		{ID: 15, AnalyteID: 7, SpeciesID: 2, Min: 16, Max: 36},
+		{ID: 16, AnalyteID: 8, SpeciesID: 2, Min: 12, Max: 130},
+	}
+	for _, lr := range labRanges {
+		var existing entities.LabReferenceRange
+		result := db.First(&existing, "id = ?", lr.ID)
+		if result.Error != nil && errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			if err := db.Create(&lr).Error; err != nil {
+				log.Printf("Error insertando LabReferenceRange %v: %v\n", lr, err)
+			}
+		}
+	}
+	syncSequence(db, "lab_reference_ranges")
+
+	// Diagnoses
+	diagnoses := []entities.Diagnosis{
+		{ID: 1, Code: "INF-001", Name: "Parvovirosis canina", Category: "Infecciosas", SpeciesID: &dog, StatusID: 1},
+		{ID: 2, Code: "INF-002", Name: "Moquillo canino", Category: "Infecciosas", SpeciesID: &dog, StatusID: 1},
+		{ID: 3, Code: "INF-003", Name: "Traqueobronquitis infecciosa canina", Category: "Infecciosas", SpeciesID: &dog, StatusID: 1},
+		{ID: 4, Code: "INF-004", Name: "Panleucopenia felina", Category: "Infecciosas", SpeciesID: &cat, StatusID: 1},
+		{ID: 5, Code: "INF-005", Name: "Complejo respiratorio felino", Category: "Infecciosas", SpeciesID: &cat, StatusID: 1},
+		{ID: 6, Code: "PAR-001", Name: "Parasitosis intestinal", Category: "Parasitarias", StatusID: 1},
+		{ID: 7, Code: "PAR-002", Name: "Ehrlichiosis", Category: "Parasitarias", SpeciesID: &dog, StatusID: 1},
+		{ID: 8, Code: "DER-001", Name: "Otitis externa", Category: "Dermatología", StatusID: 1},
+		{ID: 9, Code: "DER-002", Name: "Dermatitis alérgica por pulgas", Category: "Dermatología", StatusID: 1},
+		{ID: 10, Code: "DER-003", Name: "Dermatitis atópica", Category: "Dermatología", StatusID: 1},
+		{ID: 11, Code: "GAS-001", Name: "Gastroenteritis aguda", Category: "Digestivas", StatusID: 1},
+		{ID: 12, Code: "ODO-001", Name: "Enfermedad periodontal", Category: "Odontología", StatusID: 1},
+		{ID: 13, Code: "URI-001", Name: "Enfermedad renal crónica", Category: "Urinarias", StatusID: 1},
+		{ID: 14, Code: "URI-002", Name: "Cistitis idiopática felina", Category: "Urinarias", SpeciesID: &cat, StatusID: 1},
+		{ID: 15, Code: "END-001", Name: "Diabetes mellitus", Category: "Endocrinas", StatusID: 1},
+		{ID: 16, Code: "END-002", Name: "Hipertiroidismo felino", Category: "Endocrinas", SpeciesID: &cat, StatusID: 1},
+		{ID: 17, Code: "CAR-001", Name: "Enfermedad degenerativa de la válvula mitral", Category: "Cardiología", SpeciesID: &dog, StatusID: 1},
+		{ID: 18, Code: "MUS-001", Name: "Displasia de cadera", Category: "Musculoesqueléticas", SpeciesID: &dog, StatusID: 1},
+		{ID: 19, Code: "GEN-001", Name: "Obesidad", Category: "Generales", StatusID: 1},
+	}
+	for _, d := range diagnoses {
+		var existing entities.Diagnosis
+		result := db.First(&existing, "id = ?", d.ID)
+		if result.Error != nil && errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			if err := db.Create(&d).Error; err != nil {
+				log.Printf("Error insertando Diagnosis %v: %v\n", d, err)
+			}
+		}
+	}
+	syncSequence(db, "diagnoses")
+
 	return nil
 }
+
+// syncSequence mueve la secuencia del ID al máximo existente para que los
+// registros creados desde la API no choquen con los IDs fijos del seed.
+func syncSequence(db *gorm.DB, table string) {
+	query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', 'id'), COALESCE((SELECT MAX(id) FROM %s), 1))", table, table)
+	if err := db.Exec(query).Error; err != nil {
+		log.Printf("Error sincronizando secuencia de %s: %v\n", table, err)
+	}
+}
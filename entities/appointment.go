@@ -7,21 +7,54 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	AppointmentStatusScheduled = 1
+	AppointmentStatusFinished  = 2
+	AppointmentStatusCancelled = 3
+	AppointmentStatusNoShow    = 4
+	AppointmentStatusCheckedIn = 5
+	AppointmentStatusInProcess = 6
+)
+
 type Appointment struct {
 	ID                    uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
 	PetID                 uuid.UUID  `gorm:"type:uuid;not null" json:"pet_id"`
 	Pet                   Pet        `gorm:"foreignKey:PetID" json:"pet"`
 	VetID                 *uuid.UUID `gorm:"type:uuid" json:"vet_id,omitempty"`
 	Vet                   User       `gorm:"foreignKey:VetID" json:"vet"`
+	ClinicID              *int       `gorm:"not null" json:"clinic_id,omitempty"`
+	Clinic                *Clinic    `gorm:"foreignKey:ClinicID" json:"clinic,omitempty"`
+	RoomID                *int       `json:"room_id,omitempty"`
+	Room                  *Room      `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+	ResourceID            *int       `json:"resource_id,omitempty"`
+	Resource              *Resource  `gorm:"foreignKey:ResourceID" json:"resource,omitempty"`
 	Date                  string     `gorm:"size:10;not null" json:"date"`
 	Time                  string     `gorm:"size:5;not null" json:"time"`
 	StatusID              int        `gorm:"not null;default:1" json:"status_id"`
 	Reason                string     `gorm:"size:300" json:"reason,omitempty"`
 	WeightKg              *float64   `gorm:"type:numeric(5,2)" json:"weight_kg,omitempty"`
 	Temperature           *float64   `gorm:"type:numeric(4,1)" json:"temperature,omitempty"`
-	VaccinationStatus     string     `gorm:"size:300" json:"vaccination_status,omitempty"`
-	MedicationsPrescribed string     `gorm:"size:300" json:"medications_prescribed,omitempty"`
+	VaccinationStatus     string     `gorm:"size:300" json:"vaccination_status,omitempty"`     // Deprecated: usar Vaccination; se conserva para el historial.
+	MedicationsPrescribed string     `gorm:"size:300" json:"medications_prescribed,omitempty"` // Deprecated: usar Prescription; se conserva para el historial.
 	AdditionalNotes       string     `gorm:"size:500" json:"additional_notes,omitempty"`
+	RescheduleCount       int        `gorm:"not null;default:0" json:"reschedule_count"`
+	DepositRequired       bool       `gorm:"not null;default:false" json:"deposit_required"`
+	ApprovalRequired      bool       `gorm:"not null;default:false" json:"approval_required"`
+	ApprovedByID          *uuid.UUID `gorm:"type:uuid" json:"approved_by_id,omitempty"`
+	ApprovedAt            *time.Time `json:"approved_at,omitempty"`
+	DepositRecordedByID   *uuid.UUID `gorm:"type:uuid" json:"deposit_recorded_by_id,omitempty"`
+	DepositPaidAt         *time.Time `json:"deposit_paid_at,omitempty"`
+	IsWalkIn              bool       `gorm:"not null;default:false" json:"is_walk_in"`
+	CheckedInAt           *time.Time `json:"checked_in_at,omitempty"`
+	StartedAt             *time.Time `json:"started_at,omitempty"`
+	FinishedAt            *time.Time `json:"finished_at,omitempty"`
+	RecordVersion         int        `gorm:"not null;default:1" json:"record_version"`
+
+	Prescriptions []Prescription `gorm:"foreignKey:AppointmentID" json:"prescriptions,omitempty"`
+	ClinicalNote  *ClinicalNote  `gorm:"foreignKey:AppointmentID" json:"clinical_note,omitempty"`
+	LabOrders     []LabOrder     `gorm:"foreignKey:AppointmentID" json:"lab_orders,omitempty"`
+
+	Diagnoses []AppointmentDiagnosis `gorm:"foreignKey:AppointmentID" json:"diagnoses,omitempty"`
 
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
@@ -37,5 +70,14 @@ func (a *Appointment) BeforeCreate(tx *gorm.DB) (err error) {
 	if a.ID == uuid.Nil {
 		a.ID = uuid.New()
 	}
+	a.SetDefaultClinic()
 	return
 }
+
+// SetDefaultClinic asigna la clínica principal a la cita si no tiene una.
+func (a *Appointment) SetDefaultClinic() {
+	if a.ClinicID == nil {
+		id := MainClinicID
+		a.ClinicID = &id
+	}
+}
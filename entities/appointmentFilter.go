@@ -0,0 +1,41 @@
+package entities
+
+import "time"
+
+type AppointmentFilter struct {
+	DateFrom  *time.Time
+	DateTo    *time.Time
+	VetID     string
+	OwnerID   string
+	PetID     string
+	SpeciesID int
+	ClinicID  *int
+	StatusIDs []int
+	Query     string
+
+	DiagnosisID int
+
+	Page     int
+	PageSize int
+	SortBy   string
+	SortDesc bool
+}
+
+const (
+	AppointmentDefaultPageSize = 20
+	AppointmentMaxPageSize     = 100
+)
+
+// Normalize completa la paginación: sin page se usa la primera página y sin
+// page_size el tamaño por defecto, limitado a AppointmentMaxPageSize.
+func (f *AppointmentFilter) Normalize() {
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.PageSize < 1 {
+		f.PageSize = AppointmentDefaultPageSize
+	}
+	if f.PageSize > AppointmentMaxPageSize {
+		f.PageSize = AppointmentMaxPageSize
+	}
+}
@@ -0,0 +1,96 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AppointmentRecordVersion guarda una versión de los datos clínicos de una
+// cita finalizada. La versión 1 es el registro original y se guarda al hacer
+// la primera enmienda; cada enmienda agrega una versión nueva con su autor y
+// motivo. La cita y su nota clínica conservan siempre la versión más reciente.
+type AppointmentRecordVersion struct {
+	ID                    uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	AppointmentID         uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_appointment_record_version" json:"appointment_id"`
+	Version               int        `gorm:"not null;uniqueIndex:idx_appointment_record_version" json:"version"`
+	Reason                string     `gorm:"size:300" json:"reason,omitempty"`
+	WeightKg              *float64   `gorm:"type:numeric(5,2)" json:"weight_kg,omitempty"`
+	Temperature           *float64   `gorm:"type:numeric(4,1)" json:"temperature,omitempty"`
+	VaccinationStatus     string     `gorm:"size:300" json:"vaccination_status,omitempty"`
+	MedicationsPrescribed string     `gorm:"size:300" json:"medications_prescribed,omitempty"`
+	AdditionalNotes       string     `gorm:"size:500" json:"additional_notes,omitempty"`
+	HasClinicalNote       bool       `gorm:"not null;default:false" json:"has_clinical_note"`
+	Subjective            string     `gorm:"type:text" json:"subjective,omitempty"`
+	Objective             string     `gorm:"type:text" json:"objective,omitempty"`
+	Assessment            string     `gorm:"type:text" json:"assessment,omitempty"`
+	Plan                  string     `gorm:"type:text" json:"plan,omitempty"`
+	DiagnosisCodes        string     `gorm:"size:500" json:"diagnosis_codes,omitempty"`
+	AuthorID              *uuid.UUID `gorm:"type:uuid" json:"author_id,omitempty"`
+	AuthorName            string     `gorm:"size:100" json:"author_name"`
+	AmendmentReason       string     `gorm:"size:500" json:"amendment_reason,omitempty"`
+	CreatedAt             time.Time  `gorm:"not null" json:"created_at"`
+}
+
+// NewOriginalRecordVersion toma los datos clínicos actuales de la cita como
+// versión 1. La cita debe traer precargados el veterinario y la nota clínica.
+func NewOriginalRecordVersion(app *Appointment) AppointmentRecordVersion {
+	v := AppointmentRecordVersion{
+		AppointmentID:         app.ID,
+		Version:               1,
+		Reason:                app.Reason,
+		WeightKg:              app.WeightKg,
+		Temperature:           app.Temperature,
+		VaccinationStatus:     app.VaccinationStatus,
+		MedicationsPrescribed: app.MedicationsPrescribed,
+		AdditionalNotes:       app.AdditionalNotes,
+		AuthorID:              app.VetID,
+		AuthorName:            app.Vet.FullName,
+		CreatedAt:             app.UpdatedAt,
+	}
+	if app.FinishedAt != nil {
+		v.CreatedAt = *app.FinishedAt
+	}
+	if n := app.ClinicalNote; n != nil {
+		v.HasClinicalNote = true
+		v.Subjective = n.Subjective
+		v.Objective = n.Objective
+		v.Assessment = n.Assessment
+		v.Plan = n.Plan
+		v.DiagnosisCodes = n.DiagnosisCodes
+	}
+	return v
+}
+
+// AppointmentFields devuelve los campos de la cita que cambian con la versión.
+func (v *AppointmentRecordVersion) AppointmentFields() map[string]interface{} {
+	return map[string]interface{}{
+		"reason":                 v.Reason,
+		"weight_kg":              v.WeightKg,
+		"temperature":            v.Temperature,
+		"vaccination_status":     v.VaccinationStatus,
+		"medications_prescribed": v.MedicationsPrescribed,
+		"additional_notes":       v.AdditionalNotes,
+		"record_version":         v.Version,
+	}
+}
+
+// ClinicalNoteFields devuelve los campos de la nota clínica que cambian con la
+// versión.
+func (v *AppointmentRecordVersion) ClinicalNoteFields() map[string]interface{} {
+	return map[string]interface{}{
+		"subjective":      v.Subjective,
+		"objective":       v.Objective,
+		"assessment":      v.Assessment,
+		"plan":            v.Plan,
+		"diagnosis_codes": v.DiagnosisCodes,
+	}
+}
+
+func (v *AppointmentRecordVersion) BeforeCreate(tx *gorm.DB) (err error) {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AppointmentReschedule struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	AppointmentID uuid.UUID  `gorm:"type:uuid;not null;index" json:"appointment_id"`
+	PreviousDate  string     `gorm:"size:10;not null" json:"previous_date"`
+	PreviousTime  string     `gorm:"size:5;not null" json:"previous_time"`
+	NewDate       string     `gorm:"size:10;not null" json:"new_date"`
+	NewTime       string     `gorm:"size:5;not null" json:"new_time"`
+	Reason        string     `gorm:"size:300" json:"reason,omitempty"`
+	RequestedByID *uuid.UUID `gorm:"type:uuid" json:"requested_by_id,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (r *AppointmentReschedule) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Attachment guarda los datos de un archivo clínico (radiografías, resultados
+// de laboratorio, fotos) de una mascota y, opcionalmente, de una cita. El
+// contenido vive en el almacenamiento de archivos bajo StorageKey.
+type Attachment struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	PetID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"pet_id"`
+	AppointmentID *uuid.UUID `gorm:"type:uuid;index" json:"appointment_id,omitempty"`
+	UploadedByID  uuid.UUID  `gorm:"type:uuid;not null" json:"uploaded_by_id"`
+	FileName      string     `gorm:"size:255;not null" json:"file_name"`
+	ContentType   string     `gorm:"size:100;not null" json:"content_type"`
+	SizeBytes     int64      `gorm:"not null" json:"size_bytes"`
+	StorageKey    string     `gorm:"size:300;not null" json:"-"`
+	Description   string     `gorm:"size:300" json:"description,omitempty"`
+	StatusID      int        `gorm:"not null;default:1" json:"status_id"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (a *Attachment) BeforeCreate(tx *gorm.DB) (err error) {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+// Breed es el catálogo de razas de cada especie. Aliases guarda, separados
+// por comas, otros nombres con los que suele escribirse la raza. Cada
+// especie tiene una raza IsMixed ("Mestizo / Otra") que se usa junto con el
+// texto libre Pet.Breed cuando la raza no está en el catálogo.
+type Breed struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	SpeciesID int       `gorm:"not null;uniqueIndex:idx_breed_species_name" json:"species_id"`
+	Species   *Species  `gorm:"foreignKey:SpeciesID" json:"species,omitempty"`
+	Name      string    `gorm:"size:100;not null;uniqueIndex:idx_breed_species_name" json:"name"`
+	Aliases   string    `gorm:"size:300" json:"aliases,omitempty"`
+	IsMixed   bool      `gorm:"not null;default:false" json:"is_mixed"`
+	StatusID  int       `gorm:"not null;default:1" json:"status_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// LegacyBreedMatch es una mascota cuyo texto de raza corresponde a una raza
+// del catálogo.
+type LegacyBreedMatch struct {
+	Pet   Pet
+	Breed Breed
+}
+
+// LegacyBreedResult resume la migración del texto libre Pet.Breed al
+// catálogo de razas. Matched son las coincidencias exactas, que se guardan;
+// Suggested, las aproximadas, que no se guardan hasta que alguien las
+// confirme eligiendo la raza en la mascota.
+type LegacyBreedResult struct {
+	Scanned   int
+	Matched   []LegacyBreedMatch
+	Suggested []LegacyBreedMatch
+	Unmatched []Pet
+}
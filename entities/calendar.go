@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type VetTimeOff struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	VetID     uuid.UUID `gorm:"type:uuid;not null;index" json:"vet_id"`
+	Vet       User      `gorm:"foreignKey:VetID" json:"vet"`
+	StartDate time.Time `gorm:"type:date;not null" json:"start_date"`
+	EndDate   time.Time `gorm:"type:date;not null" json:"end_date"`
+	Reason    string    `gorm:"size:200" json:"reason,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type ClinicClosure struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Date        time.Time `gorm:"type:date;not null;uniqueIndex" json:"date"`
+	Description string    `gorm:"size:200;not null" json:"description"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type AvailabilitySlot struct {
+	Time      string
+	Available bool
+}
+
+func (t *VetTimeOff) BeforeCreate(tx *gorm.DB) (err error) {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return
+}
+
+func (c *ClinicClosure) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,51 @@
+package entities
+
+import "time"
+
+// MainClinicID es la clínica que se crea al iniciar la base de datos. Las
+// citas que no indican clínica quedan en ella.
+const MainClinicID = 1
+
+type Clinic struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string    `gorm:"size:100;not null;unique" json:"name"`
+	Address   string    `gorm:"size:200" json:"address"`
+	Phone     string    `gorm:"size:9" json:"phone"`
+	StatusID  int       `gorm:"not null;default:1" json:"status_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type Room struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ClinicID  int       `gorm:"not null;index" json:"clinic_id"`
+	Clinic    Clinic    `gorm:"foreignKey:ClinicID" json:"clinic"`
+	Name      string    `gorm:"size:80;not null" json:"name"`
+	Capacity  int       `gorm:"not null;default:1" json:"capacity"`
+	StatusID  int       `gorm:"not null;default:1" json:"status_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type Resource struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ClinicID  int       `gorm:"not null;index" json:"clinic_id"`
+	Clinic    Clinic    `gorm:"foreignKey:ClinicID" json:"clinic"`
+	Name      string    `gorm:"size:80;not null" json:"name"`
+	Quantity  int       `gorm:"not null;default:1" json:"quantity"`
+	StatusID  int       `gorm:"not null;default:1" json:"status_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// SlotQuery describe un horario a verificar contra las citas activas. Los
+// campos vacíos no se usan como filtro.
+type SlotQuery struct {
+	Date       string
+	Time       string
+	ExcludeID  string
+	ClinicID   *int
+	VetID      *string
+	RoomID     *int
+	ResourceID *int
+}
@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ClinicalNoteStatusDraft = 1
+	ClinicalNoteStatusFinal = 2
+)
+
+// ClinicalNote es la nota clínica SOAP de una cita. Se guarda como borrador
+// mientras la consulta está abierta y queda finalizada, sin más cambios, al
+// finalizar la cita o cuando el veterinario la cierra.
+type ClinicalNote struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	AppointmentID  uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"appointment_id"`
+	PetID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"pet_id"`
+	VetID          uuid.UUID  `gorm:"type:uuid;not null" json:"vet_id"`
+	Vet            User       `gorm:"foreignKey:VetID" json:"vet"`
+	TemplateID     *int       `json:"template_id,omitempty"`
+	Subjective     string     `gorm:"type:text" json:"subjective"`
+	Objective      string     `gorm:"type:text" json:"objective"`
+	Assessment     string     `gorm:"type:text" json:"assessment"`
+	Plan           string     `gorm:"type:text" json:"plan"`
+	DiagnosisCodes string     `gorm:"size:500" json:"diagnosis_codes,omitempty"`
+	StatusID       int        `gorm:"not null;default:1" json:"status_id"`
+	LastSavedAt    time.Time  `gorm:"not null" json:"last_saved_at"`
+	FinalizedAt    *time.Time `json:"finalized_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ClinicalNoteTemplate es una plantilla SOAP. Las plantillas sin VetID son de
+// la clínica y las ve todo el personal; las demás, solo su autor.
+type ClinicalNoteTemplate struct {
+	ID         int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	VetID      *uuid.UUID `gorm:"type:uuid;index" json:"vet_id,omitempty"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	Subjective string     `gorm:"type:text" json:"subjective"`
+	Objective  string     `gorm:"type:text" json:"objective"`
+	Assessment string     `gorm:"type:text" json:"assessment"`
+	Plan       string     `gorm:"type:text" json:"plan"`
+	StatusID   int        `gorm:"not null;default:1" json:"status_id"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Codes separa los códigos de diagnóstico guardados como lista separada por
+// comas.
+func (n *ClinicalNote) Codes() []string {
+	codes := []string{}
+	for _, code := range strings.Split(n.DiagnosisCodes, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+func (n *ClinicalNote) BeforeCreate(tx *gorm.DB) (err error) {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,80 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Diagnosis es el catálogo de diagnósticos codificados. SpeciesID vacío
+// indica que el diagnóstico aplica a cualquier especie.
+type Diagnosis struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Code      string    `gorm:"size:20;not null;unique" json:"code"`
+	Name      string    `gorm:"size:150;not null" json:"name"`
+	Category  string    `gorm:"size:50" json:"category,omitempty"`
+	SpeciesID *int      `json:"species_id,omitempty"`
+	Species   *Species  `gorm:"foreignKey:SpeciesID" json:"species,omitempty"`
+	StatusID  int       `gorm:"not null;default:1" json:"status_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// AppliesTo indica si el diagnóstico puede usarse para la especie.
+func (d *Diagnosis) AppliesTo(speciesID int) bool {
+	return d.SpeciesID == nil || *d.SpeciesID == speciesID
+}
+
+// AppointmentDiagnosis vincula un diagnóstico del catálogo a una cita. Cada
+// cita con diagnósticos tiene exactamente uno principal.
+type AppointmentDiagnosis struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	AppointmentID  uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_appointment_diagnosis" json:"appointment_id"`
+	DiagnosisID    int        `gorm:"not null;uniqueIndex:idx_appointment_diagnosis;index" json:"diagnosis_id"`
+	Diagnosis      Diagnosis  `gorm:"foreignKey:DiagnosisID" json:"diagnosis"`
+	IsPrimary      bool       `gorm:"not null;default:false" json:"is_primary"`
+	Notes          string     `gorm:"size:300" json:"notes,omitempty"`
+	RecordedByID   *uuid.UUID `gorm:"type:uuid" json:"recorded_by_id,omitempty"`
+	RecordedByName string     `gorm:"size:100" json:"recorded_by_name"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// DiagnosisReportFilter acota el reporte de diagnósticos a las citas
+// finalizadas del periodo.
+type DiagnosisReportFilter struct {
+	DateFrom    *time.Time
+	DateTo      *time.Time
+	SpeciesID   int
+	ClinicID    *int
+	PrimaryOnly bool
+}
+
+// DiagnosisCount resume cuántas veces se registró un diagnóstico y en
+// cuántas mascotas distintas.
+type DiagnosisCount struct {
+	DiagnosisID  int    `json:"diagnosis_id"`
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	Category     string `json:"category,omitempty"`
+	Total        int    `json:"total"`
+	PrimaryCount int    `json:"primary_count"`
+	Pets         int    `json:"pets"`
+}
+
+// HasDiagnosis indica si la cita tiene registrado el diagnóstico.
+func (a *Appointment) HasDiagnosis(diagnosisID int) bool {
+	for _, d := range a.Diagnoses {
+		if d.DiagnosisID == diagnosisID {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *AppointmentDiagnosis) BeforeCreate(tx *gorm.DB) (err error) {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return
+}
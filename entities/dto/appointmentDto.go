@@ -2,6 +2,7 @@ package dto
 
 import (
 	"VetiCare/entities"
+	"time"
 )
 
 type AppointmentDTO struct {
@@ -10,6 +11,12 @@ type AppointmentDTO struct {
 	Pet                   PetDTO   `json:"pet"`
 	VetID                 *string  `json:"vet_id,omitempty"`
 	Vet                   UserDTO  `json:"vet"`
+	ClinicID              *int     `json:"clinic_id,omitempty"`
+	ClinicName            string   `json:"clinic_name,omitempty"`
+	RoomID                *int     `json:"room_id,omitempty"`
+	RoomName              string   `json:"room_name,omitempty"`
+	ResourceID            *int     `json:"resource_id,omitempty"`
+	ResourceName          string   `json:"resource_name,omitempty"`
 	Date                  string   `json:"date"`
 	Time                  string   `json:"time"`
 	StatusID              int      `json:"status_id"`
@@ -20,8 +27,27 @@ type AppointmentDTO struct {
 	VaccinationStatus     string   `json:"vaccination_status,omitempty"`
 	MedicationsPrescribed string   `json:"medications_prescribed,omitempty"`
 	AdditionalNotes       string   `json:"additional_notes,omitempty"`
+	RescheduleCount       int      `json:"reschedule_count"`
+	DepositRequired       bool     `json:"deposit_required"`
+	ApprovalRequired      bool     `json:"approval_required"`
+	ApprovedByID          *string  `json:"approved_by_id,omitempty"`
+	ApprovedAt            *string  `json:"approved_at,omitempty"`
+	DepositPaidAt         *string  `json:"deposit_paid_at,omitempty"`
+	IsWalkIn              bool     `json:"is_walk_in"`
+	CheckedInAt           *string  `json:"checked_in_at,omitempty"`
+	StartedAt             *string  `json:"started_at,omitempty"`
+	FinishedAt            *string  `json:"finished_at,omitempty"`
+	RecordVersion         int      `json:"record_version"`
+	VersionsURL           string   `json:"versions_url,omitempty"`
 	CreatedAt             string   `json:"created_at"`
 	UpdatedAt             string   `json:"updated_at"`
+
+	Prescriptions []PrescriptionDTO `json:"prescriptions,omitempty"`
+	VitalFlags    []VitalFlagDTO    `json:"vital_flags,omitempty"`
+	ClinicalNote  *ClinicalNoteDTO  `json:"clinical_note,omitempty"`
+	LabOrders     []LabOrderDTO     `json:"lab_orders,omitempty"`
+
+	Diagnoses []AppointmentDiagnosisDTO `json:"diagnoses,omitempty"`
 }
 
 func NewAppointmentDTO(app *entities.Appointment) AppointmentDTO {
@@ -29,6 +55,9 @@ func NewAppointmentDTO(app *entities.Appointment) AppointmentDTO {
 		1: "Agendada",
 		2: "Finalizada",
 		3: "Cancelada",
+		4: "No asistió",
+		5: "En espera",
+		6: "En consulta",
 	}
 
 	statusText, ok := statusMap[app.StatusID]
@@ -42,12 +71,35 @@ func NewAppointmentDTO(app *entities.Appointment) AppointmentDTO {
 		vetID = &s
 	}
 
+	var approvedBy *string
+	if app.ApprovedByID != nil {
+		s := app.ApprovedByID.String()
+		approvedBy = &s
+	}
+
+	var clinicName, roomName, resourceName string
+	if app.Clinic != nil {
+		clinicName = app.Clinic.Name
+	}
+	if app.Room != nil {
+		roomName = app.Room.Name
+	}
+	if app.Resource != nil {
+		resourceName = app.Resource.Name
+	}
+
 	return AppointmentDTO{
 		ID:                    app.ID.String(),
 		PetID:                 app.PetID.String(),
 		Pet:                   ToPetDTO(&app.Pet),
 		VetID:                 vetID,
 		Vet:                   ToUserDTO(&app.Vet),
+		ClinicID:              app.ClinicID,
+		ClinicName:            clinicName,
+		RoomID:                app.RoomID,
+		RoomName:              roomName,
+		ResourceID:            app.ResourceID,
+		ResourceName:          resourceName,
 		Date:                  app.Date,
 		Time:                  app.Time,
 		StatusID:              app.StatusID,
@@ -58,7 +110,99 @@ func NewAppointmentDTO(app *entities.Appointment) AppointmentDTO {
 		VaccinationStatus:     app.VaccinationStatus,
 		MedicationsPrescribed: app.MedicationsPrescribed,
 		AdditionalNotes:       app.AdditionalNotes,
+		RescheduleCount:       app.RescheduleCount,
+		DepositRequired:       app.DepositRequired,
+		ApprovalRequired:      app.ApprovalRequired,
+		ApprovedByID:          approvedBy,
+		ApprovedAt:            formatOptionalTime(app.ApprovedAt),
+		DepositPaidAt:         formatOptionalTime(app.DepositPaidAt),
+		IsWalkIn:              app.IsWalkIn,
+		CheckedInAt:           formatOptionalTime(app.CheckedInAt),
+		StartedAt:             formatOptionalTime(app.StartedAt),
+		FinishedAt:            formatOptionalTime(app.FinishedAt),
+		RecordVersion:         app.RecordVersion,
+		VersionsURL:           recordVersionsURL(app),
+		Prescriptions:         prescriptionDTOsOrNil(app.Prescriptions),
+		VitalFlags:            vitalFlagDTOsOrNil(app.VitalFlags()),
+		ClinicalNote:          clinicalNoteDTOOrNil(app.ClinicalNote),
+		LabOrders:             labOrderDTOsOrNil(app.LabOrders),
+		Diagnoses:             appointmentDiagnosisDTOsOrNil(app.Diagnoses),
 		CreatedAt:             app.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:             app.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
+
+// recordVersionsURL enlaza las versiones anteriores de las citas enmendadas.
+func recordVersionsURL(app *entities.Appointment) string {
+	if app.RecordVersion <= 1 {
+		return ""
+	}
+	return "/api/appointments/" + app.ID.String() + "/versions"
+}
+
+func prescriptionDTOsOrNil(list []entities.Prescription) []PrescriptionDTO {
+	if len(list) == 0 {
+		return nil
+	}
+	return ToPrescriptionDTOs(list)
+}
+
+func formatOptionalDate(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format("02-01-2006")
+	return &s
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format("2006-01-02 15:04:05")
+	return &s
+}
+
+type WalkInDTO struct {
+	PetID    string  `json:"pet_id"`
+	VetID    *string `json:"vet_id,omitempty"`
+	ClinicID *int    `json:"clinic_id,omitempty"`
+	Reason   string  `json:"reason,omitempty"`
+}
+
+type RescheduleAppointmentDTO struct {
+	Date   string `json:"date"`
+	Time   string `json:"time"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type AppointmentRescheduleDTO struct {
+	ID            string  `json:"id"`
+	AppointmentID string  `json:"appointment_id"`
+	PreviousDate  string  `json:"previous_date"`
+	PreviousTime  string  `json:"previous_time"`
+	NewDate       string  `json:"new_date"`
+	NewTime       string  `json:"new_time"`
+	Reason        string  `json:"reason,omitempty"`
+	RequestedByID *string `json:"requested_by_id,omitempty"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+func ToAppointmentRescheduleDTO(r *entities.AppointmentReschedule) AppointmentRescheduleDTO {
+	var requestedBy *string
+	if r.RequestedByID != nil {
+		s := r.RequestedByID.String()
+		requestedBy = &s
+	}
+	return AppointmentRescheduleDTO{
+		ID:            r.ID.String(),
+		AppointmentID: r.AppointmentID.String(),
+		PreviousDate:  r.PreviousDate,
+		PreviousTime:  r.PreviousTime,
+		NewDate:       r.NewDate,
+		NewTime:       r.NewTime,
+		Reason:        r.Reason,
+		RequestedByID: requestedBy,
+		CreatedAt:     r.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
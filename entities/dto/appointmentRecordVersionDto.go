@@ -0,0 +1,119 @@
+package dto
+
+import (
+	"VetiCare/entities"
+	"strconv"
+	"strings"
+)
+
+// AppointmentAmendmentInputDTO trae solo los campos que se corrigen; los
+// omitidos conservan el valor de la versión vigente.
+type AppointmentAmendmentInputDTO struct {
+	AmendmentReason       string    `json:"amendment_reason"`
+	Reason                *string   `json:"reason,omitempty"`
+	WeightKg              *float64  `json:"weight_kg,omitempty"`
+	Temperature           *float64  `json:"temperature,omitempty"`
+	VaccinationStatus     *string   `json:"vaccination_status,omitempty"`
+	MedicationsPrescribed *string   `json:"medications_prescribed,omitempty"`
+	AdditionalNotes       *string   `json:"additional_notes,omitempty"`
+	Subjective            *string   `json:"subjective,omitempty"`
+	Objective             *string   `json:"objective,omitempty"`
+	Assessment            *string   `json:"assessment,omitempty"`
+	Plan                  *string   `json:"plan,omitempty"`
+	DiagnosisCodes        *[]string `json:"diagnosis_codes,omitempty"`
+}
+
+// Changes convierte la enmienda en el mapa de campos que espera el servicio.
+func (in AppointmentAmendmentInputDTO) Changes() map[string]interface{} {
+	changes := map[string]interface{}{}
+	texts := map[string]*string{
+		"reason":                 in.Reason,
+		"vaccination_status":     in.VaccinationStatus,
+		"medications_prescribed": in.MedicationsPrescribed,
+		"additional_notes":       in.AdditionalNotes,
+		"subjective":             in.Subjective,
+		"objective":              in.Objective,
+		"assessment":             in.Assessment,
+		"plan":                   in.Plan,
+	}
+	for key, value := range texts {
+		if value != nil {
+			changes[key] = strings.TrimSpace(*value)
+		}
+	}
+	if in.WeightKg != nil {
+		changes["weight_kg"] = *in.WeightKg
+	}
+	if in.Temperature != nil {
+		changes["temperature"] = *in.Temperature
+	}
+	if in.DiagnosisCodes != nil {
+		changes["diagnosis_codes"] = JoinDiagnosisCodes(*in.DiagnosisCodes)
+	}
+	return changes
+}
+
+type AppointmentRecordVersionDTO struct {
+	AppointmentID         string                `json:"appointment_id"`
+	Version               int                   `json:"version"`
+	Reason                string                `json:"reason,omitempty"`
+	WeightKg              *float64              `json:"weight_kg,omitempty"`
+	Temperature           *float64              `json:"temperature,omitempty"`
+	VaccinationStatus     string                `json:"vaccination_status,omitempty"`
+	MedicationsPrescribed string                `json:"medications_prescribed,omitempty"`
+	AdditionalNotes       string                `json:"additional_notes,omitempty"`
+	ClinicalNote          *RecordVersionNoteDTO `json:"clinical_note,omitempty"`
+	AuthorID              *string               `json:"author_id,omitempty"`
+	AuthorName            string                `json:"author_name"`
+	AmendmentReason       string                `json:"amendment_reason,omitempty"`
+	CreatedAt             string                `json:"created_at"`
+	URL                   string                `json:"url"`
+}
+
+type RecordVersionNoteDTO struct {
+	Subjective     string   `json:"subjective"`
+	Objective      string   `json:"objective"`
+	Assessment     string   `json:"assessment"`
+	Plan           string   `json:"plan"`
+	DiagnosisCodes []string `json:"diagnosis_codes"`
+}
+
+func ToAppointmentRecordVersionDTO(v *entities.AppointmentRecordVersion) AppointmentRecordVersionDTO {
+	result := AppointmentRecordVersionDTO{
+		AppointmentID:         v.AppointmentID.String(),
+		Version:               v.Version,
+		Reason:                v.Reason,
+		WeightKg:              v.WeightKg,
+		Temperature:           v.Temperature,
+		VaccinationStatus:     v.VaccinationStatus,
+		MedicationsPrescribed: v.MedicationsPrescribed,
+		AdditionalNotes:       v.AdditionalNotes,
+		AuthorName:            v.AuthorName,
+		AmendmentReason:       v.AmendmentReason,
+		CreatedAt:             v.CreatedAt.Format("2006-01-02 15:04:05"),
+		URL:                   "/api/appointments/" + v.AppointmentID.String() + "/versions/" + strconv.Itoa(v.Version),
+	}
+	if v.AuthorID != nil {
+		id := v.AuthorID.String()
+		result.AuthorID = &id
+	}
+	if v.HasClinicalNote {
+		note := entities.ClinicalNote{DiagnosisCodes: v.DiagnosisCodes}
+		result.ClinicalNote = &RecordVersionNoteDTO{
+			Subjective:     v.Subjective,
+			Objective:      v.Objective,
+			Assessment:     v.Assessment,
+			Plan:           v.Plan,
+			DiagnosisCodes: note.Codes(),
+		}
+	}
+	return result
+}
+
+func ToAppointmentRecordVersionDTOs(list []entities.AppointmentRecordVersion) []AppointmentRecordVersionDTO {
+	dtos := []AppointmentRecordVersionDTO{}
+	for _, v := range list {
+		dtos = append(dtos, ToAppointmentRecordVersionDTO(&v))
+	}
+	return dtos
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"VetiCare/entities"
+)
+
+type AttachmentDTO struct {
+	ID            string  `json:"id"`
+	PetID         string  `json:"pet_id"`
+	AppointmentID *string `json:"appointment_id,omitempty"`
+	UploadedByID  string  `json:"uploaded_by_id"`
+	FileName      string  `json:"file_name"`
+	ContentType   string  `json:"content_type"`
+	SizeBytes     int64   `json:"size_bytes"`
+	Description   string  `json:"description,omitempty"`
+	DownloadURL   string  `json:"download_url"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+func ToAttachmentDTO(a *entities.Attachment) AttachmentDTO {
+	result := AttachmentDTO{
+		ID:           a.ID.String(),
+		PetID:        a.PetID.String(),
+		UploadedByID: a.UploadedByID.String(),
+		FileName:     a.FileName,
+		ContentType:  a.ContentType,
+		SizeBytes:    a.SizeBytes,
+		Description:  a.Description,
+		DownloadURL:  "/api/attachments/" + a.ID.String() + "/file",
+		CreatedAt:    a.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if a.AppointmentID != nil {
+		id := a.AppointmentID.String()
+		result.AppointmentID = &id
+	}
+	return result
+}
+
+func ToAttachmentDTOs(list []entities.Attachment) []AttachmentDTO {
+	dtos := []AttachmentDTO{}
+	for _, a := range list {
+		dtos = append(dtos, ToAttachmentDTO(&a))
+	}
+	return dtos
+}
@@ -0,0 +1,85 @@
+package dto
+
+import "VetiCare/entities"
+
+type BreedDTO struct {
+	ID        int    `json:"id"`
+	SpeciesID int    `json:"species_id"`
+	Name      string `json:"name"`
+	Aliases   string `json:"aliases,omitempty"`
+	IsMixed   bool   `json:"is_mixed"`
+	StatusID  int    `json:"status_id"`
+	Status    string `json:"status"`
+}
+
+type LegacyBreedItemDTO struct {
+	PetID       string `json:"pet_id"`
+	PetName     string `json:"pet_name"`
+	SpeciesName string `json:"species_name"`
+	Breed       string `json:"breed"`
+	BreedID     int    `json:"breed_id,omitempty"`
+	BreedName   string `json:"breed_name,omitempty"`
+}
+
+type LegacyBreedResultDTO struct {
+	DryRun    bool                 `json:"dry_run"`
+	Scanned   int                  `json:"scanned"`
+	Matched   []LegacyBreedItemDTO `json:"matched"`
+	Suggested []LegacyBreedItemDTO `json:"suggested"`
+	Unmatched []LegacyBreedItemDTO `json:"unmatched"`
+}
+
+func ToBreedDTO(b *entities.Breed) BreedDTO {
+	status := "Inactiva"
+	if b.StatusID == 1 {
+		status = "Activa"
+	}
+	return BreedDTO{
+		ID:        b.ID,
+		SpeciesID: b.SpeciesID,
+		Name:      b.Name,
+		Aliases:   b.Aliases,
+		IsMixed:   b.IsMixed,
+		StatusID:  b.StatusID,
+		Status:    status,
+	}
+}
+
+func ToLegacyBreedResultDTO(result *entities.LegacyBreedResult, dryRun bool) LegacyBreedResultDTO {
+	out := LegacyBreedResultDTO{
+		DryRun:    dryRun,
+		Scanned:   result.Scanned,
+		Matched:   []LegacyBreedItemDTO{},
+		Suggested: []LegacyBreedItemDTO{},
+		Unmatched: []LegacyBreedItemDTO{},
+	}
+	for _, m := range result.Matched {
+		out.Matched = append(out.Matched, legacyBreedMatchItem(&m))
+	}
+	for _, m := range result.Suggested {
+		out.Suggested = append(out.Suggested, legacyBreedMatchItem(&m))
+	}
+	for _, pet := range result.Unmatched {
+		out.Unmatched = append(out.Unmatched, legacyBreedItem(&pet))
+	}
+	return out
+}
+
+func legacyBreedMatchItem(m *entities.LegacyBreedMatch) LegacyBreedItemDTO {
+	item := legacyBreedItem(&m.Pet)
+	item.BreedID = m.Breed.ID
+	item.BreedName = m.Breed.Name
+	return item
+}
+
+func legacyBreedItem(pet *entities.Pet) LegacyBreedItemDTO {
+	item := LegacyBreedItemDTO{
+		PetID:       pet.ID.String(),
+		PetName:     pet.Name,
+		SpeciesName: pet.Species.Name,
+	}
+	if pet.Breed != nil {
+		item.Breed = *pet.Breed
+	}
+	return item
+}
@@ -0,0 +1,79 @@
+package dto
+
+import "VetiCare/entities"
+
+type TimeOffInputDTO struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type VetTimeOffDTO struct {
+	ID        string `json:"id"`
+	VetID     string `json:"vet_id"`
+	VetName   string `json:"vet_name"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Reason    string `json:"reason,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+type ClinicClosureInputDTO struct {
+	Date        string `json:"date"`
+	Description string `json:"description"`
+}
+
+type ClinicClosureDTO struct {
+	ID          string `json:"id"`
+	Date        string `json:"date"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type AvailabilitySlotDTO struct {
+	Time      string `json:"time"`
+	Available bool   `json:"available"`
+}
+
+type AvailabilityDTO struct {
+	VetID string                `json:"vet_id"`
+	Date  string                `json:"date"`
+	Slots []AvailabilitySlotDTO `json:"slots"`
+}
+
+func ToVetTimeOffDTO(t *entities.VetTimeOff) VetTimeOffDTO {
+	return VetTimeOffDTO{
+		ID:        t.ID.String(),
+		VetID:     t.VetID.String(),
+		VetName:   t.Vet.FullName,
+		StartDate: t.StartDate.Format("02-01-2006"),
+		EndDate:   t.EndDate.Format("02-01-2006"),
+		Reason:    t.Reason,
+		CreatedAt: t.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func ToClinicClosureDTO(c *entities.ClinicClosure) ClinicClosureDTO {
+	return ClinicClosureDTO{
+		ID:          c.ID.String(),
+		Date:        c.Date.Format("02-01-2006"),
+		Description: c.Description,
+		CreatedAt:   c.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func ToAvailabilityDTO(vetID, date string, slots []entities.AvailabilitySlot) AvailabilityDTO {
+	result := AvailabilityDTO{VetID: vetID, Date: date, Slots: []AvailabilitySlotDTO{}}
+	for _, slot := range slots {
+		result.Slots = append(result.Slots, AvailabilitySlotDTO{Time: slot.Time, Available: slot.Available})
+	}
+	return result
+}
+
+func ToAppointmentDTOs(apps []entities.Appointment) []AppointmentDTO {
+	dtos := []AppointmentDTO{}
+	for _, app := range apps {
+		dtos = append(dtos, NewAppointmentDTO(&app))
+	}
+	return dtos
+}
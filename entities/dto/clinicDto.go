@@ -0,0 +1,80 @@
+package dto
+
+import "VetiCare/entities"
+
+type ClinicDTO struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Phone    string `json:"phone"`
+	StatusID int    `json:"status_id"`
+	Status   string `json:"status"`
+}
+
+type ClinicSummaryDTO struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type RoomDTO struct {
+	ID       int    `json:"id"`
+	ClinicID int    `json:"clinic_id"`
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"`
+	StatusID int    `json:"status_id"`
+}
+
+type ResourceDTO struct {
+	ID       int    `json:"id"`
+	ClinicID int    `json:"clinic_id"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+	StatusID int    `json:"status_id"`
+}
+
+type VetClinicsDTO struct {
+	ClinicIDs []int `json:"clinic_ids"`
+}
+
+func ToClinicDTO(c *entities.Clinic) ClinicDTO {
+	statusText := "Inactiva"
+	if c.StatusID == 1 {
+		statusText = "Activa"
+	}
+	return ClinicDTO{
+		ID:       c.ID,
+		Name:     c.Name,
+		Address:  c.Address,
+		Phone:    c.Phone,
+		StatusID: c.StatusID,
+		Status:   statusText,
+	}
+}
+
+func ToClinicSummaryDTOs(clinics []entities.Clinic) []ClinicSummaryDTO {
+	var result []ClinicSummaryDTO
+	for _, c := range clinics {
+		result = append(result, ClinicSummaryDTO{ID: c.ID, Name: c.Name})
+	}
+	return result
+}
+
+func ToRoomDTO(r *entities.Room) RoomDTO {
+	return RoomDTO{
+		ID:       r.ID,
+		ClinicID: r.ClinicID,
+		Name:     r.Name,
+		Capacity: r.Capacity,
+		StatusID: r.StatusID,
+	}
+}
+
+func ToResourceDTO(r *entities.Resource) ResourceDTO {
+	return ResourceDTO{
+		ID:       r.ID,
+		ClinicID: r.ClinicID,
+		Name:     r.Name,
+		Quantity: r.Quantity,
+		StatusID: r.StatusID,
+	}
+}
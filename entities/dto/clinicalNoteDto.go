@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"VetiCare/entities"
+	"strings"
+)
+
+type ClinicalNoteInputDTO struct {
+	TemplateID     *int     `json:"template_id,omitempty"`
+	Subjective     string   `json:"subjective"`
+	Objective      string   `json:"objective"`
+	Assessment     string   `json:"assessment"`
+	Plan           string   `json:"plan"`
+	DiagnosisCodes []string `json:"diagnosis_codes,omitempty"`
+}
+
+type ClinicalNoteDTO struct {
+	ID             string   `json:"id"`
+	AppointmentID  string   `json:"appointment_id"`
+	PetID          string   `json:"pet_id"`
+	VetID          string   `json:"vet_id"`
+	VetName        string   `json:"vet_name,omitempty"`
+	TemplateID     *int     `json:"template_id,omitempty"`
+	Subjective     string   `json:"subjective"`
+	Objective      string   `json:"objective"`
+	Assessment     string   `json:"assessment"`
+	Plan           string   `json:"plan"`
+	DiagnosisCodes []string `json:"diagnosis_codes"`
+	StatusID       int      `json:"status_id"`
+	Status         string   `json:"status"`
+	LastSavedAt    string   `json:"last_saved_at"`
+	FinalizedAt    *string  `json:"finalized_at,omitempty"`
+}
+
+type ClinicalNoteTemplateDTO struct {
+	ID         int     `json:"id"`
+	VetID      *string `json:"vet_id,omitempty"`
+	Shared     bool    `json:"shared"`
+	Name       string  `json:"name"`
+	Subjective string  `json:"subjective"`
+	Objective  string  `json:"objective"`
+	Assessment string  `json:"assessment"`
+	Plan       string  `json:"plan"`
+	StatusID   int     `json:"status_id"`
+}
+
+// JoinDiagnosisCodes normaliza los códigos y los une en el formato en que se
+// guardan en la nota.
+func JoinDiagnosisCodes(codes []string) string {
+	cleaned := []string{}
+	for _, code := range codes {
+		if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+			cleaned = append(cleaned, code)
+		}
+	}
+	return strings.Join(cleaned, ",")
+}
+
+func ToClinicalNoteDTO(n *entities.ClinicalNote) ClinicalNoteDTO {
+	status := "Borrador"
+	if n.StatusID == entities.ClinicalNoteStatusFinal {
+		status = "Finalizada"
+	}
+	return ClinicalNoteDTO{
+		ID:             n.ID.String(),
+		AppointmentID:  n.AppointmentID.String(),
+		PetID:          n.PetID.String(),
+		VetID:          n.VetID.String(),
+		VetName:        n.Vet.FullName,
+		TemplateID:     n.TemplateID,
+		Subjective:     n.Subjective,
+		Objective:      n.Objective,
+		Assessment:     n.Assessment,
+		Plan:           n.Plan,
+		DiagnosisCodes: n.Codes(),
+		StatusID:       n.StatusID,
+		Status:         status,
+		LastSavedAt:    n.LastSavedAt.Format("2006-01-02 15:04:05"),
+		FinalizedAt:    formatOptionalTime(n.FinalizedAt),
+	}
+}
+
+func clinicalNoteDTOOrNil(n *entities.ClinicalNote) *ClinicalNoteDTO {
+	if n == nil {
+		return nil
+	}
+	result := ToClinicalNoteDTO(n)
+	return &result
+}
+
+func ToClinicalNoteTemplateDTO(t *entities.ClinicalNoteTemplate) ClinicalNoteTemplateDTO {
+	result := ClinicalNoteTemplateDTO{
+		ID:         t.ID,
+		Shared:     t.VetID == nil,
+		Name:       t.Name,
+		Subjective: t.Subjective,
+		Objective:  t.Objective,
+		Assessment: t.Assessment,
+		Plan:       t.Plan,
+		StatusID:   t.StatusID,
+	}
+	if t.VetID != nil {
+		id := t.VetID.String()
+		result.VetID = &id
+	}
+	return result
+}
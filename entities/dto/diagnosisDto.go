@@ -0,0 +1,89 @@
+package dto
+
+import "VetiCare/entities"
+
+type DiagnosisDTO struct {
+	ID          int    `json:"id"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Category    string `json:"category,omitempty"`
+	SpeciesID   *int   `json:"species_id,omitempty"`
+	SpeciesName string `json:"species_name,omitempty"`
+	StatusID    int    `json:"status_id"`
+	Status      string `json:"status"`
+}
+
+type AppointmentDiagnosisInputDTO struct {
+	DiagnosisID int    `json:"diagnosis_id"`
+	IsPrimary   bool   `json:"is_primary"`
+	Notes       string `json:"notes,omitempty"`
+}
+
+type AppointmentDiagnosesInputDTO struct {
+	Diagnoses []AppointmentDiagnosisInputDTO `json:"diagnoses"`
+}
+
+type AppointmentDiagnosisDTO struct {
+	ID             string `json:"id"`
+	DiagnosisID    int    `json:"diagnosis_id"`
+	Code           string `json:"code"`
+	Name           string `json:"name"`
+	Category       string `json:"category,omitempty"`
+	IsPrimary      bool   `json:"is_primary"`
+	Notes          string `json:"notes,omitempty"`
+	RecordedByName string `json:"recorded_by_name"`
+	CreatedAt      string `json:"created_at"`
+}
+
+func ToDiagnosisDTO(d *entities.Diagnosis) DiagnosisDTO {
+	status := "Inactivo"
+	if d.StatusID == 1 {
+		status = "Activo"
+	}
+	result := DiagnosisDTO{
+		ID:        d.ID,
+		Code:      d.Code,
+		Name:      d.Name,
+		Category:  d.Category,
+		SpeciesID: d.SpeciesID,
+		StatusID:  d.StatusID,
+		Status:    status,
+	}
+	if d.Species != nil {
+		result.SpeciesName = d.Species.Name
+	}
+	return result
+}
+
+func ToDiagnosisDTOs(list []entities.Diagnosis) []DiagnosisDTO {
+	dtos := []DiagnosisDTO{}
+	for i := range list {
+		dtos = append(dtos, ToDiagnosisDTO(&list[i]))
+	}
+	return dtos
+}
+
+func ToAppointmentDiagnosisDTOs(list []entities.AppointmentDiagnosis) []AppointmentDiagnosisDTO {
+	dtos := []AppointmentDiagnosisDTO{}
+	for _, d := range list {
+		dtos = append(dtos, AppointmentDiagnosisDTO{
+			ID:             d.ID.String(),
+			DiagnosisID:    d.DiagnosisID,
+			Code:           d.Diagnosis.Code,
+			Name:           d.Diagnosis.Name,
+			Category:       d.Diagnosis.Category,
+			IsPrimary:      d.IsPrimary,
+			Notes:          d.Notes,
+			RecordedByName: d.RecordedByName,
+			CreatedAt:      d.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return dtos
+}
+
+func appointmentDiagnosisDTOsOrNil(list []entities.AppointmentDiagnosis) []AppointmentDiagnosisDTO {
+	if len(list) == 0 {
+		return nil
+	}
+	return ToAppointmentDiagnosisDTOs(list)
+}
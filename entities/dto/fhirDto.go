@@ -0,0 +1,738 @@
+package dto
+
+import (
+	"VetiCare/entities"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Formato de intercambio de historiales
+//
+// El historial de una mascota se intercambia como un Bundle de tipo
+// "collection" con recursos modelados sobre HL7 FHIR R4. Solo se usan los
+// campos que VetiCare entiende; los demás se ignoran al importar.
+//
+//   - Patient: la mascota. Nombre, fecha de nacimiento y la extensión
+//     patient-animal con la especie y la raza como texto. El microchip, si
+//     lo tiene, va como identifier con system FHIRSystemMicrochip.
+//   - RelatedPerson: el dueño, con relationship OWN. El DUI va como
+//     identifier con system FHIRSystemDUI y es obligatorio.
+//   - Encounter: cada cita finalizada. period.start es la fecha y hora de la
+//     cita; reasonCode lleva el motivo y los diagnósticos codificados; las
+//     notas adicionales van en la extensión FHIRExtensionNotes.
+//   - Observation: un signo vital por recurso, codificado con LOINC y con el
+//     código propio de VetiCare (weight_kg, temperature, ...).
+//   - MedicationRequest: un medicamento recetado, con la dosis como texto.
+//   - Immunization: una vacuna aplicada, con lote, fabricante y dosis.
+//
+// Las referencias usan la forma "Tipo/id" o el fullUrl "urn:uuid:id" de la
+// entrada.
+const (
+	FHIRSystemDUI         = "urn:veticare:dui"
+	FHIRSystemPet         = "urn:veticare:pet"
+	FHIRSystemMicrochip   = "urn:veticare:microchip"
+	FHIRSystemVital       = "urn:veticare:vital"
+	FHIRSystemDiagnosis   = "urn:veticare:diagnosis"
+	FHIRSystemLOINC       = "http://loinc.org"
+	FHIRSystemUCUM        = "http://unitsofmeasure.org"
+	FHIRSystemRoleCode    = "http://terminology.hl7.org/CodeSystem/v3-RoleCode"
+	FHIRSystemActCode     = "http://terminology.hl7.org/CodeSystem/v3-ActCode"
+	FHIRExtensionAnimal   = "http://hl7.org/fhir/StructureDefinition/patient-animal"
+	FHIRExtensionNotes    = "urn:veticare:encounter-notes"
+	FHIRDateTimeLayout    = time.RFC3339
+	FHIRDateLayout        = "2006-01-02"
+	fhirRoleCodeOwner     = "OWN"
+	fhirEncounterFinished = "finished"
+
+	fhirVitalBodyCondition = "body_condition_score"
+)
+
+var ErrInvalidFHIRBundle = errors.New("el archivo no es un Bundle válido")
+
+type FHIRBundle struct {
+	ResourceType string            `json:"resourceType"`
+	ID           string            `json:"id,omitempty"`
+	Type         string            `json:"type"`
+	Timestamp    string            `json:"timestamp,omitempty"`
+	Entry        []FHIRBundleEntry `json:"entry"`
+}
+
+type FHIRBundleEntry struct {
+	FullURL  string          `json:"fullUrl,omitempty"`
+	Resource json.RawMessage `json:"resource"`
+}
+
+type FHIRCoding struct {
+	System  string `json:"system,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+type FHIRCodeableConcept struct {
+	Coding []FHIRCoding `json:"coding,omitempty"`
+	Text   string       `json:"text,omitempty"`
+}
+
+type FHIRIdentifier struct {
+	System string `json:"system,omitempty"`
+	Value  string `json:"value"`
+}
+
+type FHIRReference struct {
+	Reference string `json:"reference,omitempty"`
+	Display   string `json:"display,omitempty"`
+}
+
+type FHIRHumanName struct {
+	Text string `json:"text"`
+}
+
+type FHIRContactPoint struct {
+	System string `json:"system"`
+	Value  string `json:"value"`
+}
+
+type FHIRQuantity struct {
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit,omitempty"`
+	System string  `json:"system,omitempty"`
+	Code   string  `json:"code,omitempty"`
+}
+
+type FHIRPeriod struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+type FHIRAnnotation struct {
+	Text string `json:"text"`
+}
+
+type FHIRExtension struct {
+	URL                  string               `json:"url"`
+	ValueString          string               `json:"valueString,omitempty"`
+	ValueCodeableConcept *FHIRCodeableConcept `json:"valueCodeableConcept,omitempty"`
+	Extension            []FHIRExtension      `json:"extension,omitempty"`
+}
+
+type FHIRPatient struct {
+	ResourceType string           `json:"resourceType"`
+	ID           string           `json:"id,omitempty"`
+	Identifier   []FHIRIdentifier `json:"identifier,omitempty"`
+	Active       *bool            `json:"active,omitempty"`
+	Name         []FHIRHumanName  `json:"name"`
+	BirthDate    string           `json:"birthDate,omitempty"`
+	Extension    []FHIRExtension  `json:"extension,omitempty"`
+}
+
+type FHIRRelatedPerson struct {
+	ResourceType string                `json:"resourceType"`
+	ID           string                `json:"id,omitempty"`
+	Identifier   []FHIRIdentifier      `json:"identifier"`
+	Patient      FHIRReference         `json:"patient"`
+	Relationship []FHIRCodeableConcept `json:"relationship,omitempty"`
+	Name         []FHIRHumanName       `json:"name"`
+	Telecom      []FHIRContactPoint    `json:"telecom,omitempty"`
+}
+
+type FHIREncounterParticipant struct {
+	Individual FHIRReference `json:"individual"`
+}
+
+type FHIREncounter struct {
+	ResourceType    string                     `json:"resourceType"`
+	ID              string                     `json:"id,omitempty"`
+	Status          string                     `json:"status"`
+	Class           FHIRCoding                 `json:"class"`
+	Subject         FHIRReference              `json:"subject"`
+	Participant     []FHIREncounterParticipant `json:"participant,omitempty"`
+	Period          FHIRPeriod                 `json:"period"`
+	ReasonCode      []FHIRCodeableConcept      `json:"reasonCode,omitempty"`
+	ServiceProvider *FHIRReference             `json:"serviceProvider,omitempty"`
+	Extension       []FHIRExtension            `json:"extension,omitempty"`
+}
+
+type FHIRObservation struct {
+	ResourceType      string              `json:"resourceType"`
+	ID                string              `json:"id,omitempty"`
+	Status            string              `json:"status"`
+	Code              FHIRCodeableConcept `json:"code"`
+	Subject           FHIRReference       `json:"subject"`
+	Encounter         *FHIRReference      `json:"encounter,omitempty"`
+	EffectiveDateTime string              `json:"effectiveDateTime"`
+	ValueQuantity     *FHIRQuantity       `json:"valueQuantity,omitempty"`
+}
+
+type FHIRDosage struct {
+	Text string `json:"text"`
+}
+
+type FHIRMedicationRequest struct {
+	ResourceType              string              `json:"resourceType"`
+	ID                        string              `json:"id,omitempty"`
+	Status                    string              `json:"status"`
+	Intent                    string              `json:"intent"`
+	MedicationCodeableConcept FHIRCodeableConcept `json:"medicationCodeableConcept"`
+	Subject                   FHIRReference       `json:"subject"`
+	Encounter                 *FHIRReference      `json:"encounter,omitempty"`
+	AuthoredOn                string              `json:"authoredOn,omitempty"`
+	Requester                 *FHIRReference      `json:"requester,omitempty"`
+	DosageInstruction         []FHIRDosage        `json:"dosageInstruction,omitempty"`
+}
+
+type FHIRProtocolApplied struct {
+	DoseNumberPositiveInt int `json:"doseNumberPositiveInt"`
+}
+
+type FHIRPerformer struct {
+	Actor FHIRReference `json:"actor"`
+}
+
+type FHIRImmunization struct {
+	ResourceType       string                `json:"resourceType"`
+	ID                 string                `json:"id,omitempty"`
+	Status             string                `json:"status"`
+	VaccineCode        FHIRCodeableConcept   `json:"vaccineCode"`
+	Patient            FHIRReference         `json:"patient"`
+	Encounter          *FHIRReference        `json:"encounter,omitempty"`
+	OccurrenceDateTime string                `json:"occurrenceDateTime"`
+	LotNumber          string                `json:"lotNumber,omitempty"`
+	Manufacturer       *FHIRReference        `json:"manufacturer,omitempty"`
+	ProtocolApplied    []FHIRProtocolApplied `json:"protocolApplied,omitempty"`
+	Performer          []FHIRPerformer       `json:"performer,omitempty"`
+	Note               []FHIRAnnotation      `json:"note,omitempty"`
+}
+
+// FHIRRecord son los recursos del Bundle ya separados por tipo. Ignored
+// cuenta los recursos de tipos que VetiCare no importa.
+type FHIRRecord struct {
+	Patients           []FHIRPatient
+	RelatedPersons     []FHIRRelatedPerson
+	Encounters         []FHIREncounter
+	Observations       []FHIRObservation
+	MedicationRequests []FHIRMedicationRequest
+	Immunizations      []FHIRImmunization
+	Ignored            map[string]int
+}
+
+type RecordImportResultDTO struct {
+	DryRun               bool     `json:"dry_run"`
+	OwnerID              string   `json:"owner_id,omitempty"`
+	OwnerCreated         bool     `json:"owner_created"`
+	PetID                string   `json:"pet_id,omitempty"`
+	PetCreated           bool     `json:"pet_created"`
+	EncountersCreated    int      `json:"encounters_created"`
+	EncountersSkipped    int      `json:"encounters_skipped"`
+	ObservationsCreated  int      `json:"observations_created"`
+	ObservationsSkipped  int      `json:"observations_skipped"`
+	ImmunizationsCreated int      `json:"immunizations_created"`
+	ImmunizationsSkipped int      `json:"immunizations_skipped"`
+	MedicationsImported  int      `json:"medications_imported"`
+	MedicationsSkipped   int      `json:"medications_skipped"`
+	Warnings             []string `json:"warnings"`
+}
+
+// FHIRReferenceID devuelve el id al que apunta una referencia "Tipo/id" o
+// "urn:uuid:id".
+func FHIRReferenceID(ref string) string {
+	ref = strings.TrimPrefix(ref, "urn:uuid:")
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		return ref[i+1:]
+	}
+	return ref
+}
+
+// ParseFHIRDateTime acepta un dateTime completo o solo la fecha, como
+// permite FHIR.
+func ParseFHIRDateTime(value string) (time.Time, error) {
+	if t, err := time.Parse(FHIRDateTimeLayout, value); err == nil {
+		return t.In(time.Local), nil
+	}
+	return time.ParseInLocation(FHIRDateLayout, value, time.Local)
+}
+
+// IdentifierValue devuelve el valor del identificador con el system indicado.
+func (p FHIRRelatedPerson) IdentifierValue(system string) string {
+	return fhirIdentifierValue(p.Identifier, system)
+}
+
+func (p FHIRPatient) IdentifierValue(system string) string {
+	return fhirIdentifierValue(p.Identifier, system)
+}
+
+func fhirIdentifierValue(list []FHIRIdentifier, system string) string {
+	for _, id := range list {
+		if id.System == system {
+			return strings.TrimSpace(id.Value)
+		}
+	}
+	return ""
+}
+
+// TelecomValue devuelve el primer medio de contacto del tipo indicado.
+func (p FHIRRelatedPerson) TelecomValue(system string) string {
+	for _, c := range p.Telecom {
+		if c.System == system {
+			return strings.TrimSpace(c.Value)
+		}
+	}
+	return ""
+}
+
+// IsOwner indica si la persona es el dueño según relationship. Si no trae
+// relationship se asume que lo es.
+func (p FHIRRelatedPerson) IsOwner() bool {
+	if len(p.Relationship) == 0 {
+		return true
+	}
+	for _, rel := range p.Relationship {
+		for _, c := range rel.Coding {
+			if c.Code == fhirRoleCodeOwner {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AnimalDetail devuelve el texto de la especie o la raza de la extensión
+// patient-animal.
+func (p FHIRPatient) AnimalDetail(name string) string {
+	for _, ext := range p.Extension {
+		if ext.URL != FHIRExtensionAnimal {
+			continue
+		}
+		for _, sub := range ext.Extension {
+			if sub.URL == name && sub.ValueCodeableConcept != nil {
+				return strings.TrimSpace(fhirConceptText(*sub.ValueCodeableConcept))
+			}
+		}
+	}
+	return ""
+}
+
+func (p FHIRPatient) DisplayName() string {
+	if len(p.Name) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(p.Name[0].Text)
+}
+
+func (p FHIRRelatedPerson) DisplayName() string {
+	if len(p.Name) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(p.Name[0].Text)
+}
+
+// Notes devuelve el texto de la extensión de notas del encuentro.
+func (e FHIREncounter) Notes() string {
+	for _, ext := range e.Extension {
+		if ext.URL == FHIRExtensionNotes {
+			return strings.TrimSpace(ext.ValueString)
+		}
+	}
+	return ""
+}
+
+// Reason devuelve el motivo de la consulta: el primer reasonCode que no es
+// un diagnóstico codificado de VetiCare.
+func (e FHIREncounter) Reason() string {
+	for _, reason := range e.ReasonCode {
+		if len(reason.Coding) == 0 || reason.Coding[0].System != FHIRSystemDiagnosis {
+			return strings.TrimSpace(fhirConceptText(reason))
+		}
+	}
+	return ""
+}
+
+// VitalCode devuelve el signo vital de la observación con los nombres de
+// entities.VitalX, buscando el código propio o el de LOINC.
+func (o FHIRObservation) VitalCode() string {
+	for _, c := range o.Code.Coding {
+		if c.System == FHIRSystemVital {
+			return c.Code
+		}
+	}
+	for _, c := range o.Code.Coding {
+		if c.System != FHIRSystemLOINC {
+			continue
+		}
+		for vital, def := range fhirVitalCodes {
+			if def.loinc == c.Code {
+				return vital
+			}
+		}
+	}
+	return ""
+}
+
+func (m FHIRMedicationRequest) Text() string {
+	text := fhirConceptText(m.MedicationCodeableConcept)
+	for _, d := range m.DosageInstruction {
+		if d.Text != "" {
+			text += " " + d.Text
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+func (i FHIRImmunization) DoseNumber() int {
+	if len(i.ProtocolApplied) == 0 {
+		return 0
+	}
+	return i.ProtocolApplied[0].DoseNumberPositiveInt
+}
+
+func fhirConceptText(c FHIRCodeableConcept) string {
+	if c.Text != "" {
+		return c.Text
+	}
+	for _, coding := range c.Coding {
+		if coding.Display != "" {
+			return coding.Display
+		}
+	}
+	return ""
+}
+
+// ParseFHIRBundle separa los recursos del Bundle por tipo. Solo falla si el
+// JSON no tiene la forma esperada; las reglas de contenido se revisan en
+// validators.
+func ParseFHIRBundle(bundle FHIRBundle) (*FHIRRecord, error) {
+	if bundle.ResourceType != "Bundle" {
+		return nil, ErrInvalidFHIRBundle
+	}
+	record := &FHIRRecord{Ignored: map[string]int{}}
+	for i, entry := range bundle.Entry {
+		var head struct {
+			ResourceType string `json:"resourceType"`
+		}
+		if err := json.Unmarshal(entry.Resource, &head); err != nil {
+			return nil, fmt.Errorf("%w: entrada %d", ErrInvalidFHIRBundle, i+1)
+		}
+		var err error
+		switch head.ResourceType {
+		case "Patient":
+			var r FHIRPatient
+			err = json.Unmarshal(entry.Resource, &r)
+			r.ID = fhirEntryID(r.ID, entry.FullURL)
+			record.Patients = append(record.Patients, r)
+		case "RelatedPerson":
+			var r FHIRRelatedPerson
+			err = json.Unmarshal(entry.Resource, &r)
+			r.ID = fhirEntryID(r.ID, entry.FullURL)
+			record.RelatedPersons = append(record.RelatedPersons, r)
+		case "Encounter":
+			var r FHIREncounter
+			err = json.Unmarshal(entry.Resource, &r)
+			r.ID = fhirEntryID(r.ID, entry.FullURL)
+			record.Encounters = append(record.Encounters, r)
+		case "Observation":
+			var r FHIRObservation
+			err = json.Unmarshal(entry.Resource, &r)
+			r.ID = fhirEntryID(r.ID, entry.FullURL)
+			record.Observations = append(record.Observations, r)
+		case "MedicationRequest":
+			var r FHIRMedicationRequest
+			err = json.Unmarshal(entry.Resource, &r)
+			r.ID = fhirEntryID(r.ID, entry.FullURL)
+			record.MedicationRequests = append(record.MedicationRequests, r)
+		case "Immunization":
+			var r FHIRImmunization
+			err = json.Unmarshal(entry.Resource, &r)
+			r.ID = fhirEntryID(r.ID, entry.FullURL)
+			record.Immunizations = append(record.Immunizations, r)
+		default:
+			record.Ignored[head.ResourceType]++
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: entrada %d (%s)", ErrInvalidFHIRBundle, i+1, head.ResourceType)
+		}
+	}
+	return record, nil
+}
+
+func fhirEntryID(id, fullURL string) string {
+	if id != "" {
+		return id
+	}
+	return FHIRReferenceID(fullURL)
+}
+
+type fhirVitalCode struct {
+	loinc   string
+	display string
+	unit    string
+	ucum    string
+}
+
+var fhirVitalCodes = map[string]fhirVitalCode{
+	entities.VitalWeight:          {loinc: "29463-7", display: "Body weight", unit: "kg", ucum: "kg"},
+	entities.VitalTemperature:     {loinc: "8310-5", display: "Body temperature", unit: "°C", ucum: "Cel"},
+	entities.VitalHeartRate:       {loinc: "8867-4", display: "Heart rate", unit: "lpm", ucum: "/min"},
+	entities.VitalRespiratoryRate: {loinc: "9279-1", display: "Respiratory rate", unit: "rpm", ucum: "/min"},
+	fhirVitalBodyCondition:        {display: "Body condition score", unit: "puntos", ucum: "{score}"},
+}
+
+// ToFHIRBundle arma el Bundle con el historial exportado.
+func ToFHIRBundle(export *entities.PetRecordExport, now time.Time) FHIRBundle {
+	pet := export.Pet
+	patientRef := FHIRReference{Reference: "Patient/" + pet.ID.String(), Display: pet.Name}
+	bundle := FHIRBundle{
+		ResourceType: "Bundle",
+		Type:         "collection",
+		Timestamp:    now.Format(FHIRDateTimeLayout),
+	}
+	add := func(id string, resource interface{}) {
+		raw, _ := json.Marshal(resource)
+		bundle.Entry = append(bundle.Entry, FHIRBundleEntry{FullURL: "urn:uuid:" + id, Resource: raw})
+	}
+
+	active := pet.StatusID == 1
+	patient := FHIRPatient{
+		ResourceType: "Patient",
+		ID:           pet.ID.String(),
+		Identifier:   []FHIRIdentifier{{System: FHIRSystemPet, Value: pet.ID.String()}},
+		Active:       &active,
+		Name:         []FHIRHumanName{{Text: pet.Name}},
+	}
+	if pet.Microchip != nil {
+		patient.Identifier = append(patient.Identifier, FHIRIdentifier{System: FHIRSystemMicrochip, Value: *pet.Microchip})
+	}
+	if pet.BirthDate != nil {
+		patient.BirthDate = pet.BirthDate.Format(FHIRDateLayout)
+	}
+	animal := FHIRExtension{URL: FHIRExtensionAnimal, Extension: []FHIRExtension{
+		{URL: "species", ValueCodeableConcept: &FHIRCodeableConcept{Text: pet.Species.Name}},
+	}}
+	if breed := pet.BreedName(); breed != "" {
+		animal.Extension = append(animal.Extension, FHIRExtension{URL: "breed", ValueCodeableConcept: &FHIRCodeableConcept{Text: breed}})
+	}
+	patient.Extension = []FHIRExtension{animal}
+	add(pet.ID.String(), patient)
+
+	owner := FHIRRelatedPerson{
+		ResourceType: "RelatedPerson",
+		ID:           pet.Owner.ID.String(),
+		Identifier:   []FHIRIdentifier{{System: FHIRSystemDUI, Value: pet.Owner.DUI}},
+		Patient:      patientRef,
+		Relationship: []FHIRCodeableConcept{{Coding: []FHIRCoding{{System: FHIRSystemRoleCode, Code: fhirRoleCodeOwner, Display: "owner"}}}},
+		Name:         []FHIRHumanName{{Text: pet.Owner.FullName}},
+	}
+	if pet.Owner.Phone != "" {
+		owner.Telecom = append(owner.Telecom, FHIRContactPoint{System: "phone", Value: pet.Owner.Phone})
+	}
+	if pet.Owner.Email != "" {
+		owner.Telecom = append(owner.Telecom, FHIRContactPoint{System: "email", Value: pet.Owner.Email})
+	}
+	add(pet.Owner.ID.String(), owner)
+
+	for _, app := range export.Appointments {
+		encounterID := app.ID.String()
+		encounterRef := &FHIRReference{Reference: "Encounter/" + encounterID}
+		start, err := time.ParseInLocation("02-01-2006 15:04", app.Date+" "+app.Time, time.Local)
+		if err != nil {
+			start = app.CreatedAt
+		}
+		encounter := FHIREncounter{
+			ResourceType: "Encounter",
+			ID:           encounterID,
+			Status:       fhirEncounterFinished,
+			Class:        FHIRCoding{System: FHIRSystemActCode, Code: "AMB", Display: "ambulatory"},
+			Subject:      patientRef,
+			Period:       FHIRPeriod{Start: start.Format(FHIRDateTimeLayout)},
+		}
+		if app.FinishedAt != nil {
+			encounter.Period.End = app.FinishedAt.Format(FHIRDateTimeLayout)
+		}
+		if app.Vet.FullName != "" {
+			encounter.Participant = []FHIREncounterParticipant{{Individual: FHIRReference{Display: app.Vet.FullName}}}
+		}
+		if app.Reason != "" {
+			encounter.ReasonCode = append(encounter.ReasonCode, FHIRCodeableConcept{Text: app.Reason})
+		}
+		for _, d := range app.Diagnoses {
+			encounter.ReasonCode = append(encounter.ReasonCode, FHIRCodeableConcept{
+				Coding: []FHIRCoding{{System: FHIRSystemDiagnosis, Code: d.Diagnosis.Code, Display: d.Diagnosis.Name}},
+				Text:   d.Diagnosis.Name,
+			})
+		}
+		if app.Clinic != nil {
+			encounter.ServiceProvider = &FHIRReference{Display: app.Clinic.Name}
+		}
+		if app.AdditionalNotes != "" {
+			encounter.Extension = []FHIRExtension{{URL: FHIRExtensionNotes, ValueString: app.AdditionalNotes}}
+		}
+		add(encounterID, encounter)
+
+		effective := start.Format(FHIRDateTimeLayout)
+		if app.WeightKg != nil {
+			add(encounterID+"-weight", fhirObservation(encounterID+"-weight", entities.VitalWeight, *app.WeightKg, patientRef, encounterRef, effective))
+		}
+		if app.Temperature != nil {
+			add(encounterID+"-temperature", fhirObservation(encounterID+"-temperature", entities.VitalTemperature, *app.Temperature, patientRef, encounterRef, effective))
+		}
+
+		for _, p := range app.Prescriptions {
+			status := "completed"
+			if p.StatusID == entities.PrescriptionStatusVoided {
+				status = "cancelled"
+			}
+			for _, item := range p.Items {
+				if status == "completed" && item.EndsOn(p.SignedAt).After(now) {
+					status = "active"
+				}
+				dosage := strings.TrimSpace(fmt.Sprintf("%s %s %s por %d días. %s", item.Dose, item.Route, item.Frequency, item.DurationDays, item.Instructions))
+				add(item.ID.String(), FHIRMedicationRequest{
+					ResourceType:              "MedicationRequest",
+					ID:                        item.ID.String(),
+					Status:                    status,
+					Intent:                    "order",
+					MedicationCodeableConcept: FHIRCodeableConcept{Text: strings.TrimSpace(item.Drug + " " + item.Strength)},
+					Subject:                   patientRef,
+					Encounter:                 encounterRef,
+					AuthoredOn:                p.SignedAt.Format(FHIRDateTimeLayout),
+					Requester:                 &FHIRReference{Display: p.Vet.FullName},
+					DosageInstruction:         []FHIRDosage{{Text: dosage}},
+				})
+			}
+		}
+		if app.MedicationsPrescribed != "" {
+			add(encounterID+"-medications", FHIRMedicationRequest{
+				ResourceType:              "MedicationRequest",
+				ID:                        encounterID + "-medications",
+				Status:                    "unknown",
+				Intent:                    "order",
+				MedicationCodeableConcept: FHIRCodeableConcept{Text: app.MedicationsPrescribed},
+				Subject:                   patientRef,
+				Encounter:                 encounterRef,
+				AuthoredOn:                effective,
+			})
+		}
+	}
+
+	for _, v := range export.VitalSigns {
+		id := v.ID.String()
+		var encounterRef *FHIRReference
+		if v.AppointmentID != nil {
+			encounterRef = &FHIRReference{Reference: "Encounter/" + v.AppointmentID.String()}
+		}
+		effective := v.RecordedAt.Format(FHIRDateTimeLayout)
+		values := map[string]*float64{
+			entities.VitalWeight:      v.WeightKg,
+			entities.VitalTemperature: v.Temperature,
+		}
+		for code, n := range map[string]*int{
+			entities.VitalHeartRate:       v.HeartRate,
+			entities.VitalRespiratoryRate: v.RespiratoryRate,
+			fhirVitalBodyCondition:        v.BodyConditionScore,
+		} {
+			if n != nil {
+				f := float64(*n)
+				values[code] = &f
+			}
+		}
+		for _, code := range []string{entities.VitalWeight, entities.VitalTemperature, entities.VitalHeartRate, entities.VitalRespiratoryRate, fhirVitalBodyCondition} {
+			if values[code] != nil {
+				add(id+"-"+code, fhirObservation(id+"-"+code, code, *values[code], patientRef, encounterRef, effective))
+			}
+		}
+	}
+
+	for _, v := range export.Vaccinations {
+		immunization := FHIRImmunization{
+			ResourceType:       "Immunization",
+			ID:                 v.ID.String(),
+			Status:             "completed",
+			VaccineCode:        FHIRCodeableConcept{Text: v.Vaccine.Name},
+			Patient:            patientRef,
+			OccurrenceDateTime: v.DateGiven.Format(FHIRDateLayout),
+			LotNumber:          v.LotNumber,
+			ProtocolApplied:    []FHIRProtocolApplied{{DoseNumberPositiveInt: v.DoseNumber}},
+		}
+		if v.AppointmentID != nil {
+			immunization.Encounter = &FHIRReference{Reference: "Encounter/" + v.AppointmentID.String()}
+		}
+		if v.Manufacturer != "" {
+			immunization.Manufacturer = &FHIRReference{Display: v.Manufacturer}
+		}
+		if v.AdministeredBy != nil {
+			immunization.Performer = []FHIRPerformer{{Actor: FHIRReference{Display: v.AdministeredBy.FullName}}}
+		}
+		if v.Notes != "" {
+			immunization.Note = []FHIRAnnotation{{Text: v.Notes}}
+		}
+		add(v.ID.String(), immunization)
+	}
+	return bundle
+}
+
+func fhirObservation(id, vital string, value float64, subject FHIRReference, encounter *FHIRReference, effective string) FHIRObservation {
+	def := fhirVitalCodes[vital]
+	code := FHIRCodeableConcept{Text: def.display}
+	if def.loinc != "" {
+		code.Coding = append(code.Coding, FHIRCoding{System: FHIRSystemLOINC, Code: def.loinc, Display: def.display})
+	}
+	code.Coding = append(code.Coding, FHIRCoding{System: FHIRSystemVital, Code: vital})
+	return FHIRObservation{
+		ResourceType:      "Observation",
+		ID:                id,
+		Status:            "final",
+		Code:              code,
+		Subject:           subject,
+		Encounter:         encounter,
+		EffectiveDateTime: effective,
+		ValueQuantity:     &FHIRQuantity{Value: value, Unit: def.unit, System: FHIRSystemUCUM, Code: def.ucum},
+	}
+}
+
+func ToRecordImportResultDTO(r *entities.RecordImportResult) RecordImportResultDTO {
+	warnings := r.Warnings
+	if warnings == nil {
+		warnings = []string{}
+	}
+	return RecordImportResultDTO{
+		DryRun:               r.DryRun,
+		OwnerID:              r.OwnerID,
+		OwnerCreated:         r.OwnerCreated,
+		PetID:                r.PetID,
+		PetCreated:           r.PetCreated,
+		EncountersCreated:    r.EncountersCreated,
+		EncountersSkipped:    r.EncountersSkipped,
+		ObservationsCreated:  r.ObservationsCreated,
+		ObservationsSkipped:  r.ObservationsSkipped,
+		ImmunizationsCreated: r.ImmunizationsCreated,
+		ImmunizationsSkipped: r.ImmunizationsSkipped,
+		MedicationsImported:  r.MedicationsImported,
+		MedicationsSkipped:   r.MedicationsSkipped,
+		Warnings:             warnings,
+	}
+}
+
+// FHIRVitalValue asigna el valor de la observación al campo del signo vital.
+// Devuelve false si el código no corresponde a ningún signo conocido.
+func FHIRVitalValue(v *entities.VitalSign, code string, value float64) bool {
+	switch code {
+	case entities.VitalWeight:
+		v.WeightKg = &value
+	case entities.VitalTemperature:
+		v.Temperature = &value
+	case entities.VitalHeartRate:
+		n := int(math.Round(value))
+		v.HeartRate = &n
+	case entities.VitalRespiratoryRate:
+		n := int(math.Round(value))
+		v.RespiratoryRate = &n
+	case fhirVitalBodyCondition:
+		n := int(math.Round(value))
+		v.BodyConditionScore = &n
+	default:
+		return false
+	}
+	return true
+}
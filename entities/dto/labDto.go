@@ -0,0 +1,183 @@
+package dto
+
+import "VetiCare/entities"
+
+type LabReferenceRangeDTO struct {
+	SpeciesID int     `json:"species_id"`
+	Min       float64 `json:"min"`
+	Max       float64 `json:"max"`
+}
+
+type LabAnalyteDTO struct {
+	ID        int                    `json:"id"`
+	LabTestID int                    `json:"lab_test_id"`
+	Name      string                 `json:"name"`
+	Unit      string                 `json:"unit,omitempty"`
+	SortOrder int                    `json:"sort_order"`
+	StatusID  int                    `json:"status_id"`
+	Ranges    []LabReferenceRangeDTO `json:"ranges"`
+}
+
+type LabTestDTO struct {
+	ID         int             `json:"id"`
+	Name       string          `json:"name"`
+	Code       string          `json:"code,omitempty"`
+	SampleType string          `json:"sample_type,omitempty"`
+	StatusID   int             `json:"status_id"`
+	Status     string          `json:"status"`
+	Analytes   []LabAnalyteDTO `json:"analytes"`
+}
+
+type LabOrderInputDTO struct {
+	LabTestID  int    `json:"lab_test_id"`
+	Laboratory string `json:"laboratory,omitempty"`
+	External   bool   `json:"external"`
+	Notes      string `json:"notes,omitempty"`
+}
+
+type LabResultInputDTO struct {
+	AnalyteID int      `json:"analyte_id"`
+	Value     *float64 `json:"value,omitempty"`
+	TextValue string   `json:"text_value,omitempty"`
+}
+
+type LabResultsInputDTO struct {
+	Results []LabResultInputDTO `json:"results"`
+	Notes   string              `json:"notes,omitempty"`
+}
+
+type LabResultDTO struct {
+	AnalyteID   int      `json:"analyte_id"`
+	AnalyteName string   `json:"analyte_name"`
+	Unit        string   `json:"unit,omitempty"`
+	Value       *float64 `json:"value,omitempty"`
+	TextValue   string   `json:"text_value,omitempty"`
+	RefMin      *float64 `json:"ref_min,omitempty"`
+	RefMax      *float64 `json:"ref_max,omitempty"`
+	Flag        string   `json:"flag,omitempty"`
+}
+
+type LabOrderDTO struct {
+	ID            string         `json:"id"`
+	AppointmentID string         `json:"appointment_id"`
+	PetID         string         `json:"pet_id"`
+	LabTestID     int            `json:"lab_test_id"`
+	LabTestName   string         `json:"lab_test_name"`
+	OrderedByID   *string        `json:"ordered_by_id,omitempty"`
+	OrderedByName string         `json:"ordered_by_name"`
+	Laboratory    string         `json:"laboratory"`
+	External      bool           `json:"external"`
+	StatusID      int            `json:"status_id"`
+	Status        string         `json:"status"`
+	Notes         string         `json:"notes,omitempty"`
+	SampleSentAt  *string        `json:"sample_sent_at,omitempty"`
+	ResultedAt    *string        `json:"resulted_at,omitempty"`
+	ResultNotes   string         `json:"result_notes,omitempty"`
+	Abnormal      bool           `json:"abnormal"`
+	Results       []LabResultDTO `json:"results"`
+	CreatedAt     string         `json:"created_at"`
+}
+
+func ToLabAnalyteDTO(a *entities.LabAnalyte) LabAnalyteDTO {
+	result := LabAnalyteDTO{
+		ID:        a.ID,
+		LabTestID: a.LabTestID,
+		Name:      a.Name,
+		Unit:      a.Unit,
+		SortOrder: a.SortOrder,
+		StatusID:  a.StatusID,
+		Ranges:    []LabReferenceRangeDTO{},
+	}
+	for _, rng := range a.Ranges {
+		result.Ranges = append(result.Ranges, LabReferenceRangeDTO{SpeciesID: rng.SpeciesID, Min: rng.Min, Max: rng.Max})
+	}
+	return result
+}
+
+func ToLabTestDTO(t *entities.LabTest) LabTestDTO {
+	status := "Inactiva"
+	if t.StatusID == 1 {
+		status = "Activa"
+	}
+	result := LabTestDTO{
+		ID:         t.ID,
+		Name:       t.Name,
+		Code:       t.Code,
+		SampleType: t.SampleType,
+		StatusID:   t.StatusID,
+		Status:     status,
+		Analytes:   []LabAnalyteDTO{},
+	}
+	for _, a := range t.Analytes {
+		result.Analytes = append(result.Analytes, ToLabAnalyteDTO(&a))
+	}
+	return result
+}
+
+func ToLabTestDTOs(list []entities.LabTest) []LabTestDTO {
+	dtos := []LabTestDTO{}
+	for _, t := range list {
+		dtos = append(dtos, ToLabTestDTO(&t))
+	}
+	return dtos
+}
+
+func ToLabOrderDTO(o *entities.LabOrder) LabOrderDTO {
+	statusMap := map[int]string{
+		entities.LabOrderStatusPending:   "Pendiente",
+		entities.LabOrderStatusSent:      "Muestra enviada",
+		entities.LabOrderStatusCompleted: "Completada",
+		entities.LabOrderStatusCancelled: "Cancelada",
+	}
+	result := LabOrderDTO{
+		ID:            o.ID.String(),
+		AppointmentID: o.AppointmentID.String(),
+		PetID:         o.PetID.String(),
+		LabTestID:     o.LabTestID,
+		LabTestName:   o.LabTest.Name,
+		OrderedByName: o.OrderedByName,
+		Laboratory:    o.Laboratory,
+		External:      o.External,
+		StatusID:      o.StatusID,
+		Status:        statusMap[o.StatusID],
+		Notes:         o.Notes,
+		SampleSentAt:  formatOptionalTime(o.SampleSentAt),
+		ResultedAt:    formatOptionalTime(o.ResultedAt),
+		ResultNotes:   o.ResultNotes,
+		Abnormal:      len(o.AbnormalResults()) > 0,
+		Results:       []LabResultDTO{},
+		CreatedAt:     o.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if o.OrderedByID != nil {
+		id := o.OrderedByID.String()
+		result.OrderedByID = &id
+	}
+	for _, r := range o.Results {
+		result.Results = append(result.Results, LabResultDTO{
+			AnalyteID:   r.AnalyteID,
+			AnalyteName: r.Analyte.Name,
+			Unit:        r.Analyte.Unit,
+			Value:       r.Value,
+			TextValue:   r.TextValue,
+			RefMin:      r.RefMin,
+			RefMax:      r.RefMax,
+			Flag:        r.Flag,
+		})
+	}
+	return result
+}
+
+func ToLabOrderDTOs(list []entities.LabOrder) []LabOrderDTO {
+	dtos := []LabOrderDTO{}
+	for _, o := range list {
+		dtos = append(dtos, ToLabOrderDTO(&o))
+	}
+	return dtos
+}
+
+func labOrderDTOsOrNil(list []entities.LabOrder) []LabOrderDTO {
+	if len(list) == 0 {
+		return nil
+	}
+	return ToLabOrderDTOs(list)
+}
@@ -20,10 +20,21 @@ type PetDTO struct {
 	Species   SpeciesDTO     `json:"species"`
 	BirthDate *time.Time     `json:"birth_date"`
 	Breed     *string        `json:"breed,omitempty"`
+	BreedID   *int           `json:"breed_id,omitempty"`
+	BreedName string         `json:"breed_name,omitempty"`
 	StatusID  int            `json:"status_id"`
 	Status    string         `json:"status"`
 	CreatedAt *string        `json:"created_at,omitempty"`
 	UpdatedAt *string        `json:"updated_at,omitempty"`
+
+	Microchip            *string    `json:"microchip,omitempty"`
+	MicrochipImplantedAt *time.Time `json:"microchip_implanted_at,omitempty"`
+	MicrochipLocation    string     `json:"microchip_location,omitempty"`
+
+	CriticalAlerts []PetAlertDTO `json:"critical_alerts,omitempty"`
+
+	PhotoURL     *string `json:"photo_url,omitempty"`
+	ThumbnailURL *string `json:"thumbnail_url,omitempty"`
 }
 
 func ToPetDTO(pet *entities.Pet) PetDTO {
@@ -55,11 +66,78 @@ func ToPetDTO(pet *entities.Pet) PetDTO {
 		},
 		BirthDate: pet.BirthDate,
 		Breed:     pet.Breed,
+		BreedID:   pet.BreedID,
+		BreedName: pet.BreedName(),
 		StatusID:  pet.StatusID,
 		Status:    statusText,
 		CreatedAt: createdAtStr,
 		UpdatedAt: updatedAtStr,
+
+		Microchip:            pet.Microchip,
+		MicrochipImplantedAt: pet.MicrochipImplantedAt,
+		MicrochipLocation:    pet.MicrochipLocation,
+
+		CriticalAlerts: petAlertDTOsOrNil(pet.CriticalAlerts()),
+
+		PhotoURL:     petPhotoURL(pet, false),
+		ThumbnailURL: petPhotoURL(pet, true),
+	}
+}
+
+// petPhotoURL incluye la versión de la foto para que la URL cambie cuando se
+// reemplaza y pueda guardarse en caché.
+func petPhotoURL(pet *entities.Pet, thumbnail bool) *string {
+	if pet.PhotoVersion == "" {
+		return nil
+	}
+	url := "/api/pets/" + pet.ID.String() + "/photo?v=" + pet.PhotoVersion
+	if thumbnail {
+		url += "&size=thumb"
+	}
+	return &url
+}
+
+// MicrochipLookupDTO es lo que ve el personal al leer el chip de una mascota
+// encontrada: la mascota y cómo contactar a su dueño.
+type MicrochipLookupDTO struct {
+	PetID                string     `json:"pet_id"`
+	PetName              string     `json:"pet_name"`
+	SpeciesName          string     `json:"species_name"`
+	BreedName            string     `json:"breed_name,omitempty"`
+	Status               string     `json:"status"`
+	Microchip            string     `json:"microchip"`
+	MicrochipImplantedAt *time.Time `json:"microchip_implanted_at,omitempty"`
+	MicrochipLocation    string     `json:"microchip_location,omitempty"`
+	ThumbnailURL         *string    `json:"thumbnail_url,omitempty"`
+	OwnerID              string     `json:"owner_id"`
+	OwnerName            string     `json:"owner_name"`
+	OwnerPhone           string     `json:"owner_phone"`
+	OwnerEmail           string     `json:"owner_email"`
+}
+
+func ToMicrochipLookupDTO(pet *entities.Pet) MicrochipLookupDTO {
+	status := "Inactiva"
+	if pet.StatusID == 1 {
+		status = "Activa"
+	}
+	out := MicrochipLookupDTO{
+		PetID:                pet.ID.String(),
+		PetName:              pet.Name,
+		SpeciesName:          pet.Species.Name,
+		BreedName:            pet.BreedName(),
+		Status:               status,
+		MicrochipImplantedAt: pet.MicrochipImplantedAt,
+		MicrochipLocation:    pet.MicrochipLocation,
+		ThumbnailURL:         petPhotoURL(pet, true),
+		OwnerID:              pet.OwnerID.String(),
+		OwnerName:            pet.Owner.FullName,
+		OwnerPhone:           pet.Owner.Phone,
+		OwnerEmail:           pet.Owner.Email,
+	}
+	if pet.Microchip != nil {
+		out.Microchip = *pet.Microchip
 	}
+	return out
 }
 
 func ToUserSummaryDTO(u *entities.User) UserSummaryDTO {
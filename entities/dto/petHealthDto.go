@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"VetiCare/entities"
+)
+
+type PetAlertDTO struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+type PetAllergyDTO struct {
+	ID        string `json:"id,omitempty"`
+	PetID     string `json:"pet_id,omitempty"`
+	Agent     string `json:"agent"`
+	Reaction  string `json:"reaction,omitempty"`
+	Severity  string `json:"severity"`
+	Notes     string `json:"notes,omitempty"`
+	StatusID  int    `json:"status_id,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+type PetConditionDTO struct {
+	ID          string  `json:"id,omitempty"`
+	PetID       string  `json:"pet_id,omitempty"`
+	Name        string  `json:"name"`
+	DiagnosedOn *string `json:"diagnosed_on,omitempty"`
+	Critical    bool    `json:"critical"`
+	Notes       string  `json:"notes,omitempty"`
+	StatusID    int     `json:"status_id,omitempty"`
+	CreatedAt   string  `json:"created_at,omitempty"`
+}
+
+type PetBehaviorAlertDTO struct {
+	ID          string `json:"id,omitempty"`
+	PetID       string `json:"pet_id,omitempty"`
+	Description string `json:"description"`
+	Critical    *bool  `json:"critical,omitempty"`
+	StatusID    int    `json:"status_id,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+}
+
+func petAlertDTOsOrNil(alerts []entities.PetAlert) []PetAlertDTO {
+	if len(alerts) == 0 {
+		return nil
+	}
+	dtos := make([]PetAlertDTO, 0, len(alerts))
+	for _, a := range alerts {
+		dtos = append(dtos, PetAlertDTO{Type: a.Type, Description: a.Description})
+	}
+	return dtos
+}
+
+func ToPetAllergyDTO(a *entities.PetAllergy) PetAllergyDTO {
+	return PetAllergyDTO{
+		ID:        a.ID.String(),
+		PetID:     a.PetID.String(),
+		Agent:     a.Agent,
+		Reaction:  a.Reaction,
+		Severity:  a.Severity,
+		Notes:     a.Notes,
+		StatusID:  a.StatusID,
+		CreatedAt: a.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func ToPetConditionDTO(c *entities.PetCondition) PetConditionDTO {
+	return PetConditionDTO{
+		ID:          c.ID.String(),
+		PetID:       c.PetID.String(),
+		Name:        c.Name,
+		DiagnosedOn: formatOptionalDate(c.DiagnosedOn),
+		Critical:    c.Critical,
+		Notes:       c.Notes,
+		StatusID:    c.StatusID,
+		CreatedAt:   c.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func ToPetBehaviorAlertDTO(b *entities.PetBehaviorAlert) PetBehaviorAlertDTO {
+	critical := b.Critical
+	return PetBehaviorAlertDTO{
+		ID:          b.ID.String(),
+		PetID:       b.PetID.String(),
+		Description: b.Description,
+		Critical:    &critical,
+		StatusID:    b.StatusID,
+		CreatedAt:   b.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,154 @@
+package dto
+
+import "VetiCare/entities"
+
+type PrescriptionItemDTO struct {
+	ID           string `json:"id,omitempty"`
+	Drug         string `json:"drug"`
+	Strength     string `json:"strength,omitempty"`
+	Dose         string `json:"dose"`
+	Route        string `json:"route"`
+	Frequency    string `json:"frequency"`
+	DurationDays int    `json:"duration_days"`
+	Quantity     string `json:"quantity,omitempty"`
+	Instructions string `json:"instructions,omitempty"`
+}
+
+type PrescriptionInputDTO struct {
+	Notes string                `json:"notes,omitempty"`
+	Items []PrescriptionItemDTO `json:"items"`
+}
+
+type VoidPrescriptionDTO struct {
+	Reason string `json:"reason"`
+}
+
+type PrescriptionDTO struct {
+	ID             string                `json:"id"`
+	AppointmentID  string                `json:"appointment_id"`
+	PetID          string                `json:"pet_id"`
+	PetName        string                `json:"pet_name,omitempty"`
+	VetID          string                `json:"vet_id"`
+	VetName        string                `json:"vet_name"`
+	StatusID       int                   `json:"status_id"`
+	Status         string                `json:"status"`
+	Notes          string                `json:"notes,omitempty"`
+	SignedAt       string                `json:"signed_at"`
+	Signature      string                `json:"signature"`
+	SignatureValid bool                  `json:"signature_valid"`
+	VoidedAt       *string               `json:"voided_at,omitempty"`
+	VoidedByID     *string               `json:"voided_by_id,omitempty"`
+	VoidReason     string                `json:"void_reason,omitempty"`
+	Items          []PrescriptionItemDTO `json:"items"`
+
+	AllergyWarnings []string `json:"allergy_warnings,omitempty"`
+}
+
+type ActiveMedicationDTO struct {
+	PrescriptionID string `json:"prescription_id"`
+	Drug           string `json:"drug"`
+	Strength       string `json:"strength,omitempty"`
+	Dose           string `json:"dose"`
+	Route          string `json:"route"`
+	Frequency      string `json:"frequency"`
+	Instructions   string `json:"instructions,omitempty"`
+	VetName        string `json:"vet_name"`
+	StartedOn      string `json:"started_on"`
+	EndsOn         string `json:"ends_on"`
+}
+
+type MedicalHistoryDTO struct {
+	PetID             string                `json:"pet_id"`
+	Appointments      []AppointmentDTO      `json:"appointments"`
+	ActiveMedications []ActiveMedicationDTO `json:"active_medications"`
+	Attachments       []AttachmentDTO       `json:"attachments"`
+}
+
+func ToPrescriptionItem(in PrescriptionItemDTO) entities.PrescriptionItem {
+	return entities.PrescriptionItem{
+		Drug:         in.Drug,
+		Strength:     in.Strength,
+		Dose:         in.Dose,
+		Route:        in.Route,
+		Frequency:    in.Frequency,
+		DurationDays: in.DurationDays,
+		Quantity:     in.Quantity,
+		Instructions: in.Instructions,
+	}
+}
+
+func ToPrescriptionDTO(p *entities.Prescription) PrescriptionDTO {
+	status := "Vigente"
+	if p.StatusID == entities.PrescriptionStatusVoided {
+		status = "Anulada"
+	}
+	result := PrescriptionDTO{
+		ID:             p.ID.String(),
+		AppointmentID:  p.AppointmentID.String(),
+		PetID:          p.PetID.String(),
+		PetName:        p.Pet.Name,
+		VetID:          p.VetID.String(),
+		VetName:        p.Vet.FullName,
+		StatusID:       p.StatusID,
+		Status:         status,
+		Notes:          p.Notes,
+		SignedAt:       p.SignedAt.Format("2006-01-02 15:04:05"),
+		Signature:      p.Signature,
+		SignatureValid: p.Signature == p.ContentHash(),
+		VoidedAt:       formatOptionalTime(p.VoidedAt),
+		VoidReason:     p.VoidReason,
+		Items:          []PrescriptionItemDTO{},
+
+		AllergyWarnings: p.AllergyWarnings,
+	}
+	if p.VoidedByID != nil {
+		id := p.VoidedByID.String()
+		result.VoidedByID = &id
+	}
+	for _, i := range p.Items {
+		result.Items = append(result.Items, PrescriptionItemDTO{
+			ID:           i.ID.String(),
+			Drug:         i.Drug,
+			Strength:     i.Strength,
+			Dose:         i.Dose,
+			Route:        i.Route,
+			Frequency:    i.Frequency,
+			DurationDays: i.DurationDays,
+			Quantity:     i.Quantity,
+			Instructions: i.Instructions,
+		})
+	}
+	return result
+}
+
+func ToPrescriptionDTOs(list []entities.Prescription) []PrescriptionDTO {
+	dtos := []PrescriptionDTO{}
+	for _, p := range list {
+		dtos = append(dtos, ToPrescriptionDTO(&p))
+	}
+	return dtos
+}
+
+func ToMedicalHistoryDTO(petID string, history *entities.MedicalHistory) MedicalHistoryDTO {
+	result := MedicalHistoryDTO{
+		PetID:             petID,
+		Appointments:      ToAppointmentDTOs(history.Appointments),
+		ActiveMedications: []ActiveMedicationDTO{},
+		Attachments:       ToAttachmentDTOs(history.Attachments),
+	}
+	for _, med := range history.ActiveMedications {
+		result.ActiveMedications = append(result.ActiveMedications, ActiveMedicationDTO{
+			PrescriptionID: med.Prescription.ID.String(),
+			Drug:           med.Item.Drug,
+			Strength:       med.Item.Strength,
+			Dose:           med.Item.Dose,
+			Route:          med.Item.Route,
+			Frequency:      med.Item.Frequency,
+			Instructions:   med.Item.Instructions,
+			VetName:        med.Prescription.Vet.FullName,
+			StartedOn:      med.Prescription.SignedAt.Format("02-01-2006"),
+			EndsOn:         med.EndsOn.AddDate(0, 0, -1).Format("02-01-2006"),
+		})
+	}
+	return result
+}
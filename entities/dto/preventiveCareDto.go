@@ -0,0 +1,139 @@
+package dto
+
+import (
+	"VetiCare/entities"
+	"time"
+)
+
+type DewormingInputDTO struct {
+	AppointmentID    *string  `json:"appointment_id,omitempty"`
+	Product          string   `json:"product"`
+	Dose             string   `json:"dose,omitempty"`
+	WeightKg         *float64 `json:"weight_kg,omitempty"`
+	DateGiven        string   `json:"date_given"`
+	AdministeredByID *string  `json:"administered_by_id,omitempty"`
+	NextDueDate      string   `json:"next_due_date,omitempty"`
+	Notes            string   `json:"notes,omitempty"`
+}
+
+type DewormingDTO struct {
+	ID                 string   `json:"id"`
+	PetID              string   `json:"pet_id"`
+	PetName            string   `json:"pet_name"`
+	AppointmentID      *string  `json:"appointment_id,omitempty"`
+	Product            string   `json:"product"`
+	Dose               string   `json:"dose,omitempty"`
+	WeightKg           *float64 `json:"weight_kg,omitempty"`
+	DateGiven          string   `json:"date_given"`
+	AdministeredByID   *string  `json:"administered_by_id,omitempty"`
+	AdministeredByName string   `json:"administered_by_name,omitempty"`
+	NextDueDate        *string  `json:"next_due_date,omitempty"`
+	Notes              string   `json:"notes,omitempty"`
+	CreatedAt          string   `json:"created_at"`
+}
+
+type PreventiveProtocolDTO struct {
+	ID           int    `json:"id"`
+	SpeciesID    int    `json:"species_id"`
+	SpeciesName  string `json:"species_name,omitempty"`
+	Name         string `json:"name"`
+	CareType     string `json:"care_type"`
+	VaccineID    *int   `json:"vaccine_id,omitempty"`
+	VaccineName  string `json:"vaccine_name,omitempty"`
+	MinAgeWeeks  int    `json:"min_age_weeks"`
+	MaxAgeWeeks  *int   `json:"max_age_weeks,omitempty"`
+	IntervalDays int    `json:"interval_days"`
+	StatusID     int    `json:"status_id"`
+	Status       string `json:"status"`
+}
+
+type CareDueItemDTO struct {
+	PetID          string  `json:"pet_id"`
+	PetName        string  `json:"pet_name"`
+	SpeciesName    string  `json:"species_name"`
+	OwnerID        string  `json:"owner_id"`
+	OwnerName      string  `json:"owner_name"`
+	OwnerEmail     string  `json:"owner_email"`
+	OwnerPhone     string  `json:"owner_phone,omitempty"`
+	CareType       string  `json:"care_type"`
+	CareName       string  `json:"care_name"`
+	LastGiven      *string `json:"last_given,omitempty"`
+	DueDate        string  `json:"due_date"`
+	Overdue        bool    `json:"overdue"`
+	LastReminderAt *string `json:"last_reminder_at,omitempty"`
+}
+
+func ToDewormingDTO(d *entities.Deworming) DewormingDTO {
+	result := DewormingDTO{
+		ID:          d.ID.String(),
+		PetID:       d.PetID.String(),
+		PetName:     d.Pet.Name,
+		Product:     d.Product,
+		Dose:        d.Dose,
+		WeightKg:    d.WeightKg,
+		DateGiven:   d.DateGiven.Format("02-01-2006"),
+		NextDueDate: formatOptionalDate(d.NextDueDate),
+		Notes:       d.Notes,
+		CreatedAt:   d.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if d.AppointmentID != nil {
+		id := d.AppointmentID.String()
+		result.AppointmentID = &id
+	}
+	if d.AdministeredByID != nil {
+		id := d.AdministeredByID.String()
+		result.AdministeredByID = &id
+		if d.AdministeredBy != nil {
+			result.AdministeredByName = d.AdministeredBy.FullName
+		}
+	}
+	return result
+}
+
+func ToPreventiveProtocolDTO(p *entities.PreventiveProtocol) PreventiveProtocolDTO {
+	status := "Inactivo"
+	if p.StatusID == 1 {
+		status = "Activo"
+	}
+	result := PreventiveProtocolDTO{
+		ID:           p.ID,
+		SpeciesID:    p.SpeciesID,
+		SpeciesName:  p.Species.Name,
+		Name:         p.Name,
+		CareType:     p.CareType,
+		VaccineID:    p.VaccineID,
+		MinAgeWeeks:  p.MinAgeWeeks,
+		MaxAgeWeeks:  p.MaxAgeWeeks,
+		IntervalDays: p.IntervalDays,
+		StatusID:     p.StatusID,
+		Status:       status,
+	}
+	if p.Vaccine != nil {
+		result.VaccineName = p.Vaccine.Name
+	}
+	return result
+}
+
+// ToCareDueItemDTOs marca como vencidos los cuidados cuya fecha es anterior a
+// today, que debe ser la medianoche del día actual.
+func ToCareDueItemDTOs(items []entities.CareDueItem, today time.Time) []CareDueItemDTO {
+	dtos := []CareDueItemDTO{}
+	for _, item := range items {
+		dtos = append(dtos, CareDueItemDTO{
+			PetID:          item.Pet.ID.String(),
+			PetName:        item.Pet.Name,
+			SpeciesName:    item.Pet.Species.Name,
+			OwnerID:        item.Pet.OwnerID.String(),
+			OwnerName:      item.Pet.Owner.FullName,
+			OwnerEmail:     item.Pet.Owner.Email,
+			OwnerPhone:     item.Pet.Owner.Phone,
+			CareType:       item.CareType,
+			CareName:       item.CareName,
+			LastGiven:      formatOptionalDate(item.LastGiven),
+			DueDate:        item.DueDate.Format("02-01-2006"),
+			Overdue:        item.DueDate.Before(today),
+			LastReminderAt: formatOptionalTime(item.LastReminderAt),
+		})
+	}
+	return dtos
+}
@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"VetiCare/entities"
+	"time"
+)
+
+type QueueEntryDTO struct {
+	Position      int     `json:"position"`
+	AppointmentID string  `json:"appointment_id"`
+	PetID         string  `json:"pet_id"`
+	PetName       string  `json:"pet_name"`
+	OwnerName     string  `json:"owner_name"`
+	Time          string  `json:"time"`
+	StatusID      int     `json:"status_id"`
+	Status        string  `json:"status"`
+	IsWalkIn      bool    `json:"is_walk_in"`
+	CheckedInAt   *string `json:"checked_in_at,omitempty"`
+	StartedAt     *string `json:"started_at,omitempty"`
+	WaitMinutes   int     `json:"wait_minutes"`
+}
+
+type VetQueueDTO struct {
+	VetID   *string         `json:"vet_id,omitempty"`
+	VetName string          `json:"vet_name"`
+	Entries []QueueEntryDTO `json:"entries"`
+}
+
+type QueueDTO struct {
+	Date        string        `json:"date"`
+	GeneratedAt string        `json:"generated_at"`
+	Vets        []VetQueueDTO `json:"vets"`
+}
+
+func ToQueueDTO(date time.Time, queues []entities.VetQueue) QueueDTO {
+	result := QueueDTO{
+		Date:        date.Format("02-01-2006"),
+		GeneratedAt: date.Format("2006-01-02 15:04:05"),
+		Vets:        []VetQueueDTO{},
+	}
+	for _, q := range queues {
+		var vetID *string
+		if q.VetID != nil {
+			s := q.VetID.String()
+			vetID = &s
+		}
+		vetQueue := VetQueueDTO{VetID: vetID, VetName: q.VetName, Entries: []QueueEntryDTO{}}
+		for _, e := range q.Entries {
+			app := NewAppointmentDTO(&e.Appointment)
+			vetQueue.Entries = append(vetQueue.Entries, QueueEntryDTO{
+				Position:      e.Position,
+				AppointmentID: app.ID,
+				PetID:         app.PetID,
+				PetName:       e.Appointment.Pet.Name,
+				OwnerName:     e.Appointment.Pet.Owner.FullName,
+				Time:          app.Time,
+				StatusID:      app.StatusID,
+				Status:        app.Status,
+				IsWalkIn:      app.IsWalkIn,
+				CheckedInAt:   app.CheckedInAt,
+				StartedAt:     app.StartedAt,
+				WaitMinutes:   e.WaitMinutes,
+			})
+		}
+		result.Vets = append(result.Vets, vetQueue)
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package dto
+
+import "VetiCare/entities"
+
+type ReassignmentInputDTO struct {
+	From         string `json:"from"`
+	To           string `json:"to"`
+	Reason       string `json:"reason,omitempty"`
+	ProposalHash string `json:"proposal_hash,omitempty"`
+}
+
+type ReassignmentProposalDTO struct {
+	AppointmentID string `json:"appointment_id"`
+	PetName       string `json:"pet_name"`
+	OwnerName     string `json:"owner_name"`
+	Date          string `json:"date"`
+	Time          string `json:"time"`
+	Action        string `json:"action"`
+	NewVetID      string `json:"new_vet_id,omitempty"`
+	NewVetName    string `json:"new_vet_name,omitempty"`
+	NewDate       string `json:"new_date,omitempty"`
+	NewTime       string `json:"new_time,omitempty"`
+}
+
+type ReassignmentResultDTO struct {
+	VetID       string                    `json:"vet_id"`
+	From        string                    `json:"from"`
+	To          string                    `json:"to"`
+	Applied     bool                      `json:"applied"`
+	Hash        string                    `json:"proposal_hash"`
+	Reassigned  int                       `json:"reassigned"`
+	Rescheduled int                       `json:"rescheduled"`
+	Unresolved  int                       `json:"unresolved"`
+	Proposals   []ReassignmentProposalDTO `json:"proposals"`
+}
+
+func ToReassignmentResultDTO(vetID string, in ReassignmentInputDTO, applied bool, proposals []entities.ReassignmentProposal) ReassignmentResultDTO {
+	result := ReassignmentResultDTO{
+		VetID:     vetID,
+		From:      in.From,
+		To:        in.To,
+		Applied:   applied,
+		Hash:      entities.ReassignmentHash(proposals),
+		Proposals: []ReassignmentProposalDTO{},
+	}
+	for _, p := range proposals {
+		item := ReassignmentProposalDTO{
+			AppointmentID: p.Appointment.ID.String(),
+			PetName:       p.Appointment.Pet.Name,
+			OwnerName:     p.Appointment.Pet.Owner.FullName,
+			Date:          p.Appointment.Date,
+			Time:          p.Appointment.Time,
+			Action:        p.Action,
+			NewVetName:    p.NewVetName,
+			NewDate:       p.NewDate,
+			NewTime:       p.NewTime,
+		}
+		if p.NewVetID != nil {
+			item.NewVetID = p.NewVetID.String()
+		}
+		switch p.Action {
+		case entities.ReassignmentActionReassign:
+			result.Reassigned++
+		case entities.ReassignmentActionReschedule:
+			result.Rescheduled++
+		default:
+			result.Unresolved++
+		}
+		result.Proposals = append(result.Proposals, item)
+	}
+	return result
+}
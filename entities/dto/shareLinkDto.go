@@ -0,0 +1,181 @@
+package dto
+
+import (
+	"VetiCare/entities"
+	"VetiCare/utils"
+	"time"
+)
+
+type ShareLinkInputDTO struct {
+	Scope          string `json:"scope"`
+	Recipient      string `json:"recipient,omitempty"`
+	ExpiresInHours int    `json:"expires_in_hours,omitempty"`
+	Pin            string `json:"pin,omitempty"`
+}
+
+type ShareLinkDTO struct {
+	ID                string  `json:"id"`
+	PetID             string  `json:"pet_id"`
+	PetName           string  `json:"pet_name,omitempty"`
+	Scope             string  `json:"scope"`
+	Recipient         string  `json:"recipient,omitempty"`
+	URL               string  `json:"url"`
+	Token             string  `json:"token"`
+	HasPin            bool    `json:"has_pin"`
+	CreatedByName     string  `json:"created_by_name"`
+	ExpiresAt         string  `json:"expires_at"`
+	Status            string  `json:"status"`
+	FailedPinAttempts int     `json:"failed_pin_attempts"`
+	RevokedAt         *string `json:"revoked_at,omitempty"`
+	RevokedByName     string  `json:"revoked_by_name,omitempty"`
+	AccessCount       int     `json:"access_count"`
+	LastAccessedAt    *string `json:"last_accessed_at,omitempty"`
+	CreatedAt         string  `json:"created_at"`
+}
+
+type ShareLinkAccessDTO struct {
+	ID         string `json:"id"`
+	AccessedAt string `json:"accessed_at"`
+	IPAddress  string `json:"ip_address"`
+	UserAgent  string `json:"user_agent,omitempty"`
+	Granted    bool   `json:"granted"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// SharedPetDTO omite a propósito los datos del dueño: el destinatario del
+// enlace no tiene cuenta en la clínica.
+type SharedPetDTO struct {
+	Name           string        `json:"name"`
+	Species        string        `json:"species"`
+	Breed          string        `json:"breed,omitempty"`
+	BirthDate      *string       `json:"birth_date,omitempty"`
+	CriticalAlerts []PetAlertDTO `json:"critical_alerts,omitempty"`
+}
+
+type SharedVaccinationDTO struct {
+	VaccineName        string  `json:"vaccine_name"`
+	DoseNumber         int     `json:"dose_number"`
+	DateGiven          string  `json:"date_given"`
+	NextDueDate        *string `json:"next_due_date,omitempty"`
+	Manufacturer       string  `json:"manufacturer,omitempty"`
+	LotNumber          string  `json:"lot_number,omitempty"`
+	AdministeredByName string  `json:"administered_by_name,omitempty"`
+}
+
+type SharedVisitDTO struct {
+	Date            string                    `json:"date"`
+	Time            string                    `json:"time"`
+	VetName         string                    `json:"vet_name,omitempty"`
+	ClinicName      string                    `json:"clinic_name,omitempty"`
+	Reason          string                    `json:"reason,omitempty"`
+	WeightKg        *float64                  `json:"weight_kg,omitempty"`
+	Temperature     *float64                  `json:"temperature,omitempty"`
+	AdditionalNotes string                    `json:"additional_notes,omitempty"`
+	Diagnoses       []AppointmentDiagnosisDTO `json:"diagnoses,omitempty"`
+	ClinicalNote    *ClinicalNoteDTO          `json:"clinical_note,omitempty"`
+	Prescriptions   []PrescriptionDTO         `json:"prescriptions,omitempty"`
+	LabOrders       []LabOrderDTO             `json:"lab_orders,omitempty"`
+}
+
+type SharedRecordDTO struct {
+	Scope        string                 `json:"scope"`
+	ExpiresAt    string                 `json:"expires_at"`
+	Pet          SharedPetDTO           `json:"pet"`
+	Vaccinations []SharedVaccinationDTO `json:"vaccinations"`
+	Visits       []SharedVisitDTO       `json:"visits,omitempty"`
+}
+
+func ToShareLinkDTO(l *entities.ShareLink) ShareLinkDTO {
+	token := utils.SignShareToken(l.ID.String(), l.ExpiresAt)
+	return ShareLinkDTO{
+		ID:                l.ID.String(),
+		PetID:             l.PetID.String(),
+		PetName:           l.Pet.Name,
+		Scope:             l.Scope,
+		Recipient:         l.Recipient,
+		URL:               utils.ShareLinkURL(token),
+		Token:             token,
+		HasPin:            l.HasPin(),
+		CreatedByName:     l.CreatedByName,
+		ExpiresAt:         l.ExpiresAt.Format("2006-01-02 15:04:05"),
+		Status:            l.Status(time.Now()),
+		FailedPinAttempts: l.FailedPinAttempts,
+		RevokedAt:         formatOptionalTime(l.RevokedAt),
+		RevokedByName:     l.RevokedByName,
+		AccessCount:       l.AccessCount,
+		LastAccessedAt:    formatOptionalTime(l.LastAccessedAt),
+		CreatedAt:         l.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func ToShareLinkDTOs(list []entities.ShareLink) []ShareLinkDTO {
+	dtos := []ShareLinkDTO{}
+	for _, l := range list {
+		dtos = append(dtos, ToShareLinkDTO(&l))
+	}
+	return dtos
+}
+
+func ToShareLinkAccessDTOs(list []entities.ShareLinkAccess) []ShareLinkAccessDTO {
+	dtos := []ShareLinkAccessDTO{}
+	for _, a := range list {
+		dtos = append(dtos, ShareLinkAccessDTO{
+			ID:         a.ID.String(),
+			AccessedAt: a.AccessedAt.Format("2006-01-02 15:04:05"),
+			IPAddress:  a.IPAddress,
+			UserAgent:  a.UserAgent,
+			Granted:    a.Granted,
+			Reason:     a.Reason,
+		})
+	}
+	return dtos
+}
+
+func ToSharedRecordDTO(rec *entities.SharedRecord) SharedRecordDTO {
+	out := SharedRecordDTO{
+		Scope:     rec.Link.Scope,
+		ExpiresAt: rec.Link.ExpiresAt.Format("2006-01-02 15:04:05"),
+		Pet: SharedPetDTO{
+			Name:           rec.Pet.Name,
+			Species:        rec.Pet.Species.Name,
+			Breed:          rec.Pet.BreedName(),
+			BirthDate:      formatOptionalDate(rec.Pet.BirthDate),
+			CriticalAlerts: petAlertDTOsOrNil(rec.Pet.CriticalAlerts()),
+		},
+		Vaccinations: []SharedVaccinationDTO{},
+	}
+	for _, v := range rec.Vaccinations {
+		item := SharedVaccinationDTO{
+			VaccineName:  v.Vaccine.Name,
+			DoseNumber:   v.DoseNumber,
+			DateGiven:    v.DateGiven.Format("02-01-2006"),
+			NextDueDate:  formatOptionalDate(v.NextDueDate),
+			Manufacturer: v.Manufacturer,
+			LotNumber:    v.LotNumber,
+		}
+		if v.AdministeredBy != nil {
+			item.AdministeredByName = v.AdministeredBy.FullName
+		}
+		out.Vaccinations = append(out.Vaccinations, item)
+	}
+	for _, app := range rec.Appointments {
+		visit := SharedVisitDTO{
+			Date:            app.Date,
+			Time:            app.Time,
+			VetName:         app.Vet.FullName,
+			Reason:          app.Reason,
+			WeightKg:        app.WeightKg,
+			Temperature:     app.Temperature,
+			AdditionalNotes: app.AdditionalNotes,
+			Diagnoses:       appointmentDiagnosisDTOsOrNil(app.Diagnoses),
+			ClinicalNote:    clinicalNoteDTOOrNil(app.ClinicalNote),
+			Prescriptions:   prescriptionDTOsOrNil(app.Prescriptions),
+			LabOrders:       labOrderDTOsOrNil(app.LabOrders),
+		}
+		if app.Clinic != nil {
+			visit.ClinicName = app.Clinic.Name
+		}
+		out.Visits = append(out.Visits, visit)
+	}
+	return out
+}
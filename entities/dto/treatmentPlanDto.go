@@ -0,0 +1,238 @@
+package dto
+
+import (
+	"VetiCare/entities"
+	"time"
+)
+
+type TreatmentPlanStepInputDTO struct {
+	Procedure     string  `json:"procedure"`
+	Notes         string  `json:"notes,omitempty"`
+	IntervalDays  int     `json:"interval_days"`
+	EstimatedCost float64 `json:"estimated_cost"`
+}
+
+type TreatmentPlanInputDTO struct {
+	Title       string                      `json:"title"`
+	Description string                      `json:"description,omitempty"`
+	VetID       *string                     `json:"vet_id,omitempty"`
+	ClinicID    *int                        `json:"clinic_id,omitempty"`
+	StartDate   string                      `json:"start_date,omitempty"`
+	Steps       []TreatmentPlanStepInputDTO `json:"steps"`
+}
+
+type TreatmentFollowUpInputDTO struct {
+	StepIDs []string `json:"step_ids,omitempty"`
+}
+
+type TreatmentStepAppointmentInputDTO struct {
+	AppointmentID string `json:"appointment_id"`
+}
+
+type TreatmentPlanStepDTO struct {
+	ID              string  `json:"id"`
+	StepOrder       int     `json:"step_order"`
+	Procedure       string  `json:"procedure"`
+	Notes           string  `json:"notes,omitempty"`
+	IntervalDays    int     `json:"interval_days"`
+	DueDate         string  `json:"due_date"`
+	EstimatedCost   float64 `json:"estimated_cost"`
+	StatusID        int     `json:"status_id"`
+	Status          string  `json:"status"`
+	Overdue         bool    `json:"overdue"`
+	AppointmentID   *string `json:"appointment_id,omitempty"`
+	AppointmentDate string  `json:"appointment_date,omitempty"`
+	AppointmentTime string  `json:"appointment_time,omitempty"`
+	CompletedAt     *string `json:"completed_at,omitempty"`
+}
+
+type TreatmentPlanDTO struct {
+	ID             string                 `json:"id"`
+	PetID          string                 `json:"pet_id"`
+	PetName        string                 `json:"pet_name"`
+	VetID          *string                `json:"vet_id,omitempty"`
+	VetName        string                 `json:"vet_name,omitempty"`
+	ClinicID       *int                   `json:"clinic_id,omitempty"`
+	Title          string                 `json:"title"`
+	Description    string                 `json:"description,omitempty"`
+	StartDate      string                 `json:"start_date"`
+	StatusID       int                    `json:"status_id"`
+	Status         string                 `json:"status"`
+	CreatedByName  string                 `json:"created_by_name"`
+	TotalSteps     int                    `json:"total_steps"`
+	CompletedSteps int                    `json:"completed_steps"`
+	NextDueDate    *string                `json:"next_due_date,omitempty"`
+	EstimatedTotal float64                `json:"estimated_total"`
+	CompletedCost  float64                `json:"completed_cost"`
+	CompletedAt    *string                `json:"completed_at,omitempty"`
+	CreatedAt      string                 `json:"created_at"`
+	Steps          []TreatmentPlanStepDTO `json:"steps"`
+}
+
+type TreatmentFollowUpDTO struct {
+	StepID        string  `json:"step_id"`
+	StepOrder     int     `json:"step_order"`
+	Procedure     string  `json:"procedure"`
+	DueDate       string  `json:"due_date"`
+	Date          string  `json:"date,omitempty"`
+	Time          string  `json:"time,omitempty"`
+	VetID         *string `json:"vet_id,omitempty"`
+	VetName       string  `json:"vet_name,omitempty"`
+	AppointmentID *string `json:"appointment_id,omitempty"`
+	Message       string  `json:"message,omitempty"`
+}
+
+type OverdueTreatmentStepDTO struct {
+	PlanID      string `json:"plan_id"`
+	PlanTitle   string `json:"plan_title"`
+	PetID       string `json:"pet_id"`
+	PetName     string `json:"pet_name"`
+	OwnerName   string `json:"owner_name"`
+	OwnerPhone  string `json:"owner_phone"`
+	VetName     string `json:"vet_name,omitempty"`
+	StepID      string `json:"step_id"`
+	StepOrder   int    `json:"step_order"`
+	Procedure   string `json:"procedure"`
+	DueDate     string `json:"due_date"`
+	DaysOverdue int    `json:"days_overdue"`
+}
+
+var treatmentPlanStatusNames = map[int]string{
+	entities.TreatmentPlanStatusActive:    "Activo",
+	entities.TreatmentPlanStatusCompleted: "Completado",
+	entities.TreatmentPlanStatusCancelled: "Cancelado",
+}
+
+var treatmentStepStatusNames = map[int]string{
+	entities.TreatmentStepStatusPending:   "Pendiente",
+	entities.TreatmentStepStatusScheduled: "Agendado",
+	entities.TreatmentStepStatusCompleted: "Completado",
+	entities.TreatmentStepStatusSkipped:   "Omitido",
+}
+
+func ToTreatmentPlanDTO(p *entities.TreatmentPlan) TreatmentPlanDTO {
+	now := time.Now()
+	total, completed := p.EstimatedCosts()
+	result := TreatmentPlanDTO{
+		ID:             p.ID.String(),
+		PetID:          p.PetID.String(),
+		PetName:        p.Pet.Name,
+		ClinicID:       p.ClinicID,
+		Title:          p.Title,
+		Description:    p.Description,
+		StartDate:      p.StartDate.Format("02-01-2006"),
+		StatusID:       p.StatusID,
+		Status:         treatmentPlanStatusNames[p.StatusID],
+		CreatedByName:  p.CreatedByName,
+		TotalSteps:     len(p.Steps),
+		EstimatedTotal: total,
+		CompletedCost:  completed,
+		CompletedAt:    formatOptionalTime(p.CompletedAt),
+		CreatedAt:      p.CreatedAt.Format("2006-01-02 15:04:05"),
+		Steps:          []TreatmentPlanStepDTO{},
+	}
+	if p.VetID != nil {
+		id := p.VetID.String()
+		result.VetID = &id
+	}
+	if p.Vet != nil {
+		result.VetName = p.Vet.FullName
+	}
+	for _, s := range p.Steps {
+		step := TreatmentPlanStepDTO{
+			ID:            s.ID.String(),
+			StepOrder:     s.StepOrder,
+			Procedure:     s.Procedure,
+			Notes:         s.Notes,
+			IntervalDays:  s.IntervalDays,
+			DueDate:       s.DueDate.Format("02-01-2006"),
+			EstimatedCost: s.EstimatedCost,
+			StatusID:      s.StatusID,
+			Status:        treatmentStepStatusNames[s.StatusID],
+			Overdue:       p.StatusID == entities.TreatmentPlanStatusActive && s.IsOverdue(now),
+			CompletedAt:   formatOptionalTime(s.CompletedAt),
+		}
+		if s.AppointmentID != nil {
+			id := s.AppointmentID.String()
+			step.AppointmentID = &id
+		}
+		if s.Appointment != nil {
+			step.AppointmentDate = s.Appointment.Date
+			step.AppointmentTime = s.Appointment.Time
+		}
+		switch s.StatusID {
+		case entities.TreatmentStepStatusCompleted:
+			result.CompletedSteps++
+		case entities.TreatmentStepStatusPending, entities.TreatmentStepStatusScheduled:
+			if result.NextDueDate == nil {
+				result.NextDueDate = &step.DueDate
+			}
+		}
+		result.Steps = append(result.Steps, step)
+	}
+	return result
+}
+
+func ToTreatmentPlanDTOs(list []entities.TreatmentPlan) []TreatmentPlanDTO {
+	dtos := []TreatmentPlanDTO{}
+	for i := range list {
+		dtos = append(dtos, ToTreatmentPlanDTO(&list[i]))
+	}
+	return dtos
+}
+
+func ToTreatmentFollowUpDTOs(list []entities.TreatmentFollowUp) []TreatmentFollowUpDTO {
+	dtos := []TreatmentFollowUpDTO{}
+	for _, f := range list {
+		item := TreatmentFollowUpDTO{
+			StepID:    f.Step.ID.String(),
+			StepOrder: f.Step.StepOrder,
+			Procedure: f.Step.Procedure,
+			DueDate:   f.Step.DueDate.Format("02-01-2006"),
+			Date:      f.Date,
+			Time:      f.Time,
+			VetName:   f.VetName,
+		}
+		if f.VetID != nil {
+			id := f.VetID.String()
+			item.VetID = &id
+		}
+		if f.Appointment != nil {
+			id := f.Appointment.ID.String()
+			item.AppointmentID = &id
+		}
+		if f.Date == "" {
+			item.Message = "No se encontró un turno libre; agende la cita manualmente"
+		}
+		dtos = append(dtos, item)
+	}
+	return dtos
+}
+
+func ToOverdueTreatmentStepDTOs(list []entities.TreatmentPlanStep, now time.Time) []OverdueTreatmentStepDTO {
+	dtos := []OverdueTreatmentStepDTO{}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, s := range list {
+		due := time.Date(s.DueDate.Year(), s.DueDate.Month(), s.DueDate.Day(), 0, 0, 0, 0, time.UTC)
+		item := OverdueTreatmentStepDTO{
+			PlanID:      s.PlanID.String(),
+			StepID:      s.ID.String(),
+			StepOrder:   s.StepOrder,
+			Procedure:   s.Procedure,
+			DueDate:     s.DueDate.Format("02-01-2006"),
+			DaysOverdue: int(today.Sub(due).Hours() / 24),
+		}
+		if p := s.Plan; p != nil {
+			item.PlanTitle = p.Title
+			item.PetID = p.PetID.String()
+			item.PetName = p.Pet.Name
+			item.OwnerName = p.Pet.Owner.FullName
+			item.OwnerPhone = p.Pet.Owner.Phone
+			if p.Vet != nil {
+				item.VetName = p.Vet.FullName
+			}
+		}
+		dtos = append(dtos, item)
+	}
+	return dtos
+}
@@ -1,6 +1,9 @@
 package dto
 
-import "VetiCare/entities"
+import (
+	"VetiCare/entities"
+	"VetiCare/utils"
+)
 
 type UserRoleDTO struct {
 	ID   int    `json:"id"`
@@ -17,6 +20,12 @@ type UserDTO struct {
 	RoleID   int         `json:"role_id"`
 	StatusID int         `json:"status_id"`
 	Role     UserRoleDTO `json:"role"`
+
+	NoShowCount      int  `json:"no_show_count"`
+	RequiresDeposit  bool `json:"requires_deposit"`
+	RequiresApproval bool `json:"requires_approval"`
+
+	Clinics []ClinicSummaryDTO `json:"clinics,omitempty"`
 }
 
 type UserSummaryDTO struct {
@@ -43,5 +52,9 @@ func ToUserDTO(u *entities.User) UserDTO {
 			ID:   u.Role.ID,
 			Role: u.Role.Role,
 		},
+		NoShowCount:      u.NoShowCount,
+		RequiresDeposit:  utils.RequiresDeposit(u.NoShowCount),
+		RequiresApproval: utils.RequiresApproval(u.NoShowCount),
+		Clinics:          ToClinicSummaryDTOs(u.Clinics),
 	}
 }
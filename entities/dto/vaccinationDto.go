@@ -0,0 +1,140 @@
+package dto
+
+import "VetiCare/entities"
+
+type VaccineDTO struct {
+	ID                  int    `json:"id"`
+	Name                string `json:"name"`
+	SpeciesID           *int   `json:"species_id,omitempty"`
+	SpeciesName         string `json:"species_name,omitempty"`
+	Aliases             string `json:"aliases,omitempty"`
+	DefaultIntervalDays int    `json:"default_interval_days"`
+	StatusID            int    `json:"status_id"`
+	Status              string `json:"status"`
+}
+
+type VaccinationInputDTO struct {
+	AppointmentID    *string `json:"appointment_id,omitempty"`
+	VaccineID        int     `json:"vaccine_id"`
+	Manufacturer     string  `json:"manufacturer,omitempty"`
+	LotNumber        string  `json:"lot_number,omitempty"`
+	DoseNumber       int     `json:"dose_number,omitempty"`
+	DateGiven        string  `json:"date_given"`
+	AdministeredByID *string `json:"administered_by_id,omitempty"`
+	NextDueDate      string  `json:"next_due_date,omitempty"`
+	Notes            string  `json:"notes,omitempty"`
+}
+
+type VaccinationDTO struct {
+	ID                 string  `json:"id"`
+	PetID              string  `json:"pet_id"`
+	PetName            string  `json:"pet_name"`
+	SpeciesName        string  `json:"species_name"`
+	OwnerName          string  `json:"owner_name"`
+	OwnerPhone         string  `json:"owner_phone,omitempty"`
+	AppointmentID      *string `json:"appointment_id,omitempty"`
+	VaccineID          int     `json:"vaccine_id"`
+	VaccineName        string  `json:"vaccine_name"`
+	Manufacturer       string  `json:"manufacturer,omitempty"`
+	LotNumber          string  `json:"lot_number,omitempty"`
+	DoseNumber         int     `json:"dose_number"`
+	DateGiven          string  `json:"date_given"`
+	AdministeredByID   *string `json:"administered_by_id,omitempty"`
+	AdministeredByName string  `json:"administered_by_name,omitempty"`
+	NextDueDate        *string `json:"next_due_date,omitempty"`
+	Notes              string  `json:"notes,omitempty"`
+	CreatedAt          string  `json:"created_at"`
+}
+
+type LegacyVaccinationItemDTO struct {
+	AppointmentID     string `json:"appointment_id"`
+	PetName           string `json:"pet_name"`
+	Date              string `json:"date"`
+	VaccinationStatus string `json:"vaccination_status"`
+}
+
+type LegacyVaccinationResultDTO struct {
+	Scanned   int                        `json:"scanned"`
+	Created   int                        `json:"created"`
+	Unmatched []LegacyVaccinationItemDTO `json:"unmatched"`
+}
+
+func ToVaccineDTO(v *entities.Vaccine) VaccineDTO {
+	status := "Inactiva"
+	if v.StatusID == 1 {
+		status = "Activa"
+	}
+	result := VaccineDTO{
+		ID:                  v.ID,
+		Name:                v.Name,
+		SpeciesID:           v.SpeciesID,
+		Aliases:             v.Aliases,
+		DefaultIntervalDays: v.DefaultIntervalDays,
+		StatusID:            v.StatusID,
+		Status:              status,
+	}
+	if v.Species != nil {
+		result.SpeciesName = v.Species.Name
+	}
+	return result
+}
+
+func ToVaccinationDTO(v *entities.Vaccination) VaccinationDTO {
+	result := VaccinationDTO{
+		ID:           v.ID.String(),
+		PetID:        v.PetID.String(),
+		PetName:      v.Pet.Name,
+		SpeciesName:  v.Pet.Species.Name,
+		OwnerName:    v.Pet.Owner.FullName,
+		OwnerPhone:   v.Pet.Owner.Phone,
+		VaccineID:    v.VaccineID,
+		VaccineName:  v.Vaccine.Name,
+		Manufacturer: v.Manufacturer,
+		LotNumber:    v.LotNumber,
+		DoseNumber:   v.DoseNumber,
+		DateGiven:    v.DateGiven.Format("02-01-2006"),
+		Notes:        v.Notes,
+		CreatedAt:    v.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if v.AppointmentID != nil {
+		id := v.AppointmentID.String()
+		result.AppointmentID = &id
+	}
+	if v.AdministeredByID != nil {
+		id := v.AdministeredByID.String()
+		result.AdministeredByID = &id
+		if v.AdministeredBy != nil {
+			result.AdministeredByName = v.AdministeredBy.FullName
+		}
+	}
+	if v.NextDueDate != nil {
+		next := v.NextDueDate.Format("02-01-2006")
+		result.NextDueDate = &next
+	}
+	return result
+}
+
+func ToVaccinationDTOs(list []entities.Vaccination) []VaccinationDTO {
+	dtos := []VaccinationDTO{}
+	for _, v := range list {
+		dtos = append(dtos, ToVaccinationDTO(&v))
+	}
+	return dtos
+}
+
+func ToLegacyVaccinationResultDTO(result *entities.LegacyVaccinationResult) LegacyVaccinationResultDTO {
+	out := LegacyVaccinationResultDTO{
+		Scanned:   result.Scanned,
+		Created:   result.Created,
+		Unmatched: []LegacyVaccinationItemDTO{},
+	}
+	for _, app := range result.Unmatched {
+		out.Unmatched = append(out.Unmatched, LegacyVaccinationItemDTO{
+			AppointmentID:     app.ID.String(),
+			PetName:           app.Pet.Name,
+			Date:              app.Date,
+			VaccinationStatus: app.VaccinationStatus,
+		})
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"VetiCare/entities"
+)
+
+type VitalReferenceRangeDTO struct {
+	ID           int     `json:"id"`
+	SpeciesID    int     `json:"species_id"`
+	Vital        string  `json:"vital"`
+	LifeStage    string  `json:"life_stage,omitempty"`
+	MinAgeWeeks  int     `json:"min_age_weeks"`
+	MaxAgeWeeks  *int    `json:"max_age_weeks,omitempty"`
+	Min          float64 `json:"min"`
+	Max          float64 `json:"max"`
+	PlausibleMin float64 `json:"plausible_min"`
+	PlausibleMax float64 `json:"plausible_max"`
+	StatusID     int     `json:"status_id"`
+	Status       string  `json:"status"`
+}
+
+type VitalFlagDTO struct {
+	Vital string  `json:"vital"`
+	Value float64 `json:"value"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Level string  `json:"level"`
+}
+
+func ToVitalReferenceRangeDTO(r *entities.VitalReferenceRange) VitalReferenceRangeDTO {
+	status := "Inactivo"
+	if r.StatusID == 1 {
+		status = "Activo"
+	}
+	return VitalReferenceRangeDTO{
+		ID:           r.ID,
+		SpeciesID:    r.SpeciesID,
+		Vital:        r.Vital,
+		LifeStage:    r.LifeStage,
+		MinAgeWeeks:  r.MinAgeWeeks,
+		MaxAgeWeeks:  r.MaxAgeWeeks,
+		Min:          r.Min,
+		Max:          r.Max,
+		PlausibleMin: r.PlausibleMin,
+		PlausibleMax: r.PlausibleMax,
+		StatusID:     r.StatusID,
+		Status:       status,
+	}
+}
+
+func vitalFlagDTOsOrNil(flags []entities.VitalFlag) []VitalFlagDTO {
+	if len(flags) == 0 {
+		return nil
+	}
+	dtos := make([]VitalFlagDTO, 0, len(flags))
+	for _, f := range flags {
+		dtos = append(dtos, VitalFlagDTO{Vital: f.Vital, Value: f.Value, Min: f.Min, Max: f.Max, Level: f.Level})
+	}
+	return dtos
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"VetiCare/entities"
+)
+
+type VitalSignInputDTO struct {
+	AppointmentID      *string  `json:"appointment_id,omitempty"`
+	RecordedAt         string   `json:"recorded_at,omitempty"`
+	WeightKg           *float64 `json:"weight_kg,omitempty"`
+	Temperature        *float64 `json:"temperature,omitempty"`
+	HeartRate          *int     `json:"heart_rate,omitempty"`
+	RespiratoryRate    *int     `json:"respiratory_rate,omitempty"`
+	BodyConditionScore *int     `json:"body_condition_score,omitempty"`
+	Notes              string   `json:"notes,omitempty"`
+}
+
+type VitalPointDTO struct {
+	RecordedAt         string             `json:"recorded_at"`
+	Source             string             `json:"source"`
+	AppointmentID      *string            `json:"appointment_id,omitempty"`
+	VitalSignID        *string            `json:"vital_sign_id,omitempty"`
+	WeightKg           *float64           `json:"weight_kg,omitempty"`
+	Temperature        *float64           `json:"temperature,omitempty"`
+	HeartRate          *int               `json:"heart_rate,omitempty"`
+	RespiratoryRate    *int               `json:"respiratory_rate,omitempty"`
+	BodyConditionScore *int               `json:"body_condition_score,omitempty"`
+	Changes            map[string]float64 `json:"changes"`
+
+	LabOrder *LabOrderDTO `json:"lab_order,omitempty"`
+}
+
+func ToVitalPointDTO(p *entities.VitalPoint) VitalPointDTO {
+	result := VitalPointDTO{
+		RecordedAt:         p.RecordedAt.Format("2006-01-02 15:04:05"),
+		Source:             p.Source,
+		WeightKg:           p.WeightKg,
+		Temperature:        p.Temperature,
+		HeartRate:          p.HeartRate,
+		RespiratoryRate:    p.RespiratoryRate,
+		BodyConditionScore: p.BodyConditionScore,
+		Changes:            p.Changes,
+	}
+	if p.AppointmentID != nil {
+		id := p.AppointmentID.String()
+		result.AppointmentID = &id
+	}
+	if p.VitalSignID != nil {
+		id := p.VitalSignID.String()
+		result.VitalSignID = &id
+	}
+	if p.LabOrder != nil {
+		order := ToLabOrderDTO(p.LabOrder)
+		result.LabOrder = &order
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	LabOrderStatusPending   = 1
+	LabOrderStatusSent      = 2
+	LabOrderStatusCompleted = 3
+	LabOrderStatusCancelled = 4
+
+	LabFlagLow  = "bajo"
+	LabFlagHigh = "alto"
+)
+
+// LabTest es el catálogo de pruebas de laboratorio, como un hemograma o una
+// química sanguínea. Cada prueba reporta uno o más analitos.
+type LabTest struct {
+	ID         int          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name       string       `gorm:"size:100;not null;unique" json:"name"`
+	Code       string       `gorm:"size:20" json:"code,omitempty"`
+	SampleType string       `gorm:"size:50" json:"sample_type,omitempty"`
+	StatusID   int          `gorm:"not null;default:1" json:"status_id"`
+	Analytes   []LabAnalyte `gorm:"foreignKey:LabTestID" json:"analytes,omitempty"`
+	CreatedAt  time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// LabAnalyte es un valor que reporta una prueba, con sus rangos de referencia
+// por especie. Los analitos sin rango para la especie no se marcan.
+type LabAnalyte struct {
+	ID        int                 `gorm:"primaryKey;autoIncrement" json:"id"`
+	LabTestID int                 `gorm:"not null;index" json:"lab_test_id"`
+	Name      string              `gorm:"size:100;not null" json:"name"`
+	Unit      string              `gorm:"size:30" json:"unit,omitempty"`
+	SortOrder int                 `gorm:"not null;default:0" json:"sort_order"`
+	StatusID  int                 `gorm:"not null;default:1" json:"status_id"`
+	Ranges    []LabReferenceRange `gorm:"foreignKey:AnalyteID" json:"ranges,omitempty"`
+}
+
+type LabReferenceRange struct {
+	ID        int     `gorm:"primaryKey;autoIncrement" json:"id"`
+	AnalyteID int     `gorm:"not null;uniqueIndex:idx_lab_range_species" json:"analyte_id"`
+	SpeciesID int     `gorm:"not null;uniqueIndex:idx_lab_range_species" json:"species_id"`
+	Min       float64 `gorm:"not null" json:"min"`
+	Max       float64 `gorm:"not null" json:"max"`
+}
+
+// LabOrder es una prueba solicitada durante una cita. Laboratory indica a
+// qué laboratorio se envió la muestra; External distingue los laboratorios
+// de referencia del laboratorio de la clínica.
+type LabOrder struct {
+	ID            uuid.UUID   `gorm:"type:uuid;primaryKey" json:"id"`
+	AppointmentID uuid.UUID   `gorm:"type:uuid;not null;index" json:"appointment_id"`
+	PetID         uuid.UUID   `gorm:"type:uuid;not null;index" json:"pet_id"`
+	LabTestID     int         `gorm:"not null" json:"lab_test_id"`
+	LabTest       LabTest     `gorm:"foreignKey:LabTestID" json:"lab_test"`
+	OrderedByID   *uuid.UUID  `gorm:"type:uuid" json:"ordered_by_id,omitempty"`
+	OrderedByName string      `gorm:"size:100" json:"ordered_by_name"`
+	Laboratory    string      `gorm:"size:100;not null" json:"laboratory"`
+	External      bool        `gorm:"not null;default:false" json:"external"`
+	StatusID      int         `gorm:"not null;default:1;index" json:"status_id"`
+	Notes         string      `gorm:"size:500" json:"notes,omitempty"`
+	SampleSentAt  *time.Time  `json:"sample_sent_at,omitempty"`
+	ResultedAt    *time.Time  `json:"resulted_at,omitempty"`
+	ResultNotes   string      `gorm:"size:1000" json:"result_notes,omitempty"`
+	Results       []LabResult `gorm:"foreignKey:LabOrderID" json:"results,omitempty"`
+	CreatedAt     time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// LabResult guarda el valor de un analito. El rango aplicado se copia al
+// registrar el resultado para que el historial no cambie si después se
+// ajusta el catálogo.
+type LabResult struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	LabOrderID uuid.UUID  `gorm:"type:uuid;not null;index" json:"lab_order_id"`
+	AnalyteID  int        `gorm:"not null" json:"analyte_id"`
+	Analyte    LabAnalyte `gorm:"foreignKey:AnalyteID" json:"analyte"`
+	Value      *float64   `json:"value,omitempty"`
+	TextValue  string     `gorm:"size:100" json:"text_value,omitempty"`
+	RefMin     *float64   `json:"ref_min,omitempty"`
+	RefMax     *float64   `json:"ref_max,omitempty"`
+	Flag       string     `gorm:"size:10" json:"flag,omitempty"`
+}
+
+// RangeFor devuelve el rango de referencia del analito para la especie.
+func (a *LabAnalyte) RangeFor(speciesID int) *LabReferenceRange {
+	for i := range a.Ranges {
+		if a.Ranges[i].SpeciesID == speciesID {
+			return &a.Ranges[i]
+		}
+	}
+	return nil
+}
+
+// ApplyRange copia el rango al resultado y lo marca como bajo o alto si el
+// valor numérico queda fuera.
+func (r *LabResult) ApplyRange(rng *LabReferenceRange) {
+	r.RefMin, r.RefMax, r.Flag = nil, nil, ""
+	if rng == nil {
+		return
+	}
+	min, max := rng.Min, rng.Max
+	r.RefMin, r.RefMax = &min, &max
+	if r.Value == nil {
+		return
+	}
+	switch {
+	case *r.Value < min:
+		r.Flag = LabFlagLow
+	case *r.Value > max:
+		r.Flag = LabFlagHigh
+	}
+}
+
+// AbnormalResults devuelve los resultados marcados fuera de rango.
+func (o *LabOrder) AbnormalResults() []LabResult {
+	var list []LabResult
+	for _, r := range o.Results {
+		if r.Flag != "" {
+			list = append(list, r)
+		}
+	}
+	return list
+}
+
+func (o *LabOrder) BeforeCreate(tx *gorm.DB) (err error) {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	return
+}
+
+func (r *LabResult) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return
+}
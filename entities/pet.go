@@ -14,10 +14,29 @@ type Pet struct {
 	BirthDate *time.Time `json:"birth_date,omitempty"`
 	SpeciesID int        `gorm:"not null" json:"species_id"`
 	Breed     *string    `gorm:"size:50" json:"breed,omitempty"`
+	BreedID   *int       `gorm:"index" json:"breed_id,omitempty"`
+	BreedInfo *Breed     `gorm:"foreignKey:BreedID" json:"breed_info,omitempty"`
 	StatusID  int        `gorm:"not null;default:1" json:"status_id"`
 	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 	Species   Species    `gorm:"foreignKey:SpeciesID" json:"species"`
+
+	Allergies      []PetAllergy       `gorm:"foreignKey:PetID" json:"allergies,omitempty"`
+	Conditions     []PetCondition     `gorm:"foreignKey:PetID" json:"conditions,omitempty"`
+	BehaviorAlerts []PetBehaviorAlert `gorm:"foreignKey:PetID" json:"behavior_alerts,omitempty"`
+
+	// Microchip es el número ISO 11784/11785 de 15 dígitos, guardado sin
+	// espacios. Es único entre todas las mascotas; queda vacío (NULL) si la
+	// mascota no tiene chip.
+	Microchip            *string    `gorm:"size:15;uniqueIndex" json:"microchip,omitempty"`
+	MicrochipImplantedAt *time.Time `gorm:"type:date" json:"microchip_implanted_at,omitempty"`
+	MicrochipLocation    string     `gorm:"size:100" json:"microchip_location,omitempty"`
+
+	// PhotoVersion identifica la foto actual; cambia con cada foto nueva para
+	// que la URL anterior deje de usarse y los navegadores puedan guardar la
+	// imagen en caché sin revalidarla.
+	PhotoVersion   string     `gorm:"size:36" json:"-"`
+	PhotoUpdatedAt *time.Time `json:"photo_updated_at,omitempty"`
 }
 
 func (p *Pet) BeforeCreate(tx *gorm.DB) (err error) {
@@ -26,3 +45,34 @@ func (p *Pet) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return
 }
+
+// PhotoKey devuelve la clave de almacenamiento de la foto o de su miniatura.
+func (p *Pet) PhotoKey(thumbnail bool) string {
+	suffix := ".jpg"
+	if thumbnail {
+		suffix = "-thumb.jpg"
+	}
+	return "pets/" + p.ID.String() + "/photo/" + p.PhotoVersion + suffix
+}
+
+// BreedName devuelve la raza para mostrar: el nombre del catálogo o, si la
+// raza es mestiza u otra, el texto libre que la detalla. Las mascotas aún no
+// migradas al catálogo conservan solo el texto libre.
+func (p *Pet) BreedName() string {
+	if p.BreedInfo != nil && (!p.BreedInfo.IsMixed || p.Breed == nil || *p.Breed == "") {
+		return p.BreedInfo.Name
+	}
+	if p.Breed != nil {
+		return *p.Breed
+	}
+	return ""
+}
+
+// AgeInWeeks calcula la edad de la mascota en semanas a partir de BirthDate;
+// devuelve false si no se registró la fecha de nacimiento.
+func (p *Pet) AgeInWeeks(now time.Time) (int, bool) {
+	if p.BirthDate == nil {
+		return 0, false
+	}
+	return int(now.Sub(*p.BirthDate).Hours() / (24 * 7)), true
+}
@@ -0,0 +1,117 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	AllergySeverityMild     = "leve"
+	AllergySeverityModerate = "moderada"
+	AllergySeveritySevere   = "severa"
+
+	PetAlertAllergy   = "alergia"
+	PetAlertCondition = "condicion"
+	PetAlertBehavior  = "conducta"
+)
+
+// PetAllergy registra una alergia de la mascota. StatusID 2 indica que se
+// descartó o ya no aplica.
+type PetAllergy struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	PetID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"pet_id"`
+	Agent        string     `gorm:"size:100;not null" json:"agent"`
+	Reaction     string     `gorm:"size:200" json:"reaction,omitempty"`
+	Severity     string     `gorm:"size:20;not null" json:"severity"`
+	Notes        string     `gorm:"size:300" json:"notes,omitempty"`
+	StatusID     int        `gorm:"not null;default:1" json:"status_id"`
+	RecordedByID *uuid.UUID `gorm:"type:uuid" json:"recorded_by_id,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// PetCondition registra una enfermedad crónica de la mascota, como diabetes o
+// epilepsia. Critical la muestra como alerta en todas las vistas de la
+// mascota.
+type PetCondition struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	PetID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"pet_id"`
+	Name         string     `gorm:"size:120;not null" json:"name"`
+	DiagnosedOn  *time.Time `gorm:"type:date" json:"diagnosed_on,omitempty"`
+	Critical     bool       `gorm:"not null;default:false" json:"critical"`
+	Notes        string     `gorm:"size:300" json:"notes,omitempty"`
+	StatusID     int        `gorm:"not null;default:1" json:"status_id"`
+	RecordedByID *uuid.UUID `gorm:"type:uuid" json:"recorded_by_id,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// PetBehaviorAlert es un aviso de manejo para el personal, por ejemplo
+// "muerde, usar bozal".
+type PetBehaviorAlert struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	PetID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"pet_id"`
+	Description  string     `gorm:"size:200;not null" json:"description"`
+	Critical     bool       `gorm:"not null;default:false" json:"critical"`
+	StatusID     int        `gorm:"not null;default:1" json:"status_id"`
+	RecordedByID *uuid.UUID `gorm:"type:uuid" json:"recorded_by_id,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// PetAlert resume una alerta crítica de la mascota para mostrarla junto a
+// sus datos.
+type PetAlert struct {
+	Type        string
+	Description string
+}
+
+// CriticalAlerts reúne las alergias severas, las condiciones crónicas
+// críticas y los avisos de conducta críticos que estén activos. Las
+// relaciones deben venir precargadas.
+func (p *Pet) CriticalAlerts() []PetAlert {
+	var alerts []PetAlert
+	for _, a := range p.Allergies {
+		if a.StatusID == 1 && a.Severity == AllergySeveritySevere {
+			description := a.Agent
+			if a.Reaction != "" {
+				description += " (" + a.Reaction + ")"
+			}
+			alerts = append(alerts, PetAlert{Type: PetAlertAllergy, Description: description})
+		}
+	}
+	for _, c := range p.Conditions {
+		if c.StatusID == 1 && c.Critical {
+			alerts = append(alerts, PetAlert{Type: PetAlertCondition, Description: c.Name})
+		}
+	}
+	for _, b := range p.BehaviorAlerts {
+		if b.StatusID == 1 && b.Critical {
+			alerts = append(alerts, PetAlert{Type: PetAlertBehavior, Description: b.Description})
+		}
+	}
+	return alerts
+}
+
+func (a *PetAllergy) BeforeCreate(tx *gorm.DB) (err error) {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return
+}
+
+func (c *PetCondition) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
+
+func (b *PetBehaviorAlert) BeforeCreate(tx *gorm.DB) (err error) {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,107 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	PrescriptionStatusActive = 1
+	PrescriptionStatusVoided = 2
+)
+
+// Prescription es una receta firmada por el veterinario al momento de
+// crearla. Una vez firmada no se edita: si tiene un error se anula y se emite
+// una nueva.
+type Prescription struct {
+	ID            uuid.UUID          `gorm:"type:uuid;primaryKey" json:"id"`
+	AppointmentID uuid.UUID          `gorm:"type:uuid;not null;index" json:"appointment_id"`
+	Appointment   *Appointment       `gorm:"foreignKey:AppointmentID" json:"appointment,omitempty"`
+	PetID         uuid.UUID          `gorm:"type:uuid;not null;index" json:"pet_id"`
+	Pet           Pet                `gorm:"foreignKey:PetID" json:"pet"`
+	VetID         uuid.UUID          `gorm:"type:uuid;not null" json:"vet_id"`
+	Vet           User               `gorm:"foreignKey:VetID" json:"vet"`
+	StatusID      int                `gorm:"not null;default:1" json:"status_id"`
+	Notes         string             `gorm:"size:500" json:"notes,omitempty"`
+	SignedAt      time.Time          `gorm:"not null" json:"signed_at"`
+	Signature     string             `gorm:"size:64;not null" json:"signature"`
+	VoidedAt      *time.Time         `json:"voided_at,omitempty"`
+	VoidedByID    *uuid.UUID         `gorm:"type:uuid" json:"voided_by_id,omitempty"`
+	VoidReason    string             `gorm:"size:300" json:"void_reason,omitempty"`
+	Items         []PrescriptionItem `gorm:"foreignKey:PrescriptionID" json:"items"`
+	CreatedAt     time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// AllergyWarnings no se guarda; se calcula al emitir la receta.
+	AllergyWarnings []string `gorm:"-" json:"allergy_warnings,omitempty"`
+}
+
+type PrescriptionItem struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	PrescriptionID uuid.UUID `gorm:"type:uuid;not null;index" json:"prescription_id"`
+	Drug           string    `gorm:"size:120;not null" json:"drug"`
+	Strength       string    `gorm:"size:50" json:"strength,omitempty"`
+	Dose           string    `gorm:"size:50;not null" json:"dose"`
+	Route          string    `gorm:"size:30;not null" json:"route"`
+	Frequency      string    `gorm:"size:50;not null" json:"frequency"`
+	DurationDays   int       `gorm:"not null" json:"duration_days"`
+	Quantity       string    `gorm:"size:50" json:"quantity,omitempty"`
+	Instructions   string    `gorm:"size:300" json:"instructions,omitempty"`
+}
+
+// EndsOn devuelve el día en que termina el tratamiento de un medicamento,
+// contando desde la firma de la receta.
+func (i PrescriptionItem) EndsOn(signedAt time.Time) time.Time {
+	start := time.Date(signedAt.Year(), signedAt.Month(), signedAt.Day(), 0, 0, 0, 0, signedAt.Location())
+	return start.AddDate(0, 0, i.DurationDays)
+}
+
+// ContentHash calcula la firma de la receta a partir de su contenido. Se
+// guarda al firmarla y permite detectar cambios posteriores en la base de
+// datos.
+func (p *Prescription) ContentHash() string {
+	// Los medicamentos se ordenan para que la firma no dependa del orden en
+	// que la base de datos los devuelve.
+	var lines []string
+	for _, i := range p.Items {
+		lines = append(lines, fmt.Sprintf("%s|%s|%s|%s|%s|%d|%s|%s",
+			i.Drug, i.Strength, i.Dose, i.Route, i.Frequency, i.DurationDays, i.Quantity, i.Instructions))
+	}
+	sort.Strings(lines)
+	header := fmt.Sprintf("%s|%s|%s|%s|%s", p.AppointmentID, p.PetID, p.VetID, p.SignedAt.UTC().Format(time.RFC3339), p.Notes)
+	sum := sha256.Sum256([]byte(header + "\n" + strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+type ActiveMedication struct {
+	Item         PrescriptionItem
+	Prescription Prescription
+	EndsOn       time.Time
+}
+
+type MedicalHistory struct {
+	Appointments      []Appointment
+	ActiveMedications []ActiveMedication
+	Attachments       []Attachment
+}
+
+func (p *Prescription) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return
+}
+
+func (i *PrescriptionItem) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,102 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	CareTypeVaccination = "vacuna"
+	CareTypeDeworming   = "desparasitacion"
+)
+
+type Deworming struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	PetID            uuid.UUID  `gorm:"type:uuid;not null;index" json:"pet_id"`
+	Pet              Pet        `gorm:"foreignKey:PetID" json:"pet"`
+	AppointmentID    *uuid.UUID `gorm:"type:uuid;index" json:"appointment_id,omitempty"`
+	Product          string     `gorm:"size:120;not null" json:"product"`
+	Dose             string     `gorm:"size:50" json:"dose,omitempty"`
+	WeightKg         *float64   `gorm:"type:numeric(5,2)" json:"weight_kg,omitempty"`
+	DateGiven        time.Time  `gorm:"type:date;not null" json:"date_given"`
+	AdministeredByID *uuid.UUID `gorm:"type:uuid" json:"administered_by_id,omitempty"`
+	AdministeredBy   *User      `gorm:"foreignKey:AdministeredByID" json:"administered_by,omitempty"`
+	NextDueDate      *time.Time `gorm:"type:date;index" json:"next_due_date,omitempty"`
+	Notes            string     `gorm:"size:500" json:"notes,omitempty"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// PreventiveProtocol define, por especie y rango de edad, qué cuidado
+// preventivo corresponde y cada cuántos días se repite. MaxAgeWeeks vacío
+// indica que aplica de por vida.
+type PreventiveProtocol struct {
+	ID           int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	SpeciesID    int       `gorm:"not null;index" json:"species_id"`
+	Species      Species   `gorm:"foreignKey:SpeciesID" json:"species"`
+	Name         string    `gorm:"size:100;not null" json:"name"`
+	CareType     string    `gorm:"size:20;not null" json:"care_type"`
+	VaccineID    *int      `json:"vaccine_id,omitempty"`
+	Vaccine      *Vaccine  `gorm:"foreignKey:VaccineID" json:"vaccine,omitempty"`
+	MinAgeWeeks  int       `gorm:"not null;default:0" json:"min_age_weeks"`
+	MaxAgeWeeks  *int      `json:"max_age_weeks,omitempty"`
+	IntervalDays int       `gorm:"not null" json:"interval_days"`
+	StatusID     int       `gorm:"not null;default:1" json:"status_id"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// AppliesTo indica si el protocolo corresponde a una mascota de la edad dada.
+func (p PreventiveProtocol) AppliesTo(ageWeeks int) bool {
+	if ageWeeks < p.MinAgeWeeks {
+		return false
+	}
+	return p.MaxAgeWeeks == nil || ageWeeks < *p.MaxAgeWeeks
+}
+
+// CareReminder registra cada recordatorio enviado. Key identifica la mascota,
+// el cuidado y su fecha de vencimiento para no enviar el mismo aviso dos
+// veces.
+type CareReminder struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Key      string    `gorm:"size:200;not null;uniqueIndex" json:"key"`
+	PetID    uuid.UUID `gorm:"type:uuid;not null;index" json:"pet_id"`
+	CareType string    `gorm:"size:20;not null" json:"care_type"`
+	CareName string    `gorm:"size:100;not null" json:"care_name"`
+	DueDate  time.Time `gorm:"type:date;not null" json:"due_date"`
+	Email    string    `gorm:"size:100;not null" json:"email"`
+	SentAt   time.Time `gorm:"autoCreateTime" json:"sent_at"`
+}
+
+// CareDueItem es un cuidado preventivo pendiente de una mascota. ReferenceID
+// apunta a la última dosis registrada o al protocolo cuando la mascota aún no
+// recibe la primera.
+type CareDueItem struct {
+	Pet            Pet
+	CareType       string
+	CareName       string
+	ReferenceID    string
+	LastGiven      *time.Time
+	DueDate        time.Time
+	LastReminderAt *time.Time
+}
+
+func (i CareDueItem) ReminderKey() string {
+	return i.Pet.ID.String() + "|" + i.CareType + "|" + i.ReferenceID + "|" + i.DueDate.Format("2006-01-02")
+}
+
+func (d *Deworming) BeforeCreate(tx *gorm.DB) (err error) {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return
+}
+
+func (c *CareReminder) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,15 @@
+package entities
+
+import "github.com/google/uuid"
+
+type QueueEntry struct {
+	Position    int
+	WaitMinutes int
+	Appointment Appointment
+}
+
+type VetQueue struct {
+	VetID   *uuid.UUID
+	VetName string
+	Entries []QueueEntry
+}
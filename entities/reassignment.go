@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ReassignmentActionReassign   = "reasignar"
+	ReassignmentActionReschedule = "reprogramar"
+	ReassignmentActionUnresolved = "sin_opcion"
+)
+
+// ReassignmentProposal describe qué hacer con una cita de un veterinario que
+// dejó de estar disponible: pasarla a otro veterinario en el mismo horario,
+// moverla al siguiente turno libre o dejarla pendiente si no hay opciones.
+type ReassignmentProposal struct {
+	Appointment Appointment
+	Action      string
+	NewVetID    *uuid.UUID
+	NewVetName  string
+	NewDate     string
+	NewTime     string
+	History     *AppointmentReschedule
+}
+
+// ReassignmentHash resume un conjunto de propuestas. La vista previa lo
+// devuelve y al aplicar se vuelve a calcular: si no coincide, la agenda
+// cambió desde que se revisaron las propuestas.
+func ReassignmentHash(proposals []ReassignmentProposal) string {
+	h := sha256.New()
+	for _, p := range proposals {
+		newVetID := ""
+		if p.NewVetID != nil {
+			newVetID = p.NewVetID.String()
+		}
+		h.Write([]byte(p.Appointment.ID.String() + "|" + p.Appointment.Date + "|" + p.Appointment.Time + "|" +
+			p.Action + "|" + newVetID + "|" + p.NewDate + "|" + p.NewTime + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,47 @@
+package entities
+
+// PetRecordExport reúne todo lo que sale en el intercambio de historiales:
+// la mascota con su dueño, las citas finalizadas, los signos vitales y las
+// vacunas.
+type PetRecordExport struct {
+	Pet          *Pet
+	Appointments []Appointment
+	VitalSigns   []VitalSign
+	Vaccinations []Vaccination
+}
+
+// RecordImport es lo que se guardará al importar un historial. Owner y Pet
+// pueden ser registros existentes encontrados al deduplicar; solo se crean si
+// OwnerCreated o PetCreated están activos.
+type RecordImport struct {
+	Owner        *User
+	OwnerCreated bool
+	Pet          *Pet
+	PetCreated   bool
+	Appointments []Appointment
+	VitalSigns   []VitalSign
+	Vaccinations []Vaccination
+
+	// OwnerPassword es la contraseña generada para el dueño nuevo; se envía
+	// por correo y no se guarda en claro.
+	OwnerPassword string
+}
+
+// RecordImportResult resume la importación. Los recursos omitidos son los
+// que ya existían o no se pudieron interpretar; Warnings explica cada caso.
+type RecordImportResult struct {
+	DryRun               bool
+	OwnerID              string
+	OwnerCreated         bool
+	PetID                string
+	PetCreated           bool
+	EncountersCreated    int
+	EncountersSkipped    int
+	ObservationsCreated  int
+	ObservationsSkipped  int
+	ImmunizationsCreated int
+	ImmunizationsSkipped int
+	MedicationsImported  int
+	MedicationsSkipped   int
+	Warnings             []string
+}
@@ -0,0 +1,93 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ShareScopeHistory      = "historial"
+	ShareScopeVaccinations = "vacunas"
+
+	ShareLinkMaxPinAttempts = 5
+)
+
+// ShareLink permite que alguien sin cuenta, como un especialista o una
+// guardería, consulte el historial o solo las vacunas de una mascota hasta
+// ExpiresAt. El PIN es opcional y se guarda cifrado con bcrypt.
+type ShareLink struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	PetID             uuid.UUID  `gorm:"type:uuid;not null;index" json:"pet_id"`
+	Pet               Pet        `gorm:"foreignKey:PetID" json:"pet"`
+	Scope             string     `gorm:"size:20;not null" json:"scope"`
+	Recipient         string     `gorm:"size:150" json:"recipient,omitempty"`
+	CreatedByID       uuid.UUID  `gorm:"type:uuid;not null" json:"created_by_id"`
+	CreatedByName     string     `gorm:"size:100" json:"created_by_name"`
+	ExpiresAt         time.Time  `gorm:"not null" json:"expires_at"`
+	PinHash           string     `gorm:"size:100" json:"-"`
+	FailedPinAttempts int        `gorm:"not null;default:0" json:"failed_pin_attempts"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty"`
+	RevokedByName     string     `gorm:"size:100" json:"revoked_by_name,omitempty"`
+	LastAccessedAt    *time.Time `json:"last_accessed_at,omitempty"`
+	AccessCount       int        `gorm:"not null;default:0" json:"access_count"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ShareLinkAccess registra cada intento de abrir un enlace compartido, se
+// haya concedido o no. Reason explica el rechazo.
+type ShareLinkAccess struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	LinkID     uuid.UUID `gorm:"type:uuid;not null;index" json:"link_id"`
+	AccessedAt time.Time `gorm:"not null" json:"accessed_at"`
+	IPAddress  string    `gorm:"size:64" json:"ip_address"`
+	UserAgent  string    `gorm:"size:300" json:"user_agent,omitempty"`
+	Granted    bool      `gorm:"not null" json:"granted"`
+	Reason     string    `gorm:"size:100" json:"reason,omitempty"`
+}
+
+// SharedRecord es lo que ve el destinatario del enlace. Appointments queda
+// vacío cuando el enlace solo comparte las vacunas.
+type SharedRecord struct {
+	Link         *ShareLink
+	Pet          *Pet
+	Vaccinations []Vaccination
+	Appointments []Appointment
+}
+
+func (l *ShareLink) BeforeCreate(tx *gorm.DB) (err error) {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return
+}
+
+func (a *ShareLinkAccess) BeforeCreate(tx *gorm.DB) (err error) {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return
+}
+
+func (l *ShareLink) HasPin() bool {
+	return l.PinHash != ""
+}
+
+// IsActive indica si el enlace todavía puede abrirse: no fue revocado, no
+// venció y no se bloqueó por intentos fallidos de PIN.
+func (l *ShareLink) IsActive(now time.Time) bool {
+	return l.Status(now) == "Activo"
+}
+
+func (l *ShareLink) Status(now time.Time) string {
+	switch {
+	case l.RevokedAt != nil:
+		return "Revocado"
+	case !now.Before(l.ExpiresAt):
+		return "Vencido"
+	case l.FailedPinAttempts >= ShareLinkMaxPinAttempts:
+		return "Bloqueado"
+	}
+	return "Activo"
+}
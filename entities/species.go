@@ -10,4 +10,6 @@ type Species struct {
 	ImageURL  string    `gorm:"size:255" json:"image_url"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	ReferenceRanges []VitalReferenceRange `gorm:"foreignKey:SpeciesID" json:"reference_ranges,omitempty"`
 }
@@ -0,0 +1,141 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	TreatmentPlanStatusActive    = 1
+	TreatmentPlanStatusCompleted = 2
+	TreatmentPlanStatusCancelled = 3
+
+	TreatmentStepStatusPending   = 1
+	TreatmentStepStatusScheduled = 2
+	TreatmentStepStatusCompleted = 3
+	TreatmentStepStatusSkipped   = 4
+)
+
+// TreatmentPlan agrupa las visitas de un tratamiento que se extiende en el
+// tiempo, como el control posquirúrgico o un plan dental. VetID es el
+// veterinario responsable y se usa al sugerir las citas de seguimiento.
+type TreatmentPlan struct {
+	ID            uuid.UUID           `gorm:"type:uuid;primaryKey" json:"id"`
+	PetID         uuid.UUID           `gorm:"type:uuid;not null;index" json:"pet_id"`
+	Pet           Pet                 `gorm:"foreignKey:PetID" json:"pet"`
+	VetID         *uuid.UUID          `gorm:"type:uuid" json:"vet_id,omitempty"`
+	Vet           *User               `gorm:"foreignKey:VetID" json:"vet,omitempty"`
+	ClinicID      *int                `json:"clinic_id,omitempty"`
+	Title         string              `gorm:"size:150;not null" json:"title"`
+	Description   string              `gorm:"size:1000" json:"description,omitempty"`
+	StartDate     time.Time           `gorm:"type:date;not null" json:"start_date"`
+	StatusID      int                 `gorm:"not null;default:1;index" json:"status_id"`
+	CreatedByID   *uuid.UUID          `gorm:"type:uuid" json:"created_by_id,omitempty"`
+	CreatedByName string              `gorm:"size:100" json:"created_by_name"`
+	CompletedAt   *time.Time          `json:"completed_at,omitempty"`
+	Steps         []TreatmentPlanStep `gorm:"foreignKey:PlanID" json:"steps,omitempty"`
+	CreatedAt     time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TreatmentPlanStep es una visita del plan. IntervalDays cuenta los días
+// desde el paso anterior, o desde el inicio del plan en el primer paso.
+type TreatmentPlanStep struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	PlanID        uuid.UUID      `gorm:"type:uuid;not null;index" json:"plan_id"`
+	Plan          *TreatmentPlan `gorm:"foreignKey:PlanID" json:"plan,omitempty"`
+	StepOrder     int            `gorm:"not null" json:"step_order"`
+	Procedure     string         `gorm:"size:150;not null" json:"procedure"`
+	Notes         string         `gorm:"size:500" json:"notes,omitempty"`
+	IntervalDays  int            `gorm:"not null;default:0" json:"interval_days"`
+	DueDate       time.Time      `gorm:"type:date;not null;index" json:"due_date"`
+	EstimatedCost float64        `gorm:"type:numeric(10,2);not null;default:0" json:"estimated_cost"`
+	AppointmentID *uuid.UUID     `gorm:"type:uuid;index" json:"appointment_id,omitempty"`
+	Appointment   *Appointment   `gorm:"foreignKey:AppointmentID" json:"appointment,omitempty"`
+	StatusID      int            `gorm:"not null;default:1" json:"status_id"`
+	CompletedAt   *time.Time     `json:"completed_at,omitempty"`
+}
+
+// TreatmentFollowUp es la cita de seguimiento sugerida para un paso
+// pendiente. Date queda vacío si no se encontró un turno libre.
+type TreatmentFollowUp struct {
+	Step        TreatmentPlanStep
+	Date        string
+	Time        string
+	VetID       *uuid.UUID
+	VetName     string
+	Appointment *Appointment
+}
+
+// RecalculateDueDates encadena las fechas de los pasos: cada paso vence
+// IntervalDays después del anterior. Un paso completado desplaza a los
+// siguientes según la fecha en que realmente se hizo.
+func (p *TreatmentPlan) RecalculateDueDates() {
+	base := p.StartDate
+	for i := range p.Steps {
+		step := &p.Steps[i]
+		if step.StatusID == TreatmentStepStatusCompleted && step.CompletedAt != nil {
+			done := *step.CompletedAt
+			base = time.Date(done.Year(), done.Month(), done.Day(), 0, 0, 0, 0, p.StartDate.Location())
+			continue
+		}
+		step.DueDate = base.AddDate(0, 0, step.IntervalDays)
+		base = step.DueDate
+	}
+}
+
+// IsFinished indica si todos los pasos se completaron u omitieron.
+func (p *TreatmentPlan) IsFinished() bool {
+	for _, s := range p.Steps {
+		if s.StatusID != TreatmentStepStatusCompleted && s.StatusID != TreatmentStepStatusSkipped {
+			return false
+		}
+	}
+	return len(p.Steps) > 0
+}
+
+// EstimatedCosts devuelve el costo estimado del plan y el de los pasos ya
+// completados.
+func (p *TreatmentPlan) EstimatedCosts() (total, completed float64) {
+	for _, s := range p.Steps {
+		if s.StatusID == TreatmentStepStatusSkipped {
+			continue
+		}
+		total += s.EstimatedCost
+		if s.StatusID == TreatmentStepStatusCompleted {
+			completed += s.EstimatedCost
+		}
+	}
+	return total, completed
+}
+
+// StepByID busca el paso dentro del plan.
+func (p *TreatmentPlan) StepByID(id string) *TreatmentPlanStep {
+	for i := range p.Steps {
+		if p.Steps[i].ID.String() == id {
+			return &p.Steps[i]
+		}
+	}
+	return nil
+}
+
+// IsOverdue indica si el paso venció sin que se agendara su cita.
+func (s *TreatmentPlanStep) IsOverdue(now time.Time) bool {
+	return s.StatusID == TreatmentStepStatusPending && s.DueDate.Format("2006-01-02") < now.Format("2006-01-02")
+}
+
+func (p *TreatmentPlan) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return
+}
+
+func (s *TreatmentPlanStep) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return
+}
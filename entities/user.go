@@ -15,11 +15,13 @@ type User struct {
 	PasswordHash string    `gorm:"size:175" json:"password_hash,omitempty"`
 	RoleID       int       `gorm:"not null" json:"role_id"`
 	StatusID     int       `gorm:"not null;default:1" json:"status_id"`
+	NoShowCount  int       `gorm:"not null;default:0" json:"no_show_count"`
 	Token        string    `gorm:"size:175" json:"token,omitempty"`
 	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 
-	Role UserRole `gorm:"foreignKey:RoleID;references:ID" json:"role"`
+	Role    UserRole `gorm:"foreignKey:RoleID;references:ID" json:"role"`
+	Clinics []Clinic `gorm:"many2many:vet_clinics;" json:"clinics,omitempty"`
 }
 
 type VetAppointments struct {
@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Vaccine es el catálogo de vacunas. SpeciesID vacío indica que la vacuna
+// aplica a cualquier especie. Aliases guarda, separados por comas, los
+// nombres con los que suele escribirse en el texto libre de las citas.
+type Vaccine struct {
+	ID                  int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name                string    `gorm:"size:100;not null;unique" json:"name"`
+	SpeciesID           *int      `json:"species_id,omitempty"`
+	Species             *Species  `gorm:"foreignKey:SpeciesID" json:"species,omitempty"`
+	Aliases             string    `gorm:"size:300" json:"aliases,omitempty"`
+	DefaultIntervalDays int       `gorm:"not null;default:365" json:"default_interval_days"`
+	StatusID            int       `gorm:"not null;default:1" json:"status_id"`
+	CreatedAt           time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type Vaccination struct {
+	ID               uuid.UUID    `gorm:"type:uuid;primaryKey" json:"id"`
+	PetID            uuid.UUID    `gorm:"type:uuid;not null;index" json:"pet_id"`
+	Pet              Pet          `gorm:"foreignKey:PetID" json:"pet"`
+	AppointmentID    *uuid.UUID   `gorm:"type:uuid;index" json:"appointment_id,omitempty"`
+	Appointment      *Appointment `gorm:"foreignKey:AppointmentID" json:"appointment,omitempty"`
+	VaccineID        int          `gorm:"not null;index" json:"vaccine_id"`
+	Vaccine          Vaccine      `gorm:"foreignKey:VaccineID" json:"vaccine"`
+	Manufacturer     string       `gorm:"size:100" json:"manufacturer,omitempty"`
+	LotNumber        string       `gorm:"size:50" json:"lot_number,omitempty"`
+	DoseNumber       int          `gorm:"not null;default:1" json:"dose_number"`
+	DateGiven        time.Time    `gorm:"type:date;not null" json:"date_given"`
+	AdministeredByID *uuid.UUID   `gorm:"type:uuid" json:"administered_by_id,omitempty"`
+	AdministeredBy   *User        `gorm:"foreignKey:AdministeredByID" json:"administered_by,omitempty"`
+	NextDueDate      *time.Time   `gorm:"type:date;index" json:"next_due_date,omitempty"`
+	Notes            string       `gorm:"size:500" json:"notes,omitempty"`
+	CreatedAt        time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type VaccinationDueFilter struct {
+	From      time.Time
+	To        time.Time
+	VaccineID *int
+	SpeciesID *int
+}
+
+// LegacyVaccinationResult resume la migración del texto libre
+// Appointment.VaccinationStatus a registros de vacunación.
+type LegacyVaccinationResult struct {
+	Scanned   int
+	Created   int
+	Unmatched []Appointment
+}
+
+func (v *Vaccination) BeforeCreate(tx *gorm.DB) (err error) {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return
+}
@@ -0,0 +1,162 @@
+package entities
+
+import (
+	"time"
+)
+
+const (
+	VitalWeight          = "weight_kg"
+	VitalTemperature     = "temperature"
+	VitalHeartRate       = "heart_rate"
+	VitalRespiratoryRate = "respiratory_rate"
+
+	VitalFlagLow         = "bajo"
+	VitalFlagHigh        = "alto"
+	VitalFlagImplausible = "no plausible"
+)
+
+// VitalReferenceRange define el rango normal de un signo vital para una
+// especie y, opcionalmente, una etapa de vida. Los valores fuera de
+// [PlausibleMin, PlausibleMax] se consideran errores de captura y se rechazan.
+// Un rango sin edades aplica a toda la especie.
+type VitalReferenceRange struct {
+	ID           int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	SpeciesID    int       `gorm:"not null;index" json:"species_id"`
+	Vital        string    `gorm:"size:30;not null" json:"vital"`
+	LifeStage    string    `gorm:"size:50" json:"life_stage,omitempty"`
+	MinAgeWeeks  int       `gorm:"not null;default:0" json:"min_age_weeks"`
+	MaxAgeWeeks  *int      `json:"max_age_weeks,omitempty"`
+	Min          float64   `gorm:"not null" json:"min"`
+	Max          float64   `gorm:"not null" json:"max"`
+	PlausibleMin float64   `gorm:"not null" json:"plausible_min"`
+	PlausibleMax float64   `gorm:"not null" json:"plausible_max"`
+	StatusID     int       `gorm:"not null;default:1" json:"status_id"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// VitalFlag señala un signo vital fuera del rango de referencia.
+type VitalFlag struct {
+	Vital string
+	Value float64
+	Min   float64
+	Max   float64
+	Level string
+}
+
+// IsGeneral indica si el rango aplica a cualquier edad.
+func (r VitalReferenceRange) IsGeneral() bool {
+	return r.MinAgeWeeks == 0 && r.MaxAgeWeeks == nil
+}
+
+// AppliesTo indica si el rango corresponde a una mascota de la edad dada.
+func (r VitalReferenceRange) AppliesTo(ageWeeks int) bool {
+	if ageWeeks < r.MinAgeWeeks {
+		return false
+	}
+	return r.MaxAgeWeeks == nil || ageWeeks < *r.MaxAgeWeeks
+}
+
+// ReferenceRangeFor elige el rango activo de la especie para el signo vital,
+// prefiriendo el de la etapa de vida de la mascota sobre el general. Sin edad
+// conocida solo se usa el rango general.
+func (s *Species) ReferenceRangeFor(vital string, ageWeeks int, hasAge bool) *VitalReferenceRange {
+	var general *VitalReferenceRange
+	for i := range s.ReferenceRanges {
+		r := &s.ReferenceRanges[i]
+		if r.Vital != vital || r.StatusID != 1 {
+			continue
+		}
+		if r.IsGeneral() {
+			if general == nil {
+				general = r
+			}
+			continue
+		}
+		if hasAge && r.AppliesTo(ageWeeks) {
+			return r
+		}
+	}
+	return general
+}
+
+// CheckVitals compara los valores con los rangos de la especie y devuelve los
+// signos fuera del rango normal. Los valores fuera del rango plausible se
+// marcan como VitalFlagImplausible con los límites plausibles.
+func (s *Species) CheckVitals(values map[string]float64, ageWeeks int, hasAge bool) []VitalFlag {
+	var flags []VitalFlag
+	for _, vital := range []string{VitalWeight, VitalTemperature, VitalHeartRate, VitalRespiratoryRate} {
+		value, ok := values[vital]
+		if !ok {
+			continue
+		}
+		r := s.ReferenceRangeFor(vital, ageWeeks, hasAge)
+		if r == nil {
+			continue
+		}
+		flag := VitalFlag{Vital: vital, Value: value, Min: r.Min, Max: r.Max}
+		switch {
+		case value < r.PlausibleMin || value > r.PlausibleMax:
+			flag.Min, flag.Max = r.PlausibleMin, r.PlausibleMax
+			flag.Level = VitalFlagImplausible
+		case value < r.Min:
+			flag.Level = VitalFlagLow
+		case value > r.Max:
+			flag.Level = VitalFlagHigh
+		default:
+			continue
+		}
+		flags = append(flags, flag)
+	}
+	return flags
+}
+
+// FirstImplausible devuelve el primer signo marcado como no plausible.
+func FirstImplausible(flags []VitalFlag) *VitalFlag {
+	for i := range flags {
+		if flags[i].Level == VitalFlagImplausible {
+			return &flags[i]
+		}
+	}
+	return nil
+}
+
+// VitalValues reúne los signos vitales registrados en la cita.
+func (a *Appointment) VitalValues() map[string]float64 {
+	values := map[string]float64{}
+	if a.WeightKg != nil {
+		values[VitalWeight] = *a.WeightKg
+	}
+	if a.Temperature != nil {
+		values[VitalTemperature] = *a.Temperature
+	}
+	return values
+}
+
+// VitalFlags evalúa los signos de la cita con los rangos de la especie de la
+// mascota, que deben venir precargados en Pet.Species.ReferenceRanges.
+func (a *Appointment) VitalFlags() []VitalFlag {
+	ageWeeks, hasAge := a.Pet.AgeInWeeks(a.CreatedAt)
+	if t, err := time.ParseInLocation("02-01-2006", a.Date, time.Local); err == nil {
+		ageWeeks, hasAge = a.Pet.AgeInWeeks(t)
+	}
+	return a.Pet.Species.CheckVitals(a.VitalValues(), ageWeeks, hasAge)
+}
+
+// VitalValues reúne los signos vitales registrados en la toma.
+func (v *VitalSign) VitalValues() map[string]float64 {
+	values := map[string]float64{}
+	if v.WeightKg != nil {
+		values[VitalWeight] = *v.WeightKg
+	}
+	if v.Temperature != nil {
+		values[VitalTemperature] = *v.Temperature
+	}
+	if v.HeartRate != nil {
+		values[VitalHeartRate] = float64(*v.HeartRate)
+	}
+	if v.RespiratoryRate != nil {
+		values[VitalRespiratoryRate] = float64(*v.RespiratoryRate)
+	}
+	return values
+}
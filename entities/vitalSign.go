@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	VitalSourceAppointment = "cita"
+	VitalSourceRecord      = "registro"
+	VitalSourceLab         = "laboratorio"
+)
+
+// VitalSign guarda signos vitales tomados fuera de la cita (por ejemplo, un
+// pesaje en recepción) o que complementan los de una cita.
+type VitalSign struct {
+	ID                 uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	PetID              uuid.UUID  `gorm:"type:uuid;not null;index" json:"pet_id"`
+	AppointmentID      *uuid.UUID `gorm:"type:uuid;index" json:"appointment_id,omitempty"`
+	RecordedAt         time.Time  `gorm:"not null" json:"recorded_at"`
+	WeightKg           *float64   `gorm:"type:numeric(5,2)" json:"weight_kg,omitempty"`
+	Temperature        *float64   `gorm:"type:numeric(4,1)" json:"temperature,omitempty"`
+	HeartRate          *int       `json:"heart_rate,omitempty"`
+	RespiratoryRate    *int       `json:"respiratory_rate,omitempty"`
+	BodyConditionScore *int       `json:"body_condition_score,omitempty"`
+	RecordedByID       *uuid.UUID `gorm:"type:uuid" json:"recorded_by_id,omitempty"`
+	Notes              string     `gorm:"size:300" json:"notes,omitempty"`
+	CreatedAt          time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// VitalPoint es una toma de signos vitales en la serie de la mascota. Los
+// cambios porcentuales se calculan contra la toma anterior que registró el
+// mismo signo. Los puntos de laboratorio no traen signos, solo LabOrder con
+// los resultados de una orden completada.
+type VitalPoint struct {
+	RecordedAt         time.Time
+	Source             string
+	AppointmentID      *uuid.UUID
+	VitalSignID        *uuid.UUID
+	WeightKg           *float64
+	Temperature        *float64
+	HeartRate          *int
+	RespiratoryRate    *int
+	BodyConditionScore *int
+	Changes            map[string]float64
+
+	LabOrder *LabOrder
+}
+
+func (v *VitalSign) BeforeCreate(tx *gorm.DB) (err error) {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return
+}
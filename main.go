@@ -5,12 +5,14 @@ import (
 	"github.com/joho/godotenv"
 	"log"
 	"net/http"
+	"time"
 
 	"VetiCare/controllers"
 	"VetiCare/data"
 	"VetiCare/middlewares"
 	"VetiCare/repositories"
 	"VetiCare/services"
+	"VetiCare/utils"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
@@ -36,12 +38,26 @@ func main() {
 	adminService := services.NewAdminService(adminRepo)
 	adminController := controllers.NewAdminController(adminService)
 
+	calendarRepo := repositories.NewCalendarRepositoryGORM(db)
+	prescriptionRepo := repositories.NewPrescriptionRepositoryGORM(db)
+	clinicRepo := repositories.NewClinicRepositoryGORM(db)
+	clinicalNoteRepo := repositories.NewClinicalNoteRepositoryGORM(db)
+	attachmentRepo := repositories.NewAttachmentRepositoryGORM(db)
+	labRepo := repositories.NewLabRepositoryGORM(db)
+	diagnosisRepo := repositories.NewDiagnosisRepositoryGORM(db)
+	treatmentPlanRepo := repositories.NewTreatmentPlanRepositoryGORM(db)
+	shareLinkRepo := repositories.NewShareLinkRepositoryGORM(db)
+	recordInterchangeRepo := repositories.NewRecordInterchangeRepositoryGORM(db)
+	petRepo := repositories.NewPetRepositoryGORM(db)
+	speciesRepo := repositories.NewSpeciesRepositoryGORM(db)
+	petAccess := services.NewPetAccess(userRepo, adminRepo)
+
 	appointmentRepo := repositories.NewAppointmentRepositoryGORM(db)
-	appointmentService := services.NewAppointmentService(appointmentRepo)
+	appointmentService := services.NewAppointmentService(appointmentRepo, calendarRepo, clinicRepo, userRepo, prescriptionRepo, clinicalNoteRepo, attachmentRepo, treatmentPlanRepo, petRepo, petAccess)
 	appointmentController := controllers.NewAppointmentController(appointmentService)
+	go appointmentService.StartNoShowJob(time.Duration(utils.GetEnvInt("NO_SHOW_JOB_INTERVAL_MINUTES", 15)) * time.Minute)
 
-	petRepo := repositories.NewPetRepositoryGORM(db)
-	petService := services.NewPetService(petRepo)
+	petService := services.NewPetService(petRepo, speciesRepo, petAccess)
 	petController := controllers.NewPetController(petService)
 
 	adminTypeRepo := repositories.NewAdminTypeRepositoryGORM(db)
@@ -52,25 +68,112 @@ func main() {
 	userRoleService := services.NewUserRoleService(userRoleRepo)
 	userRoleController := controllers.NewUserRoleController(userRoleService)
 
-	speciesRepo := repositories.NewSpeciesRepositoryGORM(db)
-	speciesService := services.NewSpeciesService(speciesRepo)
+	speciesService := services.NewSpeciesService(speciesRepo, petRepo)
 	speciesController := controllers.NewSpeciesController(speciesService)
 
+	calendarService := services.NewCalendarService(calendarRepo, appointmentRepo, userRepo, clinicRepo)
+	calendarController := controllers.NewCalendarController(calendarService)
+
+	clinicService := services.NewClinicService(clinicRepo)
+	clinicController := controllers.NewClinicController(clinicService)
+
+	vaccinationRepo := repositories.NewVaccinationRepositoryGORM(db)
+	vaccinationService := services.NewVaccinationService(vaccinationRepo, petRepo, appointmentRepo, userRepo, petAccess)
+	vaccinationController := controllers.NewVaccinationController(vaccinationService)
+
+	prescriptionService := services.NewPrescriptionService(prescriptionRepo, appointmentRepo, userRepo, petRepo, petAccess)
+	prescriptionController := controllers.NewPrescriptionController(prescriptionService)
+
+	preventiveCareRepo := repositories.NewPreventiveCareRepositoryGORM(db)
+	preventiveCareService := services.NewPreventiveCareService(preventiveCareRepo, vaccinationRepo, petRepo, userRepo, petAccess)
+	preventiveCareController := controllers.NewPreventiveCareController(preventiveCareService)
+	go preventiveCareService.StartReminderJob(time.Duration(utils.GetEnvInt("REMINDER_JOB_INTERVAL_HOURS", 24)) * time.Hour)
+
+	vitalSignRepo := repositories.NewVitalSignRepositoryGORM(db)
+	vitalSignService := services.NewVitalSignService(vitalSignRepo, appointmentRepo, petRepo, labRepo, petAccess)
+	vitalSignController := controllers.NewVitalSignController(vitalSignService)
+
+	petHealthRepo := repositories.NewPetHealthRepositoryGORM(db)
+	petHealthService := services.NewPetHealthService(petHealthRepo, petRepo, petAccess)
+	petHealthController := controllers.NewPetHealthController(petHealthService)
+
+	clinicalNoteService := services.NewClinicalNoteService(clinicalNoteRepo, appointmentRepo, userRepo, petAccess)
+	clinicalNoteController := controllers.NewClinicalNoteController(clinicalNoteService)
+
+	fileStorage, err := repositories.NewLocalFileStorage(utils.GetEnv("ATTACHMENTS_DIR", "uploads"))
+	if err != nil {
+		log.Fatal("Error al preparar el almacenamiento de archivos:", err)
+	}
+	attachmentService := services.NewAttachmentService(attachmentRepo, fileStorage, petRepo, appointmentRepo, petAccess)
+	attachmentController := controllers.NewAttachmentController(attachmentService)
+
+	historyExportService := services.NewHistoryExportService(appointmentRepo, prescriptionRepo, vaccinationRepo, vitalSignRepo, petRepo, petAccess)
+	historyExportController := controllers.NewHistoryExportController(historyExportService)
+
+	recordAmendmentService := services.NewRecordAmendmentService(appointmentRepo, clinicalNoteRepo, petAccess)
+	recordAmendmentController := controllers.NewRecordAmendmentController(recordAmendmentService)
+
+	labService := services.NewLabService(labRepo, appointmentRepo, petRepo, speciesRepo, petAccess)
+	labController := controllers.NewLabController(labService)
+
+	diagnosisService := services.NewDiagnosisService(diagnosisRepo, appointmentRepo, speciesRepo, petAccess)
+	diagnosisController := controllers.NewDiagnosisController(diagnosisService)
+
+	treatmentPlanService := services.NewTreatmentPlanService(treatmentPlanRepo, appointmentRepo, petRepo, userRepo, appointmentService, petAccess)
+	treatmentPlanController := controllers.NewTreatmentPlanController(treatmentPlanService)
+
+	shareLinkService := services.NewShareLinkService(shareLinkRepo, petRepo, appointmentRepo, vaccinationRepo, petAccess)
+	shareLinkController := controllers.NewShareLinkController(shareLinkService)
+
+	recordInterchangeService := services.NewRecordInterchangeService(recordInterchangeRepo, petRepo, userRepo, speciesRepo, appointmentRepo, vitalSignRepo, vaccinationRepo, petAccess)
+	recordInterchangeController := controllers.NewRecordInterchangeController(recordInterchangeService)
+
+	petPhotoService := services.NewPetPhotoService(petRepo, fileStorage, petAccess)
+	petPhotoController := controllers.NewPetPhotoController(petPhotoService)
+
 	r := mux.NewRouter()
 
 	userController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
 	adminController.RegisterPublicRoutes(r, middlewares.AdminRegisterMiddleware)
 	adminController.RegisterProtectedRoutes(r, middlewares.AdminProtected)
 	appointmentController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	appointmentController.RegisterAdminRoutes(r, middlewares.AdminProtected)
 	petController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
 	adminTypeController.RegisterRoutes(r, middlewares.AdminProtected)
 	userRoleController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
 	speciesController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	speciesController.RegisterAdminRoutes(r, middlewares.AdminProtected)
+	calendarController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	calendarController.RegisterAdminRoutes(r, middlewares.AdminProtected)
+	clinicController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	clinicController.RegisterAdminRoutes(r, middlewares.AdminProtected)
+	vaccinationController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	vaccinationController.RegisterAdminRoutes(r, middlewares.AdminProtected)
+	prescriptionController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	preventiveCareController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	preventiveCareController.RegisterAdminRoutes(r, middlewares.AdminProtected)
+	vitalSignController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	petHealthController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	clinicalNoteController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	clinicalNoteController.RegisterAdminRoutes(r, middlewares.AdminProtected)
+	attachmentController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	historyExportController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	recordAmendmentController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	labController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	labController.RegisterAdminRoutes(r, middlewares.AdminProtected)
+	diagnosisController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	diagnosisController.RegisterAdminRoutes(r, middlewares.AdminProtected)
+	treatmentPlanController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	shareLinkController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	shareLinkController.RegisterPublicRoutes(r)
+	recordInterchangeController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
+	petPhotoController.RegisterRoutes(r, middlewares.JWTAuthMiddleware)
 
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PATCH", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Authorization", "Content-Type", "X-Admin-Secret"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type", "X-Admin-Secret", "X-Share-PIN"},
+		ExposedHeaders:   []string{"X-Total-Count", "X-Page", "X-Page-Size"},
 		AllowCredentials: true,
 	})
 
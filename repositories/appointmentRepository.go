@@ -3,9 +3,13 @@ package repositories
 import (
 	"VetiCare/entities"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type appointmentRepositoryGORM struct {
@@ -16,6 +20,32 @@ func NewAppointmentRepositoryGORM(db *gorm.DB) AppointmentRepository {
 	return &appointmentRepositoryGORM{db: db}
 }
 
+func preloadAppointmentRelations(db *gorm.DB) *gorm.DB {
+	db = preloadPetAlerts("Pet.")(db)
+	db = preloadAppointmentDiagnoses("")(db)
+	return db.
+		Preload("Pet").
+		Preload("Pet.Owner").
+		Preload("Pet.Species").
+		Preload("Pet.Species.ReferenceRanges", "status_id = ?", 1).
+		Preload("Vet").
+		Preload("Vet.Role").
+		Preload("Clinic").
+		Preload("Room").
+		Preload("Resource")
+}
+
+// byClinic filtra por clínica cuando se indica una; column es la columna que
+// guarda la clínica en la consulta.
+func byClinic(column string, clinicID *int) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if clinicID == nil {
+			return db
+		}
+		return db.Where(column+" = ?", *clinicID)
+	}
+}
+
 func (r *appointmentRepositoryGORM) Create(app *entities.Appointment) error {
 	return r.db.Create(app).Error
 }
@@ -23,11 +53,7 @@ func (r *appointmentRepositoryGORM) Create(app *entities.Appointment) error {
 func (r *appointmentRepositoryGORM) GetByID(id string) (*entities.Appointment, error) {
 	var app entities.Appointment
 	err := r.db.
-		Preload("Pet").
-		Preload("Pet.Owner").
-		Preload("Pet.Species").
-		Preload("Vet").
-		Preload("Vet.Role").
+		Scopes(preloadAppointmentRelations).
 		Where("id = ?", id).
 		First(&app).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -36,66 +62,125 @@ func (r *appointmentRepositoryGORM) GetByID(id string) (*entities.Appointment, e
 	return &app, err
 }
 
-func (r *appointmentRepositoryGORM) GetByUserID(userID string) ([]entities.Appointment, error) {
+func (r *appointmentRepositoryGORM) GetByUserID(userID string, clinicID *int) ([]entities.Appointment, error) {
 	var apps []entities.Appointment
 	err := r.db.Joins("JOIN pets ON pets.id = appointments.pet_id").
 		Where("pets.owner_id = ?", userID).
-		Preload("Pet").
-		Preload("Pet.Owner").
-		Preload("Pet.Species").
-		Preload("Vet").
-		Preload("Vet.Role").
+		Scopes(byClinic("appointments.clinic_id", clinicID)).
+		Scopes(preloadAppointmentRelations).
 		Find(&apps).Error
 	return apps, err
 }
 
-func (r *appointmentRepositoryGORM) GetAll() ([]entities.Appointment, error) {
+var appointmentSortColumns = map[string]string{
+	"date":       "TO_TIMESTAMP(appointments.date || ' ' || appointments.time, 'DD-MM-YYYY HH24:MI')",
+	"created_at": "appointments.created_at",
+	"updated_at": "appointments.updated_at",
+	"status":     "appointments.status_id",
+}
+
+func (r *appointmentRepositoryGORM) Search(filter entities.AppointmentFilter) ([]entities.Appointment, int64, error) {
+	query := r.db.Model(&entities.Appointment{}).
+		Joins("JOIN pets ON pets.id = appointments.pet_id")
+
+	if filter.DateFrom != nil {
+		query = query.Where("TO_DATE(appointments.date, 'DD-MM-YYYY') >= ?", filter.DateFrom.Format("2006-01-02"))
+	}
+	if filter.DateTo != nil {
+		query = query.Where("TO_DATE(appointments.date, 'DD-MM-YYYY') <= ?", filter.DateTo.Format("2006-01-02"))
+	}
+	if filter.VetID != "" {
+		query = query.Where("appointments.vet_id = ?", filter.VetID)
+	}
+	if filter.OwnerID != "" {
+		query = query.Where("pets.owner_id = ?", filter.OwnerID)
+	}
+	if filter.PetID != "" {
+		query = query.Where("appointments.pet_id = ?", filter.PetID)
+	}
+	if filter.SpeciesID > 0 {
+		query = query.Where("pets.species_id = ?", filter.SpeciesID)
+	}
+	if len(filter.StatusIDs) > 0 {
+		query = query.Where("appointments.status_id IN ?", filter.StatusIDs)
+	}
+	if filter.ClinicID != nil {
+		query = query.Where("appointments.clinic_id = ?", *filter.ClinicID)
+	}
+	if filter.Query != "" {
+		query = query.Where("appointments.reason ILIKE ?", "%"+escapeLike(filter.Query)+"%")
+	}
+	if filter.DiagnosisID > 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM appointment_diagnoses ad WHERE ad.appointment_id = appointments.id AND ad.diagnosis_id = ?)", filter.DiagnosisID)
+	}
+
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := appointmentSortColumns[filter.SortBy]
+	if !ok {
+		column = appointmentSortColumns["date"]
+	}
+	direction := " ASC"
+	if filter.SortDesc {
+		direction = " DESC"
+	}
+
+	query = query.Order(column + direction).Order("appointments.id")
+	if filter.PageSize > 0 {
+		query = query.Offset((filter.Page - 1) * filter.PageSize).Limit(filter.PageSize)
+	}
 	var apps []entities.Appointment
-	err := r.db.
-		Preload("Pet").
-		Preload("Pet.Owner").
-		Preload("Pet.Species").
-		Preload("Vet").
-		Preload("Vet.Role").
-		Find(&apps).Error
-	return apps, err
+	err := query.Scopes(preloadAppointmentRelations).Find(&apps).Error
+	return apps, total, err
+}
+
+// escapeLike escapa los comodines de LIKE para que el texto buscado se tome
+// literal; PostgreSQL usa la barra invertida como escape por defecto.
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(text)
 }
 
-func (r *appointmentRepositoryGORM) GetAppointmentsByStatus(statusID int) ([]entities.Appointment, error) {
+func (r *appointmentRepositoryGORM) GetAppointmentsByStatus(statusID int, clinicID *int) ([]entities.Appointment, error) {
 	var apps []entities.Appointment
 	err := r.db.
 		Where("status_id = ?", statusID).
-		Preload("Pet").
-		Preload("Pet.Owner").
-		Preload("Pet.Species").
-		Preload("Vet").
-		Preload("Vet.Role").
+		Scopes(byClinic("clinic_id", clinicID)).
+		Scopes(preloadAppointmentRelations).
 		Find(&apps).Error
 	return apps, err
 }
 
-func (r *appointmentRepositoryGORM) GetAppointmentsByStatusAndDate(date time.Time) ([]entities.Appointment, error) {
+func (r *appointmentRepositoryGORM) GetAppointmentsByStatusAndDate(date time.Time, clinicID *int) ([]entities.Appointment, error) {
 	var apps []entities.Appointment
 	dateStr := date.Format("02-01-2006")
 	err := r.db.
 		Where("(status_id = 1 or status_id = 2) AND date = ?", dateStr).
-		Preload("Pet").
-		Preload("Pet.Owner").
-		Preload("Pet.Species").
-		Preload("Vet").
-		Preload("Vet.Role").
+		Scopes(byClinic("clinic_id", clinicID)).
+		Scopes(preloadAppointmentRelations).
 		Find(&apps).Error
 	return apps, err
 }
 
 func (r *appointmentRepositoryGORM) GetMedicalHistoryByPetID(petID string) ([]entities.Appointment, error) {
 	var apps []entities.Appointment
-	err := r.db.Where("pet_id = ? AND status_id = ?", petID, 2).
-		Preload("Pet").
-		Preload("Pet.Owner").
-		Preload("Pet.Species").
-		Preload("Vet").
-		Preload("Vet.Role").
+	db := r.db.Preload("LabOrders", func(db *gorm.DB) *gorm.DB {
+		return db.Where("status_id <> ?", entities.LabOrderStatusCancelled).Order("created_at ASC")
+	})
+	db = preloadLabOrderRelations("LabOrders.")(db)
+	err := db.Where("pet_id = ? AND status_id = ?", petID, 2).
+		Scopes(preloadAppointmentRelations).
+		Preload("Prescriptions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("signed_at ASC")
+		}).
+		Preload("Prescriptions.Items").
+		Preload("Prescriptions.Vet").
+		Preload("ClinicalNote").
+		Preload("ClinicalNote.Vet").
 		Find(&apps).Error
 	return apps, err
 }
@@ -113,45 +198,49 @@ func (r *appointmentRepositoryGORM) UpdateStatus(id string, statusID int) error
 		Update("status_id", statusID).Error
 }
 
+// Delete cancela la cita si sigue agendada y devuelve su estado final. Las
+// citas en cualquier otro estado no cambian.
 func (r *appointmentRepositoryGORM) Delete(id string) (int, error) {
-	var app entities.Appointment
-	result := r.db.First(&app, "id = ?", id)
+	result := r.db.Model(&entities.Appointment{}).
+		Where("id = ? AND status_id = ?", id, entities.AppointmentStatusScheduled).
+		Update("status_id", entities.AppointmentStatusCancelled)
 	if result.Error != nil {
 		return 0, result.Error
 	}
-	if result.RowsAffected == 0 {
-		return 0, errors.New("cita no encontrada")
-	}
-	newStatus := 1
-	if app.StatusID == 1 {
-		newStatus = 2
-	}
-	err := r.db.Model(&entities.Appointment{}).Where("id = ?", id).Update("status_id", newStatus).Error
-	if err != nil {
+	var app entities.Appointment
+	if err := r.db.Select("status_id").First(&app, "id = ?", id).Error; err != nil {
 		return 0, err
 	}
-	return newStatus, nil
+	return app.StatusID, nil
 }
 
-func (r *appointmentRepositoryGORM) CountAppointmentsByStatus(statusID int) (int, error) {
+func (r *appointmentRepositoryGORM) CountAppointmentsByStatus(statusID int, clinicID *int) (int, error) {
 	var count int64
-	err := r.db.Model(&entities.Appointment{}).Where("status_id = ?", statusID).Count(&count).Error
+	err := r.db.Model(&entities.Appointment{}).
+		Where("status_id = ?", statusID).
+		Scopes(byClinic("clinic_id", clinicID)).
+		Count(&count).Error
 	return int(count), err
 }
 
-func (r *appointmentRepositoryGORM) CountVets() (int, error) {
+func (r *appointmentRepositoryGORM) CountVets(clinicID *int) (int, error) {
 	var count int64
-	err := r.db.Model(&entities.User{}).
-		Where("role_id = ? AND status_id = ?", 2, 1).Count(&count).Error // 2 = vet, 1 = activo
+	query := r.db.Model(&entities.User{}).
+		Where("role_id = ? AND status_id = ?", 2, 1) // 2 = vet, 1 = activo
+	if clinicID != nil {
+		query = query.Where("id IN (SELECT user_id FROM vet_clinics WHERE clinic_id = ?)", *clinicID)
+	}
+	err := query.Count(&count).Error
 	return int(count), err
 }
 
-func (r *appointmentRepositoryGORM) GetVetsWithMostAppointments(limit int) ([]entities.VetAppointments, error) {
+func (r *appointmentRepositoryGORM) GetVetsWithMostAppointments(limit int, clinicID *int) ([]entities.VetAppointments, error) {
 	var results []entities.VetAppointments
 	err := r.db.Table("appointments a").
 		Select("a.vet_id as vet_id, u.full_name as vet_name, count(a.id) as appointments").
 		Joins("left join users u on a.vet_id = u.id").
 		Where("a.vet_id IS NOT NULL").
+		Scopes(byClinic("a.clinic_id", clinicID)).
 		Group("a.vet_id, u.full_name").
 		Order("appointments desc").
 		Limit(limit).
@@ -159,7 +248,7 @@ func (r *appointmentRepositoryGORM) GetVetsWithMostAppointments(limit int) ([]en
 	return results, err
 }
 
-func (r *appointmentRepositoryGORM) CountAttendedByMonthLast6Months() ([]entities.MonthlyAppointments, error) {
+func (r *appointmentRepositoryGORM) CountAttendedByMonthLast6Months(clinicID *int) ([]entities.MonthlyAppointments, error) {
 	var results []entities.MonthlyAppointments
 	sixMonthsAgo := time.Now().AddDate(0, -6, 0)
 	err := r.db.
@@ -168,6 +257,7 @@ func (r *appointmentRepositoryGORM) CountAttendedByMonthLast6Months() ([]entitie
 				EXTRACT(MONTH FROM TO_DATE(date, 'DD-MM-YYYY')) AS month,
 				COUNT(*) AS count`).
 		Where("TO_DATE(date, 'DD-MM-YYYY') >= ? AND status_id = ?", sixMonthsAgo, 2).
+		Scopes(byClinic("clinic_id", clinicID)).
 		Group("year, month").
 		Order("year DESC, month DESC").
 		Scan(&results).Error
@@ -175,11 +265,284 @@ func (r *appointmentRepositoryGORM) CountAttendedByMonthLast6Months() ([]entitie
 	return results, err
 }
 
-func (r *appointmentRepositoryGORM) ExistsAppointmentForPet(date, time string) (bool, error) {
+// CountActiveInSlot cuenta las citas que ocupan el horario indicado, sin
+// contar canceladas ni inasistencias.
+func (r *appointmentRepositoryGORM) CountActiveInSlot(slot entities.SlotQuery) (int64, error) {
 	var count int64
-	err := r.db.
+	query := r.db.
 		Model(&entities.Appointment{}).
-		Where("date = ? AND time = ?", date, time).
-		Count(&count).Error
-	return count > 0, err
+		Where("date = ? AND time = ? AND status_id NOT IN ?", slot.Date, slot.Time, []int{
+			entities.AppointmentStatusCancelled,
+			entities.AppointmentStatusNoShow,
+		})
+	if slot.ExcludeID != "" {
+		query = query.Where("id <> ?", slot.ExcludeID)
+	}
+	if slot.ClinicID != nil {
+		query = query.Where("clinic_id = ?", *slot.ClinicID)
+	}
+	if slot.VetID != nil {
+		query = query.Where("vet_id = ?", *slot.VetID)
+	}
+	if slot.RoomID != nil {
+		query = query.Where("room_id = ?", *slot.RoomID)
+	}
+	if slot.ResourceID != nil {
+		query = query.Where("resource_id = ?", *slot.ResourceID)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
+
+func (r *appointmentRepositoryGORM) Reschedule(app *entities.Appointment, history *entities.AppointmentReschedule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entities.Appointment{}).
+			Where("id = ?", app.ID).
+			Updates(map[string]interface{}{
+				"date":             history.NewDate,
+				"time":             history.NewTime,
+				"reschedule_count": gorm.Expr("reschedule_count + 1"),
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(history).Error
+	})
+}
+
+func (r *appointmentRepositoryGORM) GetReschedules(appointmentID string) ([]entities.AppointmentReschedule, error) {
+	var list []entities.AppointmentReschedule
+	err := r.db.
+		Where("appointment_id = ?", appointmentID).
+		Order("created_at ASC").
+		Find(&list).Error
+	return list, err
+}
+
+// AmendRecord guarda la enmienda y copia sus datos a la cita y a la nota
+// clínica. original es la versión 1 y solo se envía en la primera enmienda.
+// Devuelve false si otra enmienda se guardó antes y la versión ya no es la
+// esperada.
+func (r *appointmentRepositoryGORM) AmendRecord(original, amendment *entities.AppointmentRecordVersion) (bool, error) {
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Appointment{}).
+			Where("id = ? AND record_version = ?", amendment.AppointmentID, amendment.Version-1).
+			Updates(amendment.AppointmentFields())
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if original != nil {
+			if err := tx.Create(original).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(amendment).Error; err != nil {
+			return err
+		}
+		if amendment.HasClinicalNote {
+			err := tx.Model(&entities.ClinicalNote{}).
+				Where("appointment_id = ?", amendment.AppointmentID).
+				Updates(amendment.ClinicalNoteFields()).Error
+			if err != nil {
+				return err
+			}
+		}
+		applied = true
+		return nil
+	})
+	return applied, err
+}
+
+func (r *appointmentRepositoryGORM) GetRecordVersions(appointmentID string) ([]entities.AppointmentRecordVersion, error) {
+	var list []entities.AppointmentRecordVersion
+	err := r.db.
+		Where("appointment_id = ?", appointmentID).
+		Order("version DESC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *appointmentRepositoryGORM) GetRecordVersion(appointmentID string, version int) (*entities.AppointmentRecordVersion, error) {
+	var v entities.AppointmentRecordVersion
+	err := r.db.Where("appointment_id = ? AND version = ?", appointmentID, version).First(&v).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &v, err
+}
+
+func (r *appointmentRepositoryGORM) GetOwnerByPetID(petID string) (*entities.User, error) {
+	var owner entities.User
+	err := r.db.
+		Joins("JOIN pets ON pets.owner_id = users.id").
+		Where("pets.id = ?", petID).
+		First(&owner).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &owner, err
+}
+
+func (r *appointmentRepositoryGORM) GetScheduledUntil(date time.Time) ([]entities.Appointment, error) {
+	var apps []entities.Appointment
+	err := r.db.
+		Where("status_id = ? AND TO_DATE(date, 'DD-MM-YYYY') <= ?", entities.AppointmentStatusScheduled, date.Format("2006-01-02")).
+		Find(&apps).Error
+	return apps, err
+}
+
+func (r *appointmentRepositoryGORM) MarkNoShow(ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var marked []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var updated []entities.Appointment
+		err := tx.Model(&updated).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Where("id IN ? AND status_id = ?", ids, entities.AppointmentStatusScheduled).
+			Update("status_id", entities.AppointmentStatusNoShow).Error
+		if err != nil {
+			return err
+		}
+		for _, app := range updated {
+			marked = append(marked, app.ID.String())
+		}
+		if len(marked) == 0 {
+			return nil
+		}
+		return tx.Exec(`UPDATE users SET no_show_count = no_show_count + sub.total
+			FROM (SELECT pets.owner_id, COUNT(*) AS total
+				FROM appointments JOIN pets ON pets.id = appointments.pet_id
+				WHERE appointments.id IN ? GROUP BY pets.owner_id) AS sub
+			WHERE users.id = sub.owner_id`, marked).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return marked, nil
+}
+
+func (r *appointmentRepositoryGORM) ChangeStatus(id string, fromStatusID int, fields map[string]interface{}, noShowDelta int) (bool, error) {
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Appointment{}).
+			Where("id = ? AND status_id = ?", id, fromStatusID).
+			Updates(fields)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		updated = true
+		if noShowDelta == 0 {
+			return nil
+		}
+		return tx.Exec(`UPDATE users SET no_show_count = GREATEST(no_show_count + ?, 0)
+			WHERE id = (SELECT pets.owner_id FROM appointments JOIN pets ON pets.id = appointments.pet_id
+				WHERE appointments.id = ?)`, noShowDelta, id).Error
+	})
+	return updated, err
+}
+
+func (r *appointmentRepositoryGORM) Approve(id string, approvedBy *uuid.UUID) error {
+	return r.db.Model(&entities.Appointment{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"approved_by_id": approvedBy,
+			"approved_at":    time.Now(),
+		}).Error
+}
+
+func (r *appointmentRepositoryGORM) RecordDeposit(id string, recordedBy *uuid.UUID) error {
+	return r.db.Model(&entities.Appointment{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"deposit_recorded_by_id": recordedBy,
+			"deposit_paid_at":        time.Now(),
+		}).Error
+}
+
+func (r *appointmentRepositoryGORM) GetQueueByDate(date time.Time, clinicID *int) ([]entities.Appointment, error) {
+	var apps []entities.Appointment
+	err := r.db.
+		Where("date = ? AND status_id IN ?", date.Format("02-01-2006"), []int{
+			entities.AppointmentStatusScheduled,
+			entities.AppointmentStatusCheckedIn,
+			entities.AppointmentStatusInProcess,
+		}).
+		Scopes(byClinic("clinic_id", clinicID)).
+		Scopes(preloadAppointmentRelations).
+		Order("time ASC").
+		Find(&apps).Error
+	return apps, err
+}
+
+// GetActiveByDateRange devuelve las citas agendadas entre dos fechas. Si se
+// indica un veterinario solo se consideran las suyas.
+func (r *appointmentRepositoryGORM) GetActiveByDateRange(vetID string, from, to time.Time) ([]entities.Appointment, error) {
+	var apps []entities.Appointment
+	query := r.db.
+		Where("status_id IN ?", []int{
+			entities.AppointmentStatusScheduled,
+			entities.AppointmentStatusCheckedIn,
+			entities.AppointmentStatusInProcess,
+		}).
+		Where("TO_DATE(date, 'DD-MM-YYYY') BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if vetID != "" {
+		query = query.Where("vet_id = ?", vetID)
+	}
+	err := query.
+		Scopes(preloadAppointmentRelations).
+		Order("TO_TIMESTAMP(date || ' ' || time, 'DD-MM-YYYY HH24:MI') ASC").
+		Find(&apps).Error
+	return apps, err
+}
+
+// ApplyReassignments aplica todas las propuestas en una sola transacción; si
+// alguna cita dejó de estar agendada mientras tanto no se aplica ninguna.
+func (r *appointmentRepositoryGORM) ApplyReassignments(proposals []entities.ReassignmentProposal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, p := range proposals {
+			fields := map[string]interface{}{}
+			switch p.Action {
+			case entities.ReassignmentActionReassign:
+				fields["vet_id"] = p.NewVetID
+			case entities.ReassignmentActionReschedule:
+				fields["vet_id"] = p.NewVetID
+				fields["date"] = p.NewDate
+				fields["time"] = p.NewTime
+				fields["reschedule_count"] = gorm.Expr("reschedule_count + 1")
+			default:
+				continue
+			}
+			result := tx.Model(&entities.Appointment{}).
+				Where("id = ? AND status_id = ?", p.Appointment.ID, entities.AppointmentStatusScheduled).
+				Updates(fields)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("la cita %s ya no está agendada", p.Appointment.ID)
+			}
+			if p.History != nil {
+				if err := tx.Create(p.History).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// GetWithVitalsByPetID devuelve las citas atendidas o en curso de la mascota
+// que registraron peso o temperatura.
+func (r *appointmentRepositoryGORM) GetWithVitalsByPetID(petID string) ([]entities.Appointment, error) {
+	var apps []entities.Appointment
+	err := r.db.
+		Where("pet_id = ?", petID).
+		Where("status_id NOT IN ?", []int{entities.AppointmentStatusCancelled, entities.AppointmentStatusNoShow}).
+		Where("weight_kg IS NOT NULL OR temperature IS NOT NULL").
+		Order("TO_TIMESTAMP(date || ' ' || time, 'DD-MM-YYYY HH24:MI') ASC").
+		Find(&apps).Error
+	return apps, err
 }
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"VetiCare/entities"
+	"errors"
+	"gorm.io/gorm"
+)
+
+type attachmentRepositoryGORM struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepositoryGORM(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepositoryGORM{db: db}
+}
+
+func (r *attachmentRepositoryGORM) Create(a *entities.Attachment) error {
+	return r.db.Create(a).Error
+}
+
+func (r *attachmentRepositoryGORM) GetByID(id string) (*entities.Attachment, error) {
+	var a entities.Attachment
+	err := r.db.First(&a, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &a, err
+}
+
+func (r *attachmentRepositoryGORM) GetByPetID(petID string, onlyActive bool) ([]entities.Attachment, error) {
+	var list []entities.Attachment
+	query := r.db.Where("pet_id = ?", petID)
+	if onlyActive {
+		query = query.Where("status_id = ?", 1)
+	}
+	err := query.Order("created_at DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *attachmentRepositoryGORM) GetByAppointmentID(appointmentID string) ([]entities.Attachment, error) {
+	var list []entities.Attachment
+	err := r.db.Where("appointment_id = ? AND status_id = ?", appointmentID, 1).
+		Order("created_at DESC").
+		Find(&list).Error
+	return list, err
+}
+
+// Delete alterna el adjunto entre activo e inactivo. El archivo se conserva en
+// el almacenamiento porque forma parte del expediente clínico.
+func (r *attachmentRepositoryGORM) Delete(id string) (int, error) {
+	return toggleStatus(r.db, &entities.Attachment{}, id)
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"VetiCare/entities"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type calendarRepositoryGORM struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepositoryGORM(db *gorm.DB) CalendarRepository {
+	return &calendarRepositoryGORM{db: db}
+}
+
+func (r *calendarRepositoryGORM) CreateTimeOff(timeOff *entities.VetTimeOff) error {
+	return r.db.Create(timeOff).Error
+}
+
+func (r *calendarRepositoryGORM) GetTimeOffByID(id string) (*entities.VetTimeOff, error) {
+	var timeOff entities.VetTimeOff
+	err := r.db.Preload("Vet").Preload("Vet.Role").Where("id = ?", id).First(&timeOff).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &timeOff, err
+}
+
+func (r *calendarRepositoryGORM) GetTimeOffByVet(vetID string) ([]entities.VetTimeOff, error) {
+	var list []entities.VetTimeOff
+	err := r.db.
+		Preload("Vet").
+		Preload("Vet.Role").
+		Where("vet_id = ?", vetID).
+		Order("start_date ASC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *calendarRepositoryGORM) DeleteTimeOff(id string) error {
+	result := r.db.Delete(&entities.VetTimeOff{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("ausencia no encontrada")
+	}
+	return nil
+}
+
+func (r *calendarRepositoryGORM) IsVetOnTimeOff(vetID string, date time.Time) (bool, error) {
+	var count int64
+	day := date.Format("2006-01-02")
+	err := r.db.Model(&entities.VetTimeOff{}).
+		Where("vet_id = ? AND start_date <= ? AND end_date >= ?", vetID, day, day).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *calendarRepositoryGORM) CreateClosure(closure *entities.ClinicClosure) error {
+	return r.db.Create(closure).Error
+}
+
+func (r *calendarRepositoryGORM) GetClosures(from, to *time.Time) ([]entities.ClinicClosure, error) {
+	var list []entities.ClinicClosure
+	query := r.db.Order("date ASC")
+	if from != nil {
+		query = query.Where("date >= ?", from.Format("2006-01-02"))
+	}
+	if to != nil {
+		query = query.Where("date <= ?", to.Format("2006-01-02"))
+	}
+	err := query.Find(&list).Error
+	return list, err
+}
+
+func (r *calendarRepositoryGORM) DeleteClosure(id string) error {
+	result := r.db.Delete(&entities.ClinicClosure{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("día de cierre no encontrado")
+	}
+	return nil
+}
+
+func (r *calendarRepositoryGORM) IsClinicClosed(date time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&entities.ClinicClosure{}).
+		Where("date = ?", date.Format("2006-01-02")).
+		Count(&count).Error
+	return count > 0, err
+}
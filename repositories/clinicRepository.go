@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"VetiCare/entities"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type clinicRepositoryGORM struct {
+	db *gorm.DB
+}
+
+func NewClinicRepositoryGORM(db *gorm.DB) ClinicRepository {
+	return &clinicRepositoryGORM{db: db}
+}
+
+func (r *clinicRepositoryGORM) GetAll() ([]entities.Clinic, error) {
+	var list []entities.Clinic
+	err := r.db.Order("id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *clinicRepositoryGORM) GetByID(id int) (*entities.Clinic, error) {
+	var c entities.Clinic
+	err := r.db.First(&c, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &c, err
+}
+
+func (r *clinicRepositoryGORM) Create(clinic *entities.Clinic) error {
+	return r.db.Create(clinic).Error
+}
+
+func (r *clinicRepositoryGORM) Update(id int, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return r.db.Model(&entities.Clinic{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *clinicRepositoryGORM) Delete(id int) (int, error) {
+	var c entities.Clinic
+	result := r.db.First(&c, "id = ?", id)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	newStatus := 1
+	if c.StatusID == 1 {
+		newStatus = 2
+	}
+	err := r.db.Model(&entities.Clinic{}).Where("id = ?", id).Update("status_id", newStatus).Error
+	if err != nil {
+		return 0, err
+	}
+	return newStatus, nil
+}
+
+func (r *clinicRepositoryGORM) GetRooms(clinicID int) ([]entities.Room, error) {
+	var list []entities.Room
+	err := r.db.Preload("Clinic").Where("clinic_id = ?", clinicID).Order("id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *clinicRepositoryGORM) GetRoomByID(id int) (*entities.Room, error) {
+	var room entities.Room
+	err := r.db.Preload("Clinic").First(&room, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &room, err
+}
+
+func (r *clinicRepositoryGORM) CreateRoom(room *entities.Room) error {
+	return r.db.Create(room).Error
+}
+
+func (r *clinicRepositoryGORM) UpdateRoom(id int, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return r.db.Model(&entities.Room{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *clinicRepositoryGORM) GetResources(clinicID int) ([]entities.Resource, error) {
+	var list []entities.Resource
+	err := r.db.Preload("Clinic").Where("clinic_id = ?", clinicID).Order("id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *clinicRepositoryGORM) GetResourceByID(id int) (*entities.Resource, error) {
+	var resource entities.Resource
+	err := r.db.Preload("Clinic").First(&resource, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &resource, err
+}
+
+func (r *clinicRepositoryGORM) CreateResource(resource *entities.Resource) error {
+	return r.db.Create(resource).Error
+}
+
+func (r *clinicRepositoryGORM) UpdateResource(id int, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return r.db.Model(&entities.Resource{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *clinicRepositoryGORM) GetVets(clinicID int) ([]entities.User, error) {
+	var vets []entities.User
+	err := r.db.
+		Joins("JOIN vet_clinics ON vet_clinics.user_id = users.id").
+		Where("vet_clinics.clinic_id = ? AND users.role_id = ? AND users.status_id = ?", clinicID, 2, 1).
+		Preload("Role").
+		Preload("Clinics").
+		Find(&vets).Error
+	return vets, err
+}
+
+func (r *clinicRepositoryGORM) SetVetClinics(vetID string, clinicIDs []int) error {
+	var vet entities.User
+	if err := r.db.First(&vet, "id = ?", vetID).Error; err != nil {
+		return err
+	}
+	var clinics []entities.Clinic
+	if len(clinicIDs) > 0 {
+		if err := r.db.Where("id IN ?", clinicIDs).Find(&clinics).Error; err != nil {
+			return err
+		}
+		if len(clinics) != len(clinicIDs) {
+			return errors.New("una o más clínicas no existen")
+		}
+	}
+	return r.db.Model(&vet).Association("Clinics").Replace(clinics)
+}
+
+func (r *clinicRepositoryGORM) IsVetInClinic(vetID string, clinicID int) (bool, error) {
+	var count int64
+	err := r.db.Table("vet_clinics").
+		Where("user_id = ? AND clinic_id = ?", vetID, clinicID).
+		Count(&count).Error
+	return count > 0, err
+}
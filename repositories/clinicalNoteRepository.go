@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"VetiCare/entities"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+type clinicalNoteRepositoryGORM struct {
+	db *gorm.DB
+}
+
+func NewClinicalNoteRepositoryGORM(db *gorm.DB) ClinicalNoteRepository {
+	return &clinicalNoteRepositoryGORM{db: db}
+}
+
+func (r *clinicalNoteRepositoryGORM) GetByAppointmentID(appointmentID string) (*entities.ClinicalNote, error) {
+	var n entities.ClinicalNote
+	err := r.db.Preload("Vet").First(&n, "appointment_id = ?", appointmentID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &n, err
+}
+
+func (r *clinicalNoteRepositoryGORM) Create(note *entities.ClinicalNote) error {
+	return r.db.Create(note).Error
+}
+
+// UpdateDraft sobrescribe el contenido del borrador y devuelve false si la nota
+// ya estaba finalizada, por si se cerró entre la lectura y el guardado.
+func (r *clinicalNoteRepositoryGORM) UpdateDraft(note *entities.ClinicalNote) (bool, error) {
+	result := r.db.Model(&entities.ClinicalNote{}).
+		Where("id = ? AND status_id = ?", note.ID, entities.ClinicalNoteStatusDraft).
+		Updates(map[string]interface{}{
+			"vet_id":          note.VetID,
+			"template_id":     note.TemplateID,
+			"subjective":      note.Subjective,
+			"objective":       note.Objective,
+			"assessment":      note.Assessment,
+			"plan":            note.Plan,
+			"diagnosis_codes": note.DiagnosisCodes,
+			"last_saved_at":   note.LastSavedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *clinicalNoteRepositoryGORM) FinalizeByAppointmentID(appointmentID string, at time.Time) error {
+	return r.db.Model(&entities.ClinicalNote{}).
+		Where("appointment_id = ? AND status_id = ?", appointmentID, entities.ClinicalNoteStatusDraft).
+		Updates(map[string]interface{}{
+			"status_id":    entities.ClinicalNoteStatusFinal,
+			"finalized_at": at,
+		}).Error
+}
+
+func (r *clinicalNoteRepositoryGORM) GetTemplates(vetID string, onlyActive bool) ([]entities.ClinicalNoteTemplate, error) {
+	var list []entities.ClinicalNoteTemplate
+	query := r.db.Where("vet_id IS NULL OR vet_id = ?", vetID)
+	if onlyActive {
+		query = query.Where("status_id = ?", 1)
+	}
+	err := query.Order("name ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *clinicalNoteRepositoryGORM) GetTemplateByID(id int) (*entities.ClinicalNoteTemplate, error) {
+	var t entities.ClinicalNoteTemplate
+	err := r.db.First(&t, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &t, err
+}
+
+func (r *clinicalNoteRepositoryGORM) CreateTemplate(t *entities.ClinicalNoteTemplate) error {
+	return r.db.Create(t).Error
+}
+
+func (r *clinicalNoteRepositoryGORM) UpdateTemplate(id int, fields map[string]interface{}) error {
+	return r.db.Model(&entities.ClinicalNoteTemplate{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *clinicalNoteRepositoryGORM) DeleteTemplate(id int) (int, error) {
+	var t entities.ClinicalNoteTemplate
+	if err := r.db.First(&t, "id = ?", id).Error; err != nil {
+		return 0, err
+	}
+	newStatus := 1
+	if t.StatusID == 1 {
+		newStatus = 2
+	}
+	err := r.db.Model(&t).Update("status_id", newStatus).Error
+	return newStatus, err
+}
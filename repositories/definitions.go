@@ -2,7 +2,10 @@ package repositories
 
 import (
 	"VetiCare/entities"
+	"io"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type UserRepository interface {
@@ -10,7 +13,8 @@ type UserRepository interface {
 	Login(email, password string) (*entities.User, error)
 	Create(user *entities.User) error
 	GetByEmail(email string) (*entities.User, error)
-	GetByRole(roleID int) ([]entities.User, error)
+	GetByDUI(dui string) (*entities.User, error)
+	GetByRole(roleID int, clinicID *int) ([]entities.User, error)
 	GetByID(id string) (*entities.User, error)
 	GetAll() ([]entities.User, error)
 	Update(id string, fields map[string]interface{}) error
@@ -37,6 +41,8 @@ type PetRepository interface {
 	Delete(id string) (int, error)
 	GetActivePets() ([]entities.Pet, error)
 	GetPetsByOwner(ownerID string) ([]entities.Pet, error)
+	GetLegacyBreedPets() ([]entities.Pet, error)
+	GetByMicrochip(number string) (*entities.Pet, error)
 }
 
 type SpeciesRepository interface {
@@ -45,25 +51,205 @@ type SpeciesRepository interface {
 	Create(species *entities.Species) error
 	Update(id int, fields map[string]interface{}) error
 	Delete(id int) error
+	GetReferenceRanges(speciesID int, onlyActive bool) ([]entities.VitalReferenceRange, error)
+	GetReferenceRangeByID(id int) (*entities.VitalReferenceRange, error)
+	CreateReferenceRange(r *entities.VitalReferenceRange) error
+	UpdateReferenceRange(id int, fields map[string]interface{}) error
+	DeleteReferenceRange(id int) (int, error)
+	GetBreeds(speciesID int, onlyActive bool) ([]entities.Breed, error)
+	GetBreedByID(id int) (*entities.Breed, error)
+	CreateBreed(b *entities.Breed) error
+	UpdateBreed(id int, fields map[string]interface{}) error
+	DeleteBreed(id int) (int, error)
+}
+
+type VaccinationRepository interface {
+	GetVaccines(onlyActive bool) ([]entities.Vaccine, error)
+	GetVaccineByID(id int) (*entities.Vaccine, error)
+	CreateVaccine(vaccine *entities.Vaccine) error
+	UpdateVaccine(id int, fields map[string]interface{}) error
+	DeleteVaccine(id int) (int, error)
+
+	Create(vaccination *entities.Vaccination) error
+	GetByID(id string) (*entities.Vaccination, error)
+	GetByPetID(petID string) ([]entities.Vaccination, error)
+	CountDoses(petID string, vaccineID int) (int64, error)
+	ExistsForAppointment(appointmentID string, vaccineID int) (bool, error)
+	GetDue(filter entities.VaccinationDueFilter) ([]entities.Vaccination, error)
+	GetLegacyAppointments() ([]entities.Appointment, error)
+}
+
+type PrescriptionRepository interface {
+	Create(prescription *entities.Prescription) error
+	GetByID(id string) (*entities.Prescription, error)
+	GetByAppointmentID(appointmentID string) ([]entities.Prescription, error)
+	GetByPetID(petID string) ([]entities.Prescription, error)
+	GetActiveByPetID(petID string) ([]entities.Prescription, error)
+	Void(id string, voidedBy *uuid.UUID, reason string) error
+}
+
+type PreventiveCareRepository interface {
+	CreateDeworming(deworming *entities.Deworming) error
+	GetDewormingByID(id string) (*entities.Deworming, error)
+	GetDewormingsByPet(petID string) ([]entities.Deworming, error)
+	GetLatestDewormings(from, to time.Time) ([]entities.Deworming, error)
+	GetPetIDsWithDeworming() ([]uuid.UUID, error)
+	GetPetIDsWithVaccine(vaccineID int) ([]uuid.UUID, error)
+
+	GetProtocols(speciesID *int, onlyActive bool) ([]entities.PreventiveProtocol, error)
+	GetProtocolByID(id int) (*entities.PreventiveProtocol, error)
+	CreateProtocol(protocol *entities.PreventiveProtocol) error
+	UpdateProtocol(id int, fields map[string]interface{}) error
+	DeleteProtocol(id int) (int, error)
+
+	GetRemindersByKeys(keys []string) ([]entities.CareReminder, error)
+	CreateReminders(reminders []entities.CareReminder) error
+}
+
+type VitalSignRepository interface {
+	Create(vital *entities.VitalSign) error
+	GetByID(id string) (*entities.VitalSign, error)
+	GetByPetID(petID string) ([]entities.VitalSign, error)
+}
+
+type PetHealthRepository interface {
+	GetAllergies(petID string, onlyActive bool) ([]entities.PetAllergy, error)
+	GetAllergyByID(id string) (*entities.PetAllergy, error)
+	CreateAllergy(a *entities.PetAllergy) error
+	UpdateAllergy(id string, fields map[string]interface{}) error
+	DeleteAllergy(id string) (int, error)
+	GetConditions(petID string, onlyActive bool) ([]entities.PetCondition, error)
+	GetConditionByID(id string) (*entities.PetCondition, error)
+	CreateCondition(c *entities.PetCondition) error
+	UpdateCondition(id string, fields map[string]interface{}) error
+	DeleteCondition(id string) (int, error)
+	GetBehaviorAlerts(petID string, onlyActive bool) ([]entities.PetBehaviorAlert, error)
+	GetBehaviorAlertByID(id string) (*entities.PetBehaviorAlert, error)
+	CreateBehaviorAlert(b *entities.PetBehaviorAlert) error
+	UpdateBehaviorAlert(id string, fields map[string]interface{}) error
+	DeleteBehaviorAlert(id string) (int, error)
+}
+
+type ClinicalNoteRepository interface {
+	GetByAppointmentID(appointmentID string) (*entities.ClinicalNote, error)
+	Create(note *entities.ClinicalNote) error
+	UpdateDraft(note *entities.ClinicalNote) (bool, error)
+	FinalizeByAppointmentID(appointmentID string, at time.Time) error
+	GetTemplates(vetID string, onlyActive bool) ([]entities.ClinicalNoteTemplate, error)
+	GetTemplateByID(id int) (*entities.ClinicalNoteTemplate, error)
+	CreateTemplate(t *entities.ClinicalNoteTemplate) error
+	UpdateTemplate(id int, fields map[string]interface{}) error
+	DeleteTemplate(id int) (int, error)
+}
+
+type AttachmentRepository interface {
+	Create(a *entities.Attachment) error
+	GetByID(id string) (*entities.Attachment, error)
+	GetByPetID(petID string, onlyActive bool) ([]entities.Attachment, error)
+	GetByAppointmentID(appointmentID string) ([]entities.Attachment, error)
+	Delete(id string) (int, error)
+}
+
+// FileStorage guarda el contenido de los archivos adjuntos. La implementación
+// local escribe en disco; otra compatible con S3 puede reemplazarla sin tocar
+// los servicios.
+type FileStorage interface {
+	Save(key string, content io.Reader) (int64, error)
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+type LabRepository interface {
+	GetTests(onlyActive bool) ([]entities.LabTest, error)
+	GetTestByID(id int) (*entities.LabTest, error)
+	CreateTest(t *entities.LabTest) error
+	UpdateTest(id int, fields map[string]interface{}) error
+	DeleteTest(id int) (int, error)
+	GetAnalyteByID(id int) (*entities.LabAnalyte, error)
+	CreateAnalyte(a *entities.LabAnalyte) error
+	UpdateAnalyte(id int, fields map[string]interface{}) error
+	DeleteAnalyte(id int) (int, error)
+	SaveReferenceRange(rng *entities.LabReferenceRange) error
+	DeleteReferenceRange(analyteID, speciesID int) (bool, error)
+	CreateOrder(o *entities.LabOrder) error
+	GetOrderByID(id string) (*entities.LabOrder, error)
+	GetOrdersByAppointmentID(appointmentID string) ([]entities.LabOrder, error)
+	GetOrdersByPetID(petID string, statusID *int) ([]entities.LabOrder, error)
+	GetOpenOrders() ([]entities.LabOrder, error)
+	UpdateOrderStatus(id string, fromStatuses []int, fields map[string]interface{}) (bool, error)
+	SaveResults(orderID string, results []entities.LabResult, fields map[string]interface{}) (bool, error)
+}
+
+type DiagnosisRepository interface {
+	GetDiagnoses(onlyActive bool, speciesID int, query string) ([]entities.Diagnosis, error)
+	GetDiagnosisByID(id int) (*entities.Diagnosis, error)
+	GetDiagnosesByIDs(ids []int) ([]entities.Diagnosis, error)
+	CreateDiagnosis(d *entities.Diagnosis) error
+	UpdateDiagnosis(id int, fields map[string]interface{}) error
+	DeleteDiagnosis(id int) (int, error)
+	GetByAppointmentID(appointmentID string) ([]entities.AppointmentDiagnosis, error)
+	ReplaceForAppointment(appointmentID string, openStatuses []int, list []entities.AppointmentDiagnosis) (bool, error)
+	CountByDiagnosis(filter entities.DiagnosisReportFilter) ([]entities.DiagnosisCount, error)
+}
+
+type TreatmentPlanRepository interface {
+	Create(plan *entities.TreatmentPlan) error
+	GetByID(id string) (*entities.TreatmentPlan, error)
+	GetByPetID(petID string, statusID *int) ([]entities.TreatmentPlan, error)
+	Update(id string, fields map[string]interface{}) error
+	AddStep(step *entities.TreatmentPlanStep) error
+	SaveProgress(plan *entities.TreatmentPlan) error
+	GetStepByAppointmentID(appointmentID string) (*entities.TreatmentPlanStep, error)
+	GetOverdueSteps(today time.Time, clinicID *int) ([]entities.TreatmentPlanStep, error)
+}
+
+type ShareLinkRepository interface {
+	Create(link *entities.ShareLink) error
+	GetByID(id string) (*entities.ShareLink, error)
+	GetByPetID(petID string) ([]entities.ShareLink, error)
+	Revoke(id, revokedByName string, at time.Time) error
+	ReservePinAttempt(id string, maxAttempts int) (bool, error)
+	ResetFailedPin(id string) error
+	RecordAccess(access *entities.ShareLinkAccess) error
+	GetAccesses(linkID string) ([]entities.ShareLinkAccess, error)
+}
+
+type RecordInterchangeRepository interface {
+	SaveImport(imp *entities.RecordImport) error
 }
 
 type AppointmentRepository interface {
 	Create(app *entities.Appointment) error
 	GetByID(id string) (*entities.Appointment, error)
-	GetAll() ([]entities.Appointment, error)
+	Search(filter entities.AppointmentFilter) ([]entities.Appointment, int64, error)
 	Update(id string, fields map[string]interface{}) error
 	Delete(id string) (int, error)
 	UpdateStatus(id string, statusID int) error
-	GetByUserID(userID string) ([]entities.Appointment, error)
+	GetByUserID(userID string, clinicID *int) ([]entities.Appointment, error)
 	GetMedicalHistoryByPetID(petID string) ([]entities.Appointment, error)
-	GetAppointmentsByStatus(statusID int) ([]entities.Appointment, error)
-	GetAppointmentsByStatusAndDate(date time.Time) ([]entities.Appointment, error)
-	ExistsAppointmentForPet(date, time string) (bool, error)
+	AmendRecord(original, amendment *entities.AppointmentRecordVersion) (bool, error)
+	GetRecordVersions(appointmentID string) ([]entities.AppointmentRecordVersion, error)
+	GetRecordVersion(appointmentID string, version int) (*entities.AppointmentRecordVersion, error)
+	GetAppointmentsByStatus(statusID int, clinicID *int) ([]entities.Appointment, error)
+	GetAppointmentsByStatusAndDate(date time.Time, clinicID *int) ([]entities.Appointment, error)
+	CountActiveInSlot(slot entities.SlotQuery) (int64, error)
+	Reschedule(app *entities.Appointment, history *entities.AppointmentReschedule) error
+	GetReschedules(appointmentID string) ([]entities.AppointmentReschedule, error)
+	GetOwnerByPetID(petID string) (*entities.User, error)
+	GetScheduledUntil(date time.Time) ([]entities.Appointment, error)
+	MarkNoShow(ids []string) ([]string, error)
+	ChangeStatus(id string, fromStatusID int, fields map[string]interface{}, noShowDelta int) (bool, error)
+	Approve(id string, approvedBy *uuid.UUID) error
+	RecordDeposit(id string, recordedBy *uuid.UUID) error
+	GetQueueByDate(date time.Time, clinicID *int) ([]entities.Appointment, error)
+	GetActiveByDateRange(vetID string, from, to time.Time) ([]entities.Appointment, error)
+	ApplyReassignments(proposals []entities.ReassignmentProposal) error
+	GetWithVitalsByPetID(petID string) ([]entities.Appointment, error)
 
-	CountAppointmentsByStatus(statusID int) (int, error)
-	CountVets() (int, error)
-	GetVetsWithMostAppointments(limit int) ([]entities.VetAppointments, error)
-	CountAttendedByMonthLast6Months() ([]entities.MonthlyAppointments, error)
+	CountAppointmentsByStatus(statusID int, clinicID *int) (int, error)
+	CountVets(clinicID *int) (int, error)
+	GetVetsWithMostAppointments(limit int, clinicID *int) ([]entities.VetAppointments, error)
+	CountAttendedByMonthLast6Months(clinicID *int) ([]entities.MonthlyAppointments, error)
 }
 
 type AdminTypeRepository interface {
@@ -81,3 +267,34 @@ type UserRoleRepository interface {
 	Update(id int, fields map[string]interface{}) error
 	Delete(id int) error
 }
+
+type CalendarRepository interface {
+	CreateTimeOff(timeOff *entities.VetTimeOff) error
+	GetTimeOffByID(id string) (*entities.VetTimeOff, error)
+	GetTimeOffByVet(vetID string) ([]entities.VetTimeOff, error)
+	DeleteTimeOff(id string) error
+	IsVetOnTimeOff(vetID string, date time.Time) (bool, error)
+	CreateClosure(closure *entities.ClinicClosure) error
+	GetClosures(from, to *time.Time) ([]entities.ClinicClosure, error)
+	DeleteClosure(id string) error
+	IsClinicClosed(date time.Time) (bool, error)
+}
+
+type ClinicRepository interface {
+	GetAll() ([]entities.Clinic, error)
+	GetByID(id int) (*entities.Clinic, error)
+	Create(clinic *entities.Clinic) error
+	Update(id int, fields map[string]interface{}) error
+	Delete(id int) (int, error)
+	GetRooms(clinicID int) ([]entities.Room, error)
+	GetRoomByID(id int) (*entities.Room, error)
+	CreateRoom(room *entities.Room) error
+	UpdateRoom(id int, fields map[string]interface{}) error
+	GetResources(clinicID int) ([]entities.Resource, error)
+	GetResourceByID(id int) (*entities.Resource, error)
+	CreateResource(resource *entities.Resource) error
+	UpdateResource(id int, fields map[string]interface{}) error
+	GetVets(clinicID int) ([]entities.User, error)
+	SetVetClinics(vetID string, clinicIDs []int) error
+	IsVetInClinic(vetID string, clinicID int) (bool, error)
+}
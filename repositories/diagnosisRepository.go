@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"VetiCare/entities"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+type diagnosisRepositoryGORM struct {
+	db *gorm.DB
+}
+
+func NewDiagnosisRepositoryGORM(db *gorm.DB) DiagnosisRepository {
+	return &diagnosisRepositoryGORM{db: db}
+}
+
+// preloadAppointmentDiagnoses precarga los diagnósticos de la cita con el
+// principal primero. prefix es la ruta de la relación, por ejemplo
+// "Diagnoses." cuando se cargan desde la cita.
+func preloadAppointmentDiagnoses(prefix string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Preload(prefix+"Diagnoses", func(db *gorm.DB) *gorm.DB {
+				return db.Order("is_primary DESC, created_at ASC")
+			}).
+			Preload(prefix + "Diagnoses.Diagnosis")
+	}
+}
+
+// GetDiagnoses lista el catálogo. Con speciesID solo devuelve los que aplican
+// a esa especie y query busca en el código y el nombre.
+func (r *diagnosisRepositoryGORM) GetDiagnoses(onlyActive bool, speciesID int, query string) ([]entities.Diagnosis, error) {
+	var list []entities.Diagnosis
+	db := r.db.Preload("Species")
+	if onlyActive {
+		db = db.Where("status_id = ?", 1)
+	}
+	if speciesID > 0 {
+		db = db.Where("(species_id IS NULL OR species_id = ?)", speciesID)
+	}
+	if query != "" {
+		db = db.Where("(code ILIKE ? OR name ILIKE ?)", "%"+query+"%", "%"+query+"%")
+	}
+	err := db.Order("code ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *diagnosisRepositoryGORM) GetDiagnosisByID(id int) (*entities.Diagnosis, error) {
+	var d entities.Diagnosis
+	err := r.db.Preload("Species").First(&d, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &d, err
+}
+
+func (r *diagnosisRepositoryGORM) GetDiagnosesByIDs(ids []int) ([]entities.Diagnosis, error) {
+	var list []entities.Diagnosis
+	err := r.db.Where("id IN ?", ids).Find(&list).Error
+	return list, err
+}
+
+func (r *diagnosisRepositoryGORM) CreateDiagnosis(d *entities.Diagnosis) error {
+	return r.db.Create(d).Error
+}
+
+func (r *diagnosisRepositoryGORM) UpdateDiagnosis(id int, fields map[string]interface{}) error {
+	return r.db.Model(&entities.Diagnosis{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *diagnosisRepositoryGORM) DeleteDiagnosis(id int) (int, error) {
+	return toggleStatus(r.db, &entities.Diagnosis{}, id)
+}
+
+func (r *diagnosisRepositoryGORM) GetByAppointmentID(appointmentID string) ([]entities.AppointmentDiagnosis, error) {
+	var list []entities.AppointmentDiagnosis
+	err := r.db.
+		Preload("Diagnosis").
+		Where("appointment_id = ?", appointmentID).
+		Order("is_primary DESC, created_at ASC").
+		Find(&list).Error
+	return list, err
+}
+
+// ReplaceForAppointment reemplaza los diagnósticos de la cita en una
+// transacción. Devuelve false si la cita ya no está en uno de openStatuses.
+func (r *diagnosisRepositoryGORM) ReplaceForAppointment(appointmentID string, openStatuses []int, list []entities.AppointmentDiagnosis) (bool, error) {
+	saved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Appointment{}).
+			Where("id = ? AND status_id IN ?", appointmentID, openStatuses).
+			Update("updated_at", time.Now())
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Where("appointment_id = ?", appointmentID).Delete(&entities.AppointmentDiagnosis{}).Error; err != nil {
+			return err
+		}
+		if len(list) > 0 {
+			if err := tx.Omit("Diagnosis").Create(&list).Error; err != nil {
+				return err
+			}
+		}
+		saved = true
+		return nil
+	})
+	return saved, err
+}
+
+// CountByDiagnosis cuenta los diagnósticos registrados en citas finalizadas,
+// del más frecuente al menos frecuente.
+func (r *diagnosisRepositoryGORM) CountByDiagnosis(filter entities.DiagnosisReportFilter) ([]entities.DiagnosisCount, error) {
+	var results []entities.DiagnosisCount
+	query := r.db.Table("appointment_diagnoses ad").
+		Select(`d.id AS diagnosis_id, d.code AS code, d.name AS name, d.category AS category,
+				COUNT(*) AS total,
+				SUM(CASE WHEN ad.is_primary THEN 1 ELSE 0 END) AS primary_count,
+				COUNT(DISTINCT a.pet_id) AS pets`).
+		Joins("JOIN diagnoses d ON d.id = ad.diagnosis_id").
+		Joins("JOIN appointments a ON a.id = ad.appointment_id").
+		Joins("JOIN pets p ON p.id = a.pet_id").
+		Where("a.status_id = ?", entities.AppointmentStatusFinished)
+
+	if filter.DateFrom != nil {
+		query = query.Where("TO_DATE(a.date, 'DD-MM-YYYY') >= ?", filter.DateFrom.Format("2006-01-02"))
+	}
+	if filter.DateTo != nil {
+		query = query.Where("TO_DATE(a.date, 'DD-MM-YYYY') <= ?", filter.DateTo.Format("2006-01-02"))
+	}
+	if filter.SpeciesID > 0 {
+		query = query.Where("p.species_id = ?", filter.SpeciesID)
+	}
+	if filter.PrimaryOnly {
+		query = query.Where("ad.is_primary = ?", true)
+	}
+	err := query.
+		Scopes(byClinic("a.clinic_id", filter.ClinicID)).
+		Group("d.id, d.code, d.name, d.category").
+		Order("total DESC, d.code ASC").
+		Scan(&results).Error
+	return results, err
+}
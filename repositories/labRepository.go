@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"VetiCare/entities"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type labRepositoryGORM struct {
+	db *gorm.DB
+}
+
+func NewLabRepositoryGORM(db *gorm.DB) LabRepository {
+	return &labRepositoryGORM{db: db}
+}
+
+// preloadLabTest precarga los analitos de la prueba, en el orden en que se
+// reportan, con sus rangos por especie. prefix es la ruta de la relación, por
+// ejemplo "LabTest." cuando la prueba viene dentro de una orden.
+func preloadLabTest(prefix string, onlyActive bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Preload(prefix+"Analytes", func(db *gorm.DB) *gorm.DB {
+				if onlyActive {
+					db = db.Where("status_id = ?", 1)
+				}
+				return db.Order("sort_order ASC, id ASC")
+			}).
+			Preload(prefix + "Analytes.Ranges")
+	}
+}
+
+// preloadLabOrderRelations precarga la prueba y los resultados de la orden.
+// prefix permite usarla desde la cita, por ejemplo "LabOrders.".
+func preloadLabOrderRelations(prefix string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = preloadLabTest(prefix+"LabTest.", false)(db)
+		return db.
+			Preload(prefix+"LabTest").
+			Preload(prefix+"Results", func(db *gorm.DB) *gorm.DB {
+				return db.Joins("JOIN lab_analytes ON lab_analytes.id = lab_results.analyte_id").
+					Order("lab_analytes.sort_order ASC, lab_analytes.id ASC")
+			}).
+			Preload(prefix + "Results.Analyte")
+	}
+}
+
+func (r *labRepositoryGORM) GetTests(onlyActive bool) ([]entities.LabTest, error) {
+	var list []entities.LabTest
+	query := r.db.Scopes(preloadLabTest("", onlyActive))
+	if onlyActive {
+		query = query.Where("status_id = ?", 1)
+	}
+	err := query.Order("name ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *labRepositoryGORM) GetTestByID(id int) (*entities.LabTest, error) {
+	var t entities.LabTest
+	err := r.db.Scopes(preloadLabTest("", false)).First(&t, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &t, err
+}
+
+func (r *labRepositoryGORM) CreateTest(t *entities.LabTest) error {
+	return r.db.Create(t).Error
+}
+
+func (r *labRepositoryGORM) UpdateTest(id int, fields map[string]interface{}) error {
+	return r.db.Model(&entities.LabTest{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *labRepositoryGORM) DeleteTest(id int) (int, error) {
+	return toggleStatus(r.db, &entities.LabTest{}, id)
+}
+
+func (r *labRepositoryGORM) GetAnalyteByID(id int) (*entities.LabAnalyte, error) {
+	var a entities.LabAnalyte
+	err := r.db.Preload("Ranges").First(&a, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &a, err
+}
+
+func (r *labRepositoryGORM) CreateAnalyte(a *entities.LabAnalyte) error {
+	return r.db.Create(a).Error
+}
+
+func (r *labRepositoryGORM) UpdateAnalyte(id int, fields map[string]interface{}) error {
+	return r.db.Model(&entities.LabAnalyte{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *labRepositoryGORM) DeleteAnalyte(id int) (int, error) {
+	return toggleStatus(r.db, &entities.LabAnalyte{}, id)
+}
+
+// SaveReferenceRange crea el rango del analito para la especie o reemplaza
+// el que ya existía.
+func (r *labRepositoryGORM) SaveReferenceRange(rng *entities.LabReferenceRange) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "analyte_id"}, {Name: "species_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"min", "max"}),
+	}).Create(rng).Error
+}
+
+func (r *labRepositoryGORM) DeleteReferenceRange(analyteID, speciesID int) (bool, error) {
+	result := r.db.Where("analyte_id = ? AND species_id = ?", analyteID, speciesID).Delete(&entities.LabReferenceRange{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *labRepositoryGORM) CreateOrder(o *entities.LabOrder) error {
+	return r.db.Omit("LabTest").Create(o).Error
+}
+
+func (r *labRepositoryGORM) GetOrderByID(id string) (*entities.LabOrder, error) {
+	var o entities.LabOrder
+	err := r.db.Scopes(preloadLabOrderRelations("")).First(&o, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &o, err
+}
+
+func (r *labRepositoryGORM) GetOrdersByAppointmentID(appointmentID string) ([]entities.LabOrder, error) {
+	var list []entities.LabOrder
+	err := r.db.Scopes(preloadLabOrderRelations("")).
+		Where("appointment_id = ?", appointmentID).
+		Order("created_at ASC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *labRepositoryGORM) GetOrdersByPetID(petID string, statusID *int) ([]entities.LabOrder, error) {
+	var list []entities.LabOrder
+	query := r.db.Scopes(preloadLabOrderRelations("")).Where("pet_id = ?", petID)
+	if statusID != nil {
+		query = query.Where("status_id = ?", *statusID)
+	}
+	err := query.Order("created_at DESC").Find(&list).Error
+	return list, err
+}
+
+// GetOpenOrders devuelve las órdenes pendientes o con la muestra enviada, de
+// la más antigua a la más reciente.
+func (r *labRepositoryGORM) GetOpenOrders() ([]entities.LabOrder, error) {
+	var list []entities.LabOrder
+	err := r.db.Preload("LabTest").
+		Where("status_id IN ?", []int{entities.LabOrderStatusPending, entities.LabOrderStatusSent}).
+		Order("created_at ASC").
+		Find(&list).Error
+	return list, err
+}
+
+// UpdateOrderStatus cambia la orden solo si sigue en alguno de los estados
+// indicados; devuelve false si otro cambio se adelantó.
+func (r *labRepositoryGORM) UpdateOrderStatus(id string, fromStatuses []int, fields map[string]interface{}) (bool, error) {
+	result := r.db.Model(&entities.LabOrder{}).
+		Where("id = ? AND status_id IN ?", id, fromStatuses).
+		Updates(fields)
+	return result.RowsAffected > 0, result.Error
+}
+
+// SaveResults guarda los resultados y completa la orden en una transacción.
+// Devuelve false si la orden ya estaba completada o cancelada.
+func (r *labRepositoryGORM) SaveResults(orderID string, results []entities.LabResult, fields map[string]interface{}) (bool, error) {
+	saved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.LabOrder{}).
+			Where("id = ? AND status_id IN ?", orderID, []int{entities.LabOrderStatusPending, entities.LabOrderStatusSent}).
+			Updates(fields)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Omit("Analyte").Create(&results).Error; err != nil {
+			return err
+		}
+		saved = true
+		return nil
+	})
+	return saved, err
+}
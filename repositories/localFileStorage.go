@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrInvalidStorageKey = errors.New("ruta de archivo inválida")
+
+type localFileStorage struct {
+	root string
+}
+
+// NewLocalFileStorage guarda los archivos bajo root, creando la carpeta si no
+// existe.
+func NewLocalFileStorage(root string) (FileStorage, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	return &localFileStorage{root: abs}, nil
+}
+
+// path resuelve la clave dentro de root y rechaza claves que intenten salir de
+// la carpeta.
+func (s *localFileStorage) path(key string) (string, error) {
+	full := filepath.Join(s.root, filepath.FromSlash(key))
+	if !strings.HasPrefix(full, s.root+string(os.PathSeparator)) {
+		return "", ErrInvalidStorageKey
+	}
+	return full, nil
+}
+
+func (s *localFileStorage) Save(key string, content io.Reader) (int64, error) {
+	full, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0o750); err != nil {
+		return 0, err
+	}
+	f, err := os.OpenFile(full, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(f, content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(full)
+		return 0, err
+	}
+	return n, nil
+}
+
+func (s *localFileStorage) Open(key string) (io.ReadCloser, error) {
+	full, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(full)
+}
+
+func (s *localFileStorage) Delete(key string) error {
+	full, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(full); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"VetiCare/entities"
+	"errors"
+	"gorm.io/gorm"
+)
+
+type petHealthRepositoryGORM struct {
+	db *gorm.DB
+}
+
+func NewPetHealthRepositoryGORM(db *gorm.DB) PetHealthRepository {
+	return &petHealthRepositoryGORM{db: db}
+}
+
+// preloadPetAlerts precarga las alergias, condiciones y avisos de conducta
+// activos de la mascota. prefix es la ruta de la relación, por ejemplo "Pet."
+// cuando la mascota viene dentro de una cita.
+func preloadPetAlerts(prefix string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Preload(prefix+"Allergies", "status_id = ?", 1).
+			Preload(prefix+"Conditions", "status_id = ?", 1).
+			Preload(prefix+"BehaviorAlerts", "status_id = ?", 1)
+	}
+}
+
+// toggleStatus alterna el registro entre activo (1) e inactivo (2) y devuelve
+// el nuevo estado.
+func toggleStatus(db *gorm.DB, model interface{}, id interface{}) (int, error) {
+	var current struct{ StatusID int }
+	if err := db.Model(model).Select("status_id").Where("id = ?", id).Take(&current).Error; err != nil {
+		return 0, err
+	}
+	newStatus := 1
+	if current.StatusID == 1 {
+		newStatus = 2
+	}
+	err := db.Model(model).Where("id = ?", id).Update("status_id", newStatus).Error
+	return newStatus, err
+}
+
+func (r *petHealthRepositoryGORM) GetAllergies(petID string, onlyActive bool) ([]entities.PetAllergy, error) {
+	var list []entities.PetAllergy
+	query := r.db.Where("pet_id = ?", petID)
+	if onlyActive {
+		query = query.Where("status_id = ?", 1)
+	}
+	err := query.Order("created_at ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *petHealthRepositoryGORM) GetAllergyByID(id string) (*entities.PetAllergy, error) {
+	var a entities.PetAllergy
+	err := r.db.First(&a, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &a, err
+}
+
+func (r *petHealthRepositoryGORM) CreateAllergy(a *entities.PetAllergy) error {
+	return r.db.Create(a).Error
+}
+
+func (r *petHealthRepositoryGORM) UpdateAllergy(id string, fields map[string]interface{}) error {
+	return r.db.Model(&entities.PetAllergy{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *petHealthRepositoryGORM) DeleteAllergy(id string) (int, error) {
+	return toggleStatus(r.db, &entities.PetAllergy{}, id)
+}
+
+func (r *petHealthRepositoryGORM) GetConditions(petID string, onlyActive bool) ([]entities.PetCondition, error) {
+	var list []entities.PetCondition
+	query := r.db.Where("pet_id = ?", petID)
+	if onlyActive {
+		query = query.Where("status_id = ?", 1)
+	}
+	err := query.Order("created_at ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *petHealthRepositoryGORM) GetConditionByID(id string) (*entities.PetCondition, error) {
+	var c entities.PetCondition
+	err := r.db.First(&c, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &c, err
+}
+
+func (r *petHealthRepositoryGORM) CreateCondition(c *entities.PetCondition) error {
+	return r.db.Create(c).Error
+}
+
+func (r *petHealthRepositoryGORM) UpdateCondition(id string, fields map[string]interface{}) error {
+	return r.db.Model(&entities.PetCondition{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *petHealthRepositoryGORM) DeleteCondition(id string) (int, error) {
+	return toggleStatus(r.db, &entities.PetCondition{}, id)
+}
+
+func (r *petHealthRepositoryGORM) GetBehaviorAlerts(petID string, onlyActive bool) ([]entities.PetBehaviorAlert, error) {
+	var list []entities.PetBehaviorAlert
+	query := r.db.Where("pet_id = ?", petID)
+	if onlyActive {
+		query = query.Where("status_id = ?", 1)
+	}
+	err := query.Order("created_at ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *petHealthRepositoryGORM) GetBehaviorAlertByID(id string) (*entities.PetBehaviorAlert, error) {
+	var b entities.PetBehaviorAlert
+	err := r.db.First(&b, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &b, err
+}
+
+func (r *petHealthRepositoryGORM) CreateBehaviorAlert(b *entities.PetBehaviorAlert) error {
+	return r.db.Create(b).Error
+}
+
+func (r *petHealthRepositoryGORM) UpdateBehaviorAlert(id string, fields map[string]interface{}) error {
+	return r.db.Model(&entities.PetBehaviorAlert{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *petHealthRepositoryGORM) DeleteBehaviorAlert(id string) (int, error) {
+	return toggleStatus(r.db, &entities.PetBehaviorAlert{}, id)
+}
@@ -8,6 +8,10 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrMicrochipTaken indica que otra mascota guardó el mismo microchip entre
+// la comprobación del servicio y la escritura; lo detecta el índice único.
+var ErrMicrochipTaken = errors.New("el microchip ya está registrado en otra mascota")
+
 type petRepositoryGORM struct {
 	db *gorm.DB
 }
@@ -17,12 +21,12 @@ func NewPetRepositoryGORM(db *gorm.DB) PetRepository {
 }
 
 func (r *petRepositoryGORM) Create(pet *entities.Pet) error {
-	return r.db.Create(pet).Error
+	return r.translateError(r.db.Create(pet).Error)
 }
 
 func (r *petRepositoryGORM) GetByID(id string) (*entities.Pet, error) {
 	var pet entities.Pet
-	err := r.db.Preload("Owner").Preload("Species").Where("id = ?", id).First(&pet).Error
+	err := r.db.Preload("Owner").Preload("Species").Preload("BreedInfo").Preload("Species.ReferenceRanges", "status_id = ?", 1).Scopes(preloadPetAlerts("")).Where("id = ?", id).First(&pet).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -31,19 +35,39 @@ func (r *petRepositoryGORM) GetByID(id string) (*entities.Pet, error) {
 
 func (r *petRepositoryGORM) GetAll() ([]entities.Pet, error) {
 	var pets []entities.Pet
-	err := r.db.Preload("Owner").Preload("Species").Find(&pets).Error
+	err := r.db.Preload("Owner").Preload("Species").Preload("BreedInfo").Scopes(preloadPetAlerts("")).Find(&pets).Error
 	return pets, err
 }
 
 func (r *petRepositoryGORM) GetActivePets() ([]entities.Pet, error) {
 	var pets []entities.Pet
-	err := r.db.Preload("Owner").Preload("Species").Where("status_id = ?", 1).Find(&pets).Error
+	err := r.db.Preload("Owner").Preload("Species").Preload("BreedInfo").Scopes(preloadPetAlerts("")).Where("status_id = ?", 1).Find(&pets).Error
 	return pets, err
 }
 
 func (r *petRepositoryGORM) GetPetsByOwner(ownerID string) ([]entities.Pet, error) {
 	var pets []entities.Pet
-	err := r.db.Preload("Owner").Preload("Species").Where("owner_id = ?", ownerID).Find(&pets).Error
+	err := r.db.Preload("Owner").Preload("Species").Preload("BreedInfo").Scopes(preloadPetAlerts("")).Where("owner_id = ?", ownerID).Find(&pets).Error
+	return pets, err
+}
+
+func (r *petRepositoryGORM) GetByMicrochip(number string) (*entities.Pet, error) {
+	var pet entities.Pet
+	err := r.db.Preload("Owner").Preload("Species").Preload("BreedInfo").Where("microchip = ?", number).First(&pet).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &pet, err
+}
+
+// GetLegacyBreedPets devuelve las mascotas que tienen la raza escrita como
+// texto libre pero todavía no apuntan a una raza del catálogo.
+func (r *petRepositoryGORM) GetLegacyBreedPets() ([]entities.Pet, error) {
+	var pets []entities.Pet
+	err := r.db.Preload("Species").
+		Where("breed_id IS NULL AND breed IS NOT NULL AND TRIM(breed) <> ''").
+		Order("species_id ASC, name ASC").
+		Find(&pets).Error
 	return pets, err
 }
 
@@ -51,7 +75,21 @@ func (r *petRepositoryGORM) Update(id string, fields map[string]interface{}) err
 	if len(fields) == 0 {
 		return nil
 	}
-	return r.db.Model(&entities.Pet{}).Where("id = ?", id).Updates(fields).Error
+	return r.translateError(r.db.Model(&entities.Pet{}).Where("id = ?", id).Updates(fields).Error)
+}
+
+// translateError convierte la violación del índice único del microchip en
+// ErrMicrochipTaken. Es el único índice único de la tabla aparte del ID.
+func (r *petRepositoryGORM) translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if translator, ok := r.db.Dialector.(gorm.ErrorTranslator); ok {
+		if errors.Is(translator.Translate(err), gorm.ErrDuplicatedKey) {
+			return ErrMicrochipTaken
+		}
+	}
+	return err
 }
 
 func (r *petRepositoryGORM) Delete(id string) (int, error) {
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"VetiCare/entities"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type prescriptionRepositoryGORM struct {
+	db *gorm.DB
+}
+
+func NewPrescriptionRepositoryGORM(db *gorm.DB) PrescriptionRepository {
+	return &prescriptionRepositoryGORM{db: db}
+}
+
+func preloadPrescriptionRelations(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Items").
+		Preload("Pet").
+		Preload("Pet.Species").
+		Preload("Vet")
+}
+
+// Create guarda la receta junto con sus medicamentos en una misma
+// transacción.
+func (r *prescriptionRepositoryGORM) Create(prescription *entities.Prescription) error {
+	return r.db.Create(prescription).Error
+}
+
+func (r *prescriptionRepositoryGORM) GetByID(id string) (*entities.Prescription, error) {
+	var p entities.Prescription
+	err := r.db.Scopes(preloadPrescriptionRelations).Where("id = ?", id).First(&p).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &p, err
+}
+
+func (r *prescriptionRepositoryGORM) GetByAppointmentID(appointmentID string) ([]entities.Prescription, error) {
+	var list []entities.Prescription
+	err := r.db.Scopes(preloadPrescriptionRelations).
+		Where("appointment_id = ?", appointmentID).
+		Order("signed_at DESC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *prescriptionRepositoryGORM) GetByPetID(petID string) ([]entities.Prescription, error) {
+	var list []entities.Prescription
+	err := r.db.Scopes(preloadPrescriptionRelations).
+		Where("pet_id = ?", petID).
+		Order("signed_at DESC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *prescriptionRepositoryGORM) GetActiveByPetID(petID string) ([]entities.Prescription, error) {
+	var list []entities.Prescription
+	err := r.db.Scopes(preloadPrescriptionRelations).
+		Where("pet_id = ? AND status_id = ?", petID, entities.PrescriptionStatusActive).
+		Order("signed_at DESC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *prescriptionRepositoryGORM) Void(id string, voidedBy *uuid.UUID, reason string) error {
+	result := r.db.Model(&entities.Prescription{}).
+		Where("id = ? AND status_id = ?", id, entities.PrescriptionStatusActive).
+		Updates(map[string]interface{}{
+			"status_id":    entities.PrescriptionStatusVoided,
+			"voided_at":    time.Now(),
+			"voided_by_id": voidedBy,
+			"void_reason":  reason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("la receta %s no está activa", id)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"VetiCare/entities"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type preventiveCareRepositoryGORM struct {
+	db *gorm.DB
+}
+
+func NewPreventiveCareRepositoryGORM(db *gorm.DB) PreventiveCareRepository {
+	return &preventiveCareRepositoryGORM{db: db}
+}
+
+func preloadDewormingRelations(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Pet").
+		Preload("Pet.Owner").
+		Preload("Pet.Species").
+		Preload("AdministeredBy")
+}
+
+func (r *preventiveCareRepositoryGORM) CreateDeworming(deworming *entities.Deworming) error {
+	return r.db.Create(deworming).Error
+}
+
+func (r *preventiveCareRepositoryGORM) GetDewormingByID(id string) (*entities.Deworming, error) {
+	var d entities.Deworming
+	err := r.db.Scopes(preloadDewormingRelations).Where("id = ?", id).First(&d).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &d, err
+}
+
+func (r *preventiveCareRepositoryGORM) GetDewormingsByPet(petID string) ([]entities.Deworming, error) {
+	var list []entities.Deworming
+	err := r.db.Scopes(preloadDewormingRelations).
+		Where("pet_id = ?", petID).
+		Order("date_given DESC").
+		Find(&list).Error
+	return list, err
+}
+
+// GetLatestDewormings devuelve la última desparasitación de cada mascota
+// activa cuando su próxima dosis vence dentro del rango.
+func (r *preventiveCareRepositoryGORM) GetLatestDewormings(from, to time.Time) ([]entities.Deworming, error) {
+	var list []entities.Deworming
+	err := r.db.
+		Joins("JOIN pets ON pets.id = dewormings.pet_id").
+		Where("pets.status_id = ?", 1).
+		Where("dewormings.next_due_date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM dewormings newer
+			WHERE newer.pet_id = dewormings.pet_id
+			AND newer.date_given > dewormings.date_given)`).
+		Scopes(preloadDewormingRelations).
+		Order("dewormings.next_due_date ASC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *preventiveCareRepositoryGORM) GetPetIDsWithDeworming() ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&entities.Deworming{}).Distinct().Pluck("pet_id", &ids).Error
+	return ids, err
+}
+
+func (r *preventiveCareRepositoryGORM) GetPetIDsWithVaccine(vaccineID int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&entities.Vaccination{}).
+		Where("vaccine_id = ?", vaccineID).
+		Distinct().
+		Pluck("pet_id", &ids).Error
+	return ids, err
+}
+
+func (r *preventiveCareRepositoryGORM) GetProtocols(speciesID *int, onlyActive bool) ([]entities.PreventiveProtocol, error) {
+	var list []entities.PreventiveProtocol
+	query := r.db.Preload("Species").Preload("Vaccine")
+	if speciesID != nil {
+		query = query.Where("species_id = ?", *speciesID)
+	}
+	if onlyActive {
+		query = query.Where("status_id = ?", 1)
+	}
+	err := query.Order("species_id ASC, min_age_weeks ASC, id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *preventiveCareRepositoryGORM) GetProtocolByID(id int) (*entities.PreventiveProtocol, error) {
+	var p entities.PreventiveProtocol
+	err := r.db.Preload("Species").Preload("Vaccine").First(&p, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &p, err
+}
+
+func (r *preventiveCareRepositoryGORM) CreateProtocol(protocol *entities.PreventiveProtocol) error {
+	return r.db.Create(protocol).Error
+}
+
+func (r *preventiveCareRepositoryGORM) UpdateProtocol(id int, fields map[string]interface{}) error {
+	return r.db.Model(&entities.PreventiveProtocol{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *preventiveCareRepositoryGORM) DeleteProtocol(id int) (int, error) {
+	var p entities.PreventiveProtocol
+	if err := r.db.First(&p, "id = ?", id).Error; err != nil {
+		return 0, err
+	}
+	newStatus := 1
+	if p.StatusID == 1 {
+		newStatus = 2
+	}
+	err := r.db.Model(&p).Update("status_id", newStatus).Error
+	return newStatus, err
+}
+
+func (r *preventiveCareRepositoryGORM) GetRemindersByKeys(keys []string) ([]entities.CareReminder, error) {
+	var list []entities.CareReminder
+	if len(keys) == 0 {
+		return list, nil
+	}
+	err := r.db.Where("key IN ?", keys).Find(&list).Error
+	return list, err
+}
+
+// CreateReminders ignora los recordatorios cuya clave ya existe, por si dos
+// ejecuciones del job se cruzan.
+func (r *preventiveCareRepositoryGORM) CreateReminders(reminders []entities.CareReminder) error {
+	if len(reminders) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&reminders).Error
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"VetiCare/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type recordInterchangeRepositoryGORM struct {
+	db *gorm.DB
+}
+
+func NewRecordInterchangeRepositoryGORM(db *gorm.DB) RecordInterchangeRepository {
+	return &recordInterchangeRepositoryGORM{db: db}
+}
+
+// SaveImport guarda en una sola transacción el dueño y la mascota nuevos y
+// los registros clínicos importados, para no dejar historiales a medias.
+func (r *recordInterchangeRepositoryGORM) SaveImport(imp *entities.RecordImport) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if imp.OwnerCreated {
+			if err := tx.Omit(clause.Associations).Create(imp.Owner).Error; err != nil {
+				return err
+			}
+		}
+		if imp.PetCreated {
+			if err := tx.Omit(clause.Associations).Create(imp.Pet).Error; err != nil {
+				return err
+			}
+		}
+		if len(imp.Appointments) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&imp.Appointments).Error; err != nil {
+				return err
+			}
+		}
+		if len(imp.VitalSigns) > 0 {
+			if err := tx.Create(&imp.VitalSigns).Error; err != nil {
+				return err
+			}
+		}
+		if len(imp.Vaccinations) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&imp.Vaccinations).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
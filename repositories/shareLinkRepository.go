@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"VetiCare/entities"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+type shareLinkRepositoryGORM struct {
+	db *gorm.DB
+}
+
+func NewShareLinkRepositoryGORM(db *gorm.DB) ShareLinkRepository {
+	return &shareLinkRepositoryGORM{db: db}
+}
+
+func (r *shareLinkRepositoryGORM) Create(link *entities.ShareLink) error {
+	return r.db.Omit("Pet").Create(link).Error
+}
+
+func (r *shareLinkRepositoryGORM) GetByID(id string) (*entities.ShareLink, error) {
+	var link entities.ShareLink
+	err := r.db.Preload("Pet").First(&link, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &link, err
+}
+
+func (r *shareLinkRepositoryGORM) GetByPetID(petID string) ([]entities.ShareLink, error) {
+	var list []entities.ShareLink
+	err := r.db.Where("pet_id = ?", petID).Order("created_at DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *shareLinkRepositoryGORM) Revoke(id, revokedByName string, at time.Time) error {
+	return r.db.Model(&entities.ShareLink{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":      at,
+			"revoked_by_name": revokedByName,
+		}).Error
+}
+
+// ReservePinAttempt cuenta el intento antes de comprobar el PIN, en una sola
+// sentencia, para que las peticiones simultáneas no superen el máximo. Devuelve
+// false si el enlace ya agotó sus intentos.
+func (r *shareLinkRepositoryGORM) ReservePinAttempt(id string, maxAttempts int) (bool, error) {
+	result := r.db.Model(&entities.ShareLink{}).
+		Where("id = ? AND failed_pin_attempts < ?", id, maxAttempts).
+		UpdateColumn("failed_pin_attempts", gorm.Expr("failed_pin_attempts + 1"))
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *shareLinkRepositoryGORM) ResetFailedPin(id string) error {
+	return r.db.Model(&entities.ShareLink{}).
+		Where("id = ?", id).
+		UpdateColumn("failed_pin_attempts", 0).Error
+}
+
+// RecordAccess guarda el intento en la bitácora y, si se concedió, actualiza
+// el contador y la fecha del último acceso del enlace.
+func (r *shareLinkRepositoryGORM) RecordAccess(access *entities.ShareLinkAccess) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(access).Error; err != nil {
+			return err
+		}
+		if !access.Granted {
+			return nil
+		}
+		return tx.Model(&entities.ShareLink{}).
+			Where("id = ?", access.LinkID).
+			UpdateColumns(map[string]interface{}{
+				"access_count":     gorm.Expr("access_count + 1"),
+				"last_accessed_at": access.AccessedAt,
+			}).Error
+	})
+}
+
+func (r *shareLinkRepositoryGORM) GetAccesses(linkID string) ([]entities.ShareLinkAccess, error) {
+	var list []entities.ShareLinkAccess
+	err := r.db.Where("link_id = ?", linkID).Order("accessed_at DESC").Find(&list).Error
+	return list, err
+}
@@ -22,7 +22,9 @@ func (r *speciesRepositoryGORM) GetAll() ([]entities.Species, error) {
 
 func (r *speciesRepositoryGORM) GetByID(id int) (*entities.Species, error) {
 	var s entities.Species
-	err := r.db.First(&s, "id = ?", id).Error
+	err := r.db.Preload("ReferenceRanges", func(db *gorm.DB) *gorm.DB {
+		return db.Order("vital ASC, min_age_weeks ASC, id ASC")
+	}).First(&s, "id = ?", id).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -40,3 +42,83 @@ func (r *speciesRepositoryGORM) Update(id int, fields map[string]interface{}) er
 func (r *speciesRepositoryGORM) Delete(id int) error {
 	return r.db.Delete(&entities.Species{}, id).Error
 }
+
+func (r *speciesRepositoryGORM) GetReferenceRanges(speciesID int, onlyActive bool) ([]entities.VitalReferenceRange, error) {
+	var list []entities.VitalReferenceRange
+	query := r.db.Where("species_id = ?", speciesID)
+	if onlyActive {
+		query = query.Where("status_id = ?", 1)
+	}
+	err := query.Order("vital ASC, min_age_weeks ASC, id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *speciesRepositoryGORM) GetReferenceRangeByID(id int) (*entities.VitalReferenceRange, error) {
+	var vr entities.VitalReferenceRange
+	err := r.db.First(&vr, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &vr, err
+}
+
+func (r *speciesRepositoryGORM) CreateReferenceRange(vr *entities.VitalReferenceRange) error {
+	return r.db.Create(vr).Error
+}
+
+func (r *speciesRepositoryGORM) UpdateReferenceRange(id int, fields map[string]interface{}) error {
+	return r.db.Model(&entities.VitalReferenceRange{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *speciesRepositoryGORM) DeleteReferenceRange(id int) (int, error) {
+	var vr entities.VitalReferenceRange
+	if err := r.db.First(&vr, "id = ?", id).Error; err != nil {
+		return 0, err
+	}
+	newStatus := 1
+	if vr.StatusID == 1 {
+		newStatus = 2
+	}
+	err := r.db.Model(&vr).Update("status_id", newStatus).Error
+	return newStatus, err
+}
+
+func (r *speciesRepositoryGORM) GetBreeds(speciesID int, onlyActive bool) ([]entities.Breed, error) {
+	var list []entities.Breed
+	query := r.db.Where("species_id = ?", speciesID)
+	if onlyActive {
+		query = query.Where("status_id = ?", 1)
+	}
+	err := query.Order("is_mixed ASC, name ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *speciesRepositoryGORM) GetBreedByID(id int) (*entities.Breed, error) {
+	var b entities.Breed
+	err := r.db.First(&b, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &b, err
+}
+
+func (r *speciesRepositoryGORM) CreateBreed(b *entities.Breed) error {
+	return r.db.Create(b).Error
+}
+
+func (r *speciesRepositoryGORM) UpdateBreed(id int, fields map[string]interface{}) error {
+	return r.db.Model(&entities.Breed{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *speciesRepositoryGORM) DeleteBreed(id int) (int, error) {
+	var b entities.Breed
+	if err := r.db.First(&b, "id = ?", id).Error; err != nil {
+		return 0, err
+	}
+	newStatus := 1
+	if b.StatusID == 1 {
+		newStatus = 2
+	}
+	err := r.db.Model(&b).Update("status_id", newStatus).Error
+	return newStatus, err
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"VetiCare/entities"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+type treatmentPlanRepositoryGORM struct {
+	db *gorm.DB
+}
+
+func NewTreatmentPlanRepositoryGORM(db *gorm.DB) TreatmentPlanRepository {
+	return &treatmentPlanRepositoryGORM{db: db}
+}
+
+func preloadTreatmentPlanRelations(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Pet").
+		Preload("Pet.Owner").
+		Preload("Pet.Species").
+		Preload("Vet").
+		Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("step_order ASC")
+		}).
+		Preload("Steps.Appointment")
+}
+
+func (r *treatmentPlanRepositoryGORM) Create(plan *entities.TreatmentPlan) error {
+	return r.db.Omit("Pet", "Vet").Create(plan).Error
+}
+
+func (r *treatmentPlanRepositoryGORM) GetByID(id string) (*entities.TreatmentPlan, error) {
+	var plan entities.TreatmentPlan
+	err := r.db.Scopes(preloadTreatmentPlanRelations).First(&plan, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &plan, err
+}
+
+func (r *treatmentPlanRepositoryGORM) GetByPetID(petID string, statusID *int) ([]entities.TreatmentPlan, error) {
+	var list []entities.TreatmentPlan
+	query := r.db.Where("pet_id = ?", petID)
+	if statusID != nil {
+		query = query.Where("status_id = ?", *statusID)
+	}
+	err := query.
+		Scopes(preloadTreatmentPlanRelations).
+		Order("start_date DESC, created_at DESC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *treatmentPlanRepositoryGORM) Update(id string, fields map[string]interface{}) error {
+	return r.db.Model(&entities.TreatmentPlan{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *treatmentPlanRepositoryGORM) AddStep(step *entities.TreatmentPlanStep) error {
+	return r.db.Omit("Plan", "Appointment").Create(step).Error
+}
+
+// SaveProgress guarda en una transacción el estado, la cita y la fecha de
+// vencimiento de cada paso junto con el estado del plan.
+func (r *treatmentPlanRepositoryGORM) SaveProgress(plan *entities.TreatmentPlan) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, step := range plan.Steps {
+			err := tx.Model(&entities.TreatmentPlanStep{}).
+				Where("id = ?", step.ID).
+				Updates(map[string]interface{}{
+					"status_id":      step.StatusID,
+					"appointment_id": step.AppointmentID,
+					"due_date":       step.DueDate,
+					"completed_at":   step.CompletedAt,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Model(&entities.TreatmentPlan{}).
+			Where("id = ?", plan.ID).
+			Updates(map[string]interface{}{
+				"status_id":    plan.StatusID,
+				"completed_at": plan.CompletedAt,
+			}).Error
+	})
+}
+
+func (r *treatmentPlanRepositoryGORM) GetStepByAppointmentID(appointmentID string) (*entities.TreatmentPlanStep, error) {
+	var step entities.TreatmentPlanStep
+	err := r.db.First(&step, "appointment_id = ?", appointmentID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &step, err
+}
+
+// GetOverdueSteps devuelve los pasos pendientes de planes activos cuya fecha
+// de vencimiento ya pasó, del más atrasado al más reciente.
+func (r *treatmentPlanRepositoryGORM) GetOverdueSteps(today time.Time, clinicID *int) ([]entities.TreatmentPlanStep, error) {
+	var list []entities.TreatmentPlanStep
+	err := r.db.
+		Joins("JOIN treatment_plans ON treatment_plans.id = treatment_plan_steps.plan_id").
+		Where("treatment_plans.status_id = ? AND treatment_plan_steps.status_id = ?", entities.TreatmentPlanStatusActive, entities.TreatmentStepStatusPending).
+		Where("treatment_plan_steps.due_date < ?", today.Format("2006-01-02")).
+		Scopes(byClinic("treatment_plans.clinic_id", clinicID)).
+		Preload("Plan").
+		Preload("Plan.Pet").
+		Preload("Plan.Pet.Owner").
+		Preload("Plan.Vet").
+		Order("treatment_plan_steps.due_date ASC").
+		Find(&list).Error
+	return list, err
+}
@@ -22,7 +22,7 @@ func (r *userRepositoryGORM) Create(user *entities.User) error {
 
 func (r *userRepositoryGORM) GetByID(id string) (*entities.User, error) {
 	var u entities.User
-	result := r.db.Preload("Role").Where("id = ?", id).First(&u)
+	result := r.db.Preload("Role").Preload("Clinics").Where("id = ?", id).First(&u)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -38,15 +38,28 @@ func (r *userRepositoryGORM) GetByEmail(email string) (*entities.User, error) {
 	return &u, result.Error
 }
 
-func (r *userRepositoryGORM) GetByRole(roleID int) ([]entities.User, error) {
+func (r *userRepositoryGORM) GetByDUI(dui string) (*entities.User, error) {
+	var u entities.User
+	result := r.db.Preload("Role").Where("dui = ?", dui).First(&u)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &u, result.Error
+}
+
+func (r *userRepositoryGORM) GetByRole(roleID int, clinicID *int) ([]entities.User, error) {
 	var users []entities.User
-	result := r.db.Preload("Role").Where("role_id = ? AND status_id = ?", roleID, 1).Find(&users)
+	query := r.db.Preload("Role").Preload("Clinics").Where("role_id = ? AND status_id = ?", roleID, 1)
+	if clinicID != nil {
+		query = query.Where("id IN (?)", r.db.Table("vet_clinics").Select("user_id").Where("clinic_id = ?", *clinicID))
+	}
+	result := query.Order("id").Find(&users)
 	return users, result.Error
 }
 
 func (r *userRepositoryGORM) GetAll() ([]entities.User, error) {
 	var users []entities.User
-	result := r.db.Preload("Role").Find(&users)
+	result := r.db.Preload("Role").Preload("Clinics").Find(&users)
 	return users, result.Error
 }
 
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"VetiCare/entities"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type vaccinationRepositoryGORM struct {
+	db *gorm.DB
+}
+
+func NewVaccinationRepositoryGORM(db *gorm.DB) VaccinationRepository {
+	return &vaccinationRepositoryGORM{db: db}
+}
+
+func preloadVaccinationRelations(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Pet").
+		Preload("Pet.Owner").
+		Preload("Pet.Species").
+		Preload("Vaccine").
+		Preload("AdministeredBy")
+}
+
+func (r *vaccinationRepositoryGORM) GetVaccines(onlyActive bool) ([]entities.Vaccine, error) {
+	var list []entities.Vaccine
+	query := r.db.Preload("Species")
+	if onlyActive {
+		query = query.Where("status_id = ?", 1)
+	}
+	err := query.Order("name ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *vaccinationRepositoryGORM) GetVaccineByID(id int) (*entities.Vaccine, error) {
+	var vaccine entities.Vaccine
+	err := r.db.Preload("Species").First(&vaccine, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &vaccine, err
+}
+
+func (r *vaccinationRepositoryGORM) CreateVaccine(vaccine *entities.Vaccine) error {
+	return r.db.Create(vaccine).Error
+}
+
+func (r *vaccinationRepositoryGORM) UpdateVaccine(id int, fields map[string]interface{}) error {
+	return r.db.Model(&entities.Vaccine{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *vaccinationRepositoryGORM) DeleteVaccine(id int) (int, error) {
+	var vaccine entities.Vaccine
+	if err := r.db.First(&vaccine, "id = ?", id).Error; err != nil {
+		return 0, err
+	}
+	newStatus := 1
+	if vaccine.StatusID == 1 {
+		newStatus = 2
+	}
+	err := r.db.Model(&vaccine).Update("status_id", newStatus).Error
+	return newStatus, err
+}
+
+func (r *vaccinationRepositoryGORM) Create(vaccination *entities.Vaccination) error {
+	return r.db.Create(vaccination).Error
+}
+
+func (r *vaccinationRepositoryGORM) GetByID(id string) (*entities.Vaccination, error) {
+	var vaccination entities.Vaccination
+	err := r.db.Scopes(preloadVaccinationRelations).Where("id = ?", id).First(&vaccination).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &vaccination, err
+}
+
+func (r *vaccinationRepositoryGORM) GetByPetID(petID string) ([]entities.Vaccination, error) {
+	var list []entities.Vaccination
+	err := r.db.
+		Scopes(preloadVaccinationRelations).
+		Where("pet_id = ?", petID).
+		Order("date_given DESC, dose_number DESC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *vaccinationRepositoryGORM) CountDoses(petID string, vaccineID int) (int64, error) {
+	var count int64
+	err := r.db.Model(&entities.Vaccination{}).
+		Where("pet_id = ? AND vaccine_id = ?", petID, vaccineID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *vaccinationRepositoryGORM) ExistsForAppointment(appointmentID string, vaccineID int) (bool, error) {
+	var count int64
+	err := r.db.Model(&entities.Vaccination{}).
+		Where("appointment_id = ? AND vaccine_id = ?", appointmentID, vaccineID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetDue devuelve la última dosis de cada vacuna por mascota cuya siguiente
+// aplicación vence dentro del rango; las dosis ya reemplazadas por una más
+// reciente no se incluyen.
+func (r *vaccinationRepositoryGORM) GetDue(filter entities.VaccinationDueFilter) ([]entities.Vaccination, error) {
+	var list []entities.Vaccination
+	query := r.db.
+		Joins("JOIN pets ON pets.id = vaccinations.pet_id").
+		Where("pets.status_id = ?", 1).
+		Where("vaccinations.next_due_date BETWEEN ? AND ?", filter.From.Format("2006-01-02"), filter.To.Format("2006-01-02")).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM vaccinations newer
+			WHERE newer.pet_id = vaccinations.pet_id
+			AND newer.vaccine_id = vaccinations.vaccine_id
+			AND newer.date_given > vaccinations.date_given)`)
+	if filter.VaccineID != nil {
+		query = query.Where("vaccinations.vaccine_id = ?", *filter.VaccineID)
+	}
+	if filter.SpeciesID != nil {
+		query = query.Where("pets.species_id = ?", *filter.SpeciesID)
+	}
+	err := query.
+		Scopes(preloadVaccinationRelations).
+		Order("vaccinations.next_due_date ASC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *vaccinationRepositoryGORM) GetLegacyAppointments() ([]entities.Appointment, error) {
+	var apps []entities.Appointment
+	err := r.db.
+		Preload("Pet").
+		Preload("Pet.Species").
+		Where("vaccination_status IS NOT NULL AND TRIM(vaccination_status) <> ''").
+		Order("TO_DATE(date, 'DD-MM-YYYY') ASC").
+		Find(&apps).Error
+	return apps, err
+}
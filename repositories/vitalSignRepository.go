@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"VetiCare/entities"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type vitalSignRepositoryGORM struct {
+	db *gorm.DB
+}
+
+func NewVitalSignRepositoryGORM(db *gorm.DB) VitalSignRepository {
+	return &vitalSignRepositoryGORM{db: db}
+}
+
+func (r *vitalSignRepositoryGORM) Create(vital *entities.VitalSign) error {
+	return r.db.Create(vital).Error
+}
+
+func (r *vitalSignRepositoryGORM) GetByID(id string) (*entities.VitalSign, error) {
+	var v entities.VitalSign
+	err := r.db.Where("id = ?", id).First(&v).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &v, err
+}
+
+func (r *vitalSignRepositoryGORM) GetByPetID(petID string) ([]entities.VitalSign, error) {
+	var list []entities.VitalSign
+	err := r.db.Where("pet_id = ?", petID).Order("recorded_at ASC").Find(&list).Error
+	return list, err
+}
@@ -3,80 +3,1053 @@ package services
 import (
 	"VetiCare/entities"
 	"VetiCare/repositories"
+	"VetiCare/utils"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAppointmentNotFound      = errors.New("cita no encontrada")
+	ErrAppointmentNotScheduled  = errors.New("solo se pueden reprogramar citas agendadas")
+	ErrAppointmentNotCancelable = errors.New("solo se pueden cancelar citas agendadas")
+	ErrAppointmentClinicNeeded  = errors.New("la cita debe pertenecer a una clínica")
+	ErrAppointmentSlotTaken     = errors.New("ya existe una cita registrada para esa fecha y hora")
+	ErrAppointmentSameSlot      = errors.New("la nueva fecha y hora son iguales a las actuales")
+	ErrRescheduleCutoff         = errors.New("la cita ya no puede reprogramarse, se superó el tiempo límite de la clínica")
+	ErrRescheduleLimitReached   = errors.New("la cita alcanzó el número máximo de reprogramaciones")
+	ErrRescheduleDateTimeInPast = errors.New("la nueva fecha y hora no pueden ser en el pasado")
+	ErrApprovalNotRequired      = errors.New("la cita no requiere aprobación")
+	ErrApprovalPending          = errors.New("la cita requiere aprobación del personal antes de atenderse")
+	ErrDepositNotRequired       = errors.New("la cita no requiere depósito")
+	ErrDepositPending           = errors.New("la cita requiere registrar el depósito antes de atenderse")
+	ErrInvalidStatusTransition  = errors.New("el cambio de estado no es válido para la cita")
+	ErrCheckInNotToday          = errors.New("solo se puede registrar la llegada de citas del día")
+	ErrVetNotInClinic           = errors.New("el veterinario no está asignado a la clínica de la cita")
+	ErrRoomNotFound             = errors.New("sala no encontrada o inactiva")
+	ErrRoomNotInClinic          = errors.New("la sala no pertenece a la clínica de la cita")
+	ErrRoomFull                 = errors.New("la sala no tiene capacidad disponible en ese horario")
+	ErrResourceNotFound         = errors.New("equipo no encontrado o inactivo")
+	ErrResourceNotInClinic      = errors.New("el equipo no pertenece a la clínica de la cita")
+	ErrResourceUnavailable      = errors.New("el equipo no está disponible en ese horario")
+	ErrReassignmentChanged      = errors.New("las propuestas cambiaron desde la vista previa; revíselas de nuevo")
+	ErrAppointmentStatusChanged = errors.New("el estado de la cita cambió mientras se actualizaba; consúltela de nuevo")
+	ErrAppointmentRecordLocked  = errors.New("la cita está finalizada; sus datos clínicos solo pueden corregirse con una enmienda")
 )
 
+// lockedRecordFields son los campos de una cita finalizada que ya no se editan
+// directamente; se corrigen con una enmienda para conservar el historial.
+var lockedRecordFields = []string{"pet_id", "reason", "weight_kg", "temperature", "vaccination_status", "medications_prescribed", "additional_notes"}
+
 type AppointmentService struct {
-	Repo repositories.AppointmentRepository
+	Repo             repositories.AppointmentRepository
+	CalendarRepo     repositories.CalendarRepository
+	ClinicRepo       repositories.ClinicRepository
+	UserRepo         repositories.UserRepository
+	PrescriptionRepo repositories.PrescriptionRepository
+	ClinicalNoteRepo repositories.ClinicalNoteRepository
+	AttachmentRepo   repositories.AttachmentRepository
+
+	TreatmentPlanRepo repositories.TreatmentPlanRepository
+	PetRepo           repositories.PetRepository
+	Access            *PetAccess
 }
 
-func NewAppointmentService(repo repositories.AppointmentRepository) *AppointmentService {
-	return &AppointmentService{Repo: repo}
+func NewAppointmentService(repo repositories.AppointmentRepository, calendarRepo repositories.CalendarRepository, clinicRepo repositories.ClinicRepository, userRepo repositories.UserRepository, prescriptionRepo repositories.PrescriptionRepository, clinicalNoteRepo repositories.ClinicalNoteRepository, attachmentRepo repositories.AttachmentRepository, treatmentPlanRepo repositories.TreatmentPlanRepository, petRepo repositories.PetRepository, access *PetAccess) *AppointmentService {
+	return &AppointmentService{Repo: repo, CalendarRepo: calendarRepo, ClinicRepo: clinicRepo, UserRepo: userRepo, PrescriptionRepo: prescriptionRepo, ClinicalNoteRepo: clinicalNoteRepo, AttachmentRepo: attachmentRepo, TreatmentPlanRepo: treatmentPlanRepo, PetRepo: petRepo, Access: access}
 }
 
+// checkCalendar rechaza fechas en las que la clínica está cerrada o en las que
+// el veterinario asignado tiene registrada una ausencia.
+func (s *AppointmentService) checkCalendar(date string, vetID *uuid.UUID) error {
+	day, err := time.ParseInLocation(utils.AppointmentDateLayout, date, time.Local)
+	if err != nil {
+		return fmt.Errorf("fecha inválida: %s", date)
+	}
+	closed, err := s.CalendarRepo.IsClinicClosed(day)
+	if err != nil {
+		return err
+	}
+	if closed {
+		return ErrClinicClosed
+	}
+	if vetID != nil {
+		onTimeOff, err := s.CalendarRepo.IsVetOnTimeOff(vetID.String(), day)
+		if err != nil {
+			return err
+		}
+		if onTimeOff {
+			return ErrVetOnTimeOff
+		}
+	}
+	return nil
+}
+
+// CreateAppointment aplica la política de inasistencias del dueño antes de
+// guardar la cita: según su historial puede exigir depósito o aprobación del
+// personal de la clínica. Sin clínica indicada, la cita queda en la principal.
 func (s *AppointmentService) CreateAppointment(app *entities.Appointment) error {
+	app.SetDefaultClinic()
+	owner, err := s.Repo.GetOwnerByPetID(app.PetID.String())
+	if err != nil {
+		return err
+	}
+	if owner != nil {
+		app.DepositRequired = utils.RequiresDeposit(owner.NoShowCount)
+		app.ApprovalRequired = utils.RequiresApproval(owner.NoShowCount)
+	}
+	if err := s.checkCalendar(app.Date, app.VetID); err != nil {
+		return err
+	}
+	if err := s.checkSlot(app); err != nil {
+		return err
+	}
 	return s.Repo.Create(app)
 }
 
+// checkSlot verifica que la cita no choque con otras citas activas: un
+// veterinario no atiende dos citas a la vez y cada sala o equipo admite tantas
+// citas simultáneas como su capacidad. Las citas sin veterinario ni sala
+// conservan la regla original de un solo turno por horario en la clínica.
+func (s *AppointmentService) checkSlot(app *entities.Appointment) error {
+	slot := entities.SlotQuery{Date: app.Date, Time: app.Time}
+	if app.ID != uuid.Nil {
+		slot.ExcludeID = app.ID.String()
+	}
+
+	if app.VetID != nil {
+		vetID := app.VetID.String()
+		if app.ClinicID != nil {
+			assigned, err := s.ClinicRepo.IsVetInClinic(vetID, *app.ClinicID)
+			if err != nil {
+				return err
+			}
+			if !assigned {
+				return ErrVetNotInClinic
+			}
+		}
+		query := slot
+		query.VetID = &vetID
+		count, err := s.Repo.CountActiveInSlot(query)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAppointmentSlotTaken
+		}
+	}
+
+	if app.RoomID != nil {
+		room, err := s.ClinicRepo.GetRoomByID(*app.RoomID)
+		if err != nil {
+			return err
+		}
+		if room == nil || room.StatusID != 1 {
+			return ErrRoomNotFound
+		}
+		if app.ClinicID != nil && room.ClinicID != *app.ClinicID {
+			return ErrRoomNotInClinic
+		}
+		query := slot
+		query.RoomID = app.RoomID
+		count, err := s.Repo.CountActiveInSlot(query)
+		if err != nil {
+			return err
+		}
+		if count >= int64(room.Capacity) {
+			return ErrRoomFull
+		}
+	}
+
+	if app.ResourceID != nil {
+		resource, err := s.ClinicRepo.GetResourceByID(*app.ResourceID)
+		if err != nil {
+			return err
+		}
+		if resource == nil || resource.StatusID != 1 {
+			return ErrResourceNotFound
+		}
+		if app.ClinicID != nil && resource.ClinicID != *app.ClinicID {
+			return ErrResourceNotInClinic
+		}
+		query := slot
+		query.ResourceID = app.ResourceID
+		count, err := s.Repo.CountActiveInSlot(query)
+		if err != nil {
+			return err
+		}
+		if count >= int64(resource.Quantity) {
+			return ErrResourceUnavailable
+		}
+	}
+
+	if app.VetID == nil && app.RoomID == nil {
+		query := slot
+		query.ClinicID = app.ClinicID
+		count, err := s.Repo.CountActiveInSlot(query)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAppointmentSlotTaken
+		}
+	}
+	return nil
+}
+
+func uuidField(value interface{}) (*uuid.UUID, error) {
+	str, ok := value.(string)
+	if !ok || str == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(str)
+	if err != nil {
+		return nil, fmt.Errorf("UUID inválido: %s", str)
+	}
+	return &id, nil
+}
+
+func intField(value interface{}) (*int, error) {
+	if value == nil {
+		return nil, nil
+	}
+	number, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("valor numérico inválido: %v", value)
+	}
+	n := int(number)
+	return &n, nil
+}
+
 func (s *AppointmentService) GetAppointmentByID(id string) (*entities.Appointment, error) {
 	return s.Repo.GetByID(id)
 }
 
-func (s *AppointmentService) GetAllAppointments() ([]entities.Appointment, error) {
-	return s.Repo.GetAll()
+func (s *AppointmentService) SearchAppointments(filter entities.AppointmentFilter) ([]entities.Appointment, int64, error) {
+	filter.Normalize()
+	return s.Repo.Search(filter)
 }
 
 func (s *AppointmentService) UpdateAppointment(id string, fields map[string]interface{}) error {
+	if err := s.checkRecordLock(id, fields); err != nil {
+		return err
+	}
+	if err := s.checkVitalFields(id, fields); err != nil {
+		return err
+	}
+	newDate, hasDate := fields["date"].(string)
+	newTime, hasTime := fields["time"].(string)
+	_, hasVet := fields["vet_id"]
+	_, hasClinic := fields["clinic_id"]
+	_, hasRoom := fields["room_id"]
+	_, hasResource := fields["resource_id"]
+	if !hasDate && !hasTime && !hasVet && !hasClinic && !hasRoom && !hasResource {
+		return s.Repo.Update(id, fields)
+	}
+
+	current, err := s.Repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return ErrAppointmentNotFound
+	}
+
+	candidate := *current
+	if hasVet {
+		if candidate.VetID, err = uuidField(fields["vet_id"]); err != nil {
+			return err
+		}
+	}
+	if hasClinic {
+		if candidate.ClinicID, err = intField(fields["clinic_id"]); err != nil {
+			return err
+		}
+		if candidate.ClinicID == nil {
+			return ErrAppointmentClinicNeeded
+		}
+	}
+	if hasRoom {
+		if candidate.RoomID, err = intField(fields["room_id"]); err != nil {
+			return err
+		}
+	}
+	if hasResource {
+		if candidate.ResourceID, err = intField(fields["resource_id"]); err != nil {
+			return err
+		}
+	}
+	if !hasDate {
+		newDate = current.Date
+	}
+	if !hasTime {
+		newTime = current.Time
+	}
+
+	if newDate != current.Date || newTime != current.Time {
+		if _, err := s.reschedule(&candidate, newDate, newTime, "Cambio de fecha desde la edición de la cita", nil); err != nil {
+			return err
+		}
+	} else {
+		if err := s.checkCalendar(candidate.Date, candidate.VetID); err != nil {
+			return err
+		}
+		if err := s.checkSlot(&candidate); err != nil {
+			return err
+		}
+	}
+	delete(fields, "date")
+	delete(fields, "time")
 	return s.Repo.Update(id, fields)
 }
 
-func (s *AppointmentService) GetAppointmentsByStatus(statusID int) ([]entities.Appointment, error) {
-	return s.Repo.GetAppointmentsByStatus(statusID)
+// checkRecordLock rechaza cambios a los datos clínicos de una cita finalizada.
+func (s *AppointmentService) checkRecordLock(id string, fields map[string]interface{}) error {
+	locked := false
+	for _, key := range lockedRecordFields {
+		if _, ok := fields[key]; ok {
+			locked = true
+			break
+		}
+	}
+	if !locked {
+		return nil
+	}
+	app, err := s.Repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if app == nil {
+		return ErrAppointmentNotFound
+	}
+	if app.StatusID == entities.AppointmentStatusFinished {
+		return ErrAppointmentRecordLocked
+	}
+	return nil
 }
 
-func (s *AppointmentService) GetAppointmentsByStatusAndDate(date time.Time) ([]entities.Appointment, error) {
-	return s.Repo.GetAppointmentsByStatusAndDate(date)
+// checkVitalFields valida el peso y la temperatura que se registran en la cita
+// contra los rangos posibles de la especie de la mascota.
+func (s *AppointmentService) checkVitalFields(id string, fields map[string]interface{}) error {
+	weight, hasWeight := fields["weight_kg"].(float64)
+	temperature, hasTemperature := fields["temperature"].(float64)
+	if !hasWeight && !hasTemperature {
+		return nil
+	}
+	app, err := s.Repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if app == nil {
+		return ErrAppointmentNotFound
+	}
+	if hasWeight {
+		app.WeightKg = &weight
+	}
+	if hasTemperature {
+		app.Temperature = &temperature
+	}
+	at := app.CreatedAt
+	if t, err := time.ParseInLocation(utils.AppointmentDateLayout, app.Date, time.Local); err == nil {
+		at = t
+	}
+	return checkPlausibleVitals(&app.Pet, at, app.VitalValues())
 }
 
-func (s *AppointmentService) UpdateStatus(id string, statusID int) error {
-	return s.Repo.UpdateStatus(id, statusID)
+// Reschedule mueve una cita agendada a un nuevo horario dejando registro del
+// horario anterior. Aplica la política de la clínica: no se permite reprogramar
+// con menos de RESCHEDULE_CUTOFF_HOURS de anticipación ni más de
+// RESCHEDULE_MAX_COUNT veces (0 desactiva el límite).
+func (s *AppointmentService) Reschedule(id, newDate, newTime, reason string, requestedBy *uuid.UUID) (*entities.Appointment, error) {
+	app, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrAppointmentNotFound
+	}
+	return s.reschedule(app, newDate, newTime, reason, requestedBy)
 }
 
-func (s *AppointmentService) GetByUserID(userID string) ([]entities.Appointment, error) {
-	return s.Repo.GetByUserID(userID)
+func (s *AppointmentService) reschedule(app *entities.Appointment, newDate, newTime, reason string, requestedBy *uuid.UUID) (*entities.Appointment, error) {
+	if app.StatusID != entities.AppointmentStatusScheduled {
+		return nil, ErrAppointmentNotScheduled
+	}
+	if app.Date == newDate && app.Time == newTime {
+		return nil, ErrAppointmentSameSlot
+	}
+
+	newSlot, err := utils.ParseAppointmentDateTime(newDate, newTime)
+	if err != nil {
+		return nil, err
+	}
+	if newSlot.Before(time.Now()) {
+		return nil, ErrRescheduleDateTimeInPast
+	}
+
+	cutoff := time.Duration(utils.GetEnvInt("RESCHEDULE_CUTOFF_HOURS", 24)) * time.Hour
+	if currentSlot, err := utils.ParseAppointmentDateTime(app.Date, app.Time); err == nil {
+		if time.Until(currentSlot) < cutoff {
+			return nil, ErrRescheduleCutoff
+		}
+	}
+	if maxCount := utils.GetEnvInt("RESCHEDULE_MAX_COUNT", 3); maxCount > 0 && app.RescheduleCount >= maxCount {
+		return nil, ErrRescheduleLimitReached
+	}
+
+	if err := s.checkCalendar(newDate, app.VetID); err != nil {
+		return nil, err
+	}
+	target := *app
+	target.Date = newDate
+	target.Time = newTime
+	if err := s.checkSlot(&target); err != nil {
+		return nil, err
+	}
+
+	history := &entities.AppointmentReschedule{
+		AppointmentID: app.ID,
+		PreviousDate:  app.Date,
+		PreviousTime:  app.Time,
+		NewDate:       newDate,
+		NewTime:       newTime,
+		Reason:        reason,
+		RequestedByID: requestedBy,
+	}
+	if err := s.Repo.Reschedule(app, history); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.Repo.GetByID(app.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	notifyReschedule(updated, history)
+	return updated, nil
+}
+
+func (s *AppointmentService) GetReschedules(appointmentID string) ([]entities.AppointmentReschedule, error) {
+	return s.Repo.GetReschedules(appointmentID)
+}
+
+func notifyReschedule(app *entities.Appointment, history *entities.AppointmentReschedule) {
+	if app == nil {
+		return
+	}
+	recipients := map[string]string{}
+	if app.Pet.Owner.Email != "" {
+		recipients[app.Pet.Owner.Email] = app.Pet.Owner.FullName
+	}
+	if app.VetID != nil && app.Vet.Email != "" {
+		recipients[app.Vet.Email] = app.Vet.FullName
+	}
+	for email, name := range recipients {
+		body := fmt.Sprintf(
+			"Hola %s,\n\nLa cita de %s programada para el %s a las %s fue reprogramada para el %s a las %s.\n\nSaludos.",
+			name, app.Pet.Name, history.PreviousDate, history.PreviousTime, history.NewDate, history.NewTime,
+		)
+		go func(to, body string) {
+			if err := utils.SendMail(to, "Cita reprogramada en PetVet", body); err != nil {
+				fmt.Println("Error enviando correo de reprogramación:", err)
+			}
+		}(email, body)
+	}
 }
 
-func (s *AppointmentService) GetMedicalHistoryByPetID(petID string) ([]entities.Appointment, error) {
-	return s.Repo.GetMedicalHistoryByPetID(petID)
+// ProposeReassignments arma, para cada cita agendada del veterinario entre
+// from y to, una propuesta: otro veterinario libre en el mismo horario o, si no
+// hay ninguno, el siguiente turno disponible después del rango dentro de
+// REASSIGNMENT_SEARCH_DAYS días. Las citas que ya pasaron no se incluyen.
+func (s *AppointmentService) ProposeReassignments(vetID string, from, to time.Time) ([]entities.ReassignmentProposal, error) {
+	if to.Before(from) {
+		return nil, ErrInvalidDateRange
+	}
+	vet, err := s.UserRepo.GetByID(vetID)
+	if err != nil {
+		return nil, err
+	}
+	if vet == nil || vet.RoleID != 2 {
+		return nil, ErrVetNotFound
+	}
+	apps, err := s.Repo.GetActiveByDateRange(vetID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	vets, err := s.UserRepo.GetByRole(2, nil)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []entities.User
+	for _, v := range vets {
+		if v.ID != vet.ID {
+			candidates = append(candidates, v)
+		}
+	}
+	// Después del rango el veterinario vuelve a estar disponible, salvo que
+	// haya sido desactivado.
+	laterCandidates := candidates
+	if vet.StatusID == 1 {
+		laterCandidates = append([]entities.User{*vet}, candidates...)
+	}
+
+	booked := map[string]bool{}
+	load := map[uuid.UUID]int{}
+	now := time.Now()
+	proposals := []entities.ReassignmentProposal{}
+	for _, app := range apps {
+		if app.StatusID != entities.AppointmentStatusScheduled {
+			continue
+		}
+		if start, err := utils.ParseAppointmentDateTime(app.Date, app.Time); err != nil || start.Before(now) {
+			continue
+		}
+
+		proposal := entities.ReassignmentProposal{Appointment: app, Action: entities.ReassignmentActionUnresolved}
+		if v := s.findFreeVet(app, app.Date, app.Time, candidates, booked, load); v != nil {
+			proposal.Action = entities.ReassignmentActionReassign
+			proposal.NewVetID = &v.ID
+			proposal.NewVetName = v.FullName
+			proposal.NewDate = app.Date
+			proposal.NewTime = app.Time
+		} else if date, clock, v := s.findNextSlot(app, to, laterCandidates, booked, load); v != nil {
+			proposal.Action = entities.ReassignmentActionReschedule
+			proposal.NewVetID = &v.ID
+			proposal.NewVetName = v.FullName
+			proposal.NewDate = date
+			proposal.NewTime = clock
+		}
+		proposals = append(proposals, proposal)
+	}
+	return proposals, nil
+}
+
+// ApplyReassignments vuelve a calcular las propuestas y las aplica en una
+// sola transacción, solo si coinciden con las de la vista previa (mismo
+// proposalHash). Cada dueño afectado recibe un único correo con todos sus
+// cambios.
+func (s *AppointmentService) ApplyReassignments(vetID string, from, to time.Time, reason, proposalHash string) ([]entities.ReassignmentProposal, error) {
+	proposals, err := s.ProposeReassignments(vetID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if entities.ReassignmentHash(proposals) != proposalHash {
+		return nil, ErrReassignmentChanged
+	}
+	for i := range proposals {
+		p := &proposals[i]
+		if p.Action != entities.ReassignmentActionReschedule {
+			continue
+		}
+		p.History = &entities.AppointmentReschedule{
+			AppointmentID: p.Appointment.ID,
+			PreviousDate:  p.Appointment.Date,
+			PreviousTime:  p.Appointment.Time,
+			NewDate:       p.NewDate,
+			NewTime:       p.NewTime,
+			Reason:        reason,
+		}
+	}
+	if err := s.Repo.ApplyReassignments(proposals); err != nil {
+		return nil, err
+	}
+	notifyReassignments(proposals)
+	return proposals, nil
+}
+
+// findFreeVet devuelve el veterinario con menos citas asignadas en esta
+// reasignación que puede atender la cita en la fecha y hora indicadas.
+func (s *AppointmentService) findFreeVet(app entities.Appointment, date, clock string, candidates []entities.User, booked map[string]bool, load map[uuid.UUID]int) *entities.User {
+	ordered := append([]entities.User(nil), candidates...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return load[ordered[i].ID] < load[ordered[j].ID]
+	})
+	for i := range ordered {
+		v := &ordered[i]
+		key := v.ID.String() + "|" + date + "|" + clock
+		if booked[key] {
+			continue
+		}
+		target := app
+		target.VetID = &v.ID
+		target.Date = date
+		target.Time = clock
+		if s.checkCalendar(date, target.VetID) != nil {
+			continue
+		}
+		if s.checkSlot(&target) != nil {
+			continue
+		}
+		booked[key] = true
+		load[v.ID]++
+		return v
+	}
+	return nil
+}
+
+func (s *AppointmentService) findNextSlot(app entities.Appointment, after time.Time, candidates []entities.User, booked map[string]bool, load map[uuid.UUID]int) (string, string, *entities.User) {
+	now := time.Now()
+	days := utils.GetEnvInt("REASSIGNMENT_SEARCH_DAYS", 14)
+	for d := 1; d <= days; d++ {
+		day := after.AddDate(0, 0, d)
+		date := day.Format(utils.AppointmentDateLayout)
+		if s.checkCalendar(date, nil) != nil {
+			continue
+		}
+		slots, err := clinicSlots(day)
+		if err != nil {
+			return "", "", nil
+		}
+		for _, slot := range slots {
+			start, err := utils.ParseAppointmentDateTime(date, slot.Time)
+			if err != nil || start.Before(now) {
+				continue
+			}
+			if v := s.findFreeVet(app, date, slot.Time, candidates, booked, load); v != nil {
+				return date, slot.Time, v
+			}
+		}
+	}
+	return "", "", nil
+}
+
+// SuggestSlot busca, desde el día from y durante los días indicados, el
+// primer turno en que la cita puede agendarse sin chocar con el calendario ni
+// con otras citas. booked evita sugerir dos veces el mismo turno en una
+// misma tanda.
+func (s *AppointmentService) SuggestSlot(app entities.Appointment, from time.Time, days int, booked map[string]bool) (string, string, bool) {
+	now := time.Now()
+	for d := 0; d < days; d++ {
+		day := from.AddDate(0, 0, d)
+		date := day.Format(utils.AppointmentDateLayout)
+		if s.checkCalendar(date, app.VetID) != nil {
+			continue
+		}
+		slots, err := clinicSlots(day)
+		if err != nil {
+			return "", "", false
+		}
+		for _, slot := range slots {
+			start, err := utils.ParseAppointmentDateTime(date, slot.Time)
+			if err != nil || start.Before(now) || booked[date+"|"+slot.Time] {
+				continue
+			}
+			target := app
+			target.Date = date
+			target.Time = slot.Time
+			if s.checkSlot(&target) == nil {
+				booked[date+"|"+slot.Time] = true
+				return date, slot.Time, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+func notifyReassignments(proposals []entities.ReassignmentProposal) {
+	type ownerChanges struct {
+		name  string
+		lines []string
+	}
+	byOwner := map[string]*ownerChanges{}
+	var order []string
+	for _, p := range proposals {
+		owner := p.Appointment.Pet.Owner
+		if owner.Email == "" {
+			continue
+		}
+		var line string
+		switch p.Action {
+		case entities.ReassignmentActionReassign:
+			line = fmt.Sprintf("- La cita de %s del %s a las %s será atendida por %s.",
+				p.Appointment.Pet.Name, p.Appointment.Date, p.Appointment.Time, p.NewVetName)
+		case entities.ReassignmentActionReschedule:
+			line = fmt.Sprintf("- La cita de %s del %s a las %s fue reprogramada para el %s a las %s con %s.",
+				p.Appointment.Pet.Name, p.Appointment.Date, p.Appointment.Time, p.NewDate, p.NewTime, p.NewVetName)
+		default:
+			line = fmt.Sprintf("- La cita de %s del %s a las %s debe reprogramarse; la clínica se pondrá en contacto con usted.",
+				p.Appointment.Pet.Name, p.Appointment.Date, p.Appointment.Time)
+		}
+		changes, ok := byOwner[owner.Email]
+		if !ok {
+			changes = &ownerChanges{name: owner.FullName}
+			byOwner[owner.Email] = changes
+			order = append(order, owner.Email)
+		}
+		changes.lines = append(changes.lines, line)
+	}
+	for _, email := range order {
+		changes := byOwner[email]
+		body := fmt.Sprintf(
+			"Hola %s,\n\nSu veterinario no estará disponible, por lo que hicimos los siguientes cambios:\n\n%s\n\nSaludos.",
+			changes.name, strings.Join(changes.lines, "\n"),
+		)
+		go func(to, body string) {
+			if err := utils.SendMail(to, "Cambios en sus citas en PetVet", body); err != nil {
+				fmt.Println("Error enviando correo de reasignación:", err)
+			}
+		}(email, body)
+	}
+}
+
+func (s *AppointmentService) GetAppointmentsByStatus(statusID int, clinicID *int) ([]entities.Appointment, error) {
+	return s.Repo.GetAppointmentsByStatus(statusID, clinicID)
+}
+
+func (s *AppointmentService) GetAppointmentsByStatusAndDate(date time.Time, clinicID *int) ([]entities.Appointment, error) {
+	return s.Repo.GetAppointmentsByStatusAndDate(date, clinicID)
+}
+
+// UpdateStatus cambia el estado de la cita. Finalizarla, registrar la
+// llegada, la consulta o la inasistencia es tarea del personal; agendarla de
+// nuevo o cancelarla también la puede hacer el dueño de la mascota.
+func (s *AppointmentService) UpdateStatus(requesterID, id string, statusID int) error {
+	app, err := s.Repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if app == nil {
+		return ErrAppointmentNotFound
+	}
+	switch statusID {
+	case entities.AppointmentStatusScheduled, entities.AppointmentStatusCancelled:
+		err = s.Access.CheckPet(requesterID, &app.Pet)
+	default:
+		err = s.Access.CheckStaff(requesterID)
+	}
+	if err != nil {
+		return err
+	}
+	if statusID == entities.AppointmentStatusFinished &&
+		(app.StatusID == entities.AppointmentStatusCancelled || app.StatusID == entities.AppointmentStatusNoShow) {
+		return ErrInvalidStatusTransition
+	}
+
+	if app.StatusID == entities.AppointmentStatusFinished && statusID != entities.AppointmentStatusFinished {
+		return ErrAppointmentRecordLocked
+	}
+
+	if app.StatusID == entities.AppointmentStatusScheduled && statusID != app.StatusID &&
+		statusID != entities.AppointmentStatusCancelled && statusID != entities.AppointmentStatusNoShow {
+		if err := checkBookingPolicy(app); err != nil {
+			return err
+		}
+	}
+
+	fields := map[string]interface{}{"status_id": statusID}
+	now := time.Now()
+	switch statusID {
+	case entities.AppointmentStatusCheckedIn:
+		if app.StatusID != entities.AppointmentStatusScheduled {
+			return ErrInvalidStatusTransition
+		}
+		if app.Date != now.Format(utils.AppointmentDateLayout) {
+			return ErrCheckInNotToday
+		}
+		fields["checked_in_at"] = now
+	case entities.AppointmentStatusInProcess:
+		if app.StatusID != entities.AppointmentStatusCheckedIn {
+			return ErrInvalidStatusTransition
+		}
+		fields["started_at"] = now
+	case entities.AppointmentStatusFinished:
+		if app.StatusID == entities.AppointmentStatusCheckedIn || app.StatusID == entities.AppointmentStatusInProcess {
+			fields["finished_at"] = now
+		}
+	}
+	noShowDelta := 0
+	switch {
+	case statusID == entities.AppointmentStatusNoShow && app.StatusID != entities.AppointmentStatusNoShow:
+		noShowDelta = 1
+	case statusID != entities.AppointmentStatusNoShow && app.StatusID == entities.AppointmentStatusNoShow:
+		noShowDelta = -1
+	}
+	updated, err := s.Repo.ChangeStatus(id, app.StatusID, fields, noShowDelta)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrAppointmentStatusChanged
+	}
+	if statusID == entities.AppointmentStatusFinished {
+		if err := s.ClinicalNoteRepo.FinalizeByAppointmentID(id, now); err != nil {
+			return err
+		}
+	}
+	return syncTreatmentStep(s.TreatmentPlanRepo, id, statusID, now)
+}
+
+// CreateWalkIn registra a una mascota que llegó sin cita previa; queda en la
+// sala de espera desde el momento de su registro.
+func (s *AppointmentService) CreateWalkIn(app *entities.Appointment) error {
+	now := time.Now()
+	app.Date = now.Format(utils.AppointmentDateLayout)
+	app.Time = now.Format(utils.AppointmentTimeLayout)
+	app.StatusID = entities.AppointmentStatusCheckedIn
+	app.IsWalkIn = true
+	app.CheckedInAt = &now
+	app.SetDefaultClinic()
+	if err := s.checkCalendar(app.Date, app.VetID); err != nil {
+		return err
+	}
+	return s.Repo.Create(app)
+}
+
+// GetTodayQueue arma la cola del día por veterinario: primero quien está en
+// consulta, luego quienes esperan por orden de llegada y al final las citas
+// agendadas que aún no llegan.
+func (s *AppointmentService) GetTodayQueue(now time.Time, clinicID *int) ([]entities.VetQueue, error) {
+	apps, err := s.Repo.GetQueueByDate(now, clinicID)
+	if err != nil {
+		return nil, err
+	}
+
+	rank := map[int]int{
+		entities.AppointmentStatusInProcess: 0,
+		entities.AppointmentStatusCheckedIn: 1,
+		entities.AppointmentStatusScheduled: 2,
+	}
+	sort.SliceStable(apps, func(i, j int) bool {
+		a, b := apps[i], apps[j]
+		if rank[a.StatusID] != rank[b.StatusID] {
+			return rank[a.StatusID] < rank[b.StatusID]
+		}
+		if a.CheckedInAt != nil && b.CheckedInAt != nil && !a.CheckedInAt.Equal(*b.CheckedInAt) {
+			return a.CheckedInAt.Before(*b.CheckedInAt)
+		}
+		return a.Time < b.Time
+	})
+
+	var queues []entities.VetQueue
+	index := map[string]int{}
+	for _, app := range apps {
+		key := ""
+		if app.VetID != nil {
+			key = app.VetID.String()
+		}
+		i, ok := index[key]
+		if !ok {
+			queue := entities.VetQueue{VetID: app.VetID, VetName: app.Vet.FullName}
+			if app.VetID == nil {
+				queue.VetName = "Sin asignar"
+			}
+			queues = append(queues, queue)
+			i = len(queues) - 1
+			index[key] = i
+		}
+
+		entry := entities.QueueEntry{
+			Position:    len(queues[i].Entries) + 1,
+			Appointment: app,
+		}
+		switch {
+		case app.CheckedInAt != nil && app.StartedAt != nil:
+			entry.WaitMinutes = int(app.StartedAt.Sub(*app.CheckedInAt).Minutes())
+		case app.CheckedInAt != nil:
+			entry.WaitMinutes = int(now.Sub(*app.CheckedInAt).Minutes())
+		}
+		queues[i].Entries = append(queues[i].Entries, entry)
+	}
+	return queues, nil
+}
+
+// checkBookingPolicy impide atender una cita agendada mientras falte la
+// aprobación o el depósito que exigió la política de inasistencias.
+func checkBookingPolicy(app *entities.Appointment) error {
+	if app.ApprovalRequired && app.ApprovedAt == nil {
+		return ErrApprovalPending
+	}
+	if app.DepositRequired && app.DepositPaidAt == nil {
+		return ErrDepositPending
+	}
+	return nil
+}
+
+// Approve deja registrada la aprobación de una cita que la requiere. Solo el
+// personal de la clínica puede aprobarla.
+func (s *AppointmentService) Approve(requesterID, id string) (*entities.Appointment, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	app, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrAppointmentNotFound
+	}
+	if !app.ApprovalRequired {
+		return nil, ErrApprovalNotRequired
+	}
+	if err := s.Repo.Approve(id, parseRequesterID(requesterID)); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByID(id)
+}
+
+// RecordDeposit registra que el dueño pagó el depósito que exige la cita.
+// Solo el personal de la clínica puede registrarlo.
+func (s *AppointmentService) RecordDeposit(requesterID, id string) (*entities.Appointment, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	app, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrAppointmentNotFound
+	}
+	if !app.DepositRequired {
+		return nil, ErrDepositNotRequired
+	}
+	if err := s.Repo.RecordDeposit(id, parseRequesterID(requesterID)); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByID(id)
+}
+
+func parseRequesterID(requesterID string) *uuid.UUID {
+	parsed, err := uuid.Parse(requesterID)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
+// MarkNoShows marca como inasistencia las citas que siguen agendadas después
+// de su hora de finalización e incrementa el contador de cada dueño.
+func (s *AppointmentService) MarkNoShows(now time.Time) (int, error) {
+	apps, err := s.Repo.GetScheduledUntil(now)
+	if err != nil {
+		return 0, err
+	}
+	duration := utils.AppointmentDuration()
+	var ids []string
+	for _, app := range apps {
+		start, err := utils.ParseAppointmentDateTime(app.Date, app.Time)
+		if err != nil {
+			continue
+		}
+		if start.Add(duration).Before(now) {
+			ids = append(ids, app.ID.String())
+		}
+	}
+	marked, err := s.Repo.MarkNoShow(ids)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range marked {
+		if err := syncTreatmentStep(s.TreatmentPlanRepo, id, entities.AppointmentStatusNoShow, now); err != nil {
+			return 0, err
+		}
+	}
+	return len(marked), nil
+}
+
+func (s *AppointmentService) StartNoShowJob(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if count, err := s.MarkNoShows(time.Now()); err != nil {
+			log.Println("Error marcando inasistencias:", err)
+		} else if count > 0 {
+			log.Printf("Se marcaron %d citas como inasistencia\n", count)
+		}
+		<-ticker.C
+	}
+}
+
+func (s *AppointmentService) GetByUserID(userID string, clinicID *int) ([]entities.Appointment, error) {
+	return s.Repo.GetByUserID(userID, clinicID)
+}
+
+// GetMedicalHistoryByPetID devuelve las citas finalizadas de la mascota con
+// sus recetas y los medicamentos que sigue tomando. Con diagnosisID solo se
+// incluyen las citas que tienen ese diagnóstico. Solo lo ven el dueño y el
+// personal de la clínica.
+func (s *AppointmentService) GetMedicalHistoryByPetID(requesterID, petID string, diagnosisID int) (*entities.MedicalHistory, error) {
+	pet, err := s.PetRepo.GetByID(petID)
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	if err := s.Access.CheckPet(requesterID, pet); err != nil {
+		return nil, err
+	}
+	apps, err := s.Repo.GetMedicalHistoryByPetID(petID)
+	if err != nil {
+		return nil, err
+	}
+	if diagnosisID > 0 {
+		filtered := []entities.Appointment{}
+		for _, app := range apps {
+			if app.HasDiagnosis(diagnosisID) {
+				filtered = append(filtered, app)
+			}
+		}
+		apps = filtered
+	}
+	prescriptions, err := s.PrescriptionRepo.GetActiveByPetID(petID)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := s.AttachmentRepo.GetByPetID(petID, true)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.MedicalHistory{
+		Appointments:      apps,
+		ActiveMedications: activeMedications(prescriptions, time.Now()),
+		Attachments:       attachments,
+	}, nil
 }
 
 func (s *AppointmentService) DeleteAppointment(id string) (string, error) {
+	app, err := s.Repo.GetByID(id)
+	if err != nil {
+		return "", err
+	}
+	if app == nil {
+		return "", ErrAppointmentNotFound
+	}
+	if app.StatusID == entities.AppointmentStatusFinished {
+		return "", ErrAppointmentRecordLocked
+	}
+	if app.StatusID != entities.AppointmentStatusScheduled {
+		return "", ErrAppointmentNotCancelable
+	}
 	newStatus, err := s.Repo.Delete(id)
 	if err != nil {
 		return "", err
 	}
-	if newStatus == 1 {
-		return "Cita reactivada correctamente", nil
+	if newStatus != entities.AppointmentStatusCancelled {
+		return "", ErrAppointmentNotCancelable
+	}
+	if err := syncTreatmentStep(s.TreatmentPlanRepo, id, newStatus, time.Now()); err != nil {
+		return "", err
 	}
 	return "Cita cancelada correctamente", nil
 }
 
-func (s *AppointmentService) CountAppointmentsByStatus(statusID int) (int, error) {
-	return s.Repo.CountAppointmentsByStatus(statusID)
-}
-
-func (s *AppointmentService) CountVets() (int, error) {
-	return s.Repo.CountVets()
+func (s *AppointmentService) CountAppointmentsByStatus(statusID int, clinicID *int) (int, error) {
+	return s.Repo.CountAppointmentsByStatus(statusID, clinicID)
 }
 
-func (s *AppointmentService) GetVetsWithMostAppointments(limit int) ([]entities.VetAppointments, error) {
-	return s.Repo.GetVetsWithMostAppointments(limit)
+func (s *AppointmentService) CountVets(clinicID *int) (int, error) {
+	return s.Repo.CountVets(clinicID)
 }
 
-func (s *AppointmentService) CountAttendedByMonthLast6Months() ([]entities.MonthlyAppointments, error) {
-	return s.Repo.CountAttendedByMonthLast6Months()
+func (s *AppointmentService) GetVetsWithMostAppointments(limit int, clinicID *int) ([]entities.VetAppointments, error) {
+	return s.Repo.GetVetsWithMostAppointments(limit, clinicID)
 }
 
-func (s *AppointmentService) ExistsAppointmentForPet(date, time string) (bool, error) {
-	return s.Repo.ExistsAppointmentForPet(date, time)
+func (s *AppointmentService) CountAttendedByMonthLast6Months(clinicID *int) ([]entities.MonthlyAppointments, error) {
+	return s.Repo.CountAttendedByMonthLast6Months(clinicID)
 }
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAttachmentNotFound       = errors.New("archivo adjunto no encontrado")
+	ErrAttachmentTypeNotAllowed = errors.New("tipo de archivo no permitido; se aceptan JPEG, PNG, WEBP y PDF")
+)
+
+// AttachmentContentTypes son los tipos aceptados, detectados a partir del
+// contenido del archivo y no del nombre, con la extensión que se usa al
+// guardarlo.
+var AttachmentContentTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+type AttachmentService struct {
+	Repo            repositories.AttachmentRepository
+	Storage         repositories.FileStorage
+	PetRepo         repositories.PetRepository
+	AppointmentRepo repositories.AppointmentRepository
+	Access          *PetAccess
+}
+
+func NewAttachmentService(repo repositories.AttachmentRepository, storage repositories.FileStorage, petRepo repositories.PetRepository, appointmentRepo repositories.AppointmentRepository, access *PetAccess) *AttachmentService {
+	return &AttachmentService{Repo: repo, Storage: storage, PetRepo: petRepo, AppointmentRepo: appointmentRepo, Access: access}
+}
+
+func (s *AttachmentService) getPet(petID string) (*entities.Pet, error) {
+	pet, err := s.PetRepo.GetByID(petID)
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	return pet, nil
+}
+
+// Upload guarda el archivo y sus datos. Solo el personal de la clínica puede
+// adjuntar archivos; contentType debe venir detectado del contenido.
+func (s *AttachmentService) Upload(requesterID string, a *entities.Attachment, content io.Reader) (*entities.Attachment, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	pet, err := s.getPet(a.PetID.String())
+	if err != nil {
+		return nil, err
+	}
+	if a.AppointmentID != nil {
+		app, err := s.AppointmentRepo.GetByID(a.AppointmentID.String())
+		if err != nil {
+			return nil, err
+		}
+		if app == nil {
+			return nil, ErrAppointmentNotFound
+		}
+		if app.PetID != pet.ID {
+			return nil, ErrAppointmentPetMismatch
+		}
+	}
+	ext, ok := AttachmentContentTypes[a.ContentType]
+	if !ok {
+		return nil, ErrAttachmentTypeNotAllowed
+	}
+
+	a.ID = uuid.New()
+	a.FileName = filepath.Base(strings.ReplaceAll(a.FileName, "\\", "/"))
+	a.StorageKey = "pets/" + pet.ID.String() + "/" + a.ID.String() + ext
+	size, err := s.Storage.Save(a.StorageKey, content)
+	if err != nil {
+		return nil, err
+	}
+	a.SizeBytes = size
+	if err := s.Repo.Create(a); err != nil {
+		s.Storage.Delete(a.StorageKey)
+		return nil, err
+	}
+	return s.Repo.GetByID(a.ID.String())
+}
+
+func (s *AttachmentService) GetByPetID(requesterID, petID string) ([]entities.Attachment, error) {
+	pet, err := s.getPet(petID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Access.CheckPet(requesterID, pet); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByPetID(petID, true)
+}
+
+func (s *AttachmentService) GetByAppointmentID(requesterID, appointmentID string) ([]entities.Attachment, error) {
+	app, err := s.AppointmentRepo.GetByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrAppointmentNotFound
+	}
+	if err := s.Access.CheckPet(requesterID, &app.Pet); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByAppointmentID(appointmentID)
+}
+
+// Open devuelve los datos del adjunto y su contenido si el solicitante puede
+// ver la mascota. Quien llama debe cerrar el contenido.
+func (s *AttachmentService) Open(requesterID, id string) (*entities.Attachment, io.ReadCloser, error) {
+	a, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if a == nil || a.StatusID != 1 {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	pet, err := s.getPet(a.PetID.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.Access.CheckPet(requesterID, pet); err != nil {
+		return nil, nil, err
+	}
+	content, err := s.Storage.Open(a.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, content, nil
+}
+
+func (s *AttachmentService) Delete(requesterID, id string) (string, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return "", err
+	}
+	a, err := s.Repo.GetByID(id)
+	if err != nil {
+		return "", err
+	}
+	if a == nil {
+		return "", ErrAttachmentNotFound
+	}
+	newStatus, err := s.Repo.Delete(id)
+	if err != nil {
+		return "", err
+	}
+	if newStatus == 1 {
+		return "Adjunto restaurado correctamente", nil
+	}
+	return "Adjunto eliminado correctamente", nil
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/utils"
+	"errors"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrBreedNotFound        = errors.New("raza no encontrada")
+	ErrBreedInactive        = errors.New("la raza está desactivada")
+	ErrBreedSpeciesMismatch = errors.New("la raza no corresponde a la especie de la mascota")
+	ErrMixedBreedExists     = errors.New("la especie ya tiene una raza de mestizos u otras razas")
+)
+
+func (s *SpeciesService) GetBreeds(speciesID int, onlyActive bool) ([]entities.Breed, error) {
+	species, err := s.Repo.GetByID(speciesID)
+	if err != nil {
+		return nil, err
+	}
+	if species == nil {
+		return nil, ErrSpeciesNotFound
+	}
+	return s.Repo.GetBreeds(speciesID, onlyActive)
+}
+
+func (s *SpeciesService) CreateBreed(b *entities.Breed) (*entities.Breed, error) {
+	breeds, err := s.GetBreeds(b.SpeciesID, false)
+	if err != nil {
+		return nil, err
+	}
+	if b.IsMixed {
+		for _, existing := range breeds {
+			if existing.IsMixed {
+				return nil, ErrMixedBreedExists
+			}
+		}
+	}
+	if err := s.Repo.CreateBreed(b); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetBreedByID(b.ID)
+}
+
+func (s *SpeciesService) UpdateBreed(id int, fields map[string]interface{}) (*entities.Breed, error) {
+	current, err := s.Repo.GetBreedByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, ErrBreedNotFound
+	}
+	if mixed, ok := fields["is_mixed"].(bool); ok && mixed && !current.IsMixed {
+		breeds, err := s.Repo.GetBreeds(current.SpeciesID, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, existing := range breeds {
+			if existing.IsMixed {
+				return nil, ErrMixedBreedExists
+			}
+		}
+	}
+	if err := s.Repo.UpdateBreed(id, fields); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetBreedByID(id)
+}
+
+func (s *SpeciesService) DeleteBreed(id int) (string, error) {
+	newStatus, err := s.Repo.DeleteBreed(id)
+	if err != nil {
+		return "", err
+	}
+	if newStatus == 1 {
+		return "Raza activada correctamente", nil
+	}
+	return "Raza desactivada correctamente", nil
+}
+
+// MigrateLegacyBreeds asocia el texto libre Pet.Breed con el catálogo de
+// razas de la especie de cada mascota. El texto se conserva como detalle.
+// Puede ejecutarse varias veces: solo revisa mascotas sin raza del catálogo.
+// Solo se guardan las coincidencias exactas; las aproximadas se devuelven
+// como sugerencias para confirmarlas en cada mascota, y los textos sin
+// coincidencia, para corregirlos o agregar alias. Con dryRun no se guarda
+// nada.
+func (s *SpeciesService) MigrateLegacyBreeds(dryRun bool) (*entities.LegacyBreedResult, error) {
+	pets, err := s.PetRepo.GetLegacyBreedPets()
+	if err != nil {
+		return nil, err
+	}
+	catalog := map[int][]entities.Breed{}
+	result := &entities.LegacyBreedResult{Scanned: len(pets)}
+	for _, pet := range pets {
+		breeds, ok := catalog[pet.SpeciesID]
+		if !ok {
+			if breeds, err = s.Repo.GetBreeds(pet.SpeciesID, true); err != nil {
+				return nil, err
+			}
+			catalog[pet.SpeciesID] = breeds
+		}
+		breed, exact := matchBreed(*pet.Breed, breeds)
+		if breed == nil {
+			result.Unmatched = append(result.Unmatched, pet)
+			continue
+		}
+		match := entities.LegacyBreedMatch{Pet: pet, Breed: *breed}
+		if !exact {
+			result.Suggested = append(result.Suggested, match)
+			continue
+		}
+		if !dryRun {
+			if err := s.PetRepo.Update(pet.ID.String(), map[string]interface{}{"breed_id": breed.ID}); err != nil {
+				return nil, err
+			}
+		}
+		result.Matched = append(result.Matched, match)
+	}
+	return result, nil
+}
+
+// matchBreed busca la raza que corresponde a un texto escrito a mano. Es
+// exacta si el texto es el nombre o un alias de la raza. Si no, acepta que
+// el texto contenga un nombre o alias ("labrador retriever negro"), dando
+// prioridad a la raza mestiza ("cruce de poodle"), y por último tolera
+// errores de escritura pequeños en relación con el largo del nombre. Un
+// empate entre razas distintas no se resuelve.
+func matchBreed(text string, breeds []entities.Breed) (*entities.Breed, bool) {
+	input := breedWords(text)
+	if input == "" {
+		return nil, false
+	}
+	padded := " " + input + " "
+	var contained, mixed, typo *entities.Breed
+	longest, bestDistance, tie := 0, -1, false
+	for i := range breeds {
+		b := &breeds[i]
+		for _, term := range breedTerms(b) {
+			if term == input {
+				return b, true
+			}
+			if strings.Contains(padded, " "+term+" ") {
+				if b.IsMixed {
+					mixed = b
+				} else if len(term) > longest {
+					contained, longest = b, len(term)
+				}
+				continue
+			}
+			length := len([]rune(term))
+			if length < 4 {
+				continue
+			}
+			distance := utils.Levenshtein(input, term)
+			if distance > length/4 {
+				continue
+			}
+			switch {
+			case bestDistance < 0 || distance < bestDistance:
+				typo, bestDistance, tie = b, distance, false
+			case distance == bestDistance && typo.ID != b.ID:
+				tie = true
+			}
+		}
+	}
+	switch {
+	case mixed != nil:
+		return mixed, false
+	case contained != nil:
+		return contained, false
+	case typo != nil && !tie:
+		return typo, false
+	}
+	return nil, false
+}
+
+// breedTerms devuelve el nombre y los alias de la raza normalizados.
+func breedTerms(b *entities.Breed) []string {
+	terms := []string{breedWords(b.Name)}
+	for _, alias := range strings.Split(b.Aliases, ",") {
+		if alias = breedWords(alias); alias != "" {
+			terms = append(terms, alias)
+		}
+	}
+	return terms
+}
+
+// breedWords normaliza el texto y deja solo letras y números separados por
+// un espacio, para que "Shih-Tzu" y "shih tzu" se comparen igual.
+func breedWords(s string) string {
+	words := strings.FieldsFunc(utils.NormalizeText(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"testing"
+)
+
+func TestMatchBreed(t *testing.T) {
+	breeds := []entities.Breed{
+		{ID: 1, Name: "Mestizo / Otra", Aliases: "mestizo,cruce,sin raza,otra", IsMixed: true},
+		{ID: 2, Name: "Labrador Retriever", Aliases: "labrador,lab"},
+		{ID: 3, Name: "Golden Retriever", Aliases: "golden"},
+		{ID: 4, Name: "Poodle", Aliases: "caniche"},
+		{ID: 5, Name: "Shih Tzu", Aliases: "shitzu"},
+		{ID: 6, Name: "Doodle"},
+	}
+	tests := []struct {
+		name      string
+		text      string
+		wantID    int
+		wantExact bool
+	}{
+		{"nombre exacto", "Labrador Retriever", 2, true},
+		{"alias exacto en mayúsculas", "LAB", 2, true},
+		{"separadores distintos", "Shih-Tzu", 5, true},
+		{"contiene el nombre", "labrador retriever negro", 2, false},
+		{"prefiere el nombre más largo", "golden retriever claro", 3, false},
+		{"la mestiza tiene prioridad", "cruce de poodle", 1, false},
+		{"error de escritura", "labradr", 2, false},
+		{"empate entre razas", "koodle", 0, false},
+		{"término corto sin tolerancia", "lav", 0, false},
+		{"sin coincidencia", "dragón", 0, false},
+		{"texto vacío", "  ", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breed, exact := matchBreed(tt.text, breeds)
+			gotID := 0
+			if breed != nil {
+				gotID = breed.ID
+			}
+			if gotID != tt.wantID || exact != tt.wantExact {
+				t.Errorf("matchBreed(%q) = (%d, %v), se esperaba (%d, %v)", tt.text, gotID, exact, tt.wantID, tt.wantExact)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"VetiCare/utils"
+	"errors"
+	"time"
+)
+
+var (
+	ErrClinicClosed       = errors.New("la clínica permanece cerrada en la fecha indicada")
+	ErrVetOnTimeOff       = errors.New("el veterinario no está disponible en la fecha indicada")
+	ErrInvalidDateRange   = errors.New("la fecha final no puede ser anterior a la fecha inicial")
+	ErrVetNotFound        = errors.New("veterinario no encontrado")
+	ErrTimeOffNotFound    = errors.New("ausencia no encontrada")
+	ErrInvalidClinicHours = errors.New("el horario de la clínica no está configurado correctamente")
+)
+
+type CalendarService struct {
+	Repo            repositories.CalendarRepository
+	AppointmentRepo repositories.AppointmentRepository
+	UserRepo        repositories.UserRepository
+	ClinicRepo      repositories.ClinicRepository
+}
+
+func NewCalendarService(repo repositories.CalendarRepository, appointmentRepo repositories.AppointmentRepository, userRepo repositories.UserRepository, clinicRepo repositories.ClinicRepository) *CalendarService {
+	return &CalendarService{Repo: repo, AppointmentRepo: appointmentRepo, UserRepo: userRepo, ClinicRepo: clinicRepo}
+}
+
+// AddTimeOff registra una ausencia y devuelve las citas agendadas del
+// veterinario en ese rango para que puedan reasignarse.
+func (s *CalendarService) AddTimeOff(timeOff *entities.VetTimeOff) ([]entities.Appointment, error) {
+	if timeOff.EndDate.Before(timeOff.StartDate) {
+		return nil, ErrInvalidDateRange
+	}
+	vet, err := s.UserRepo.GetByID(timeOff.VetID.String())
+	if err != nil {
+		return nil, err
+	}
+	if vet == nil || vet.RoleID != 2 {
+		return nil, ErrVetNotFound
+	}
+	if err := s.Repo.CreateTimeOff(timeOff); err != nil {
+		return nil, err
+	}
+	return s.AppointmentRepo.GetActiveByDateRange(timeOff.VetID.String(), timeOff.StartDate, timeOff.EndDate)
+}
+
+func (s *CalendarService) GetTimeOffByID(id string) (*entities.VetTimeOff, error) {
+	return s.Repo.GetTimeOffByID(id)
+}
+
+func (s *CalendarService) GetTimeOffByVet(vetID string) ([]entities.VetTimeOff, error) {
+	return s.Repo.GetTimeOffByVet(vetID)
+}
+
+func (s *CalendarService) GetAffectedByTimeOff(id string) ([]entities.Appointment, error) {
+	timeOff, err := s.Repo.GetTimeOffByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if timeOff == nil {
+		return nil, ErrTimeOffNotFound
+	}
+	return s.AppointmentRepo.GetActiveByDateRange(timeOff.VetID.String(), timeOff.StartDate, timeOff.EndDate)
+}
+
+func (s *CalendarService) DeleteTimeOff(id string) error {
+	return s.Repo.DeleteTimeOff(id)
+}
+
+// AddClosure registra un día de cierre de la clínica y devuelve las citas
+// agendadas ese día.
+func (s *CalendarService) AddClosure(closure *entities.ClinicClosure) ([]entities.Appointment, error) {
+	if err := s.Repo.CreateClosure(closure); err != nil {
+		return nil, err
+	}
+	return s.AppointmentRepo.GetActiveByDateRange("", closure.Date, closure.Date)
+}
+
+func (s *CalendarService) GetClosures(from, to *time.Time) ([]entities.ClinicClosure, error) {
+	return s.Repo.GetClosures(from, to)
+}
+
+func (s *CalendarService) DeleteClosure(id string) error {
+	return s.Repo.DeleteClosure(id)
+}
+
+// GetVetAvailability arma los turnos del día según el horario de la clínica
+// (CLINIC_OPEN_TIME, CLINIC_CLOSE_TIME) y la duración de cada cita. Un turno
+// no está disponible si la clínica cierra ese día, el veterinario está
+// ausente, el turno ya pasó o el veterinario ya tiene una cita en ese
+// horario. Si se indica una clínica con salas, el turno tampoco está
+// disponible cuando todas sus salas están llenas.
+func (s *CalendarService) GetVetAvailability(vetID string, date time.Time, clinicID *int) ([]entities.AvailabilitySlot, error) {
+	slots, err := clinicSlots(date)
+	if err != nil {
+		return nil, err
+	}
+
+	closed, err := s.Repo.IsClinicClosed(date)
+	if err != nil {
+		return nil, err
+	}
+	onTimeOff, err := s.Repo.IsVetOnTimeOff(vetID, date)
+	if err != nil {
+		return nil, err
+	}
+	if closed || onTimeOff {
+		return slots, nil
+	}
+
+	apps, err := s.AppointmentRepo.GetActiveByDateRange("", date, date)
+	if err != nil {
+		return nil, err
+	}
+	roomCapacity := 0
+	if clinicID != nil {
+		rooms, err := s.ClinicRepo.GetRooms(*clinicID)
+		if err != nil {
+			return nil, err
+		}
+		for _, room := range rooms {
+			if room.StatusID == 1 {
+				roomCapacity += room.Capacity
+			}
+		}
+	}
+
+	taken := map[string]bool{}
+	inRooms := map[string]int{}
+	for _, app := range apps {
+		if app.VetID != nil && app.VetID.String() == vetID {
+			taken[app.Time] = true
+		}
+		if clinicID != nil && app.ClinicID != nil && *app.ClinicID == *clinicID && app.RoomID != nil {
+			inRooms[app.Time]++
+		}
+	}
+	if roomCapacity > 0 {
+		for slot, count := range inRooms {
+			if count >= roomCapacity {
+				taken[slot] = true
+			}
+		}
+	}
+
+	now := time.Now()
+	for i := range slots {
+		start, err := utils.ParseAppointmentDateTime(date.Format(utils.AppointmentDateLayout), slots[i].Time)
+		if err != nil {
+			continue
+		}
+		slots[i].Available = !taken[slots[i].Time] && start.After(now)
+	}
+	return slots, nil
+}
+
+func clinicSlots(date time.Time) ([]entities.AvailabilitySlot, error) {
+	day := date.Format(utils.AppointmentDateLayout)
+	open, err := utils.ParseAppointmentDateTime(day, utils.GetEnv("CLINIC_OPEN_TIME", "08:00"))
+	if err != nil {
+		return nil, ErrInvalidClinicHours
+	}
+	closeAt, err := utils.ParseAppointmentDateTime(day, utils.GetEnv("CLINIC_CLOSE_TIME", "17:00"))
+	if err != nil || !closeAt.After(open) {
+		return nil, ErrInvalidClinicHours
+	}
+	duration := utils.AppointmentDuration()
+	if duration <= 0 {
+		return nil, ErrInvalidClinicHours
+	}
+
+	var slots []entities.AvailabilitySlot
+	for t := open; !t.Add(duration).After(closeAt); t = t.Add(duration) {
+		slots = append(slots, entities.AvailabilitySlot{Time: t.Format(utils.AppointmentTimeLayout)})
+	}
+	return slots, nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+)
+
+type ClinicService struct {
+	Repo repositories.ClinicRepository
+}
+
+func NewClinicService(repo repositories.ClinicRepository) *ClinicService {
+	return &ClinicService{Repo: repo}
+}
+
+func (s *ClinicService) GetAll() ([]entities.Clinic, error) {
+	return s.Repo.GetAll()
+}
+
+func (s *ClinicService) GetByID(id int) (*entities.Clinic, error) {
+	return s.Repo.GetByID(id)
+}
+
+func (s *ClinicService) Create(clinic *entities.Clinic) error {
+	return s.Repo.Create(clinic)
+}
+
+func (s *ClinicService) Update(id int, fields map[string]interface{}) error {
+	return s.Repo.Update(id, fields)
+}
+
+func (s *ClinicService) Delete(id int) (string, error) {
+	newStatus, err := s.Repo.Delete(id)
+	if err != nil {
+		return "", err
+	}
+	if newStatus == 1 {
+		return "Clínica activada correctamente", nil
+	}
+	return "Clínica desactivada correctamente", nil
+}
+
+func (s *ClinicService) GetRooms(clinicID int) ([]entities.Room, error) {
+	return s.Repo.GetRooms(clinicID)
+}
+
+func (s *ClinicService) GetRoomByID(id int) (*entities.Room, error) {
+	return s.Repo.GetRoomByID(id)
+}
+
+func (s *ClinicService) CreateRoom(room *entities.Room) error {
+	return s.Repo.CreateRoom(room)
+}
+
+func (s *ClinicService) UpdateRoom(id int, fields map[string]interface{}) error {
+	return s.Repo.UpdateRoom(id, fields)
+}
+
+func (s *ClinicService) GetResources(clinicID int) ([]entities.Resource, error) {
+	return s.Repo.GetResources(clinicID)
+}
+
+func (s *ClinicService) GetResourceByID(id int) (*entities.Resource, error) {
+	return s.Repo.GetResourceByID(id)
+}
+
+func (s *ClinicService) CreateResource(resource *entities.Resource) error {
+	return s.Repo.CreateResource(resource)
+}
+
+func (s *ClinicService) UpdateResource(id int, fields map[string]interface{}) error {
+	return s.Repo.UpdateResource(id, fields)
+}
+
+func (s *ClinicService) GetVets(clinicID int) ([]entities.User, error) {
+	return s.Repo.GetVets(clinicID)
+}
+
+func (s *ClinicService) SetVetClinics(vetID string, clinicIDs []int) error {
+	return s.Repo.SetVetClinics(vetID, clinicIDs)
+}
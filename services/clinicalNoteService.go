@@ -0,0 +1,224 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrClinicalNoteNotFound      = errors.New("nota clínica no encontrada")
+	ErrClinicalNoteFinalized     = errors.New("la nota clínica ya fue finalizada y no admite cambios")
+	ErrClinicalNoteNotAllowed    = errors.New("no se pueden registrar notas clínicas en citas canceladas o sin asistencia")
+	ErrClinicalNoteAuthorInvalid = errors.New("solo un veterinario activo puede redactar notas clínicas")
+	ErrNoteTemplateNotFound      = errors.New("plantilla no encontrada o inactiva")
+	ErrNoteTemplateNotOwned      = errors.New("solo el autor puede modificar la plantilla")
+)
+
+type ClinicalNoteService struct {
+	Repo            repositories.ClinicalNoteRepository
+	AppointmentRepo repositories.AppointmentRepository
+	UserRepo        repositories.UserRepository
+	Access          *PetAccess
+}
+
+func NewClinicalNoteService(repo repositories.ClinicalNoteRepository, appointmentRepo repositories.AppointmentRepository, userRepo repositories.UserRepository, access *PetAccess) *ClinicalNoteService {
+	return &ClinicalNoteService{Repo: repo, AppointmentRepo: appointmentRepo, UserRepo: userRepo, Access: access}
+}
+
+func (s *ClinicalNoteService) checkVet(vetID uuid.UUID) error {
+	vet, err := s.UserRepo.GetByID(vetID.String())
+	if err != nil {
+		return err
+	}
+	if vet == nil || vet.RoleID != 2 || vet.StatusID != 1 {
+		return ErrClinicalNoteAuthorInvalid
+	}
+	return nil
+}
+
+// GetByAppointmentID devuelve la nota de la cita al dueño de la mascota o al
+// personal de la clínica. El dueño solo ve la nota finalizada; mientras es
+// borrador se responde como si no existiera.
+func (s *ClinicalNoteService) GetByAppointmentID(requesterID, appointmentID string) (*entities.ClinicalNote, error) {
+	app, err := s.AppointmentRepo.GetByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrAppointmentNotFound
+	}
+	if err := s.Access.CheckPet(requesterID, &app.Pet); err != nil {
+		return nil, err
+	}
+	note, err := s.Repo.GetByAppointmentID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, ErrClinicalNoteNotFound
+	}
+	if note.StatusID != entities.ClinicalNoteStatusFinal {
+		staff, err := s.Access.IsStaff(requesterID)
+		if err != nil {
+			return nil, err
+		}
+		if !staff {
+			return nil, ErrClinicalNoteNotFound
+		}
+	}
+	return note, nil
+}
+
+// SaveDraft guarda el borrador de la nota de la cita; el frontend lo llama
+// periódicamente mientras el veterinario escribe. La primera vez, si se indica
+// una plantilla y las secciones vienen vacías, se parte del texto de la
+// plantilla.
+func (s *ClinicalNoteService) SaveDraft(appointmentID uuid.UUID, input entities.ClinicalNote) (*entities.ClinicalNote, error) {
+	app, err := s.AppointmentRepo.GetByID(appointmentID.String())
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrAppointmentNotFound
+	}
+	switch app.StatusID {
+	case entities.AppointmentStatusCancelled, entities.AppointmentStatusNoShow:
+		return nil, ErrClinicalNoteNotAllowed
+	case entities.AppointmentStatusFinished:
+		return nil, ErrClinicalNoteFinalized
+	}
+	if err := s.checkVet(input.VetID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.Repo.GetByAppointmentID(appointmentID.String())
+	if err != nil {
+		return nil, err
+	}
+	input.AppointmentID = appointmentID
+	input.PetID = app.PetID
+	input.StatusID = entities.ClinicalNoteStatusDraft
+	input.LastSavedAt = time.Now()
+
+	if existing == nil {
+		if err := s.applyTemplate(&input); err != nil {
+			return nil, err
+		}
+		if err := s.Repo.Create(&input); err != nil {
+			return nil, err
+		}
+		return s.Repo.GetByAppointmentID(appointmentID.String())
+	}
+	if existing.StatusID == entities.ClinicalNoteStatusFinal {
+		return nil, ErrClinicalNoteFinalized
+	}
+	input.ID = existing.ID
+	updated, err := s.Repo.UpdateDraft(&input)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrClinicalNoteFinalized
+	}
+	return s.Repo.GetByAppointmentID(appointmentID.String())
+}
+
+func (s *ClinicalNoteService) applyTemplate(note *entities.ClinicalNote) error {
+	if note.TemplateID == nil {
+		return nil
+	}
+	t, err := s.Repo.GetTemplateByID(*note.TemplateID)
+	if err != nil {
+		return err
+	}
+	if t == nil || t.StatusID != 1 || (t.VetID != nil && *t.VetID != note.VetID) {
+		return ErrNoteTemplateNotFound
+	}
+	if note.Subjective == "" && note.Objective == "" && note.Assessment == "" && note.Plan == "" {
+		note.Subjective = t.Subjective
+		note.Objective = t.Objective
+		note.Assessment = t.Assessment
+		note.Plan = t.Plan
+	}
+	return nil
+}
+
+// Finalize cierra la nota antes de finalizar la cita. Las notas que siguen en
+// borrador se cierran solas al finalizar la cita.
+func (s *ClinicalNoteService) Finalize(appointmentID string, vetID uuid.UUID) (*entities.ClinicalNote, error) {
+	if err := s.checkVet(vetID); err != nil {
+		return nil, err
+	}
+	note, err := s.GetByAppointmentID(vetID.String(), appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if note.StatusID == entities.ClinicalNoteStatusFinal {
+		return nil, ErrClinicalNoteFinalized
+	}
+	if err := s.Repo.FinalizeByAppointmentID(appointmentID, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByAppointmentID(appointmentID)
+}
+
+func (s *ClinicalNoteService) GetTemplates(vetID string, onlyActive bool) ([]entities.ClinicalNoteTemplate, error) {
+	return s.Repo.GetTemplates(vetID, onlyActive)
+}
+
+func (s *ClinicalNoteService) CreateTemplate(t *entities.ClinicalNoteTemplate) (*entities.ClinicalNoteTemplate, error) {
+	if t.VetID != nil {
+		if err := s.checkVet(*t.VetID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Repo.CreateTemplate(t); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetTemplateByID(t.ID)
+}
+
+// getOwnedTemplate devuelve la plantilla si editor puede modificarla: los
+// veterinarios solo sus propias plantillas y el administrador (editor nil)
+// cualquiera.
+func (s *ClinicalNoteService) getOwnedTemplate(id int, editor *uuid.UUID) (*entities.ClinicalNoteTemplate, error) {
+	t, err := s.Repo.GetTemplateByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrNoteTemplateNotFound
+	}
+	if editor != nil && (t.VetID == nil || *t.VetID != *editor) {
+		return nil, ErrNoteTemplateNotOwned
+	}
+	return t, nil
+}
+
+func (s *ClinicalNoteService) UpdateTemplate(id int, editor *uuid.UUID, fields map[string]interface{}) (*entities.ClinicalNoteTemplate, error) {
+	if _, err := s.getOwnedTemplate(id, editor); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateTemplate(id, fields); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetTemplateByID(id)
+}
+
+func (s *ClinicalNoteService) DeleteTemplate(id int, editor *uuid.UUID) (string, error) {
+	if _, err := s.getOwnedTemplate(id, editor); err != nil {
+		return "", err
+	}
+	newStatus, err := s.Repo.DeleteTemplate(id)
+	if err != nil {
+		return "", err
+	}
+	if newStatus == 1 {
+		return "Plantilla activada correctamente", nil
+	}
+	return "Plantilla desactivada correctamente", nil
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDiagnosisNotFound   = errors.New("diagnóstico no encontrado o inactivo")
+	ErrDiagnosisSpecies    = errors.New("el diagnóstico no aplica a la especie de la mascota")
+	ErrDiagnosisDuplicated = errors.New("un diagnóstico se registró más de una vez en la cita")
+	ErrDiagnosisPrimary    = errors.New("la cita debe tener un único diagnóstico principal")
+	ErrDiagnosisNotAllowed = errors.New("no se pueden registrar diagnósticos en citas canceladas o a las que la mascota no asistió")
+)
+
+// diagnosisOpenAppointments son los estados en los que la cita todavía admite
+// cambios en sus diagnósticos.
+var diagnosisOpenAppointments = []int{
+	entities.AppointmentStatusScheduled,
+	entities.AppointmentStatusCheckedIn,
+	entities.AppointmentStatusInProcess,
+}
+
+type DiagnosisService struct {
+	Repo            repositories.DiagnosisRepository
+	AppointmentRepo repositories.AppointmentRepository
+	SpeciesRepo     repositories.SpeciesRepository
+	Access          *PetAccess
+}
+
+func NewDiagnosisService(repo repositories.DiagnosisRepository, appointmentRepo repositories.AppointmentRepository, speciesRepo repositories.SpeciesRepository, access *PetAccess) *DiagnosisService {
+	return &DiagnosisService{Repo: repo, AppointmentRepo: appointmentRepo, SpeciesRepo: speciesRepo, Access: access}
+}
+
+func (s *DiagnosisService) GetDiagnoses(onlyActive bool, speciesID int, query string) ([]entities.Diagnosis, error) {
+	return s.Repo.GetDiagnoses(onlyActive, speciesID, query)
+}
+
+func (s *DiagnosisService) GetDiagnosisByID(id int) (*entities.Diagnosis, error) {
+	d, err := s.Repo.GetDiagnosisByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, ErrDiagnosisNotFound
+	}
+	return d, nil
+}
+
+func (s *DiagnosisService) checkSpecies(id *int) error {
+	if id == nil {
+		return nil
+	}
+	species, err := s.SpeciesRepo.GetByID(*id)
+	if err != nil {
+		return err
+	}
+	if species == nil {
+		return ErrSpeciesNotFound
+	}
+	return nil
+}
+
+func (s *DiagnosisService) CreateDiagnosis(d *entities.Diagnosis) (*entities.Diagnosis, error) {
+	if err := s.checkSpecies(d.SpeciesID); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateDiagnosis(d); err != nil {
+		return nil, err
+	}
+	return s.GetDiagnosisByID(d.ID)
+}
+
+// UpdateDiagnosis reemplaza los datos del diagnóstico. Las citas que ya lo
+// tienen registrado pasan a mostrar el nuevo nombre.
+func (s *DiagnosisService) UpdateDiagnosis(id int, d *entities.Diagnosis) (*entities.Diagnosis, error) {
+	if _, err := s.GetDiagnosisByID(id); err != nil {
+		return nil, err
+	}
+	if err := s.checkSpecies(d.SpeciesID); err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{
+		"code":       d.Code,
+		"name":       d.Name,
+		"category":   d.Category,
+		"species_id": d.SpeciesID,
+	}
+	if err := s.Repo.UpdateDiagnosis(id, fields); err != nil {
+		return nil, err
+	}
+	return s.GetDiagnosisByID(id)
+}
+
+func (s *DiagnosisService) DeleteDiagnosis(id int) (string, error) {
+	if _, err := s.GetDiagnosisByID(id); err != nil {
+		return "", err
+	}
+	newStatus, err := s.Repo.DeleteDiagnosis(id)
+	if err != nil {
+		return "", err
+	}
+	if newStatus == 1 {
+		return "Diagnóstico activado correctamente", nil
+	}
+	return "Diagnóstico desactivado correctamente", nil
+}
+
+func (s *DiagnosisService) getAppointment(id string) (*entities.Appointment, error) {
+	app, err := s.AppointmentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrAppointmentNotFound
+	}
+	return app, nil
+}
+
+func (s *DiagnosisService) GetAppointmentDiagnoses(requesterID, appointmentID string) ([]entities.AppointmentDiagnosis, error) {
+	app, err := s.getAppointment(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Access.CheckPet(requesterID, &app.Pet); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByAppointmentID(appointmentID)
+}
+
+// SetAppointmentDiagnoses reemplaza los diagnósticos codificados de la cita.
+// Si ninguno viene marcado como principal, el primero de la lista lo es.
+// Una vez finalizada la cita los diagnósticos quedan bloqueados como el
+// resto del registro clínico.
+func (s *DiagnosisService) SetAppointmentDiagnoses(requesterID, appointmentID string, list []entities.AppointmentDiagnosis) ([]entities.AppointmentDiagnosis, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	author, err := s.Access.StaffName(requesterID)
+	if err != nil {
+		return nil, err
+	}
+	app, err := s.getAppointment(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	switch app.StatusID {
+	case entities.AppointmentStatusFinished:
+		return nil, ErrAppointmentRecordLocked
+	case entities.AppointmentStatusCancelled, entities.AppointmentStatusNoShow:
+		return nil, ErrDiagnosisNotAllowed
+	}
+
+	ids := []int{}
+	seen := map[int]bool{}
+	primaries := 0
+	for _, d := range list {
+		if seen[d.DiagnosisID] {
+			return nil, ErrDiagnosisDuplicated
+		}
+		seen[d.DiagnosisID] = true
+		ids = append(ids, d.DiagnosisID)
+		if d.IsPrimary {
+			primaries++
+		}
+	}
+	if primaries > 1 {
+		return nil, ErrDiagnosisPrimary
+	}
+	if len(list) > 0 && primaries == 0 {
+		list[0].IsPrimary = true
+	}
+
+	if len(ids) > 0 {
+		catalog, err := s.Repo.GetDiagnosesByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		byID := map[int]entities.Diagnosis{}
+		for _, d := range catalog {
+			byID[d.ID] = d
+		}
+		for _, id := range ids {
+			d, ok := byID[id]
+			if !ok || d.StatusID != 1 {
+				return nil, ErrDiagnosisNotFound
+			}
+			if !d.AppliesTo(app.Pet.SpeciesID) {
+				return nil, ErrDiagnosisSpecies
+			}
+		}
+	}
+
+	var recordedBy *uuid.UUID
+	if id, err := uuid.Parse(requesterID); err == nil {
+		recordedBy = &id
+	}
+	for i := range list {
+		list[i].AppointmentID = app.ID
+		list[i].RecordedByID = recordedBy
+		list[i].RecordedByName = author
+	}
+	saved, err := s.Repo.ReplaceForAppointment(appointmentID, diagnosisOpenAppointments, list)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, ErrAppointmentRecordLocked
+	}
+	return s.Repo.GetByAppointmentID(appointmentID)
+}
+
+// Report cuenta los diagnósticos de las citas finalizadas para ver qué tan
+// frecuente es cada enfermedad en el periodo.
+func (s *DiagnosisService) Report(requesterID string, filter entities.DiagnosisReportFilter) ([]entities.DiagnosisCount, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	if filter.DateFrom != nil && filter.DateTo != nil && filter.DateTo.Before(*filter.DateFrom) {
+		return nil, ErrInvalidDateRange
+	}
+	return s.Repo.CountByDiagnosis(filter)
+}
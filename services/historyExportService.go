@@ -0,0 +1,366 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"VetiCare/utils"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HistoryExportService arma el historial clínico de una mascota en PDF para
+// entregarlo al dueño, por ejemplo cuando se muda o pide una segunda opinión.
+type HistoryExportService struct {
+	AppointmentRepo  repositories.AppointmentRepository
+	PrescriptionRepo repositories.PrescriptionRepository
+	VaccinationRepo  repositories.VaccinationRepository
+	VitalSignRepo    repositories.VitalSignRepository
+	PetRepo          repositories.PetRepository
+	Access           *PetAccess
+}
+
+func NewHistoryExportService(appointmentRepo repositories.AppointmentRepository, prescriptionRepo repositories.PrescriptionRepository, vaccinationRepo repositories.VaccinationRepository, vitalSignRepo repositories.VitalSignRepository, petRepo repositories.PetRepository, access *PetAccess) *HistoryExportService {
+	return &HistoryExportService{
+		AppointmentRepo:  appointmentRepo,
+		PrescriptionRepo: prescriptionRepo,
+		VaccinationRepo:  vaccinationRepo,
+		VitalSignRepo:    vitalSignRepo,
+		PetRepo:          petRepo,
+		Access:           access,
+	}
+}
+
+// ExportPDF genera el PDF del historial. Solo lo emite el personal de la
+// clínica, cuyo nombre queda impreso junto con la fecha de emisión.
+func (s *HistoryExportService) ExportPDF(requesterID, petID string) ([]byte, *entities.Pet, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, nil, err
+	}
+	pet, err := s.PetRepo.GetByID(petID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pet == nil {
+		return nil, nil, ErrPetNotFound
+	}
+	issuer, err := s.Access.StaffName(requesterID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	apps, err := s.AppointmentRepo.GetMedicalHistoryByPetID(petID)
+	if err != nil {
+		return nil, nil, err
+	}
+	vaccinations, err := s.VaccinationRepo.GetByPetID(petID)
+	if err != nil {
+		return nil, nil, err
+	}
+	vitals, err := s.VitalSignRepo.GetByPetID(petID)
+	if err != nil {
+		return nil, nil, err
+	}
+	prescriptions, err := s.PrescriptionRepo.GetActiveByPetID(petID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	sortAppointments(apps)
+	vaccinesByApp := map[string][]entities.Vaccination{}
+	var looseVaccinations []entities.Vaccination
+	for _, v := range vaccinations {
+		if v.AppointmentID == nil {
+			looseVaccinations = append(looseVaccinations, v)
+			continue
+		}
+		key := v.AppointmentID.String()
+		vaccinesByApp[key] = append(vaccinesByApp[key], v)
+	}
+	vitalsByApp := map[string][]entities.VitalSign{}
+	for _, v := range vitals {
+		if v.AppointmentID != nil {
+			key := v.AppointmentID.String()
+			vitalsByApp[key] = append(vitalsByApp[key], v)
+		}
+	}
+
+	doc := utils.NewPDFDocument(
+		utils.GetEnv("CLINIC_NAME", "VetiCare"),
+		clinicContactLine(),
+		fmt.Sprintf("Historial de %s - emitido el %s por %s", pet.Name, now.Format("02-01-2006 15:04"), issuer),
+	)
+	doc.AddPage()
+	doc.Heading("Historial clínico")
+	doc.Field("Fecha de emisión", now.Format("02-01-2006 15:04"))
+	doc.Field("Emitido por", issuer)
+	doc.Rule()
+
+	writePetSummary(doc, pet, now)
+
+	if meds := activeMedications(prescriptions, now); len(meds) > 0 {
+		doc.Heading("Medicamentos vigentes")
+		for _, m := range meds {
+			doc.Paragraph(fmt.Sprintf("• %s - hasta el %s", prescriptionItemLine(m.Item), m.EndsOn.Format("02-01-2006")))
+		}
+	}
+
+	doc.Heading(fmt.Sprintf("Consultas finalizadas (%d)", len(apps)))
+	if len(apps) == 0 {
+		doc.Paragraph("La mascota no tiene consultas finalizadas.")
+	}
+	for i := range apps {
+		app := &apps[i]
+		writeAppointment(doc, pet, app, vitalsByApp[app.ID.String()], vaccinesByApp[app.ID.String()])
+	}
+
+	if len(looseVaccinations) > 0 {
+		doc.Heading("Vacunas registradas fuera de consulta")
+		for _, v := range looseVaccinations {
+			doc.Paragraph("• " + vaccinationLine(v))
+		}
+	}
+
+	doc.Space(20)
+	doc.Rule()
+	doc.Paragraph(fmt.Sprintf("Documento emitido el %s por %s. Incluye únicamente las consultas finalizadas.", now.Format("02-01-2006 15:04"), issuer))
+
+	content, err := doc.Bytes()
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, pet, nil
+}
+
+func clinicContactLine() string {
+	var parts []string
+	if address := utils.GetEnv("CLINIC_ADDRESS", ""); address != "" {
+		parts = append(parts, address)
+	}
+	if phone := utils.GetEnv("CLINIC_PHONE", ""); phone != "" {
+		parts = append(parts, "Tel. "+phone)
+	}
+	return strings.Join(parts, " - ")
+}
+
+// sortAppointments ordena las citas de la más antigua a la más reciente.
+func sortAppointments(apps []entities.Appointment) {
+	sort.SliceStable(apps, func(i, j int) bool {
+		a, errA := utils.ParseAppointmentDateTime(apps[i].Date, apps[i].Time)
+		b, errB := utils.ParseAppointmentDateTime(apps[j].Date, apps[j].Time)
+		if errA != nil || errB != nil {
+			return apps[i].CreatedAt.Before(apps[j].CreatedAt)
+		}
+		return a.Before(b)
+	})
+}
+
+func writePetSummary(doc *utils.PDFDocument, pet *entities.Pet, now time.Time) {
+	doc.Heading("Mascota")
+	doc.Field("Nombre", pet.Name)
+	doc.Field("Especie", pet.Species.Name)
+	if breed := pet.BreedName(); breed != "" {
+		doc.Field("Raza", breed)
+	}
+	if pet.Microchip != nil {
+		doc.Field("Microchip", *pet.Microchip)
+	}
+	if pet.BirthDate != nil {
+		birth := pet.BirthDate.Format("02-01-2006")
+		if weeks, ok := pet.AgeInWeeks(now); ok {
+			birth += " (" + ageText(weeks) + ")"
+		}
+		doc.Field("Fecha de nacimiento", birth)
+	}
+
+	var allergies []string
+	for _, a := range pet.Allergies {
+		line := a.Agent + " (" + a.Severity
+		if a.Reaction != "" {
+			line += ", " + a.Reaction
+		}
+		allergies = append(allergies, line+")")
+	}
+	doc.Field("Alergias", strings.Join(allergies, "; "))
+	var conditions []string
+	for _, c := range pet.Conditions {
+		conditions = append(conditions, c.Name)
+	}
+	doc.Field("Condiciones crónicas", strings.Join(conditions, "; "))
+	var alerts []string
+	for _, a := range pet.CriticalAlerts() {
+		alerts = append(alerts, a.Description)
+	}
+	doc.Field("Alertas críticas", strings.Join(alerts, "; "))
+
+	doc.Heading("Dueño")
+	doc.Field("Nombre", pet.Owner.FullName)
+	doc.Field("DUI", pet.Owner.DUI)
+	doc.Field("Teléfono", pet.Owner.Phone)
+	doc.Field("Correo", pet.Owner.Email)
+}
+
+func writeAppointment(doc *utils.PDFDocument, pet *entities.Pet, app *entities.Appointment, vitals []entities.VitalSign, vaccinations []entities.Vaccination) {
+	doc.Rule()
+	doc.Subheading(fmt.Sprintf("Consulta del %s a las %s", app.Date, app.Time))
+	doc.Field("Veterinario", app.Vet.FullName)
+	if app.Clinic != nil {
+		doc.Field("Clínica", app.Clinic.Name)
+	}
+	doc.Field("Motivo", app.Reason)
+	if app.RecordVersion > 1 {
+		doc.Field("Registro", fmt.Sprintf("enmendado, versión %d", app.RecordVersion))
+	}
+
+	var lines []string
+	if values := app.VitalValues(); len(values) > 0 {
+		lines = append(lines, vitalLine(values, app.VitalFlags()))
+	}
+	for _, v := range vitals {
+		ageWeeks, hasAge := pet.AgeInWeeks(v.RecordedAt)
+		flags := pet.Species.CheckVitals(v.VitalValues(), ageWeeks, hasAge)
+		line := v.RecordedAt.Format("15:04") + ": " + vitalLine(v.VitalValues(), flags)
+		if v.BodyConditionScore != nil {
+			line += fmt.Sprintf(", condición corporal %d/9", *v.BodyConditionScore)
+		}
+		lines = append(lines, line)
+	}
+	doc.Field("Signos vitales", strings.Join(lines, "\n"))
+
+	var diagnoses []string
+	for _, d := range app.Diagnoses {
+		line := d.Diagnosis.Code + " " + d.Diagnosis.Name
+		if d.IsPrimary {
+			line += " (principal)"
+		}
+		diagnoses = append(diagnoses, line)
+	}
+	doc.Field("Diagnósticos codificados", strings.Join(diagnoses, "\n"))
+
+	if note := app.ClinicalNote; note != nil && note.StatusID == entities.ClinicalNoteStatusFinal {
+		doc.Field("Subjetivo", note.Subjective)
+		doc.Field("Objetivo", note.Objective)
+		doc.Field("Evaluación", note.Assessment)
+		doc.Field("Plan", note.Plan)
+		doc.Field("Diagnósticos", strings.Join(note.Codes(), ", "))
+	}
+	doc.Field("Notas", app.AdditionalNotes)
+
+	var vaccines []string
+	for _, v := range vaccinations {
+		vaccines = append(vaccines, vaccinationLine(v))
+	}
+	doc.Field("Vacunas", strings.Join(vaccines, "\n"))
+
+	var meds []string
+	for _, p := range app.Prescriptions {
+		suffix := ""
+		if p.StatusID == entities.PrescriptionStatusVoided {
+			suffix = " [receta anulada]"
+		}
+		for _, item := range p.Items {
+			meds = append(meds, prescriptionItemLine(item)+suffix)
+		}
+	}
+	if len(meds) == 0 && app.MedicationsPrescribed != "" {
+		meds = append(meds, app.MedicationsPrescribed)
+	}
+	doc.Field("Medicamentos", strings.Join(meds, "\n"))
+
+	var labs []string
+	for _, o := range app.LabOrders {
+		labs = append(labs, labOrderLine(o))
+	}
+	doc.Field("Laboratorio", strings.Join(labs, "\n"))
+}
+
+// labOrderLine resume la orden; si tiene resultados los lista con su marca
+// de fuera de rango.
+func labOrderLine(o entities.LabOrder) string {
+	line := o.LabTest.Name + " (" + o.Laboratory + ")"
+	if o.StatusID != entities.LabOrderStatusCompleted {
+		return line + ": pendiente de resultados"
+	}
+	var parts []string
+	for _, r := range o.Results {
+		value := r.TextValue
+		if r.Value != nil {
+			value = strconv.FormatFloat(*r.Value, 'f', -1, 64)
+		}
+		part := r.Analyte.Name + " " + value
+		if r.Analyte.Unit != "" {
+			part += " " + r.Analyte.Unit
+		}
+		if r.Flag != "" {
+			part += " (" + r.Flag + ")"
+		}
+		parts = append(parts, part)
+	}
+	line += ": " + strings.Join(parts, ", ")
+	if o.ResultNotes != "" {
+		line += ". " + o.ResultNotes
+	}
+	return line
+}
+
+// vitalLine describe los signos registrados y marca los que quedaron fuera
+// del rango de referencia de la especie.
+func vitalLine(values map[string]float64, flags []entities.VitalFlag) string {
+	levels := map[string]string{}
+	for _, f := range flags {
+		levels[f.Vital] = f.Level
+	}
+	order := []struct{ vital, label, format string }{
+		{entities.VitalWeight, "peso", "%.2f kg"},
+		{entities.VitalTemperature, "temperatura", "%.1f °C"},
+		{entities.VitalHeartRate, "frec. cardiaca", "%.0f lpm"},
+		{entities.VitalRespiratoryRate, "frec. respiratoria", "%.0f rpm"},
+	}
+	var parts []string
+	for _, o := range order {
+		value, ok := values[o.vital]
+		if !ok {
+			continue
+		}
+		part := o.label + " " + fmt.Sprintf(o.format, value)
+		if level := levels[o.vital]; level != "" {
+			part += " (" + level + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func vaccinationLine(v entities.Vaccination) string {
+	line := fmt.Sprintf("%s, dosis %d, aplicada el %s", v.Vaccine.Name, v.DoseNumber, v.DateGiven.Format("02-01-2006"))
+	if v.LotNumber != "" {
+		line += ", lote " + v.LotNumber
+	}
+	if v.NextDueDate != nil {
+		line += ", próxima dosis " + v.NextDueDate.Format("02-01-2006")
+	}
+	return line
+}
+
+func prescriptionItemLine(i entities.PrescriptionItem) string {
+	drug := i.Drug
+	if i.Strength != "" {
+		drug += " " + i.Strength
+	}
+	return fmt.Sprintf("%s: %s vía %s, %s por %d días", drug, i.Dose, i.Route, i.Frequency, i.DurationDays)
+}
+
+func ageText(weeks int) string {
+	switch {
+	case weeks >= 104:
+		return fmt.Sprintf("%d años", weeks/52)
+	case weeks >= 52:
+		return "1 año"
+	case weeks >= 8:
+		return fmt.Sprintf("%d meses", weeks*12/52)
+	}
+	return fmt.Sprintf("%d semanas", weeks)
+}
@@ -0,0 +1,371 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrLabTestNotFound        = errors.New("prueba de laboratorio no encontrada o inactiva")
+	ErrLabAnalyteNotFound     = errors.New("analito no encontrado")
+	ErrLabRangeNotFound       = errors.New("el analito no tiene rango de referencia para esa especie")
+	ErrLabOrderNotFound       = errors.New("orden de laboratorio no encontrada")
+	ErrLabOrderNotAllowed     = errors.New("no se pueden solicitar pruebas para citas canceladas o a las que la mascota no asistió")
+	ErrLabOrderClosed         = errors.New("la orden ya está completada o cancelada")
+	ErrLabOrderStatus         = errors.New("el cambio de estado no es válido para la orden; para completarla registre los resultados")
+	ErrLabResultsEmpty        = errors.New("debe registrar al menos un resultado")
+	ErrLabResultAnalyte       = errors.New("el analito no pertenece a la prueba de la orden")
+	ErrLabResultDuplicated    = errors.New("un analito se registró más de una vez")
+	ErrLabResultValueRequired = errors.New("cada resultado debe tener un valor numérico o de texto")
+)
+
+type LabService struct {
+	Repo            repositories.LabRepository
+	AppointmentRepo repositories.AppointmentRepository
+	PetRepo         repositories.PetRepository
+	SpeciesRepo     repositories.SpeciesRepository
+	Access          *PetAccess
+}
+
+func NewLabService(repo repositories.LabRepository, appointmentRepo repositories.AppointmentRepository, petRepo repositories.PetRepository, speciesRepo repositories.SpeciesRepository, access *PetAccess) *LabService {
+	return &LabService{Repo: repo, AppointmentRepo: appointmentRepo, PetRepo: petRepo, SpeciesRepo: speciesRepo, Access: access}
+}
+
+func (s *LabService) GetTests(onlyActive bool) ([]entities.LabTest, error) {
+	return s.Repo.GetTests(onlyActive)
+}
+
+func (s *LabService) GetTestByID(id int) (*entities.LabTest, error) {
+	t, err := s.Repo.GetTestByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrLabTestNotFound
+	}
+	return t, nil
+}
+
+// CreateTest crea la prueba junto con sus analitos y los rangos que traigan.
+func (s *LabService) CreateTest(t *entities.LabTest) (*entities.LabTest, error) {
+	for _, a := range t.Analytes {
+		for _, rng := range a.Ranges {
+			if err := s.checkSpecies(rng.SpeciesID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := s.Repo.CreateTest(t); err != nil {
+		return nil, err
+	}
+	return s.GetTestByID(t.ID)
+}
+
+func (s *LabService) UpdateTest(id int, fields map[string]interface{}) (*entities.LabTest, error) {
+	if _, err := s.GetTestByID(id); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateTest(id, fields); err != nil {
+		return nil, err
+	}
+	return s.GetTestByID(id)
+}
+
+func (s *LabService) DeleteTest(id int) (string, error) {
+	if _, err := s.GetTestByID(id); err != nil {
+		return "", err
+	}
+	newStatus, err := s.Repo.DeleteTest(id)
+	if err != nil {
+		return "", err
+	}
+	if newStatus == 1 {
+		return "Prueba de laboratorio activada correctamente", nil
+	}
+	return "Prueba de laboratorio desactivada correctamente", nil
+}
+
+func (s *LabService) CreateAnalyte(a *entities.LabAnalyte) (*entities.LabAnalyte, error) {
+	if _, err := s.GetTestByID(a.LabTestID); err != nil {
+		return nil, err
+	}
+	for _, rng := range a.Ranges {
+		if err := s.checkSpecies(rng.SpeciesID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Repo.CreateAnalyte(a); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetAnalyteByID(a.ID)
+}
+
+func (s *LabService) getAnalyte(id int) (*entities.LabAnalyte, error) {
+	a, err := s.Repo.GetAnalyteByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrLabAnalyteNotFound
+	}
+	return a, nil
+}
+
+func (s *LabService) UpdateAnalyte(id int, fields map[string]interface{}) (*entities.LabAnalyte, error) {
+	if _, err := s.getAnalyte(id); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateAnalyte(id, fields); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetAnalyteByID(id)
+}
+
+func (s *LabService) DeleteAnalyte(id int) (string, error) {
+	if _, err := s.getAnalyte(id); err != nil {
+		return "", err
+	}
+	newStatus, err := s.Repo.DeleteAnalyte(id)
+	if err != nil {
+		return "", err
+	}
+	if newStatus == 1 {
+		return "Analito activado correctamente", nil
+	}
+	return "Analito desactivado correctamente", nil
+}
+
+// SetReferenceRange crea o reemplaza el rango del analito para la especie.
+func (s *LabService) SetReferenceRange(rng *entities.LabReferenceRange) (*entities.LabAnalyte, error) {
+	if _, err := s.getAnalyte(rng.AnalyteID); err != nil {
+		return nil, err
+	}
+	if err := s.checkSpecies(rng.SpeciesID); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.SaveReferenceRange(rng); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetAnalyteByID(rng.AnalyteID)
+}
+
+func (s *LabService) DeleteReferenceRange(analyteID, speciesID int) error {
+	deleted, err := s.Repo.DeleteReferenceRange(analyteID, speciesID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrLabRangeNotFound
+	}
+	return nil
+}
+
+func (s *LabService) checkSpecies(id int) error {
+	species, err := s.SpeciesRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if species == nil {
+		return ErrSpeciesNotFound
+	}
+	return nil
+}
+
+// CreateOrder solicita una prueba durante la cita. Solo el personal de la
+// clínica puede hacerlo y la orden queda pendiente hasta enviar la muestra.
+func (s *LabService) CreateOrder(requesterID string, o *entities.LabOrder) (*entities.LabOrder, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	author, err := s.Access.StaffName(requesterID)
+	if err != nil {
+		return nil, err
+	}
+	app, err := s.AppointmentRepo.GetByID(o.AppointmentID.String())
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrAppointmentNotFound
+	}
+	if app.StatusID == entities.AppointmentStatusCancelled || app.StatusID == entities.AppointmentStatusNoShow {
+		return nil, ErrLabOrderNotAllowed
+	}
+	test, err := s.GetTestByID(o.LabTestID)
+	if err != nil {
+		return nil, err
+	}
+	if test.StatusID != 1 {
+		return nil, ErrLabTestNotFound
+	}
+
+	o.PetID = app.PetID
+	o.StatusID = entities.LabOrderStatusPending
+	o.OrderedByName = author
+	if id, err := uuid.Parse(requesterID); err == nil {
+		o.OrderedByID = &id
+	}
+	if err := s.Repo.CreateOrder(o); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetOrderByID(o.ID.String())
+}
+
+func (s *LabService) GetOrderByID(requesterID, id string) (*entities.LabOrder, error) {
+	o, err := s.Repo.GetOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if o == nil {
+		return nil, ErrLabOrderNotFound
+	}
+	if err := s.checkPetAccess(requesterID, o.PetID.String()); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (s *LabService) GetOrdersByAppointmentID(requesterID, appointmentID string) ([]entities.LabOrder, error) {
+	app, err := s.AppointmentRepo.GetByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrAppointmentNotFound
+	}
+	if err := s.Access.CheckPet(requesterID, &app.Pet); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetOrdersByAppointmentID(appointmentID)
+}
+
+func (s *LabService) GetOrdersByPetID(requesterID, petID string, statusID *int) ([]entities.LabOrder, error) {
+	if err := s.checkPetAccess(requesterID, petID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetOrdersByPetID(petID, statusID)
+}
+
+// GetOpenOrders es la lista de trabajo del laboratorio: las órdenes que aún
+// esperan la muestra o los resultados.
+func (s *LabService) GetOpenOrders(requesterID string) ([]entities.LabOrder, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetOpenOrders()
+}
+
+// UpdateOrderStatus marca la muestra como enviada o cancela la orden. Una
+// orden se completa solo al registrar sus resultados.
+func (s *LabService) UpdateOrderStatus(requesterID, id string, statusID int) (*entities.LabOrder, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	o, err := s.Repo.GetOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if o == nil {
+		return nil, ErrLabOrderNotFound
+	}
+
+	fields := map[string]interface{}{"status_id": statusID}
+	var from []int
+	switch statusID {
+	case entities.LabOrderStatusSent:
+		from = []int{entities.LabOrderStatusPending}
+		fields["sample_sent_at"] = time.Now()
+	case entities.LabOrderStatusCancelled:
+		from = []int{entities.LabOrderStatusPending, entities.LabOrderStatusSent}
+	default:
+		return nil, ErrLabOrderStatus
+	}
+	updated, err := s.Repo.UpdateOrderStatus(id, from, fields)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrLabOrderStatus
+	}
+	return s.Repo.GetOrderByID(id)
+}
+
+// RecordResults guarda los valores de los analitos, los marca con el rango
+// de la especie de la mascota y completa la orden. Los analitos que el
+// laboratorio no reportó simplemente no se registran.
+func (s *LabService) RecordResults(requesterID, id string, results []entities.LabResult, notes string) (*entities.LabOrder, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	o, err := s.Repo.GetOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if o == nil {
+		return nil, ErrLabOrderNotFound
+	}
+	if o.StatusID != entities.LabOrderStatusPending && o.StatusID != entities.LabOrderStatusSent {
+		return nil, ErrLabOrderClosed
+	}
+	if len(results) == 0 {
+		return nil, ErrLabResultsEmpty
+	}
+	pet, err := s.PetRepo.GetByID(o.PetID.String())
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+
+	analytes := map[int]*entities.LabAnalyte{}
+	for i := range o.LabTest.Analytes {
+		analytes[o.LabTest.Analytes[i].ID] = &o.LabTest.Analytes[i]
+	}
+	seen := map[int]bool{}
+	for i := range results {
+		r := &results[i]
+		analyte, ok := analytes[r.AnalyteID]
+		if !ok {
+			return nil, ErrLabResultAnalyte
+		}
+		if seen[r.AnalyteID] {
+			return nil, ErrLabResultDuplicated
+		}
+		seen[r.AnalyteID] = true
+		if r.Value == nil && r.TextValue == "" {
+			return nil, ErrLabResultValueRequired
+		}
+		r.LabOrderID = o.ID
+		r.ApplyRange(analyte.RangeFor(pet.SpeciesID))
+	}
+
+	fields := map[string]interface{}{
+		"status_id":    entities.LabOrderStatusCompleted,
+		"resulted_at":  time.Now(),
+		"result_notes": notes,
+	}
+	saved, err := s.Repo.SaveResults(id, results, fields)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, ErrLabOrderClosed
+	}
+	return s.Repo.GetOrderByID(id)
+}
+
+func (s *LabService) checkPetAccess(requesterID, petID string) error {
+	pet, err := s.PetRepo.GetByID(petID)
+	if err != nil {
+		return err
+	}
+	if pet == nil {
+		return ErrPetNotFound
+	}
+	return s.Access.CheckPet(requesterID, pet)
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"errors"
+)
+
+var (
+	ErrPetAccessDenied = errors.New("no tiene permiso para acceder a los datos de esta mascota")
+	ErrStaffOnly       = errors.New("la acción solo está permitida para veterinarios y administradores")
+)
+
+// PetAccess decide quién puede ver los datos clínicos de una mascota: su
+// dueño, los veterinarios activos y los administradores. El ID es el del
+// token, que puede pertenecer a un usuario o a un administrador.
+type PetAccess struct {
+	UserRepo  repositories.UserRepository
+	AdminRepo repositories.AdminRepository
+}
+
+func NewPetAccess(userRepo repositories.UserRepository, adminRepo repositories.AdminRepository) *PetAccess {
+	return &PetAccess{UserRepo: userRepo, AdminRepo: adminRepo}
+}
+
+// IsStaff indica si el solicitante es un veterinario activo o un
+// administrador.
+func (a *PetAccess) IsStaff(requesterID string) (bool, error) {
+	user, err := a.UserRepo.GetByID(requesterID)
+	if err != nil {
+		return false, err
+	}
+	if user != nil {
+		return user.RoleID == 2 && user.StatusID == 1, nil
+	}
+	admin, err := a.AdminRepo.GetByID(requesterID)
+	if err != nil {
+		return false, err
+	}
+	return admin != nil && admin.StatusID == 1, nil
+}
+
+// StaffName devuelve el nombre del veterinario o administrador con el rol
+// entre paréntesis, para dejarlo registrado como autor.
+func (a *PetAccess) StaffName(requesterID string) (string, error) {
+	user, err := a.UserRepo.GetByID(requesterID)
+	if err != nil {
+		return "", err
+	}
+	if user != nil {
+		return user.FullName + " (veterinario)", nil
+	}
+	admin, err := a.AdminRepo.GetByID(requesterID)
+	if err != nil {
+		return "", err
+	}
+	if admin == nil {
+		return "", ErrStaffOnly
+	}
+	return admin.FullName + " (administración)", nil
+}
+
+// CheckStaff devuelve ErrStaffOnly si el solicitante no es personal de la
+// clínica.
+func (a *PetAccess) CheckStaff(requesterID string) error {
+	staff, err := a.IsStaff(requesterID)
+	if err != nil {
+		return err
+	}
+	if !staff {
+		return ErrStaffOnly
+	}
+	return nil
+}
+
+// CheckPet devuelve ErrPetAccessDenied si el solicitante no es el dueño de la
+// mascota ni personal de la clínica.
+func (a *PetAccess) CheckPet(requesterID string, pet *entities.Pet) error {
+	if pet.OwnerID.String() == requesterID {
+		return nil
+	}
+	staff, err := a.IsStaff(requesterID)
+	if err != nil {
+		return err
+	}
+	if !staff {
+		return ErrPetAccessDenied
+	}
+	return nil
+}
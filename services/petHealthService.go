@@ -0,0 +1,230 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"errors"
+)
+
+var (
+	ErrAllergyNotFound       = errors.New("alergia no encontrada para la mascota")
+	ErrConditionNotFound     = errors.New("condición crónica no encontrada para la mascota")
+	ErrBehaviorAlertNotFound = errors.New("aviso de conducta no encontrado para la mascota")
+)
+
+// PetHealthService guarda las alergias, condiciones crónicas y avisos de
+// conducta de la mascota. Los ven su dueño y el personal de la clínica; el
+// dueño puede informar alergias y condiciones, pero los avisos de conducta
+// solo los registra el personal.
+type PetHealthService struct {
+	Repo    repositories.PetHealthRepository
+	PetRepo repositories.PetRepository
+	Access  *PetAccess
+}
+
+func NewPetHealthService(repo repositories.PetHealthRepository, petRepo repositories.PetRepository, access *PetAccess) *PetHealthService {
+	return &PetHealthService{Repo: repo, PetRepo: petRepo, Access: access}
+}
+
+// checkPet exige que la mascota exista y que el solicitante sea su dueño o
+// personal de la clínica.
+func (s *PetHealthService) checkPet(requesterID, petID string) error {
+	pet, err := s.PetRepo.GetByID(petID)
+	if err != nil {
+		return err
+	}
+	if pet == nil {
+		return ErrPetNotFound
+	}
+	return s.Access.CheckPet(requesterID, pet)
+}
+
+// checkStaff exige que la mascota exista y que el solicitante sea personal de
+// la clínica.
+func (s *PetHealthService) checkStaff(requesterID, petID string) error {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return err
+	}
+	pet, err := s.PetRepo.GetByID(petID)
+	if err != nil {
+		return err
+	}
+	if pet == nil {
+		return ErrPetNotFound
+	}
+	return nil
+}
+
+func (s *PetHealthService) GetAllergies(requesterID, petID string, onlyActive bool) ([]entities.PetAllergy, error) {
+	if err := s.checkPet(requesterID, petID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetAllergies(petID, onlyActive)
+}
+
+func (s *PetHealthService) CreateAllergy(requesterID string, a *entities.PetAllergy) (*entities.PetAllergy, error) {
+	if err := s.checkPet(requesterID, a.PetID.String()); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateAllergy(a); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetAllergyByID(a.ID.String())
+}
+
+func (s *PetHealthService) getAllergy(petID, id string) (*entities.PetAllergy, error) {
+	a, err := s.Repo.GetAllergyByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil || a.PetID.String() != petID {
+		return nil, ErrAllergyNotFound
+	}
+	return a, nil
+}
+
+func (s *PetHealthService) UpdateAllergy(requesterID, petID, id string, fields map[string]interface{}) (*entities.PetAllergy, error) {
+	if err := s.checkPet(requesterID, petID); err != nil {
+		return nil, err
+	}
+	if _, err := s.getAllergy(petID, id); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateAllergy(id, fields); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetAllergyByID(id)
+}
+
+func (s *PetHealthService) DeleteAllergy(requesterID, petID, id string) (string, error) {
+	if err := s.checkPet(requesterID, petID); err != nil {
+		return "", err
+	}
+	if _, err := s.getAllergy(petID, id); err != nil {
+		return "", err
+	}
+	newStatus, err := s.Repo.DeleteAllergy(id)
+	if err != nil {
+		return "", err
+	}
+	if newStatus == 1 {
+		return "Alergia activada correctamente", nil
+	}
+	return "Alergia desactivada correctamente", nil
+}
+
+func (s *PetHealthService) GetConditions(requesterID, petID string, onlyActive bool) ([]entities.PetCondition, error) {
+	if err := s.checkPet(requesterID, petID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetConditions(petID, onlyActive)
+}
+
+func (s *PetHealthService) CreateCondition(requesterID string, c *entities.PetCondition) (*entities.PetCondition, error) {
+	if err := s.checkPet(requesterID, c.PetID.String()); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateCondition(c); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetConditionByID(c.ID.String())
+}
+
+func (s *PetHealthService) getCondition(petID, id string) (*entities.PetCondition, error) {
+	c, err := s.Repo.GetConditionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil || c.PetID.String() != petID {
+		return nil, ErrConditionNotFound
+	}
+	return c, nil
+}
+
+func (s *PetHealthService) UpdateCondition(requesterID, petID, id string, fields map[string]interface{}) (*entities.PetCondition, error) {
+	if err := s.checkPet(requesterID, petID); err != nil {
+		return nil, err
+	}
+	if _, err := s.getCondition(petID, id); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateCondition(id, fields); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetConditionByID(id)
+}
+
+func (s *PetHealthService) DeleteCondition(requesterID, petID, id string) (string, error) {
+	if err := s.checkPet(requesterID, petID); err != nil {
+		return "", err
+	}
+	if _, err := s.getCondition(petID, id); err != nil {
+		return "", err
+	}
+	newStatus, err := s.Repo.DeleteCondition(id)
+	if err != nil {
+		return "", err
+	}
+	if newStatus == 1 {
+		return "Condición activada correctamente", nil
+	}
+	return "Condición desactivada correctamente", nil
+}
+
+func (s *PetHealthService) GetBehaviorAlerts(requesterID, petID string, onlyActive bool) ([]entities.PetBehaviorAlert, error) {
+	if err := s.checkPet(requesterID, petID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetBehaviorAlerts(petID, onlyActive)
+}
+
+func (s *PetHealthService) CreateBehaviorAlert(requesterID string, b *entities.PetBehaviorAlert) (*entities.PetBehaviorAlert, error) {
+	if err := s.checkStaff(requesterID, b.PetID.String()); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateBehaviorAlert(b); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetBehaviorAlertByID(b.ID.String())
+}
+
+func (s *PetHealthService) getBehaviorAlert(petID, id string) (*entities.PetBehaviorAlert, error) {
+	b, err := s.Repo.GetBehaviorAlertByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil || b.PetID.String() != petID {
+		return nil, ErrBehaviorAlertNotFound
+	}
+	return b, nil
+}
+
+func (s *PetHealthService) UpdateBehaviorAlert(requesterID, petID, id string, fields map[string]interface{}) (*entities.PetBehaviorAlert, error) {
+	if err := s.checkStaff(requesterID, petID); err != nil {
+		return nil, err
+	}
+	if _, err := s.getBehaviorAlert(petID, id); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateBehaviorAlert(id, fields); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetBehaviorAlertByID(id)
+}
+
+func (s *PetHealthService) DeleteBehaviorAlert(requesterID, petID, id string) (string, error) {
+	if err := s.checkStaff(requesterID, petID); err != nil {
+		return "", err
+	}
+	if _, err := s.getBehaviorAlert(petID, id); err != nil {
+		return "", err
+	}
+	newStatus, err := s.Repo.DeleteBehaviorAlert(id)
+	if err != nil {
+		return "", err
+	}
+	if newStatus == 1 {
+		return "Aviso de conducta activado correctamente", nil
+	}
+	return "Aviso de conducta desactivado correctamente", nil
+}
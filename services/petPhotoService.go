@@ -0,0 +1,141 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"VetiCare/utils"
+	"bytes"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrPetPhotoNotFound = errors.New("la mascota no tiene foto")
+
+const (
+	petPhotoMaxSide     = 1024
+	petThumbnailSide    = 256
+	petPhotoQuality     = 85
+	petThumbnailQuality = 80
+)
+
+// PetPhotoService guarda la foto de perfil de la mascota. Cada foto se
+// vuelve a codificar como JPEG en dos tamaños, lo que además descarta los
+// metadatos EXIF del original.
+type PetPhotoService struct {
+	PetRepo repositories.PetRepository
+	Storage repositories.FileStorage
+	Access  *PetAccess
+}
+
+func NewPetPhotoService(petRepo repositories.PetRepository, storage repositories.FileStorage, access *PetAccess) *PetPhotoService {
+	return &PetPhotoService{PetRepo: petRepo, Storage: storage, Access: access}
+}
+
+func (s *PetPhotoService) getPet(requesterID, petID string) (*entities.Pet, error) {
+	pet, err := s.PetRepo.GetByID(petID)
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	if err := s.Access.CheckPet(requesterID, pet); err != nil {
+		return nil, err
+	}
+	return pet, nil
+}
+
+// Upload reemplaza la foto de la mascota. La pueden cambiar su dueño y el
+// personal de la clínica.
+func (s *PetPhotoService) Upload(requesterID, petID string, data []byte) (*entities.Pet, error) {
+	pet, err := s.getPet(requesterID, petID)
+	if err != nil {
+		return nil, err
+	}
+	img, err := utils.DecodePhoto(data, petPhotoMaxSide)
+	if err != nil {
+		return nil, err
+	}
+	photo, err := utils.EncodeJPEG(img, petPhotoQuality)
+	if err != nil {
+		return nil, err
+	}
+	thumb, err := utils.EncodeJPEG(utils.Thumbnail(img, petThumbnailSide), petThumbnailQuality)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := *pet
+	pet.PhotoVersion = uuid.New().String()
+	if _, err := s.Storage.Save(pet.PhotoKey(false), bytes.NewReader(photo)); err != nil {
+		return nil, err
+	}
+	if _, err := s.Storage.Save(pet.PhotoKey(true), bytes.NewReader(thumb)); err != nil {
+		s.Storage.Delete(pet.PhotoKey(false))
+		return nil, err
+	}
+	now := time.Now()
+	err = s.PetRepo.Update(petID, map[string]interface{}{
+		"photo_version":    pet.PhotoVersion,
+		"photo_updated_at": now,
+	})
+	if err != nil {
+		s.Storage.Delete(pet.PhotoKey(false))
+		s.Storage.Delete(pet.PhotoKey(true))
+		return nil, err
+	}
+	s.deleteFiles(&previous)
+	pet.PhotoUpdatedAt = &now
+	return pet, nil
+}
+
+// Open devuelve la foto o la miniatura. Quien llama debe cerrar el
+// contenido.
+func (s *PetPhotoService) Open(requesterID, petID string, thumbnail bool) (*entities.Pet, io.ReadCloser, error) {
+	pet, err := s.getPet(requesterID, petID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pet.PhotoVersion == "" {
+		return nil, nil, ErrPetPhotoNotFound
+	}
+	content, err := s.Storage.Open(pet.PhotoKey(thumbnail))
+	if err != nil {
+		return nil, nil, err
+	}
+	return pet, content, nil
+}
+
+func (s *PetPhotoService) Delete(requesterID, petID string) (*entities.Pet, error) {
+	pet, err := s.getPet(requesterID, petID)
+	if err != nil {
+		return nil, err
+	}
+	if pet.PhotoVersion == "" {
+		return nil, ErrPetPhotoNotFound
+	}
+	err = s.PetRepo.Update(petID, map[string]interface{}{
+		"photo_version":    "",
+		"photo_updated_at": nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.deleteFiles(pet)
+	pet.PhotoVersion = ""
+	pet.PhotoUpdatedAt = nil
+	return pet, nil
+}
+
+// deleteFiles borra los archivos de la foto anterior. Un error aquí solo deja
+// archivos sin usar, así que no se informa.
+func (s *PetPhotoService) deleteFiles(pet *entities.Pet) {
+	if pet.PhotoVersion == "" {
+		return
+	}
+	s.Storage.Delete(pet.PhotoKey(false))
+	s.Storage.Delete(pet.PhotoKey(true))
+}
@@ -3,41 +3,236 @@ package services
 import (
 	"VetiCare/entities"
 	"VetiCare/repositories"
+	"errors"
+	"time"
+)
+
+var (
+	ErrMicrochipInUse    = errors.New("el microchip ya está registrado en otra mascota")
+	ErrMicrochipNotFound = errors.New("no hay ninguna mascota registrada con ese microchip")
+	ErrMicrochipImplant  = errors.New("la fecha de implante del microchip no puede ser anterior al nacimiento")
 )
 
 type PetService struct {
-	Repo repositories.PetRepository
+	Repo        repositories.PetRepository
+	SpeciesRepo repositories.SpeciesRepository
+	Access      *PetAccess
 }
 
-func NewPetService(repo repositories.PetRepository) *PetService {
-	return &PetService{Repo: repo}
+func NewPetService(repo repositories.PetRepository, speciesRepo repositories.SpeciesRepository, access *PetAccess) *PetService {
+	return &PetService{Repo: repo, SpeciesRepo: speciesRepo, Access: access}
 }
 
-func (s *PetService) CreatePet(pet *entities.Pet) error {
-	return s.Repo.Create(pet)
+// CreatePet valida la raza elegida del catálogo. Si solo se envía el texto
+// de la raza y coincide exactamente con una del catálogo, se asocia a ella.
+// Un dueño solo puede registrar mascotas a su nombre; el personal de la
+// clínica, a nombre de cualquiera.
+func (s *PetService) CreatePet(requesterID string, pet *entities.Pet) error {
+	if pet.OwnerID.String() != requesterID {
+		if err := s.Access.CheckStaff(requesterID); err != nil {
+			return err
+		}
+	}
+	if pet.BreedID == nil && pet.Breed != nil && *pet.Breed != "" {
+		breeds, err := s.SpeciesRepo.GetBreeds(pet.SpeciesID, true)
+		if err != nil {
+			return err
+		}
+		if breed, exact := matchBreed(*pet.Breed, breeds); exact {
+			pet.BreedID = &breed.ID
+		}
+	}
+	if pet.BreedID != nil {
+		breed, err := s.checkBreed(*pet.BreedID, pet.SpeciesID, true)
+		if err != nil {
+			return err
+		}
+		if !breed.IsMixed {
+			pet.Breed = nil
+		}
+	}
+	if pet.Microchip != nil {
+		if err := s.checkMicrochip(*pet.Microchip, ""); err != nil {
+			return err
+		}
+	}
+	return mapMicrochipTaken(s.Repo.Create(pet))
 }
 
-func (s *PetService) GetPetByID(id string) (*entities.Pet, error) {
-	return s.Repo.GetByID(id)
+// GetPetByID devuelve la mascota solo a su dueño y al personal de la
+// clínica, porque incluye el microchip y el contacto del dueño.
+func (s *PetService) GetPetByID(requesterID, id string) (*entities.Pet, error) {
+	pet, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	if err := s.Access.CheckPet(requesterID, pet); err != nil {
+		return nil, err
+	}
+	return pet, nil
 }
 
-func (s *PetService) GetAllPets() ([]entities.Pet, error) {
+func (s *PetService) GetAllPets(requesterID string) ([]entities.Pet, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
 	return s.Repo.GetAll()
 }
 
-func (s *PetService) GetActivePets() ([]entities.Pet, error) {
+func (s *PetService) GetActivePets(requesterID string) ([]entities.Pet, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
 	return s.Repo.GetActivePets()
 }
 
-func (s *PetService) GetPetsByOwner(ownerID string) ([]entities.Pet, error) {
+// GetPetsByOwner lista las mascotas de un dueño. Cada dueño ve las suyas y
+// el personal de la clínica, las de cualquiera.
+func (s *PetService) GetPetsByOwner(requesterID, ownerID string) ([]entities.Pet, error) {
+	if ownerID != requesterID {
+		if err := s.Access.CheckStaff(requesterID); err != nil {
+			return nil, err
+		}
+	}
 	return s.Repo.GetPetsByOwner(ownerID)
 }
 
-func (s *PetService) UpdatePet(id string, fields map[string]interface{}) error {
-	return s.Repo.Update(id, fields)
+// UpdatePet comprueba que la raza siga correspondiendo a la especie cuando
+// cambia cualquiera de las dos. Al elegir una raza que no es mestiza se borra
+// el texto libre, que solo detalla las razas fuera del catálogo. También
+// revisa que el microchip nuevo no esté en otra mascota y que la fecha de
+// implante no sea anterior al nacimiento. Solo el dueño y el personal pueden
+// editarla, y solo el personal puede pasarla a otro dueño.
+func (s *PetService) UpdatePet(requesterID, id string, fields map[string]interface{}) error {
+	pet, err := s.GetPetByID(requesterID, id)
+	if err != nil {
+		return err
+	}
+	if ownerID, ok := fields["owner_id"].(string); ok && ownerID != pet.OwnerID.String() {
+		if err := s.Access.CheckStaff(requesterID); err != nil {
+			return err
+		}
+	}
+	_, breedChanged := fields["breed_id"]
+	_, speciesChanged := fields["species_id"]
+	microchip, microchipChanged := fields["microchip"].(string)
+	implantedAt, implantChanged := fields["microchip_implanted_at"].(time.Time)
+	_, birthChanged := fields["birth_date"]
+	if microchipChanged {
+		if err := s.checkMicrochip(microchip, id); err != nil {
+			return err
+		}
+	}
+	if implantChanged || birthChanged {
+		birthDate := pet.BirthDate
+		if birthChanged {
+			birthDate = nil
+			if v, ok := fields["birth_date"].(time.Time); ok {
+				birthDate = &v
+			}
+		}
+		if !implantChanged && pet.MicrochipImplantedAt != nil {
+			implantedAt, implantChanged = *pet.MicrochipImplantedAt, true
+		}
+		if implantChanged && birthDate != nil && implantedAt.Before(*birthDate) {
+			return ErrMicrochipImplant
+		}
+	}
+	if !breedChanged && !speciesChanged {
+		return mapMicrochipTaken(s.Repo.Update(id, fields))
+	}
+	speciesID := pet.SpeciesID
+	if v, ok := fields["species_id"].(float64); ok {
+		speciesID = int(v)
+	}
+	breedID := pet.BreedID
+	if breedChanged {
+		breedID = nil
+		if v, ok := fields["breed_id"].(float64); ok {
+			n := int(v)
+			breedID = &n
+		}
+		fields["breed_id"] = breedID
+	}
+	if breedID != nil {
+		breed, err := s.checkBreed(*breedID, speciesID, breedChanged)
+		if err != nil {
+			return err
+		}
+		if breedChanged && !breed.IsMixed {
+			fields["breed"] = nil
+		}
+	}
+	return mapMicrochipTaken(s.Repo.Update(id, fields))
 }
 
-func (s *PetService) DeletePet(id string) (string, error) {
+// mapMicrochipTaken traduce el choque con el índice único, que ocurre si otra
+// mascota guardó el mismo microchip después de checkMicrochip.
+func mapMicrochipTaken(err error) error {
+	if errors.Is(err, repositories.ErrMicrochipTaken) {
+		return ErrMicrochipInUse
+	}
+	return err
+}
+
+// checkMicrochip rechaza el número si ya lo tiene otra mascota. El índice
+// único de la tabla lo garantiza igual; esto solo da un error claro antes de
+// escribir.
+func (s *PetService) checkMicrochip(number, petID string) error {
+	existing, err := s.Repo.GetByMicrochip(number)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID.String() != petID {
+		return ErrMicrochipInUse
+	}
+	return nil
+}
+
+// LookupMicrochip busca la mascota por el número leído en el chip para
+// contactar a su dueño. Como expone los datos de contacto, solo la puede usar
+// el personal de la clínica.
+func (s *PetService) LookupMicrochip(requesterID, number string) (*entities.Pet, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	pet, err := s.Repo.GetByMicrochip(number)
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrMicrochipNotFound
+	}
+	return pet, nil
+}
+
+// checkBreed exige que la raza exista y sea de la especie. Las razas
+// desactivadas no se pueden elegir, pero las mascotas que ya la tienen la
+// conservan.
+func (s *PetService) checkBreed(breedID, speciesID int, chosen bool) (*entities.Breed, error) {
+	breed, err := s.SpeciesRepo.GetBreedByID(breedID)
+	if err != nil {
+		return nil, err
+	}
+	if breed == nil {
+		return nil, ErrBreedNotFound
+	}
+	if breed.SpeciesID != speciesID {
+		return nil, ErrBreedSpeciesMismatch
+	}
+	if chosen && breed.StatusID != 1 {
+		return nil, ErrBreedInactive
+	}
+	return breed, nil
+}
+
+func (s *PetService) DeletePet(requesterID, id string) (string, error) {
+	if _, err := s.GetPetByID(requesterID, id); err != nil {
+		return "", err
+	}
 	newStatus, err := s.Repo.Delete(id)
 	if err != nil {
 		return "", err
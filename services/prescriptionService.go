@@ -0,0 +1,190 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"VetiCare/utils"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPrescriptionNotFound      = errors.New("receta no encontrada")
+	ErrPrescriptionVoided        = errors.New("la receta ya fue anulada")
+	ErrPrescriptionNotAllowed    = errors.New("no se pueden emitir recetas para citas canceladas o sin asistencia")
+	ErrPrescriptionSignerInvalid = errors.New("solo un veterinario activo puede firmar o anular recetas")
+)
+
+type PrescriptionService struct {
+	Repo            repositories.PrescriptionRepository
+	AppointmentRepo repositories.AppointmentRepository
+	UserRepo        repositories.UserRepository
+	PetRepo         repositories.PetRepository
+	Access          *PetAccess
+}
+
+func NewPrescriptionService(repo repositories.PrescriptionRepository, appointmentRepo repositories.AppointmentRepository, userRepo repositories.UserRepository, petRepo repositories.PetRepository, access *PetAccess) *PrescriptionService {
+	return &PrescriptionService{Repo: repo, AppointmentRepo: appointmentRepo, UserRepo: userRepo, PetRepo: petRepo, Access: access}
+}
+
+func (s *PrescriptionService) checkVet(vetID uuid.UUID) error {
+	vet, err := s.UserRepo.GetByID(vetID.String())
+	if err != nil {
+		return err
+	}
+	if vet == nil || vet.RoleID != 2 || vet.StatusID != 1 {
+		return ErrPrescriptionSignerInvalid
+	}
+	return nil
+}
+
+// Create emite una receta para la cita firmada por el veterinario que la
+// registra. La firma es un hash del contenido que permite detectar cambios
+// posteriores en la base de datos.
+func (s *PrescriptionService) Create(p *entities.Prescription) (*entities.Prescription, error) {
+	app, err := s.AppointmentRepo.GetByID(p.AppointmentID.String())
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrAppointmentNotFound
+	}
+	if app.StatusID == entities.AppointmentStatusCancelled || app.StatusID == entities.AppointmentStatusNoShow {
+		return nil, ErrPrescriptionNotAllowed
+	}
+	if err := s.checkVet(p.VetID); err != nil {
+		return nil, err
+	}
+
+	p.PetID = app.PetID
+	p.StatusID = entities.PrescriptionStatusActive
+	p.SignedAt = time.Now().Truncate(time.Second)
+	p.Signature = p.ContentHash()
+	if err := s.Repo.Create(p); err != nil {
+		return nil, err
+	}
+	created, err := s.Repo.GetByID(p.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	created.AllergyWarnings = allergyWarnings(app.Pet.Allergies, p.Items)
+	return created, nil
+}
+
+// allergyWarnings avisa cuando un medicamento coincide con una alergia activa
+// de la mascota. No impide emitir la receta: el veterinario decide.
+func allergyWarnings(allergies []entities.PetAllergy, items []entities.PrescriptionItem) []string {
+	var warnings []string
+	for _, item := range items {
+		drug := utils.NormalizeText(item.Drug)
+		for _, a := range allergies {
+			agent := utils.NormalizeText(a.Agent)
+			if a.StatusID != 1 || agent == "" || drug == "" {
+				continue
+			}
+			if strings.Contains(drug, agent) || strings.Contains(agent, drug) {
+				warnings = append(warnings, fmt.Sprintf("%s coincide con la alergia registrada a %s (severidad %s)", item.Drug, a.Agent, a.Severity))
+			}
+		}
+	}
+	return warnings
+}
+
+// Las recetas solo las ven el dueño de la mascota y el personal de la
+// clínica.
+func (s *PrescriptionService) GetByID(requesterID, id string) (*entities.Prescription, error) {
+	p, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrPrescriptionNotFound
+	}
+	if err := s.Access.CheckPet(requesterID, &p.Pet); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *PrescriptionService) GetByAppointmentID(requesterID, appointmentID string) ([]entities.Prescription, error) {
+	app, err := s.AppointmentRepo.GetByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrAppointmentNotFound
+	}
+	if err := s.Access.CheckPet(requesterID, &app.Pet); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByAppointmentID(appointmentID)
+}
+
+func (s *PrescriptionService) GetByPetID(requesterID, petID string) ([]entities.Prescription, error) {
+	pet, err := s.PetRepo.GetByID(petID)
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	if err := s.Access.CheckPet(requesterID, pet); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByPetID(petID)
+}
+
+func (s *PrescriptionService) Void(id string, voidedBy uuid.UUID, reason string) (*entities.Prescription, error) {
+	p, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrPrescriptionNotFound
+	}
+	if p.StatusID == entities.PrescriptionStatusVoided {
+		return nil, ErrPrescriptionVoided
+	}
+	if err := s.checkVet(voidedBy); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.Void(id, &voidedBy, reason); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByID(id)
+}
+
+func (s *PrescriptionService) GetActiveMedications(petID string, now time.Time) ([]entities.ActiveMedication, error) {
+	prescriptions, err := s.Repo.GetActiveByPetID(petID)
+	if err != nil {
+		return nil, err
+	}
+	return activeMedications(prescriptions, now), nil
+}
+
+// activeMedications devuelve los medicamentos de recetas vigentes cuyo
+// tratamiento aún no termina, ordenados por fecha de término.
+func activeMedications(prescriptions []entities.Prescription, now time.Time) []entities.ActiveMedication {
+	var meds []entities.ActiveMedication
+	for _, p := range prescriptions {
+		if p.StatusID != entities.PrescriptionStatusActive {
+			continue
+		}
+		for _, item := range p.Items {
+			endsOn := item.EndsOn(p.SignedAt)
+			if !endsOn.After(now) {
+				continue
+			}
+			meds = append(meds, entities.ActiveMedication{Item: item, Prescription: p, EndsOn: endsOn})
+		}
+	}
+	sort.SliceStable(meds, func(i, j int) bool {
+		return meds[i].EndsOn.Before(meds[j].EndsOn)
+	})
+	return meds
+}
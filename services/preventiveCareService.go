@@ -0,0 +1,445 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"VetiCare/utils"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrProtocolNotFound     = errors.New("protocolo no encontrado")
+	ErrInvalidProtocolType  = errors.New("el tipo de cuidado debe ser vacuna o desparasitacion")
+	ErrProtocolNeedsVaccine = errors.New("los protocolos de vacunación deben indicar una vacuna activa")
+	ErrDewormingDateFuture  = errors.New("la fecha de desparasitación no puede ser futura")
+)
+
+type PreventiveCareService struct {
+	Repo            repositories.PreventiveCareRepository
+	VaccinationRepo repositories.VaccinationRepository
+	PetRepo         repositories.PetRepository
+	UserRepo        repositories.UserRepository
+	Access          *PetAccess
+}
+
+func NewPreventiveCareService(repo repositories.PreventiveCareRepository, vaccinationRepo repositories.VaccinationRepository, petRepo repositories.PetRepository, userRepo repositories.UserRepository, access *PetAccess) *PreventiveCareService {
+	return &PreventiveCareService{Repo: repo, VaccinationRepo: vaccinationRepo, PetRepo: petRepo, UserRepo: userRepo, Access: access}
+}
+
+// RecordDeworming guarda una desparasitación. Si no se indica la próxima
+// fecha se calcula con el protocolo de la especie que corresponde a la edad
+// de la mascota o, si no hay uno, con DEWORMING_INTERVAL_DAYS. Solo el
+// personal de la clínica registra desparasitaciones.
+func (s *PreventiveCareService) RecordDeworming(requesterID string, d *entities.Deworming) (*entities.Deworming, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	pet, err := s.PetRepo.GetByID(d.PetID.String())
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	if d.DateGiven.After(time.Now()) {
+		return nil, ErrDewormingDateFuture
+	}
+	if d.AdministeredByID != nil {
+		vet, err := s.UserRepo.GetByID(d.AdministeredByID.String())
+		if err != nil {
+			return nil, err
+		}
+		if vet == nil || vet.RoleID != 2 || vet.StatusID != 1 {
+			return nil, ErrAdministeringVetInvalid
+		}
+	}
+	if d.NextDueDate == nil {
+		interval := utils.GetEnvInt("DEWORMING_INTERVAL_DAYS", 90)
+		protocols, err := s.Repo.GetProtocols(&pet.SpeciesID, true)
+		if err != nil {
+			return nil, err
+		}
+		if age, ok := pet.AgeInWeeks(d.DateGiven); ok {
+			for _, p := range protocols {
+				if p.CareType == entities.CareTypeDeworming && p.AppliesTo(age) {
+					interval = p.IntervalDays
+					break
+				}
+			}
+		}
+		next := d.DateGiven.AddDate(0, 0, interval)
+		d.NextDueDate = &next
+	}
+	if !d.NextDueDate.After(d.DateGiven) {
+		return nil, ErrNextDueBeforeGiven
+	}
+	if err := s.Repo.CreateDeworming(d); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetDewormingByID(d.ID.String())
+}
+
+func (s *PreventiveCareService) GetDewormingsByPet(requesterID, petID string) ([]entities.Deworming, error) {
+	pet, err := s.PetRepo.GetByID(petID)
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	if err := s.Access.CheckPet(requesterID, pet); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetDewormingsByPet(petID)
+}
+
+func (s *PreventiveCareService) GetProtocols(speciesID *int, onlyActive bool) ([]entities.PreventiveProtocol, error) {
+	return s.Repo.GetProtocols(speciesID, onlyActive)
+}
+
+func (s *PreventiveCareService) GetProtocolByID(id int) (*entities.PreventiveProtocol, error) {
+	return s.Repo.GetProtocolByID(id)
+}
+
+func (s *PreventiveCareService) CreateProtocol(p *entities.PreventiveProtocol) (*entities.PreventiveProtocol, error) {
+	if err := s.checkProtocol(p.CareType, p.VaccineID); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateProtocol(p); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetProtocolByID(p.ID)
+}
+
+func (s *PreventiveCareService) UpdateProtocol(id int, fields map[string]interface{}) (*entities.PreventiveProtocol, error) {
+	current, err := s.Repo.GetProtocolByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, ErrProtocolNotFound
+	}
+	careType := current.CareType
+	if v, ok := fields["care_type"].(string); ok {
+		careType = v
+	}
+	vaccineID := current.VaccineID
+	if v, ok := fields["vaccine_id"]; ok {
+		if vaccineID, err = intField(v); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkProtocol(careType, vaccineID); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateProtocol(id, fields); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetProtocolByID(id)
+}
+
+func (s *PreventiveCareService) DeleteProtocol(id int) (string, error) {
+	newStatus, err := s.Repo.DeleteProtocol(id)
+	if err != nil {
+		return "", err
+	}
+	if newStatus == 1 {
+		return "Protocolo activado correctamente", nil
+	}
+	return "Protocolo desactivado correctamente", nil
+}
+
+func (s *PreventiveCareService) checkProtocol(careType string, vaccineID *int) error {
+	switch careType {
+	case entities.CareTypeDeworming:
+		return nil
+	case entities.CareTypeVaccination:
+		if vaccineID == nil {
+			return ErrProtocolNeedsVaccine
+		}
+		vaccine, err := s.VaccinationRepo.GetVaccineByID(*vaccineID)
+		if err != nil {
+			return err
+		}
+		if vaccine == nil || vaccine.StatusID != 1 {
+			return ErrProtocolNeedsVaccine
+		}
+		return nil
+	}
+	return ErrInvalidProtocolType
+}
+
+// GetWorklist es la lista de trabajo del personal: los cuidados pendientes
+// con los datos de contacto de cada dueño.
+func (s *PreventiveCareService) GetWorklist(requesterID string, now time.Time, windowDays int) ([]entities.CareDueItem, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	return s.GetDueItems(now, windowDays)
+}
+
+// GetDueItems arma la lista de cuidados preventivos que vencen en los
+// próximos windowDays días o que vencieron en los últimos
+// REMINDER_OVERDUE_DAYS. Incluye las dosis de refuerzo según la última
+// aplicación registrada y, para las mascotas que nunca recibieron un cuidado
+// de su protocolo, la primera dosis según su edad.
+func (s *PreventiveCareService) GetDueItems(now time.Time, windowDays int) ([]entities.CareDueItem, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := today.AddDate(0, 0, -utils.GetEnvInt("REMINDER_OVERDUE_DAYS", 60))
+	until := today.AddDate(0, 0, windowDays)
+
+	var items []entities.CareDueItem
+	vaccinations, err := s.VaccinationRepo.GetDue(entities.VaccinationDueFilter{From: from, To: until})
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range vaccinations {
+		given := v.DateGiven
+		items = append(items, entities.CareDueItem{
+			Pet:         v.Pet,
+			CareType:    entities.CareTypeVaccination,
+			CareName:    v.Vaccine.Name,
+			ReferenceID: v.ID.String(),
+			LastGiven:   &given,
+			DueDate:     *v.NextDueDate,
+		})
+	}
+
+	dewormings, err := s.Repo.GetLatestDewormings(from, until)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range dewormings {
+		given := d.DateGiven
+		items = append(items, entities.CareDueItem{
+			Pet:         d.Pet,
+			CareType:    entities.CareTypeDeworming,
+			CareName:    "Desparasitación",
+			ReferenceID: d.ID.String(),
+			LastGiven:   &given,
+			DueDate:     *d.NextDueDate,
+		})
+	}
+
+	firstDoses, err := s.firstDoseItems(now, until)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, firstDoses...)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DueDate.Before(items[j].DueDate)
+	})
+	if err := s.attachLastReminders(items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// firstDoseItems busca mascotas activas con fecha de nacimiento a las que les
+// toca la primera dosis de un protocolo de su especie y que no tienen ningún
+// registro de ese cuidado.
+func (s *PreventiveCareService) firstDoseItems(now, until time.Time) ([]entities.CareDueItem, error) {
+	protocols, err := s.Repo.GetProtocols(nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(protocols) == 0 {
+		return nil, nil
+	}
+	pets, err := s.PetRepo.GetActivePets()
+	if err != nil {
+		return nil, err
+	}
+	dewormedIDs, err := s.Repo.GetPetIDsWithDeworming()
+	if err != nil {
+		return nil, err
+	}
+	dewormed := toIDSet(dewormedIDs)
+	vaccinated := map[int]map[uuid.UUID]bool{}
+
+	var items []entities.CareDueItem
+	for _, pet := range pets {
+		age, ok := pet.AgeInWeeks(now)
+		if !ok {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, p := range protocols {
+			if p.SpeciesID != pet.SpeciesID {
+				continue
+			}
+			if p.MaxAgeWeeks != nil && age >= *p.MaxAgeWeeks {
+				continue
+			}
+			due := pet.BirthDate.AddDate(0, 0, p.MinAgeWeeks*7)
+			if due.After(until) {
+				continue
+			}
+
+			careKey := p.CareType
+			switch p.CareType {
+			case entities.CareTypeDeworming:
+				if dewormed[pet.ID] {
+					continue
+				}
+			case entities.CareTypeVaccination:
+				if p.VaccineID == nil {
+					continue
+				}
+				if _, ok := vaccinated[*p.VaccineID]; !ok {
+					ids, err := s.Repo.GetPetIDsWithVaccine(*p.VaccineID)
+					if err != nil {
+						return nil, err
+					}
+					vaccinated[*p.VaccineID] = toIDSet(ids)
+				}
+				if vaccinated[*p.VaccineID][pet.ID] {
+					continue
+				}
+				careKey = fmt.Sprintf("%s-%d", p.CareType, *p.VaccineID)
+			}
+			if seen[careKey] {
+				continue
+			}
+			seen[careKey] = true
+
+			items = append(items, entities.CareDueItem{
+				Pet:         pet,
+				CareType:    p.CareType,
+				CareName:    p.Name,
+				ReferenceID: fmt.Sprintf("protocolo-%d", p.ID),
+				DueDate:     due,
+			})
+		}
+	}
+	return items, nil
+}
+
+func (s *PreventiveCareService) attachLastReminders(items []entities.CareDueItem) error {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, item.ReminderKey())
+	}
+	reminders, err := s.Repo.GetRemindersByKeys(keys)
+	if err != nil {
+		return err
+	}
+	sent := map[string]time.Time{}
+	for _, r := range reminders {
+		sent[r.Key] = r.SentAt
+	}
+	for i := range items {
+		if at, ok := sent[items[i].ReminderKey()]; ok {
+			items[i].LastReminderAt = &at
+		}
+	}
+	return nil
+}
+
+// SendDueReminders envía un correo por dueño con los cuidados pendientes de
+// sus mascotas que aún no fueron avisados, con un enlace para agendar la
+// cita (BOOKING_URL). Cada aviso se registra para no repetirlo.
+func (s *PreventiveCareService) SendDueReminders(now time.Time) (int, error) {
+	items, err := s.GetDueItems(now, utils.GetEnvInt("REMINDER_WINDOW_DAYS", 14))
+	if err != nil {
+		return 0, err
+	}
+
+	byOwner := map[string][]entities.CareDueItem{}
+	var order []string
+	for _, item := range items {
+		owner := item.Pet.Owner
+		if item.LastReminderAt != nil || owner.Email == "" || owner.StatusID != 1 {
+			continue
+		}
+		if _, ok := byOwner[owner.Email]; !ok {
+			order = append(order, owner.Email)
+		}
+		byOwner[owner.Email] = append(byOwner[owner.Email], item)
+	}
+
+	sent := 0
+	for _, email := range order {
+		ownerItems := byOwner[email]
+		if err := utils.SendMail(email, "Cuidados preventivos pendientes en PetVet", reminderBody(ownerItems, now)); err != nil {
+			log.Println("Error enviando recordatorio de cuidados:", err)
+			continue
+		}
+		var reminders []entities.CareReminder
+		for _, item := range ownerItems {
+			reminders = append(reminders, entities.CareReminder{
+				Key:      item.ReminderKey(),
+				PetID:    item.Pet.ID,
+				CareType: item.CareType,
+				CareName: item.CareName,
+				DueDate:  item.DueDate,
+				Email:    email,
+			})
+		}
+		if err := s.Repo.CreateReminders(reminders); err != nil {
+			return sent, err
+		}
+		sent += len(reminders)
+	}
+	return sent, nil
+}
+
+func (s *PreventiveCareService) StartReminderJob(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if count, err := s.SendDueReminders(time.Now()); err != nil {
+			log.Println("Error enviando recordatorios de cuidados:", err)
+		} else if count > 0 {
+			log.Printf("Se enviaron %d recordatorios de cuidados preventivos\n", count)
+		}
+		<-ticker.C
+	}
+}
+
+func reminderBody(items []entities.CareDueItem, now time.Time) string {
+	var lines []string
+	for _, item := range items {
+		when := "vence el " + item.DueDate.Format("02-01-2006")
+		if item.DueDate.Before(now) {
+			when = "venció el " + item.DueDate.Format("02-01-2006")
+		}
+		line := fmt.Sprintf("- %s: %s, %s.", item.Pet.Name, item.CareName, when)
+		if link := bookingLink(item.Pet.ID); link != "" {
+			line += " Agende su cita en " + link
+		}
+		lines = append(lines, line)
+	}
+	return fmt.Sprintf(
+		"Hola %s,\n\nSus mascotas tienen los siguientes cuidados preventivos pendientes:\n\n%s\n\nSaludos.",
+		items[0].Pet.Owner.FullName, strings.Join(lines, "\n"),
+	)
+}
+
+func bookingLink(petID uuid.UUID) string {
+	base := utils.GetEnv("BOOKING_URL", "")
+	if base == "" {
+		return ""
+	}
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+	}
+	return base + separator + "pet_id=" + petID.String()
+}
+
+func toIDSet(ids []uuid.UUID) map[uuid.UUID]bool {
+	set := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
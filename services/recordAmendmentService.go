@@ -0,0 +1,186 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"VetiCare/utils"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAmendmentNotFinished    = errors.New("solo se pueden enmendar citas finalizadas")
+	ErrAmendmentNoChanges      = errors.New("la enmienda no cambia ningún dato clínico")
+	ErrAmendmentNoClinicalNote = errors.New("la cita no tiene nota clínica para enmendar")
+	ErrAmendmentConflict       = errors.New("otra enmienda se guardó al mismo tiempo; vuelva a cargar la cita e intente de nuevo")
+	ErrRecordVersionNotFound   = errors.New("versión del registro no encontrada")
+)
+
+// noteAmendmentFields son los campos de la nota clínica que admite una
+// enmienda; el resto de campos clínicos pertenecen a la cita.
+var noteAmendmentFields = []string{"subjective", "objective", "assessment", "plan", "diagnosis_codes"}
+
+// RecordAmendmentService corrige los datos clínicos de citas finalizadas sin
+// perder lo que se registró antes: cada corrección es una versión nueva.
+type RecordAmendmentService struct {
+	AppointmentRepo  repositories.AppointmentRepository
+	ClinicalNoteRepo repositories.ClinicalNoteRepository
+	Access           *PetAccess
+}
+
+func NewRecordAmendmentService(appointmentRepo repositories.AppointmentRepository, clinicalNoteRepo repositories.ClinicalNoteRepository, access *PetAccess) *RecordAmendmentService {
+	return &RecordAmendmentService{AppointmentRepo: appointmentRepo, ClinicalNoteRepo: clinicalNoteRepo, Access: access}
+}
+
+func (s *RecordAmendmentService) getAppointment(id string) (*entities.Appointment, error) {
+	app, err := s.AppointmentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrAppointmentNotFound
+	}
+	note, err := s.ClinicalNoteRepo.GetByAppointmentID(id)
+	if err != nil {
+		return nil, err
+	}
+	app.ClinicalNote = note
+	return app, nil
+}
+
+// Amend guarda una versión nueva con los cambios indicados y la deja como
+// vigente. changes usa los nombres de columna; los textos son string, el peso
+// y la temperatura float64 y diagnosis_codes los códigos ya unidos por comas.
+// En la primera enmienda también se guarda el registro original como versión 1.
+func (s *RecordAmendmentService) Amend(requesterID, appointmentID, reason string, changes map[string]interface{}) (*entities.AppointmentRecordVersion, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	author, err := s.Access.StaffName(requesterID)
+	if err != nil {
+		return nil, err
+	}
+	app, err := s.getAppointment(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if app.StatusID != entities.AppointmentStatusFinished {
+		return nil, ErrAmendmentNotFinished
+	}
+
+	current := entities.NewOriginalRecordVersion(app)
+	var original *entities.AppointmentRecordVersion
+	if app.RecordVersion <= 1 {
+		original = &current
+	}
+
+	amendment := current
+	amendment.ID = uuid.Nil
+	amendment.Version = app.RecordVersion + 1
+	amendment.AuthorName = author
+	amendment.AmendmentReason = reason
+	amendment.CreatedAt = time.Now()
+	amendment.AuthorID = nil
+	if id, err := uuid.Parse(requesterID); err == nil {
+		amendment.AuthorID = &id
+	}
+
+	if !amendment.HasClinicalNote {
+		for _, key := range noteAmendmentFields {
+			if _, ok := changes[key]; ok {
+				return nil, ErrAmendmentNoClinicalNote
+			}
+		}
+	}
+	changed := false
+	setText := func(dst *string, key string) {
+		if v, ok := changes[key].(string); ok && v != *dst {
+			*dst = v
+			changed = true
+		}
+	}
+	setNumber := func(dst **float64, key string) {
+		if v, ok := changes[key].(float64); ok && (*dst == nil || **dst != v) {
+			*dst = &v
+			changed = true
+		}
+	}
+	setText(&amendment.Reason, "reason")
+	setNumber(&amendment.WeightKg, "weight_kg")
+	setNumber(&amendment.Temperature, "temperature")
+	setText(&amendment.VaccinationStatus, "vaccination_status")
+	setText(&amendment.MedicationsPrescribed, "medications_prescribed")
+	setText(&amendment.AdditionalNotes, "additional_notes")
+	setText(&amendment.Subjective, "subjective")
+	setText(&amendment.Objective, "objective")
+	setText(&amendment.Assessment, "assessment")
+	setText(&amendment.Plan, "plan")
+	setText(&amendment.DiagnosisCodes, "diagnosis_codes")
+	if !changed {
+		return nil, ErrAmendmentNoChanges
+	}
+
+	amended := *app
+	amended.WeightKg = amendment.WeightKg
+	amended.Temperature = amendment.Temperature
+	at := app.CreatedAt
+	if t, err := time.ParseInLocation(utils.AppointmentDateLayout, app.Date, time.Local); err == nil {
+		at = t
+	}
+	if err := checkPlausibleVitals(&app.Pet, at, amended.VitalValues()); err != nil {
+		return nil, err
+	}
+
+	applied, err := s.AppointmentRepo.AmendRecord(original, &amendment)
+	if err != nil {
+		return nil, err
+	}
+	if !applied {
+		return nil, ErrAmendmentConflict
+	}
+	return &amendment, nil
+}
+
+// GetVersions devuelve las versiones del registro de la más reciente a la más
+// antigua. Si la cita nunca se enmendó, la única versión es la actual.
+func (s *RecordAmendmentService) GetVersions(requesterID, appointmentID string) ([]entities.AppointmentRecordVersion, error) {
+	app, err := s.getAppointment(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Access.CheckPet(requesterID, &app.Pet); err != nil {
+		return nil, err
+	}
+	versions, err := s.AppointmentRepo.GetRecordVersions(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		versions = []entities.AppointmentRecordVersion{entities.NewOriginalRecordVersion(app)}
+	}
+	return versions, nil
+}
+
+func (s *RecordAmendmentService) GetVersion(requesterID, appointmentID string, version int) (*entities.AppointmentRecordVersion, error) {
+	app, err := s.getAppointment(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Access.CheckPet(requesterID, &app.Pet); err != nil {
+		return nil, err
+	}
+	if version == 1 && app.RecordVersion <= 1 {
+		original := entities.NewOriginalRecordVersion(app)
+		return &original, nil
+	}
+	v, err := s.AppointmentRepo.GetRecordVersion(appointmentID, version)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, ErrRecordVersionNotFound
+	}
+	return v, nil
+}
@@ -0,0 +1,618 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"VetiCare/repositories"
+	"VetiCare/utils"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrImportSpeciesUnknown = errors.New("la especie de la mascota no existe en el catálogo")
+	ErrImportOwnerEmail     = errors.New("el dueño no está registrado y el Bundle no trae su correo para crearlo")
+	ErrImportOwnerEmailUsed = errors.New("el correo del dueño ya está registrado con otro DUI")
+	ErrImportMicrochipUsed  = errors.New("el microchip ya está registrado en otra mascota de otro dueño o especie")
+)
+
+// RecordInterchangeService exporta e importa el historial completo de una
+// mascota en el formato de intercambio descrito en dto/fhirDto.go, para que
+// no haya que volver a digitarlo cuando la mascota cambia de clínica.
+type RecordInterchangeService struct {
+	Repo            repositories.RecordInterchangeRepository
+	PetRepo         repositories.PetRepository
+	UserRepo        repositories.UserRepository
+	SpeciesRepo     repositories.SpeciesRepository
+	AppointmentRepo repositories.AppointmentRepository
+	VitalSignRepo   repositories.VitalSignRepository
+	VaccinationRepo repositories.VaccinationRepository
+	Access          *PetAccess
+}
+
+func NewRecordInterchangeService(repo repositories.RecordInterchangeRepository, petRepo repositories.PetRepository, userRepo repositories.UserRepository, speciesRepo repositories.SpeciesRepository, appointmentRepo repositories.AppointmentRepository, vitalSignRepo repositories.VitalSignRepository, vaccinationRepo repositories.VaccinationRepository, access *PetAccess) *RecordInterchangeService {
+	return &RecordInterchangeService{
+		Repo:            repo,
+		PetRepo:         petRepo,
+		UserRepo:        userRepo,
+		SpeciesRepo:     speciesRepo,
+		AppointmentRepo: appointmentRepo,
+		VitalSignRepo:   vitalSignRepo,
+		VaccinationRepo: vaccinationRepo,
+		Access:          access,
+	}
+}
+
+// Export reúne el historial de la mascota. Igual que el PDF, solo lo emite el
+// personal de la clínica.
+func (s *RecordInterchangeService) Export(requesterID, petID string) (*entities.PetRecordExport, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	pet, err := s.PetRepo.GetByID(petID)
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	export := &entities.PetRecordExport{Pet: pet}
+	if export.Appointments, err = s.AppointmentRepo.GetMedicalHistoryByPetID(petID); err != nil {
+		return nil, err
+	}
+	sortAppointments(export.Appointments)
+	if export.VitalSigns, err = s.VitalSignRepo.GetByPetID(petID); err != nil {
+		return nil, err
+	}
+	if export.Vaccinations, err = s.VaccinationRepo.GetByPetID(petID); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// recordImporter arma la importación recurso por recurso y lleva la cuenta
+// de lo que se crea y lo que se omite.
+type recordImporter struct {
+	imp    *entities.RecordImport
+	result *entities.RecordImportResult
+
+	encounters     map[string]uuid.UUID
+	newEncounters  map[uuid.UUID]*entities.Appointment
+	existingVisits map[string]uuid.UUID
+	existingVitals map[string]bool
+	existingShots  map[string]bool
+	doseCounts     map[int]int
+}
+
+func (ri *recordImporter) warn(format string, args ...interface{}) {
+	ri.result.Warnings = append(ri.result.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Import guarda el historial del Bundle. El dueño se busca por DUI y la
+// mascota entre las del dueño por nombre y especie; si no existen se crean.
+// Las citas, signos vitales y vacunas que ya estaban registrados se omiten,
+// así que importar dos veces el mismo archivo no duplica nada. Con dryRun solo
+// se informa lo que se haría.
+func (s *RecordInterchangeService) Import(requesterID string, rec *dto.FHIRRecord, dryRun bool) (*entities.RecordImportResult, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	ri := &recordImporter{
+		imp:            &entities.RecordImport{},
+		result:         &entities.RecordImportResult{DryRun: dryRun},
+		encounters:     map[string]uuid.UUID{},
+		newEncounters:  map[uuid.UUID]*entities.Appointment{},
+		existingVisits: map[string]uuid.UUID{},
+		existingVitals: map[string]bool{},
+		existingShots:  map[string]bool{},
+		doseCounts:     map[int]int{},
+	}
+	for resourceType, count := range rec.Ignored {
+		ri.warn("se ignoraron %d recursos %s", count, resourceType)
+	}
+
+	if err := s.resolveOwner(ri, rec); err != nil {
+		return nil, err
+	}
+	if err := s.resolvePet(ri, rec.Patients[0]); err != nil {
+		return nil, err
+	}
+	ri.importEncounters(rec.Encounters)
+	ri.importMedications(rec.MedicationRequests)
+	ri.importObservations(rec.Observations)
+	vaccines, err := s.VaccinationRepo.GetVaccines(true)
+	if err != nil {
+		return nil, err
+	}
+	ri.importImmunizations(rec.Immunizations, vaccines)
+
+	imp := ri.imp
+	ri.result.OwnerID = imp.Owner.ID.String()
+	ri.result.OwnerCreated = imp.OwnerCreated
+	ri.result.PetID = imp.Pet.ID.String()
+	ri.result.PetCreated = imp.PetCreated
+	if dryRun {
+		return ri.result, nil
+	}
+	if err := s.Repo.SaveImport(imp); err != nil {
+		return nil, err
+	}
+	if imp.OwnerCreated {
+		notifyImportedOwner(imp.Owner, imp.OwnerPassword)
+	}
+	return ri.result, nil
+}
+
+func (s *RecordInterchangeService) resolveOwner(ri *recordImporter, rec *dto.FHIRRecord) error {
+	var person dto.FHIRRelatedPerson
+	for _, p := range rec.RelatedPersons {
+		if p.IsOwner() {
+			person = p
+			break
+		}
+	}
+	dui := person.IdentifierValue(dto.FHIRSystemDUI)
+	owner, err := s.UserRepo.GetByDUI(dui)
+	if err != nil {
+		return err
+	}
+	if owner != nil {
+		ri.imp.Owner = owner
+		if utils.NormalizeText(owner.FullName) != utils.NormalizeText(person.DisplayName()) {
+			ri.warn("el DUI %s ya está registrado a nombre de %s; se usó ese dueño", dui, owner.FullName)
+		}
+		return nil
+	}
+
+	email := person.TelecomValue("email")
+	if email == "" {
+		return ErrImportOwnerEmail
+	}
+	byEmail, err := s.UserRepo.GetByEmail(email)
+	if err != nil {
+		return err
+	}
+	if byEmail != nil {
+		return ErrImportOwnerEmailUsed
+	}
+	password := utils.GenerateRandomPassword(8)
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	ri.imp.Owner = &entities.User{
+		ID:           uuid.New(),
+		FullName:     person.DisplayName(),
+		DUI:          dui,
+		Phone:        person.TelecomValue("phone"),
+		Email:        email,
+		PasswordHash: hash,
+		RoleID:       1,
+		StatusID:     1,
+	}
+	ri.imp.OwnerCreated = true
+	ri.imp.OwnerPassword = password
+	return nil
+}
+
+func (s *RecordInterchangeService) resolvePet(ri *recordImporter, patient dto.FHIRPatient) error {
+	speciesName := utils.NormalizeText(patient.AnimalDetail("species"))
+	catalog, err := s.SpeciesRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	var species *entities.Species
+	for i := range catalog {
+		if utils.NormalizeText(catalog[i].Name) == speciesName {
+			species = &catalog[i]
+			break
+		}
+	}
+	if species == nil {
+		return ErrImportSpeciesUnknown
+	}
+
+	// El microchip identifica a la mascota mejor que el nombre: si ya está
+	// registrado, tiene que ser la misma mascota del mismo dueño.
+	chip := utils.NormalizeMicrochip(patient.IdentifierValue(dto.FHIRSystemMicrochip))
+	if chip != "" {
+		existing, err := s.PetRepo.GetByMicrochip(chip)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			if existing.OwnerID != ri.imp.Owner.ID || existing.SpeciesID != species.ID {
+				return ErrImportMicrochipUsed
+			}
+			ri.imp.Pet = existing
+			return s.loadExisting(ri)
+		}
+	}
+
+	name := patient.DisplayName()
+	if !ri.imp.OwnerCreated {
+		pets, err := s.PetRepo.GetPetsByOwner(ri.imp.Owner.ID.String())
+		if err != nil {
+			return err
+		}
+		for i := range pets {
+			if pets[i].SpeciesID == species.ID && utils.NormalizeText(pets[i].Name) == utils.NormalizeText(name) {
+				if chip != "" {
+					if pets[i].Microchip != nil {
+						// Otro chip: no es la misma mascota aunque se llame igual.
+						continue
+					}
+					ri.warn("la mascota %s ya existía sin microchip; el microchip %s no se registró", name, chip)
+				}
+				ri.imp.Pet = &pets[i]
+				return s.loadExisting(ri)
+			}
+		}
+	}
+
+	pet := &entities.Pet{
+		ID:        uuid.New(),
+		OwnerID:   ri.imp.Owner.ID,
+		Name:      name,
+		SpeciesID: species.ID,
+		StatusID:  1,
+	}
+	if patient.Active != nil && !*patient.Active {
+		pet.StatusID = 2
+	}
+	if chip != "" {
+		pet.Microchip = &chip
+	}
+	if patient.BirthDate != "" {
+		birth, _ := dto.ParseFHIRDateTime(patient.BirthDate)
+		pet.BirthDate = &birth
+	}
+	if breed := patient.AnimalDetail("breed"); breed != "" {
+		// El texto original se conserva y la raza del catálogo solo se asocia
+		// si coincide exactamente; una coincidencia aproximada queda como
+		// sugerencia para revisarla a mano.
+		pet.Breed = &breed
+		breeds, err := s.SpeciesRepo.GetBreeds(species.ID, true)
+		if err != nil {
+			return err
+		}
+		match, exact := matchBreed(breed, breeds)
+		switch {
+		case match != nil && exact:
+			pet.BreedID = &match.ID
+		case match != nil:
+			ri.warn("la raza %q no se asoció al catálogo; posible coincidencia: %s", breed, match.Name)
+		}
+	}
+	ri.imp.Pet = pet
+	ri.imp.PetCreated = true
+	return nil
+}
+
+// loadExisting carga lo que la mascota ya tiene registrado para omitirlo al
+// importar.
+func (s *RecordInterchangeService) loadExisting(ri *recordImporter) error {
+	petID := ri.imp.Pet.ID.String()
+	apps, err := s.AppointmentRepo.GetMedicalHistoryByPetID(petID)
+	if err != nil {
+		return err
+	}
+	for _, app := range apps {
+		ri.existingVisits[app.Date+" "+app.Time] = app.ID
+	}
+	vitals, err := s.VitalSignRepo.GetByPetID(petID)
+	if err != nil {
+		return err
+	}
+	for _, v := range vitals {
+		ri.existingVitals[v.RecordedAt.Format("2006-01-02 15:04")] = true
+	}
+	vaccinations, err := s.VaccinationRepo.GetByPetID(petID)
+	if err != nil {
+		return err
+	}
+	for _, v := range vaccinations {
+		ri.existingShots[vaccinationKey(v.VaccineID, v.DateGiven)] = true
+		if v.DoseNumber > ri.doseCounts[v.VaccineID] {
+			ri.doseCounts[v.VaccineID] = v.DoseNumber
+		}
+	}
+	return nil
+}
+
+func vaccinationKey(vaccineID int, date time.Time) string {
+	return fmt.Sprintf("%d|%s", vaccineID, date.Format("2006-01-02"))
+}
+
+// truncateRunes corta el texto al largo de la columna sin partir caracteres.
+func truncateRunes(s string, max int) (string, bool) {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s, false
+	}
+	return string(runes[:max]), true
+}
+
+func (ri *recordImporter) importEncounters(list []dto.FHIREncounter) {
+	for _, e := range list {
+		if e.Status != "finished" {
+			ri.result.EncountersSkipped++
+			ri.warn("Encounter %s omitido: solo se importan visitas finalizadas (estado %q)", e.ID, e.Status)
+			continue
+		}
+		start, _ := dto.ParseFHIRDateTime(e.Period.Start)
+		date := start.Format(utils.AppointmentDateLayout)
+		clock := start.Format(utils.AppointmentTimeLayout)
+		if id, ok := ri.existingVisits[date+" "+clock]; ok {
+			ri.encounters[e.ID] = id
+			ri.result.EncountersSkipped++
+			continue
+		}
+
+		var notes []string
+		provider, vet := "", ""
+		if e.ServiceProvider != nil {
+			provider = e.ServiceProvider.Display
+		}
+		if len(e.Participant) > 0 {
+			vet = e.Participant[0].Individual.Display
+		}
+		notes = append(notes, importOrigin(vet, provider))
+		var diagnoses []string
+		for _, reason := range e.ReasonCode {
+			for _, c := range reason.Coding {
+				if c.System == dto.FHIRSystemDiagnosis {
+					diagnoses = append(diagnoses, strings.TrimSpace(c.Code+" "+c.Display))
+				}
+			}
+		}
+		if len(diagnoses) > 0 {
+			notes = append(notes, "Diagnósticos: "+strings.Join(diagnoses, ", ")+".")
+		}
+		if text := e.Notes(); text != "" {
+			notes = append(notes, text)
+		}
+		reason, cut := truncateRunes(e.Reason(), 300)
+		if cut {
+			ri.warn("Encounter %s: el motivo se recortó a 300 caracteres", e.ID)
+		}
+		additional, cut := truncateRunes(strings.Join(notes, " "), 500)
+		if cut {
+			ri.warn("Encounter %s: las notas se recortaron a 500 caracteres", e.ID)
+		}
+
+		finished := start
+		if end, err := dto.ParseFHIRDateTime(e.Period.End); err == nil {
+			finished = end
+		}
+		app := entities.Appointment{
+			ID:              uuid.New(),
+			PetID:           ri.imp.Pet.ID,
+			Date:            date,
+			Time:            clock,
+			StatusID:        entities.AppointmentStatusFinished,
+			Reason:          reason,
+			AdditionalNotes: additional,
+			StartedAt:       &start,
+			FinishedAt:      &finished,
+		}
+		ri.imp.Appointments = append(ri.imp.Appointments, app)
+		ri.encounters[e.ID] = app.ID
+		ri.existingVisits[date+" "+clock] = app.ID
+		ri.result.EncountersCreated++
+	}
+	// Los punteros se toman al final porque append puede mover el arreglo.
+	for i := range ri.imp.Appointments {
+		ri.newEncounters[ri.imp.Appointments[i].ID] = &ri.imp.Appointments[i]
+	}
+}
+
+func importOrigin(vet, provider string) string {
+	origin := "Historial importado"
+	if provider != "" {
+		origin += " de " + provider
+	}
+	if vet != "" {
+		origin += ", atendido por " + vet
+	}
+	return origin + "."
+}
+
+// importMedications guarda los medicamentos como texto en la cita importada:
+// las recetas de VetiCare necesitan la firma de uno de sus veterinarios y no
+// se pueden emitir a nombre de otra clínica.
+func (ri *recordImporter) importMedications(list []dto.FHIRMedicationRequest) {
+	byEncounter := map[uuid.UUID][]string{}
+	var order []uuid.UUID
+	for _, m := range list {
+		text := m.Text()
+		if text == "" {
+			ri.result.MedicationsSkipped++
+			ri.warn("MedicationRequest %s omitido: no indica el medicamento", m.ID)
+			continue
+		}
+		if m.Encounter == nil {
+			ri.result.MedicationsSkipped++
+			ri.warn("MedicationRequest %s omitido: no está asociado a una visita", m.ID)
+			continue
+		}
+		id, ok := ri.encounters[dto.FHIRReferenceID(m.Encounter.Reference)]
+		if !ok || ri.newEncounters[id] == nil {
+			ri.result.MedicationsSkipped++
+			continue
+		}
+		if _, seen := byEncounter[id]; !seen {
+			order = append(order, id)
+		}
+		byEncounter[id] = append(byEncounter[id], text)
+		ri.result.MedicationsImported++
+	}
+	for _, id := range order {
+		app := ri.newEncounters[id]
+		text, cut := truncateRunes(strings.Join(byEncounter[id], "; "), 300)
+		if cut {
+			ri.warn("los medicamentos de la visita del %s se recortaron a 300 caracteres", app.Date)
+		}
+		app.MedicationsPrescribed = text
+	}
+}
+
+// importObservations agrupa en un solo registro de signos vitales las
+// observaciones de la misma visita y el mismo momento.
+func (ri *recordImporter) importObservations(list []dto.FHIRObservation) {
+	groups := map[string]*entities.VitalSign{}
+	var order []string
+	for _, o := range list {
+		code := o.VitalCode()
+		recordedAt, err := dto.ParseFHIRDateTime(o.EffectiveDateTime)
+		if code == "" || o.ValueQuantity == nil || err != nil {
+			ri.result.ObservationsSkipped++
+			ri.warn("Observation %s omitida: no es un signo vital reconocido o le falta el valor o la fecha", o.ID)
+			continue
+		}
+		minute := recordedAt.Format("2006-01-02 15:04")
+		var appointmentID *uuid.UUID
+		if o.Encounter != nil {
+			if id, ok := ri.encounters[dto.FHIRReferenceID(o.Encounter.Reference)]; ok {
+				if ri.newEncounters[id] == nil {
+					ri.result.ObservationsSkipped++
+					continue
+				}
+				appointmentID = &id
+			}
+		}
+		if ri.existingVitals[minute] {
+			ri.result.ObservationsSkipped++
+			continue
+		}
+		key := minute
+		if appointmentID != nil {
+			key = appointmentID.String() + "|" + minute
+		}
+		vital, ok := groups[key]
+		if !ok {
+			vital = &entities.VitalSign{
+				ID:            uuid.New(),
+				PetID:         ri.imp.Pet.ID,
+				AppointmentID: appointmentID,
+				RecordedAt:    recordedAt,
+				Notes:         "Importado de otro historial.",
+			}
+			groups[key] = vital
+			order = append(order, key)
+		}
+		if !dto.FHIRVitalValue(vital, code, o.ValueQuantity.Value) {
+			ri.result.ObservationsSkipped++
+			continue
+		}
+		ri.result.ObservationsCreated++
+	}
+	for _, key := range order {
+		ri.imp.VitalSigns = append(ri.imp.VitalSigns, *groups[key])
+	}
+}
+
+// matchVaccine busca la vacuna en el catálogo por nombre exacto y, si no, por
+// los alias que se usan en la migración del texto libre.
+func matchVaccine(text string, speciesID int, vaccines []entities.Vaccine) *entities.Vaccine {
+	normalized := utils.NormalizeText(text)
+	var byKeyword *entities.Vaccine
+	for i := range vaccines {
+		v := &vaccines[i]
+		if v.SpeciesID != nil && *v.SpeciesID != speciesID {
+			continue
+		}
+		if utils.NormalizeText(v.Name) == normalized {
+			return v
+		}
+		if byKeyword == nil && containsAny(strings.ToLower(text), vaccineKeywords(*v)) {
+			byKeyword = v
+		}
+	}
+	return byKeyword
+}
+
+func (ri *recordImporter) importImmunizations(list []dto.FHIRImmunization, vaccines []entities.Vaccine) {
+	for _, im := range list {
+		if im.Status != "completed" {
+			ri.result.ImmunizationsSkipped++
+			ri.warn("Immunization %s omitida: estado %q", im.ID, im.Status)
+			continue
+		}
+		name := im.VaccineCode.Text
+		if name == "" && len(im.VaccineCode.Coding) > 0 {
+			name = im.VaccineCode.Coding[0].Display
+		}
+		vaccine := matchVaccine(name, ri.imp.Pet.SpeciesID, vaccines)
+		if vaccine == nil {
+			ri.result.ImmunizationsSkipped++
+			ri.warn("Immunization %s omitida: la vacuna %q no está en el catálogo", im.ID, name)
+			continue
+		}
+		given, _ := dto.ParseFHIRDateTime(im.OccurrenceDateTime)
+		given = time.Date(given.Year(), given.Month(), given.Day(), 0, 0, 0, 0, time.Local)
+		key := vaccinationKey(vaccine.ID, given)
+		if ri.existingShots[key] {
+			ri.result.ImmunizationsSkipped++
+			continue
+		}
+
+		dose := im.DoseNumber()
+		if dose <= 0 {
+			dose = ri.doseCounts[vaccine.ID] + 1
+		}
+		if dose > ri.doseCounts[vaccine.ID] {
+			ri.doseCounts[vaccine.ID] = dose
+		}
+		notes := []string{"Importada de otro historial."}
+		if len(im.Performer) > 0 && im.Performer[0].Actor.Display != "" {
+			notes = append(notes, "Aplicada por "+im.Performer[0].Actor.Display+".")
+		}
+		for _, n := range im.Note {
+			notes = append(notes, n.Text)
+		}
+		vaccination := entities.Vaccination{
+			ID:         uuid.New(),
+			PetID:      ri.imp.Pet.ID,
+			VaccineID:  vaccine.ID,
+			DoseNumber: dose,
+			DateGiven:  given,
+		}
+		vaccination.LotNumber, _ = truncateRunes(im.LotNumber, 50)
+		if im.Manufacturer != nil {
+			vaccination.Manufacturer, _ = truncateRunes(im.Manufacturer.Display, 100)
+		}
+		vaccination.Notes, _ = truncateRunes(strings.Join(notes, " "), 500)
+		if im.Encounter != nil {
+			if id, ok := ri.encounters[dto.FHIRReferenceID(im.Encounter.Reference)]; ok {
+				vaccination.AppointmentID = &id
+			}
+		}
+		if vaccine.DefaultIntervalDays > 0 {
+			next := given.AddDate(0, 0, vaccine.DefaultIntervalDays)
+			vaccination.NextDueDate = &next
+		}
+		ri.imp.Vaccinations = append(ri.imp.Vaccinations, vaccination)
+		ri.existingShots[key] = true
+		ri.result.ImmunizationsCreated++
+	}
+}
+
+// notifyImportedOwner envía las credenciales al dueño creado por la
+// importación, igual que al registrarlo desde la clínica.
+func notifyImportedOwner(owner *entities.User, password string) {
+	body := fmt.Sprintf(
+		"Hola %s,\n\nTe informamos que has sido registrado en el sistema al recibir el historial de tu mascota, "+
+			"tus credenciales asignadas son las siguientes, tienes la opción de cambiar tu contraseña en el sistema si así lo deseas.\n\nUsuario: %s\nContraseña: %s\n\nSaludos.",
+		owner.FullName, owner.Email, password,
+	)
+	go func() {
+		if err := utils.SendMail(owner.Email, "Registro exitoso en PetVet - Usuario", body); err != nil {
+			fmt.Println("Error enviando correo al usuario:", err)
+		}
+	}()
+}
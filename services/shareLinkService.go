@@ -0,0 +1,226 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"VetiCare/utils"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrShareLinkNotFound    = errors.New("enlace compartido no encontrado")
+	ErrShareLinkExpired     = errors.New("el enlace compartido venció o fue revocado")
+	ErrShareLinkLocked      = errors.New("el enlace se bloqueó por demasiados intentos de PIN")
+	ErrShareLinkPinRequired = errors.New("el enlace requiere PIN")
+	ErrShareLinkPinInvalid  = errors.New("PIN incorrecto")
+)
+
+type ShareLinkService struct {
+	Repo            repositories.ShareLinkRepository
+	PetRepo         repositories.PetRepository
+	AppointmentRepo repositories.AppointmentRepository
+	VaccinationRepo repositories.VaccinationRepository
+	Access          *PetAccess
+}
+
+func NewShareLinkService(repo repositories.ShareLinkRepository, petRepo repositories.PetRepository, appointmentRepo repositories.AppointmentRepository, vaccinationRepo repositories.VaccinationRepository, access *PetAccess) *ShareLinkService {
+	return &ShareLinkService{Repo: repo, PetRepo: petRepo, AppointmentRepo: appointmentRepo, VaccinationRepo: vaccinationRepo, Access: access}
+}
+
+func (s *ShareLinkService) getPet(requesterID, petID string) (*entities.Pet, error) {
+	pet, err := s.PetRepo.GetByID(petID)
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	if err := s.Access.CheckPet(requesterID, pet); err != nil {
+		return nil, err
+	}
+	return pet, nil
+}
+
+// requesterName devuelve el nombre con el que queda registrado quien crea o
+// revoca un enlace: el dueño de la mascota o el personal de la clínica.
+func (s *ShareLinkService) requesterName(requesterID string, pet *entities.Pet) (string, error) {
+	if pet.OwnerID.String() == requesterID {
+		return pet.Owner.FullName + " (dueño)", nil
+	}
+	return s.Access.StaffName(requesterID)
+}
+
+// getLink devuelve el enlace si el solicitante puede administrarlo.
+func (s *ShareLinkService) getLink(requesterID, linkID string) (*entities.ShareLink, *entities.Pet, error) {
+	link, err := s.Repo.GetByID(linkID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if link == nil {
+		return nil, nil, ErrShareLinkNotFound
+	}
+	pet, err := s.getPet(requesterID, link.PetID.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	return link, pet, nil
+}
+
+// CreateLink genera un enlace para la mascota. Lo pueden crear su dueño y el
+// personal de la clínica; el PIN, si se indica, se guarda cifrado.
+func (s *ShareLinkService) CreateLink(requesterID, petID string, link *entities.ShareLink, pin string) (*entities.ShareLink, error) {
+	pet, err := s.getPet(requesterID, petID)
+	if err != nil {
+		return nil, err
+	}
+	name, err := s.requesterName(requesterID, pet)
+	if err != nil {
+		return nil, err
+	}
+	if pin != "" {
+		hash, err := utils.HashPassword(pin)
+		if err != nil {
+			return nil, err
+		}
+		link.PinHash = hash
+	}
+	link.PetID = pet.ID
+	link.CreatedByID = uuid.MustParse(requesterID)
+	link.CreatedByName = name
+	if err := s.Repo.Create(link); err != nil {
+		return nil, err
+	}
+	link.Pet = *pet
+	return link, nil
+}
+
+func (s *ShareLinkService) GetLinksByPet(requesterID, petID string) ([]entities.ShareLink, error) {
+	if _, err := s.getPet(requesterID, petID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByPetID(petID)
+}
+
+// RevokeLink anula el enlace de inmediato. Revocar un enlace ya revocado no
+// cambia quién lo revocó.
+func (s *ShareLinkService) RevokeLink(requesterID, linkID string) (*entities.ShareLink, error) {
+	link, pet, err := s.getLink(requesterID, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.RevokedAt != nil {
+		return link, nil
+	}
+	name, err := s.requesterName(requesterID, pet)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := s.Repo.Revoke(linkID, name, now); err != nil {
+		return nil, err
+	}
+	link.RevokedAt = &now
+	link.RevokedByName = name
+	return link, nil
+}
+
+func (s *ShareLinkService) GetAccessLog(requesterID, linkID string) ([]entities.ShareLinkAccess, error) {
+	if _, _, err := s.getLink(requesterID, linkID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetAccesses(linkID)
+}
+
+// OpenSharedRecord valida el token y el PIN y devuelve la parte del historial
+// que cubre el enlace. Todos los intentos sobre un enlace existente quedan en
+// la bitácora, incluidos los rechazados.
+func (s *ShareLinkService) OpenSharedRecord(token, pin, ipAddress, userAgent string) (*entities.SharedRecord, error) {
+	linkID, _, err := utils.VerifyShareToken(token)
+	if err != nil {
+		return nil, ErrShareLinkNotFound
+	}
+	link, err := s.Repo.GetByID(linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, ErrShareLinkNotFound
+	}
+
+	now := time.Now()
+	denied := func(reason string, cause error) (*entities.SharedRecord, error) {
+		if err := s.recordAccess(link, now, ipAddress, userAgent, false, reason); err != nil {
+			return nil, err
+		}
+		return nil, cause
+	}
+	switch link.Status(now) {
+	case "Revocado":
+		return denied("revocado", ErrShareLinkExpired)
+	case "Vencido":
+		return denied("vencido", ErrShareLinkExpired)
+	case "Bloqueado":
+		return denied("bloqueado", ErrShareLinkLocked)
+	}
+	if link.HasPin() {
+		if pin == "" {
+			return denied("sin PIN", ErrShareLinkPinRequired)
+		}
+		// El intento se reserva antes de comparar el PIN y se libera si es
+		// correcto, así los intentos en paralelo también cuentan.
+		reserved, err := s.Repo.ReservePinAttempt(linkID, entities.ShareLinkMaxPinAttempts)
+		if err != nil {
+			return nil, err
+		}
+		if !reserved {
+			return denied("bloqueado", ErrShareLinkLocked)
+		}
+		if !utils.CheckPasswordHash(pin, link.PinHash) {
+			return denied("PIN incorrecto", ErrShareLinkPinInvalid)
+		}
+		if err := s.Repo.ResetFailedPin(linkID); err != nil {
+			return nil, err
+		}
+	}
+
+	pet, err := s.PetRepo.GetByID(link.PetID.String())
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrShareLinkNotFound
+	}
+	record := &entities.SharedRecord{Link: link, Pet: pet}
+	record.Vaccinations, err = s.VaccinationRepo.GetByPetID(pet.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if link.Scope == entities.ShareScopeHistory {
+		record.Appointments, err = s.AppointmentRepo.GetMedicalHistoryByPetID(pet.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		sortAppointments(record.Appointments)
+	}
+	if err := s.recordAccess(link, now, ipAddress, userAgent, true, ""); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (s *ShareLinkService) recordAccess(link *entities.ShareLink, at time.Time, ipAddress, userAgent string, granted bool, reason string) error {
+	if runes := []rune(userAgent); len(runes) > 300 {
+		userAgent = string(runes[:300])
+	}
+	return s.Repo.RecordAccess(&entities.ShareLinkAccess{
+		LinkID:     link.ID,
+		AccessedAt: at,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		Granted:    granted,
+		Reason:     reason,
+	})
+}
@@ -3,14 +3,23 @@ package services
 import (
 	"VetiCare/entities"
 	"VetiCare/repositories"
+	"errors"
+)
+
+var (
+	ErrSpeciesNotFound        = errors.New("especie no encontrada")
+	ErrReferenceRangeNotFound = errors.New("rango de referencia no encontrado")
+	ErrInvalidVital           = errors.New("el signo vital debe ser weight_kg, temperature, heart_rate o respiratory_rate")
+	ErrInvalidReferenceRange  = errors.New("el rango normal debe estar dentro del rango plausible y el mínimo ser menor que el máximo")
 )
 
 type SpeciesService struct {
-	Repo repositories.SpeciesRepository
+	Repo    repositories.SpeciesRepository
+	PetRepo repositories.PetRepository
 }
 
-func NewSpeciesService(repo repositories.SpeciesRepository) *SpeciesService {
-	return &SpeciesService{Repo: repo}
+func NewSpeciesService(repo repositories.SpeciesRepository, petRepo repositories.PetRepository) *SpeciesService {
+	return &SpeciesService{Repo: repo, PetRepo: petRepo}
 }
 
 func (s *SpeciesService) GetAll() ([]entities.Species, error) {
@@ -32,3 +41,88 @@ func (s *SpeciesService) Update(id int, fields map[string]interface{}) error {
 func (s *SpeciesService) Delete(id int) error {
 	return s.Repo.Delete(id)
 }
+
+func (s *SpeciesService) GetReferenceRanges(speciesID int, onlyActive bool) ([]entities.VitalReferenceRange, error) {
+	species, err := s.Repo.GetByID(speciesID)
+	if err != nil {
+		return nil, err
+	}
+	if species == nil {
+		return nil, ErrSpeciesNotFound
+	}
+	return s.Repo.GetReferenceRanges(speciesID, onlyActive)
+}
+
+func (s *SpeciesService) CreateReferenceRange(vr *entities.VitalReferenceRange) (*entities.VitalReferenceRange, error) {
+	species, err := s.Repo.GetByID(vr.SpeciesID)
+	if err != nil {
+		return nil, err
+	}
+	if species == nil {
+		return nil, ErrSpeciesNotFound
+	}
+	if err := checkReferenceRange(vr); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateReferenceRange(vr); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetReferenceRangeByID(vr.ID)
+}
+
+// UpdateReferenceRange aplica los cambios sobre una copia del rango y valida
+// el resultado completo, para que una edición parcial no deje el mínimo por
+// encima del máximo.
+func (s *SpeciesService) UpdateReferenceRange(id int, fields map[string]interface{}) (*entities.VitalReferenceRange, error) {
+	current, err := s.Repo.GetReferenceRangeByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, ErrReferenceRangeNotFound
+	}
+	candidate := *current
+	if v, ok := fields["vital"].(string); ok {
+		candidate.Vital = v
+	}
+	for key, target := range map[string]*float64{
+		"min":           &candidate.Min,
+		"max":           &candidate.Max,
+		"plausible_min": &candidate.PlausibleMin,
+		"plausible_max": &candidate.PlausibleMax,
+	} {
+		if v, ok := fields[key].(float64); ok {
+			*target = v
+		}
+	}
+	if err := checkReferenceRange(&candidate); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateReferenceRange(id, fields); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetReferenceRangeByID(id)
+}
+
+func (s *SpeciesService) DeleteReferenceRange(id int) (string, error) {
+	newStatus, err := s.Repo.DeleteReferenceRange(id)
+	if err != nil {
+		return "", err
+	}
+	if newStatus == 1 {
+		return "Rango de referencia activado correctamente", nil
+	}
+	return "Rango de referencia desactivado correctamente", nil
+}
+
+// checkReferenceRange exige que el rango normal quede dentro del rango
+// plausible: PlausibleMin <= Min < Max <= PlausibleMax.
+func checkReferenceRange(vr *entities.VitalReferenceRange) error {
+	if _, ok := vitalNames[vr.Vital]; !ok {
+		return ErrInvalidVital
+	}
+	if vr.PlausibleMin < 0 || vr.PlausibleMin > vr.Min || vr.Min >= vr.Max || vr.Max > vr.PlausibleMax {
+		return ErrInvalidReferenceRange
+	}
+	return nil
+}
@@ -0,0 +1,404 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"VetiCare/utils"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTreatmentPlanNotFound     = errors.New("plan de tratamiento no encontrado")
+	ErrTreatmentPlanClosed       = errors.New("el plan de tratamiento ya está completado o cancelado")
+	ErrTreatmentStepNotFound     = errors.New("paso del plan no encontrado")
+	ErrTreatmentStepClosed       = errors.New("el paso ya está completado u omitido")
+	ErrTreatmentStepScheduled    = errors.New("el paso ya tiene una cita agendada")
+	ErrTreatmentAppointmentPet   = errors.New("la cita pertenece a otra mascota")
+	ErrTreatmentAppointmentState = errors.New("solo se pueden vincular citas activas o finalizadas")
+	ErrTreatmentAppointmentInUse = errors.New("la cita ya está vinculada a otro paso de un plan")
+	ErrTreatmentVetInvalid       = errors.New("el veterinario responsable debe ser un veterinario activo")
+)
+
+type TreatmentPlanService struct {
+	Repo            repositories.TreatmentPlanRepository
+	AppointmentRepo repositories.AppointmentRepository
+	PetRepo         repositories.PetRepository
+	UserRepo        repositories.UserRepository
+	Appointments    *AppointmentService
+	Access          *PetAccess
+}
+
+func NewTreatmentPlanService(repo repositories.TreatmentPlanRepository, appointmentRepo repositories.AppointmentRepository, petRepo repositories.PetRepository, userRepo repositories.UserRepository, appointments *AppointmentService, access *PetAccess) *TreatmentPlanService {
+	return &TreatmentPlanService{Repo: repo, AppointmentRepo: appointmentRepo, PetRepo: petRepo, UserRepo: userRepo, Appointments: appointments, Access: access}
+}
+
+// syncTreatmentStep actualiza el paso vinculado a la cita cuando esta cambia
+// de estado: al finalizarla el paso queda completado y los siguientes se
+// recalculan desde esa fecha; si se cancela o la mascota no asiste, el paso
+// vuelve a quedar pendiente para agendarlo de nuevo.
+func syncTreatmentStep(repo repositories.TreatmentPlanRepository, appointmentID string, statusID int, now time.Time) error {
+	if statusID != entities.AppointmentStatusFinished &&
+		statusID != entities.AppointmentStatusCancelled &&
+		statusID != entities.AppointmentStatusNoShow {
+		return nil
+	}
+	linked, err := repo.GetStepByAppointmentID(appointmentID)
+	if err != nil || linked == nil {
+		return err
+	}
+	plan, err := repo.GetByID(linked.PlanID.String())
+	if err != nil || plan == nil || plan.StatusID != entities.TreatmentPlanStatusActive {
+		return err
+	}
+	step := plan.StepByID(linked.ID.String())
+	if step == nil || step.StatusID == entities.TreatmentStepStatusCompleted {
+		return nil
+	}
+
+	if statusID == entities.AppointmentStatusFinished {
+		step.StatusID = entities.TreatmentStepStatusCompleted
+		step.CompletedAt = &now
+	} else {
+		step.StatusID = entities.TreatmentStepStatusPending
+		step.AppointmentID = nil
+	}
+	closePlanIfFinished(plan, now)
+	plan.RecalculateDueDates()
+	return repo.SaveProgress(plan)
+}
+
+func closePlanIfFinished(plan *entities.TreatmentPlan, now time.Time) {
+	if plan.IsFinished() {
+		plan.StatusID = entities.TreatmentPlanStatusCompleted
+		plan.CompletedAt = &now
+	}
+}
+
+func (s *TreatmentPlanService) checkVet(vetID *uuid.UUID) error {
+	if vetID == nil {
+		return nil
+	}
+	vet, err := s.UserRepo.GetByID(vetID.String())
+	if err != nil {
+		return err
+	}
+	if vet == nil || vet.RoleID != 2 || vet.StatusID != 1 {
+		return ErrTreatmentVetInvalid
+	}
+	return nil
+}
+
+// CreatePlan registra el plan con sus pasos en el orden recibido y calcula
+// la fecha de vencimiento de cada uno a partir de la fecha de inicio.
+func (s *TreatmentPlanService) CreatePlan(requesterID string, plan *entities.TreatmentPlan) (*entities.TreatmentPlan, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	author, err := s.Access.StaffName(requesterID)
+	if err != nil {
+		return nil, err
+	}
+	pet, err := s.PetRepo.GetByID(plan.PetID.String())
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	if err := s.checkVet(plan.VetID); err != nil {
+		return nil, err
+	}
+
+	plan.StatusID = entities.TreatmentPlanStatusActive
+	plan.CreatedByName = author
+	if id, err := uuid.Parse(requesterID); err == nil {
+		plan.CreatedByID = &id
+	}
+	for i := range plan.Steps {
+		plan.Steps[i].StepOrder = i + 1
+		plan.Steps[i].StatusID = entities.TreatmentStepStatusPending
+	}
+	plan.RecalculateDueDates()
+	if err := s.Repo.Create(plan); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByID(plan.ID.String())
+}
+
+func (s *TreatmentPlanService) getPlan(id string) (*entities.TreatmentPlan, error) {
+	plan, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, ErrTreatmentPlanNotFound
+	}
+	return plan, nil
+}
+
+// getActivePlan carga el plan para modificarlo; solo el personal de la
+// clínica cambia planes y únicamente mientras siguen activos.
+func (s *TreatmentPlanService) getActivePlan(requesterID, id string) (*entities.TreatmentPlan, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	plan, err := s.getPlan(id)
+	if err != nil {
+		return nil, err
+	}
+	if plan.StatusID != entities.TreatmentPlanStatusActive {
+		return nil, ErrTreatmentPlanClosed
+	}
+	return plan, nil
+}
+
+func (s *TreatmentPlanService) GetPlanByID(requesterID, id string) (*entities.TreatmentPlan, error) {
+	plan, err := s.getPlan(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Access.CheckPet(requesterID, &plan.Pet); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (s *TreatmentPlanService) GetPlansByPetID(requesterID, petID string, statusID *int) ([]entities.TreatmentPlan, error) {
+	pet, err := s.PetRepo.GetByID(petID)
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	if err := s.Access.CheckPet(requesterID, pet); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByPetID(petID, statusID)
+}
+
+func (s *TreatmentPlanService) UpdatePlan(requesterID, id string, fields map[string]interface{}, vetID *uuid.UUID) (*entities.TreatmentPlan, error) {
+	if _, err := s.getActivePlan(requesterID, id); err != nil {
+		return nil, err
+	}
+	if err := s.checkVet(vetID); err != nil {
+		return nil, err
+	}
+	fields["vet_id"] = vetID
+	if err := s.Repo.Update(id, fields); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByID(id)
+}
+
+// CancelPlan cierra el plan. Las citas que ya estaban agendadas se conservan
+// y deben cancelarse aparte si ya no se necesitan.
+func (s *TreatmentPlanService) CancelPlan(requesterID, id string) (*entities.TreatmentPlan, error) {
+	if _, err := s.getActivePlan(requesterID, id); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.Update(id, map[string]interface{}{"status_id": entities.TreatmentPlanStatusCancelled}); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByID(id)
+}
+
+// AddStep agrega un paso al final del plan.
+func (s *TreatmentPlanService) AddStep(requesterID, planID string, step *entities.TreatmentPlanStep) (*entities.TreatmentPlan, error) {
+	plan, err := s.getActivePlan(requesterID, planID)
+	if err != nil {
+		return nil, err
+	}
+	step.PlanID = plan.ID
+	step.StepOrder = len(plan.Steps) + 1
+	step.StatusID = entities.TreatmentStepStatusPending
+	plan.Steps = append(plan.Steps, *step)
+	plan.RecalculateDueDates()
+	step.DueDate = plan.Steps[len(plan.Steps)-1].DueDate
+	if err := s.Repo.AddStep(step); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByID(planID)
+}
+
+// SkipStep marca un paso como omitido, por ejemplo cuando el veterinario
+// decide que ya no es necesario. Si era el último pendiente el plan se
+// completa.
+func (s *TreatmentPlanService) SkipStep(requesterID, planID, stepID string) (*entities.TreatmentPlan, error) {
+	plan, err := s.getActivePlan(requesterID, planID)
+	if err != nil {
+		return nil, err
+	}
+	step := plan.StepByID(stepID)
+	if step == nil {
+		return nil, ErrTreatmentStepNotFound
+	}
+	if step.StatusID == entities.TreatmentStepStatusCompleted || step.StatusID == entities.TreatmentStepStatusSkipped {
+		return nil, ErrTreatmentStepClosed
+	}
+	step.StatusID = entities.TreatmentStepStatusSkipped
+	closePlanIfFinished(plan, time.Now())
+	plan.RecalculateDueDates()
+	if err := s.Repo.SaveProgress(plan); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByID(planID)
+}
+
+// LinkAppointment vincula al paso una cita agendada por otro medio. Si la
+// cita ya está finalizada el paso se da por completado.
+func (s *TreatmentPlanService) LinkAppointment(requesterID, planID, stepID, appointmentID string) (*entities.TreatmentPlan, error) {
+	plan, err := s.getActivePlan(requesterID, planID)
+	if err != nil {
+		return nil, err
+	}
+	step := plan.StepByID(stepID)
+	if step == nil {
+		return nil, ErrTreatmentStepNotFound
+	}
+	if step.StatusID != entities.TreatmentStepStatusPending {
+		if step.StatusID == entities.TreatmentStepStatusScheduled {
+			return nil, ErrTreatmentStepScheduled
+		}
+		return nil, ErrTreatmentStepClosed
+	}
+	app, err := s.AppointmentRepo.GetByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrAppointmentNotFound
+	}
+	if app.PetID != plan.PetID {
+		return nil, ErrTreatmentAppointmentPet
+	}
+	if app.StatusID == entities.AppointmentStatusCancelled || app.StatusID == entities.AppointmentStatusNoShow {
+		return nil, ErrTreatmentAppointmentState
+	}
+	linked, err := s.Repo.GetStepByAppointmentID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if linked != nil {
+		return nil, ErrTreatmentAppointmentInUse
+	}
+
+	step.AppointmentID = &app.ID
+	step.StatusID = entities.TreatmentStepStatusScheduled
+	if app.StatusID == entities.AppointmentStatusFinished {
+		done := time.Now()
+		if app.FinishedAt != nil {
+			done = *app.FinishedAt
+		}
+		step.StatusID = entities.TreatmentStepStatusCompleted
+		step.CompletedAt = &done
+		closePlanIfFinished(plan, time.Now())
+	}
+	plan.RecalculateDueDates()
+	if err := s.Repo.SaveProgress(plan); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByID(planID)
+}
+
+// SuggestFollowUps propone una cita para cada paso pendiente: el primer
+// turno libre desde su fecha de vencimiento (o desde hoy si ya venció) con
+// el veterinario responsable del plan. stepIDs limita la propuesta a esos
+// pasos.
+func (s *TreatmentPlanService) SuggestFollowUps(requesterID, planID string, stepIDs []string) ([]entities.TreatmentFollowUp, *entities.TreatmentPlan, error) {
+	plan, err := s.getActivePlan(requesterID, planID)
+	if err != nil {
+		return nil, nil, err
+	}
+	only := map[string]bool{}
+	for _, id := range stepIDs {
+		if plan.StepByID(id) == nil {
+			return nil, nil, ErrTreatmentStepNotFound
+		}
+		only[id] = true
+	}
+
+	days := utils.GetEnvInt("TREATMENT_FOLLOW_UP_SEARCH_DAYS", 14)
+	today := time.Now()
+	booked := map[string]bool{}
+	proposals := []entities.TreatmentFollowUp{}
+	for _, step := range plan.Steps {
+		if step.StatusID != entities.TreatmentStepStatusPending || (len(only) > 0 && !only[step.ID.String()]) {
+			continue
+		}
+		from := step.DueDate
+		if step.IsOverdue(today) {
+			from = today
+		}
+		app := followUpAppointment(plan, &step)
+		proposal := entities.TreatmentFollowUp{Step: step, VetID: plan.VetID}
+		if plan.Vet != nil {
+			proposal.VetName = plan.Vet.FullName
+		}
+		if date, clock, ok := s.Appointments.SuggestSlot(app, from, days, booked); ok {
+			proposal.Date, proposal.Time = date, clock
+		}
+		proposals = append(proposals, proposal)
+	}
+	return proposals, plan, nil
+}
+
+// ScheduleFollowUps agenda las citas sugeridas y las vincula a sus pasos. Los
+// pasos para los que no hubo turno libre quedan pendientes y se devuelven sin
+// cita.
+func (s *TreatmentPlanService) ScheduleFollowUps(requesterID, planID string, stepIDs []string) ([]entities.TreatmentFollowUp, error) {
+	proposals, plan, err := s.SuggestFollowUps(requesterID, planID, stepIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range proposals {
+		p := &proposals[i]
+		if p.Date == "" {
+			continue
+		}
+		app := followUpAppointment(plan, &p.Step)
+		app.Date, app.Time = p.Date, p.Time
+		if err := s.Appointments.CreateAppointment(&app); err != nil {
+			if errors.Is(err, ErrAppointmentSlotTaken) || errors.Is(err, ErrClinicClosed) || errors.Is(err, ErrVetOnTimeOff) {
+				p.Date, p.Time = "", ""
+				continue
+			}
+			return nil, err
+		}
+		p.Appointment = &app
+		step := plan.StepByID(p.Step.ID.String())
+		step.AppointmentID = &app.ID
+		step.StatusID = entities.TreatmentStepStatusScheduled
+		p.Step = *step
+	}
+	if err := s.Repo.SaveProgress(plan); err != nil {
+		return nil, err
+	}
+	return proposals, nil
+}
+
+func followUpAppointment(plan *entities.TreatmentPlan, step *entities.TreatmentPlanStep) entities.Appointment {
+	reason := []rune(plan.Title + ": " + step.Procedure)
+	if len(reason) > 300 {
+		reason = reason[:300]
+	}
+	return entities.Appointment{
+		PetID:    plan.PetID,
+		VetID:    plan.VetID,
+		ClinicID: plan.ClinicID,
+		StatusID: entities.AppointmentStatusScheduled,
+		Reason:   string(reason),
+	}
+}
+
+// GetOverdueSteps es el reporte de pasos vencidos sin cita agendada en los
+// planes activos.
+func (s *TreatmentPlanService) GetOverdueSteps(requesterID string, clinicID *int) ([]entities.TreatmentPlanStep, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetOverdueSteps(time.Now(), clinicID)
+}
@@ -14,6 +14,7 @@ func NewUserService(repo repositories.UserRepository) *UserService {
 }
 
 func (s *UserService) Register(user *entities.User) error {
+	assignMainClinic(user)
 	return s.Repo.Register(user)
 }
 
@@ -26,15 +27,24 @@ func (s *UserService) ChangePassword(email, currentPassword, newPassword string)
 }
 
 func (s *UserService) CreateUser(user *entities.User) error {
+	assignMainClinic(user)
 	return s.Repo.Create(user)
 }
 
+// assignMainClinic deja al veterinario nuevo en la clínica principal si no
+// se indicó otra; sin clínica no podría atender ninguna cita.
+func assignMainClinic(user *entities.User) {
+	if user.RoleID == 2 && len(user.Clinics) == 0 {
+		user.Clinics = []entities.Clinic{{ID: entities.MainClinicID}}
+	}
+}
+
 func (s *UserService) GetUserByEmail(email string) (*entities.User, error) {
 	return s.Repo.GetByEmail(email)
 }
 
-func (s *UserService) GetUsersByRole(roleID int) ([]entities.User, error) {
-	return s.Repo.GetByRole(roleID)
+func (s *UserService) GetUsersByRole(roleID int, clinicID *int) ([]entities.User, error) {
+	return s.Repo.GetByRole(roleID, clinicID)
 }
 
 func (s *UserService) GetUserByID(id string) (*entities.User, error) {
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"VetiCare/utils"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrPetNotFound             = errors.New("mascota no encontrada")
+	ErrVaccineNotFound         = errors.New("vacuna no encontrada o inactiva")
+	ErrVaccineSpeciesMismatch  = errors.New("la vacuna no aplica a la especie de la mascota")
+	ErrAppointmentPetMismatch  = errors.New("la cita no corresponde a la mascota")
+	ErrVaccinationDateInFuture = errors.New("la fecha de aplicación no puede ser futura")
+	ErrNextDueBeforeGiven      = errors.New("la próxima dosis debe ser posterior a la fecha de aplicación")
+	ErrAdministeringVetInvalid = errors.New("quien aplica la vacuna debe ser un veterinario activo")
+)
+
+// Palabras que indican que el texto libre describe una vacuna pendiente y no
+// una aplicada; esas citas se dejan para revisión manual.
+var legacyVaccinationNegations = []string{"pendiente", "falta", "sin vacuna", "no vacunad", "no aplica", "no se aplic"}
+
+type VaccinationService struct {
+	Repo            repositories.VaccinationRepository
+	PetRepo         repositories.PetRepository
+	AppointmentRepo repositories.AppointmentRepository
+	UserRepo        repositories.UserRepository
+	Access          *PetAccess
+}
+
+func NewVaccinationService(repo repositories.VaccinationRepository, petRepo repositories.PetRepository, appointmentRepo repositories.AppointmentRepository, userRepo repositories.UserRepository, access *PetAccess) *VaccinationService {
+	return &VaccinationService{Repo: repo, PetRepo: petRepo, AppointmentRepo: appointmentRepo, UserRepo: userRepo, Access: access}
+}
+
+func (s *VaccinationService) GetVaccines(onlyActive bool) ([]entities.Vaccine, error) {
+	return s.Repo.GetVaccines(onlyActive)
+}
+
+func (s *VaccinationService) GetVaccineByID(id int) (*entities.Vaccine, error) {
+	return s.Repo.GetVaccineByID(id)
+}
+
+func (s *VaccinationService) CreateVaccine(vaccine *entities.Vaccine) error {
+	return s.Repo.CreateVaccine(vaccine)
+}
+
+func (s *VaccinationService) UpdateVaccine(id int, fields map[string]interface{}) error {
+	return s.Repo.UpdateVaccine(id, fields)
+}
+
+func (s *VaccinationService) DeleteVaccine(id int) (string, error) {
+	newStatus, err := s.Repo.DeleteVaccine(id)
+	if err != nil {
+		return "", err
+	}
+	if newStatus == 1 {
+		return "Vacuna activada correctamente", nil
+	}
+	return "Vacuna desactivada correctamente", nil
+}
+
+// RecordVaccination valida y guarda una dosis. Si no se indica el número de
+// dosis se toma el siguiente según el historial de la mascota, y si no se
+// indica la próxima fecha se calcula con el intervalo de la vacuna. Solo el
+// personal de la clínica registra vacunas.
+func (s *VaccinationService) RecordVaccination(requesterID string, v *entities.Vaccination) (*entities.Vaccination, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	pet, err := s.PetRepo.GetByID(v.PetID.String())
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	vaccine, err := s.Repo.GetVaccineByID(v.VaccineID)
+	if err != nil {
+		return nil, err
+	}
+	if vaccine == nil || vaccine.StatusID != 1 {
+		return nil, ErrVaccineNotFound
+	}
+	if vaccine.SpeciesID != nil && *vaccine.SpeciesID != pet.SpeciesID {
+		return nil, ErrVaccineSpeciesMismatch
+	}
+	if v.AppointmentID != nil {
+		app, err := s.AppointmentRepo.GetByID(v.AppointmentID.String())
+		if err != nil {
+			return nil, err
+		}
+		if app == nil {
+			return nil, ErrAppointmentNotFound
+		}
+		if app.PetID != pet.ID {
+			return nil, ErrAppointmentPetMismatch
+		}
+		if v.AdministeredByID == nil {
+			v.AdministeredByID = app.VetID
+		}
+	}
+	if v.AdministeredByID != nil {
+		vet, err := s.UserRepo.GetByID(v.AdministeredByID.String())
+		if err != nil {
+			return nil, err
+		}
+		if vet == nil || vet.RoleID != 2 || vet.StatusID != 1 {
+			return nil, ErrAdministeringVetInvalid
+		}
+	}
+
+	if v.DateGiven.After(time.Now()) {
+		return nil, ErrVaccinationDateInFuture
+	}
+	if v.NextDueDate == nil && vaccine.DefaultIntervalDays > 0 {
+		next := v.DateGiven.AddDate(0, 0, vaccine.DefaultIntervalDays)
+		v.NextDueDate = &next
+	}
+	if v.NextDueDate != nil && !v.NextDueDate.After(v.DateGiven) {
+		return nil, ErrNextDueBeforeGiven
+	}
+	if v.DoseNumber == 0 {
+		count, err := s.Repo.CountDoses(pet.ID.String(), vaccine.ID)
+		if err != nil {
+			return nil, err
+		}
+		v.DoseNumber = int(count) + 1
+	}
+
+	if err := s.Repo.Create(v); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByID(v.ID.String())
+}
+
+func (s *VaccinationService) GetByPetID(requesterID, petID string) ([]entities.Vaccination, error) {
+	pet, err := s.PetRepo.GetByID(petID)
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	if err := s.Access.CheckPet(requesterID, pet); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByPetID(petID)
+}
+
+// GetDue incluye el nombre y el teléfono de cada dueño, así que solo lo ve
+// el personal de la clínica.
+func (s *VaccinationService) GetDue(requesterID string, filter entities.VaccinationDueFilter) ([]entities.Vaccination, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	if filter.To.Before(filter.From) {
+		return nil, ErrInvalidDateRange
+	}
+	return s.Repo.GetDue(filter)
+}
+
+// MigrateLegacy convierte el texto libre de Appointment.VaccinationStatus en
+// registros de vacunación buscando en él el nombre o los alias de cada vacuna
+// del catálogo. Puede ejecutarse varias veces: no duplica vacunas ya
+// registradas para la misma cita. Las citas sin coincidencias, o cuyo texto
+// indica una vacuna pendiente, se devuelven para revisión manual.
+func (s *VaccinationService) MigrateLegacy() (*entities.LegacyVaccinationResult, error) {
+	vaccines, err := s.Repo.GetVaccines(false)
+	if err != nil {
+		return nil, err
+	}
+	apps, err := s.Repo.GetLegacyAppointments()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entities.LegacyVaccinationResult{Scanned: len(apps)}
+	for _, app := range apps {
+		text := strings.ToLower(app.VaccinationStatus)
+		if containsAny(text, legacyVaccinationNegations) {
+			result.Unmatched = append(result.Unmatched, app)
+			continue
+		}
+		given, err := time.ParseInLocation(utils.AppointmentDateLayout, app.Date, time.Local)
+		if err != nil {
+			result.Unmatched = append(result.Unmatched, app)
+			continue
+		}
+
+		matched := false
+		for _, vaccine := range vaccines {
+			if vaccine.SpeciesID != nil && *vaccine.SpeciesID != app.Pet.SpeciesID {
+				continue
+			}
+			if !containsAny(text, vaccineKeywords(vaccine)) {
+				continue
+			}
+			matched = true
+			exists, err := s.Repo.ExistsForAppointment(app.ID.String(), vaccine.ID)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				continue
+			}
+			count, err := s.Repo.CountDoses(app.PetID.String(), vaccine.ID)
+			if err != nil {
+				return nil, err
+			}
+			appointmentID := app.ID
+			vaccination := entities.Vaccination{
+				PetID:            app.PetID,
+				AppointmentID:    &appointmentID,
+				VaccineID:        vaccine.ID,
+				DoseNumber:       int(count) + 1,
+				DateGiven:        given,
+				AdministeredByID: app.VetID,
+				Notes:            "Migrado del registro de la cita: " + app.VaccinationStatus,
+			}
+			if vaccine.DefaultIntervalDays > 0 {
+				next := given.AddDate(0, 0, vaccine.DefaultIntervalDays)
+				vaccination.NextDueDate = &next
+			}
+			if err := s.Repo.Create(&vaccination); err != nil {
+				return nil, err
+			}
+			result.Created++
+		}
+		if !matched {
+			result.Unmatched = append(result.Unmatched, app)
+		}
+	}
+	return result, nil
+}
+
+func vaccineKeywords(vaccine entities.Vaccine) []string {
+	keywords := []string{strings.ToLower(vaccine.Name)}
+	for _, alias := range strings.Split(vaccine.Aliases, ",") {
+		if alias = strings.TrimSpace(strings.ToLower(alias)); alias != "" {
+			keywords = append(keywords, alias)
+		}
+	}
+	return keywords
+}
+
+func containsAny(text string, words []string) bool {
+	for _, word := range words {
+		if strings.Contains(text, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"VetiCare/entities"
+	"VetiCare/repositories"
+	"VetiCare/utils"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrVitalsEmpty        = errors.New("debe registrar al menos un signo vital")
+	ErrVitalsDateInFuture = errors.New("la fecha de la toma no puede ser futura")
+	ErrImplausibleVital   = errors.New("valor de signo vital no plausible")
+)
+
+var vitalNames = map[string]string{
+	entities.VitalWeight:          "el peso",
+	entities.VitalTemperature:     "la temperatura",
+	entities.VitalHeartRate:       "la frecuencia cardiaca",
+	entities.VitalRespiratoryRate: "la frecuencia respiratoria",
+}
+
+type VitalSignService struct {
+	Repo            repositories.VitalSignRepository
+	AppointmentRepo repositories.AppointmentRepository
+	PetRepo         repositories.PetRepository
+	LabRepo         repositories.LabRepository
+	Access          *PetAccess
+}
+
+func NewVitalSignService(repo repositories.VitalSignRepository, appointmentRepo repositories.AppointmentRepository, petRepo repositories.PetRepository, labRepo repositories.LabRepository, access *PetAccess) *VitalSignService {
+	return &VitalSignService{Repo: repo, AppointmentRepo: appointmentRepo, PetRepo: petRepo, LabRepo: labRepo, Access: access}
+}
+
+// Record guarda una toma de signos vitales. Puede asociarse a una cita de la
+// mascota o registrarse sola, como un pesaje en recepción. Solo el personal
+// de la clínica registra signos vitales.
+func (s *VitalSignService) Record(requesterID string, v *entities.VitalSign) (*entities.VitalSign, error) {
+	if err := s.Access.CheckStaff(requesterID); err != nil {
+		return nil, err
+	}
+	pet, err := s.PetRepo.GetByID(v.PetID.String())
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	if v.WeightKg == nil && v.Temperature == nil && v.HeartRate == nil &&
+		v.RespiratoryRate == nil && v.BodyConditionScore == nil {
+		return nil, ErrVitalsEmpty
+	}
+	if v.AppointmentID != nil {
+		app, err := s.AppointmentRepo.GetByID(v.AppointmentID.String())
+		if err != nil {
+			return nil, err
+		}
+		if app == nil {
+			return nil, ErrAppointmentNotFound
+		}
+		if app.PetID != pet.ID {
+			return nil, ErrAppointmentPetMismatch
+		}
+		if app.StatusID == entities.AppointmentStatusFinished {
+			return nil, ErrAppointmentRecordLocked
+		}
+	}
+	if v.RecordedAt.IsZero() {
+		v.RecordedAt = time.Now()
+	}
+	if v.RecordedAt.After(time.Now()) {
+		return nil, ErrVitalsDateInFuture
+	}
+	if err := checkPlausibleVitals(pet, v.RecordedAt, v.VitalValues()); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.Create(v); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetByID(v.ID.String())
+}
+
+// GetSeries arma la serie de signos vitales de la mascota combinando el peso y
+// la temperatura guardados en sus citas con las tomas registradas aparte.
+// Las tomas asociadas a una cita se combinan con ella en un mismo punto. Los
+// resultados de laboratorio se agregan como puntos propios en la fecha en que
+// se registraron.
+func (s *VitalSignService) GetSeries(requesterID, petID string) ([]entities.VitalPoint, error) {
+	pet, err := s.PetRepo.GetByID(petID)
+	if err != nil {
+		return nil, err
+	}
+	if pet == nil {
+		return nil, ErrPetNotFound
+	}
+	if err := s.Access.CheckPet(requesterID, pet); err != nil {
+		return nil, err
+	}
+	apps, err := s.AppointmentRepo.GetWithVitalsByPetID(petID)
+	if err != nil {
+		return nil, err
+	}
+	vitals, err := s.Repo.GetByPetID(petID)
+	if err != nil {
+		return nil, err
+	}
+	completed := entities.LabOrderStatusCompleted
+	labOrders, err := s.LabRepo.GetOrdersByPetID(petID, &completed)
+	if err != nil {
+		return nil, err
+	}
+
+	points := []entities.VitalPoint{}
+	byAppointment := map[uuid.UUID]int{}
+	for _, app := range apps {
+		appointmentID := app.ID
+		points = append(points, entities.VitalPoint{
+			RecordedAt:    appointmentVitalsTime(app),
+			Source:        entities.VitalSourceAppointment,
+			AppointmentID: &appointmentID,
+			WeightKg:      app.WeightKg,
+			Temperature:   app.Temperature,
+		})
+		byAppointment[app.ID] = len(points) - 1
+	}
+	for _, v := range vitals {
+		vitalID := v.ID
+		if v.AppointmentID != nil {
+			if i, ok := byAppointment[*v.AppointmentID]; ok {
+				p := &points[i]
+				p.VitalSignID = &vitalID
+				if p.WeightKg == nil {
+					p.WeightKg = v.WeightKg
+				}
+				if p.Temperature == nil {
+					p.Temperature = v.Temperature
+				}
+				if v.HeartRate != nil {
+					p.HeartRate = v.HeartRate
+				}
+				if v.RespiratoryRate != nil {
+					p.RespiratoryRate = v.RespiratoryRate
+				}
+				if v.BodyConditionScore != nil {
+					p.BodyConditionScore = v.BodyConditionScore
+				}
+				continue
+			}
+		}
+		points = append(points, entities.VitalPoint{
+			RecordedAt:         v.RecordedAt,
+			Source:             entities.VitalSourceRecord,
+			AppointmentID:      v.AppointmentID,
+			VitalSignID:        &vitalID,
+			WeightKg:           v.WeightKg,
+			Temperature:        v.Temperature,
+			HeartRate:          v.HeartRate,
+			RespiratoryRate:    v.RespiratoryRate,
+			BodyConditionScore: v.BodyConditionScore,
+		})
+	}
+
+	for i := range labOrders {
+		o := &labOrders[i]
+		if o.ResultedAt == nil {
+			continue
+		}
+		appointmentID := o.AppointmentID
+		points = append(points, entities.VitalPoint{
+			RecordedAt:    *o.ResultedAt,
+			Source:        entities.VitalSourceLab,
+			AppointmentID: &appointmentID,
+			LabOrder:      o,
+		})
+	}
+
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].RecordedAt.Before(points[j].RecordedAt)
+	})
+	applyVitalChanges(points)
+	return points, nil
+}
+
+// checkPlausibleVitals rechaza valores imposibles para la especie de la
+// mascota, como un gato de 300 kg. La mascota debe traer precargados los
+// rangos de referencia de su especie.
+func checkPlausibleVitals(pet *entities.Pet, at time.Time, values map[string]float64) error {
+	ageWeeks, hasAge := pet.AgeInWeeks(at)
+	flag := entities.FirstImplausible(pet.Species.CheckVitals(values, ageWeeks, hasAge))
+	if flag == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %s de %g está fuera del rango posible para %s (%g a %g)",
+		ErrImplausibleVital, vitalNames[flag.Vital], flag.Value, pet.Species.Name, flag.Min, flag.Max)
+}
+
+// appointmentVitalsTime usa la hora en que terminó o empezó la consulta y,
+// si no se registró, la fecha y hora agendadas.
+func appointmentVitalsTime(app entities.Appointment) time.Time {
+	switch {
+	case app.FinishedAt != nil:
+		return *app.FinishedAt
+	case app.StartedAt != nil:
+		return *app.StartedAt
+	}
+	t, err := utils.ParseAppointmentDateTime(app.Date, app.Time)
+	if err != nil {
+		return app.CreatedAt
+	}
+	return t
+}
+
+// applyVitalChanges calcula, para cada signo de cada punto, el cambio
+// porcentual respecto de la toma anterior que registró ese mismo signo.
+func applyVitalChanges(points []entities.VitalPoint) {
+	previous := map[string]float64{}
+	for i := range points {
+		p := &points[i]
+		p.Changes = map[string]float64{}
+		values := map[string]*float64{
+			"weight_kg":            p.WeightKg,
+			"temperature":          p.Temperature,
+			"heart_rate":           intToFloat(p.HeartRate),
+			"respiratory_rate":     intToFloat(p.RespiratoryRate),
+			"body_condition_score": intToFloat(p.BodyConditionScore),
+		}
+		for key, value := range values {
+			if value == nil {
+				continue
+			}
+			if prev, ok := previous[key]; ok && prev != 0 {
+				p.Changes[key] = math.Round((*value-prev)/prev*1000) / 10
+			}
+			previous[key] = *value
+		}
+	}
+}
+
+func intToFloat(v *int) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
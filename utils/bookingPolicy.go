@@ -0,0 +1,27 @@
+package utils
+
+import "time"
+
+// Umbrales de inasistencias configurables por entorno. Un valor de 0
+// desactiva la política correspondiente.
+func NoShowDepositThreshold() int {
+	return GetEnvInt("NO_SHOW_DEPOSIT_THRESHOLD", 2)
+}
+
+func NoShowApprovalThreshold() int {
+	return GetEnvInt("NO_SHOW_APPROVAL_THRESHOLD", 3)
+}
+
+func RequiresDeposit(noShowCount int) bool {
+	threshold := NoShowDepositThreshold()
+	return threshold > 0 && noShowCount >= threshold
+}
+
+func RequiresApproval(noShowCount int) bool {
+	threshold := NoShowApprovalThreshold()
+	return threshold > 0 && noShowCount >= threshold
+}
+
+func AppointmentDuration() time.Duration {
+	return time.Duration(GetEnvInt("APPOINTMENT_DURATION_MINUTES", 30)) * time.Minute
+}
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	AppointmentDateLayout = "02-01-2006"
+	AppointmentTimeLayout = "15:04"
+)
+
+// ParseAppointmentDateTime combina la fecha (DD-MM-YYYY) y la hora (HH:MM)
+// con las que se guardan las citas en un time.Time en la zona local.
+func ParseAppointmentDateTime(date, clock string) (time.Time, error) {
+	t, err := time.ParseInLocation(AppointmentDateLayout+" "+AppointmentTimeLayout, date+" "+clock, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("fecha u hora inválida: %s %s", date, clock)
+	}
+	return t, nil
+}
@@ -0,0 +1,221 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png"
+)
+
+var (
+	ErrImageFormat   = errors.New("la imagen debe ser JPEG o PNG")
+	ErrImageTooLarge = errors.New("la imagen supera la resolución máxima permitida")
+)
+
+// maxImagePixels evita decodificar imágenes gigantes que agotarían la
+// memoria aunque el archivo comprimido sea pequeño. 16 MP cubre las fotos de
+// cualquier teléfono.
+const maxImagePixels = 16_000_000
+
+// DecodePhoto decodifica una foto JPEG o PNG, la reduce para que su lado
+// mayor no pase de maxSide y la endereza según la orientación EXIF, que se
+// pierde al volver a codificarla. Se reduce antes de girar para no copiar la
+// imagen completa otra vez.
+func DecodePhoto(data []byte, maxSide int) (*image.RGBA, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "jpeg" && format != "png") {
+		return nil, ErrImageFormat
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil, ErrImageTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrImageFormat
+	}
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+	return orient(ResizeToFit(toRGBA(img), maxSide), orientation), nil
+}
+
+// toRGBA devuelve la imagen como RGBA, copiándola solo si hace falta.
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
+
+// EncodeJPEG codifica la imagen sin metadatos: image/jpeg no escribe EXIF,
+// así que la ubicación GPS o el modelo de cámara del original no se guardan.
+func EncodeJPEG(img image.Image, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ResizeToFit reduce la imagen para que su lado mayor no pase de maxSide,
+// conservando la proporción. Las imágenes más pequeñas no se agrandan.
+func ResizeToFit(src *image.RGBA, maxSide int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if w <= maxSide && h <= maxSide {
+		return src
+	}
+	if w >= h {
+		return resizeBox(src, maxSide, max(1, h*maxSide/w))
+	}
+	return resizeBox(src, max(1, w*maxSide/h), maxSide)
+}
+
+// Thumbnail recorta el centro de la imagen en un cuadrado y lo reduce a
+// side x side.
+func Thumbnail(src *image.RGBA, side int) *image.RGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	n := min(w, h)
+	x0, y0 := b.Min.X+(w-n)/2, b.Min.Y+(h-n)/2
+	square := src.SubImage(image.Rect(x0, y0, x0+n, y0+n)).(*image.RGBA)
+	if n <= side {
+		return square
+	}
+	return resizeBox(square, side, side)
+}
+
+// resizeBox reduce la imagen promediando los píxeles de origen que cubre
+// cada píxel de destino. Para reducir fotos da buen resultado sin depender
+// de librerías externas.
+func resizeBox(src *image.RGBA, dw, dh int) *image.RGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		sy0 := y * sh / dh
+		sy1 := max((y+1)*sh/dh, sy0+1)
+		for x := 0; x < dw; x++ {
+			sx0 := x * sw / dw
+			sx1 := max((x+1)*sw/dw, sx0+1)
+			var r, g, bl, a, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				off := src.PixOffset(b.Min.X+sx0, b.Min.Y+sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint32(src.Pix[off])
+					g += uint32(src.Pix[off+1])
+					bl += uint32(src.Pix[off+2])
+					a += uint32(src.Pix[off+3])
+					off += 4
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(bl / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// orient aplica la orientación EXIF (1 a 8). Con la orientación normal
+// devuelve la misma imagen.
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			i, j := dst.PixOffset(x, y), src.PixOffset(b.Min.X+sx, b.Min.Y+sy)
+			copy(dst.Pix[i:i+4], src.Pix[j:j+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation lee la etiqueta Orientation (0x0112) del bloque EXIF del
+// JPEG. Devuelve 1 si no la encuentra.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if marker == 0xDA || length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 1
+}
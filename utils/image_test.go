@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// exifSegment arma un bloque APP1 con un IFD de una sola entrada.
+func exifSegment(order binary.ByteOrder, tag uint16, value uint16) []byte {
+	tiff := make([]byte, 8+2+12)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], tag)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], value)
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	return segment(0xE1, payload)
+}
+
+func segment(marker byte, payload []byte) []byte {
+	out := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(out[2:], uint16(len(payload)+2))
+	return append(out, payload...)
+}
+
+func jpegWith(segments ...[]byte) []byte {
+	data := []byte{0xFF, 0xD8}
+	for _, s := range segments {
+		data = append(data, s...)
+	}
+	return append(data, 0xFF, 0xDA, 0x00, 0x02)
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	jfif := segment(0xE0, []byte("JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00"))
+	truncated := jpegWith(exifSegment(binary.LittleEndian, 0x0112, 6))
+	truncated = truncated[:len(truncated)-12]
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"little endian", jpegWith(exifSegment(binary.LittleEndian, 0x0112, 6)), 6},
+		{"big endian", jpegWith(exifSegment(binary.BigEndian, 0x0112, 8)), 8},
+		{"EXIF después de JFIF", jpegWith(jfif, exifSegment(binary.BigEndian, 0x0112, 3)), 3},
+		{"EXIF sin orientación", jpegWith(exifSegment(binary.LittleEndian, 0x010F, 6)), 1},
+		{"sin EXIF", jpegWith(jfif), 1},
+		{"segmento truncado", truncated, 1},
+		{"no es JPEG", []byte("\x89PNG\r\n\x1a\n"), 1},
+		{"vacío", nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jpegOrientation(tt.data); got != tt.want {
+				t.Errorf("jpegOrientation() = %d, se esperaba %d", got, tt.want)
+			}
+		})
+	}
+}
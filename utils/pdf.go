@@ -0,0 +1,319 @@
+package utils
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Tamaño A4 en puntos y márgenes de la página.
+const (
+	pdfPageWidth    = 595.28
+	pdfPageHeight   = 841.89
+	pdfMargin       = 50.0
+	pdfHeaderHeight = 60.0
+	pdfFooterHeight = 40.0
+)
+
+const (
+	pdfFontRegular = "F1"
+	pdfFontBold    = "F2"
+)
+
+// PDFDocument genera documentos PDF sencillos de texto con las fuentes
+// Helvetica estándar, sin dependencias externas. El texto se codifica en
+// WinAnsi, suficiente para el español. Cada página lleva el encabezado con el
+// nombre de la clínica y un pie con la numeración.
+type PDFDocument struct {
+	Title    string
+	Subtitle string
+	Footer   string
+
+	pages   []*bytes.Buffer
+	current *bytes.Buffer
+	y       float64
+}
+
+func NewPDFDocument(title, subtitle, footer string) *PDFDocument {
+	return &PDFDocument{Title: title, Subtitle: subtitle, Footer: footer}
+}
+
+// AddPage comienza una página nueva y ubica el cursor bajo el encabezado.
+func (d *PDFDocument) AddPage() {
+	d.current = &bytes.Buffer{}
+	d.pages = append(d.pages, d.current)
+	d.y = pdfPageHeight - pdfMargin - pdfHeaderHeight
+}
+
+// ensureSpace salta de página si no caben height puntos más.
+func (d *PDFDocument) ensureSpace(height float64) {
+	if d.current == nil || d.y-height < pdfMargin+pdfFooterHeight {
+		d.AddPage()
+	}
+}
+
+func (d *PDFDocument) Space(height float64) {
+	d.ensureSpace(height)
+	d.y -= height
+}
+
+func (d *PDFDocument) Heading(text string) {
+	d.ensureSpace(40)
+	d.y -= 8
+	d.write(text, pdfFontBold, 13, 0)
+}
+
+func (d *PDFDocument) Subheading(text string) {
+	d.ensureSpace(30)
+	d.y -= 4
+	d.write(text, pdfFontBold, 11, 0)
+}
+
+// Paragraph escribe texto ajustado al ancho de la página; los saltos de línea
+// del texto se respetan.
+func (d *PDFDocument) Paragraph(text string) {
+	d.write(text, pdfFontRegular, 10, 0)
+}
+
+// Field escribe "etiqueta: valor" con la etiqueta en negrita. Los valores
+// vacíos se omiten.
+func (d *PDFDocument) Field(label, value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	label += ": "
+	indent := pdfTextWidth(label, pdfFontBold, 10)
+	d.ensureSpace(14)
+	d.y -= 14
+	d.text(pdfMargin, d.y, label, pdfFontBold, 10)
+	lines := pdfWrap(value, pdfFontRegular, 10, pdfPageWidth-2*pdfMargin-indent)
+	for i, line := range lines {
+		if i > 0 {
+			d.ensureSpace(14)
+			d.y -= 14
+		}
+		d.text(pdfMargin+indent, d.y, line, pdfFontRegular, 10)
+	}
+}
+
+// Rule dibuja una línea horizontal de margen a margen.
+func (d *PDFDocument) Rule() {
+	d.ensureSpace(10)
+	d.y -= 6
+	fmt.Fprintf(d.current, "0.6 w 0.7 G %.2f %.2f m %.2f %.2f l S 0 G\n", pdfMargin, d.y, pdfPageWidth-pdfMargin, d.y)
+	d.y -= 4
+}
+
+func (d *PDFDocument) write(text, font string, size, indent float64) {
+	lineHeight := size * 1.4
+	for _, line := range pdfWrap(text, font, size, pdfPageWidth-2*pdfMargin-indent) {
+		d.ensureSpace(lineHeight)
+		d.y -= lineHeight
+		d.text(pdfMargin+indent, d.y, line, font, size)
+	}
+}
+
+func (d *PDFDocument) text(x, y float64, text, font string, size float64) {
+	fmt.Fprintf(d.current, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfEscape(text))
+}
+
+// decorate agrega a cada página el encabezado y el pie con "Página n de total".
+func (d *PDFDocument) decorate(page int, content *bytes.Buffer) []byte {
+	var out bytes.Buffer
+	top := pdfPageHeight - pdfMargin
+	fmt.Fprintf(&out, "BT /%s 16 Tf %.2f %.2f Td (%s) Tj ET\n", pdfFontBold, pdfMargin, top-16, pdfEscape(d.Title))
+	if d.Subtitle != "" {
+		fmt.Fprintf(&out, "BT /%s 9 Tf %.2f %.2f Td (%s) Tj ET\n", pdfFontRegular, pdfMargin, top-30, pdfEscape(d.Subtitle))
+	}
+	fmt.Fprintf(&out, "1 w 0.2 0.4 0.6 RG %.2f %.2f m %.2f %.2f l S 0 G\n", pdfMargin, top-40, pdfPageWidth-pdfMargin, top-40)
+	out.Write(content.Bytes())
+
+	bottom := pdfMargin
+	fmt.Fprintf(&out, "0.6 w 0.7 G %.2f %.2f m %.2f %.2f l S 0 G\n", pdfMargin, bottom+18, pdfPageWidth-pdfMargin, bottom+18)
+	if d.Footer != "" {
+		fmt.Fprintf(&out, "BT /%s 8 Tf %.2f %.2f Td (%s) Tj ET\n", pdfFontRegular, pdfMargin, bottom+6, pdfEscape(d.Footer))
+	}
+	pageLabel := fmt.Sprintf("Página %d de %d", page, len(d.pages))
+	x := pdfPageWidth - pdfMargin - pdfTextWidth(pageLabel, pdfFontRegular, 8)
+	fmt.Fprintf(&out, "BT /%s 8 Tf %.2f %.2f Td (%s) Tj ET\n", pdfFontRegular, x, bottom+6, pdfEscape(pageLabel))
+	return out.Bytes()
+}
+
+// Bytes arma el archivo PDF completo.
+func (d *PDFDocument) Bytes() ([]byte, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// Objetos fijos: 1 catálogo, 2 árbol de páginas, 3 y 4 fuentes, 5 datos.
+	// Cada página ocupa dos objetos a partir del 6: la página y su contenido.
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (VetiCare) /CreationDate (D:%s) >>",
+		pdfEscape(d.Title), time.Now().Format("20060102150405")))
+
+	for i, page := range d.pages {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(d.decorate(i+1, page)); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, pdfFontRegular, pdfFontBold, 7+2*i))
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes(), nil
+}
+
+// pdfWinAnsi convierte el texto a WinAnsi (cp1252). Los caracteres que no
+// existen en esa codificación se reemplazan por "?".
+func pdfWinAnsi(s string) []byte {
+	special := map[rune]byte{
+		'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+		'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+	}
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r < 0x20:
+			continue
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		default:
+			if b, ok := special[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, c := range pdfWinAnsi(s) {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// pdfWrap divide el texto en líneas que caben en width puntos.
+func pdfWrap(text, font string, size, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := ""
+		for _, word := range words {
+			for pdfTextWidth(word, font, size) > width {
+				// Palabras más largas que la línea se cortan por caracteres.
+				runes := []rune(word)
+				cut := len(runes)
+				for cut > 1 && pdfTextWidth(string(runes[:cut]), font, size) > width {
+					cut--
+				}
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				lines = append(lines, string(runes[:cut]))
+				word = string(runes[cut:])
+			}
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if pdfTextWidth(candidate, font, size) > width {
+				lines = append(lines, line)
+				line = word
+			} else {
+				line = candidate
+			}
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Anchos de Helvetica y Helvetica-Bold (en milésimas del tamaño) para los
+// caracteres ASCII imprimibles, del 32 al 126.
+var (
+	pdfHelveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	pdfHelveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+	// Los caracteres acentuados miden lo mismo que su letra base.
+	pdfBaseLetters = strings.NewReplacer(
+		"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+		"Á", "A", "É", "E", "Í", "I", "Ó", "O", "Ú", "U", "Ü", "U", "Ñ", "N",
+		"¿", "?", "¡", "!",
+	)
+)
+
+func pdfTextWidth(text, font string, size float64) float64 {
+	widths := &pdfHelveticaWidths
+	if font == pdfFontBold {
+		widths = &pdfHelveticaBoldWidths
+	}
+	total := 0
+	for _, r := range pdfBaseLetters.Replace(text) {
+		if r >= 32 && r <= 126 {
+			total += widths[r-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidShareToken = errors.New("enlace compartido inválido")
+
+// shareLinkKey firma los enlaces compartidos. Si no se define SHARE_LINK_KEY
+// se usa la misma clave de los JWT.
+func shareLinkKey() []byte {
+	if key := os.Getenv("SHARE_LINK_KEY"); key != "" {
+		return []byte(key)
+	}
+	return jwtKey
+}
+
+func shareSignature(id string, exp int64) string {
+	mac := hmac.New(sha256.New, shareLinkKey())
+	mac.Write([]byte("share:" + id + ":" + strconv.FormatInt(exp, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignShareToken arma el token "<id>.<vencimiento>.<firma>" de un enlace
+// compartido. La firma evita que se adivinen o alteren los enlaces sin
+// consultar la base de datos.
+func SignShareToken(id string, expiresAt time.Time) string {
+	exp := expiresAt.Unix()
+	return id + "." + strconv.FormatInt(exp, 10) + "." + shareSignature(id, exp)
+}
+
+// VerifyShareToken comprueba la firma del token y devuelve el ID del enlace y
+// su vencimiento. El vencimiento lo valida quien llama, para poder registrar
+// el intento.
+func VerifyShareToken(token string) (string, time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", time.Time{}, ErrInvalidShareToken
+	}
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}, ErrInvalidShareToken
+	}
+	expected := shareSignature(parts[0], exp)
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return "", time.Time{}, ErrInvalidShareToken
+	}
+	return parts[0], time.Unix(exp, 0), nil
+}
+
+// TrustedProxies devuelve las direcciones de TRUSTED_PROXIES, separadas por
+// coma. Solo a esos proxies se les cree la cabecera X-Forwarded-For.
+func TrustedProxies() map[string]bool {
+	proxies := map[string]bool{}
+	for _, addr := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			proxies[addr] = true
+		}
+	}
+	return proxies
+}
+
+// ShareLinkURL devuelve la dirección que se entrega al destinatario. Con
+// SHARE_LINK_BASE_URL se apunta al front-end; si no, a la API.
+func ShareLinkURL(token string) string {
+	base := GetEnv("SHARE_LINK_BASE_URL", "/api/shared/")
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	return base + token
+}
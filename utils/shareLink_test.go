@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShareToken(t *testing.T) {
+	t.Setenv("SHARE_LINK_KEY", "clave-de-prueba")
+	const id = "3f1c2a9e-7b4d-4e8a-9c1f-2d5e6a7b8c9d"
+	expiresAt := time.Date(2030, 1, 15, 10, 30, 0, 0, time.UTC)
+	token := SignShareToken(id, expiresAt)
+	parts := strings.Split(token, ".")
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"token válido", token, true},
+		{"ID alterado", "4f1c2a9e-7b4d-4e8a-9c1f-2d5e6a7b8c9d." + parts[1] + "." + parts[2], false},
+		{"vencimiento alterado", parts[0] + ".1900000000." + parts[2], false},
+		{"firma alterada", parts[0] + "." + parts[1] + "." + strings.Repeat("A", len(parts[2])), false},
+		{"sin firma", parts[0] + "." + parts[1], false},
+		{"partes de más", token + ".extra", false},
+		{"vencimiento no numérico", parts[0] + ".mañana." + parts[2], false},
+		{"vacío", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotID, gotExp, err := VerifyShareToken(tt.token)
+			if !tt.valid {
+				if !errors.Is(err, ErrInvalidShareToken) {
+					t.Errorf("VerifyShareToken(%q) = %v, se esperaba ErrInvalidShareToken", tt.token, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyShareToken(%q) = %v", tt.token, err)
+			}
+			if gotID != id || !gotExp.Equal(expiresAt) {
+				t.Errorf("VerifyShareToken(%q) = (%q, %v), se esperaba (%q, %v)", tt.token, gotID, gotExp, id, expiresAt)
+			}
+		})
+	}
+}
+
+func TestShareTokenOtherKey(t *testing.T) {
+	t.Setenv("SHARE_LINK_KEY", "clave-anterior")
+	token := SignShareToken("3f1c2a9e-7b4d-4e8a-9c1f-2d5e6a7b8c9d", time.Now().Add(time.Hour))
+	t.Setenv("SHARE_LINK_KEY", "clave-nueva")
+	if _, _, err := VerifyShareToken(token); !errors.Is(err, ErrInvalidShareToken) {
+		t.Errorf("un token firmado con otra clave no debe aceptarse, err = %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"strings"
+)
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+)
+
+// NormalizeText pasa el texto a minúsculas, quita tildes y espacios sobrantes
+// para comparar nombres escritos a mano.
+func NormalizeText(s string) string {
+	s = accentReplacer.Replace(strings.ToLower(s))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Levenshtein cuenta las inserciones, borrados y sustituciones de letras
+// necesarios para convertir a en b. Sirve para aceptar errores de escritura
+// al comparar textos ya normalizados.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// NormalizeMicrochip quita los espacios, guiones y puntos con los que los
+// lectores y las etiquetas suelen agrupar los dígitos del microchip.
+func NormalizeMicrochip(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '.' {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,24 @@
+package utils
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"poodle", "poodle", 0},
+		{"labradr", "labrador", 1},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"ñandú", "nandu", 2},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, se esperaba %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
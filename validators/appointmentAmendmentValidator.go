@@ -0,0 +1,56 @@
+package validators
+
+import (
+	"VetiCare/entities/dto"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidAmendmentReason = errors.New("el motivo de la enmienda es obligatorio y debe tener máximo 500 caracteres")
+
+func ValidateAppointmentAmendmentInputDTO(in dto.AppointmentAmendmentInputDTO) error {
+	reason := strings.TrimSpace(in.AmendmentReason)
+	if reason == "" || utf8.RuneCountInString(reason) > 500 {
+		return ErrInvalidAmendmentReason
+	}
+	if err := ValidatePositiveFloat(in.WeightKg, ErrInvalidWeight); err != nil {
+		return err
+	}
+	if err := ValidatePositiveFloat(in.Temperature, ErrInvalidTemperature); err != nil {
+		return err
+	}
+	texts := []struct {
+		value *string
+		max   int
+		err   error
+	}{
+		{in.Reason, 300, ErrInvalidReasonLength},
+		{in.VaccinationStatus, 300, ErrInvalidVaccinationLen},
+		{in.MedicationsPrescribed, 300, ErrInvalidMedicationsLen},
+		{in.AdditionalNotes, 500, ErrInvalidAdditionalNotes},
+	}
+	for _, t := range texts {
+		if t.value != nil {
+			if err := ValidateMaxLen(*t.value, t.max, t.err); err != nil {
+				return err
+			}
+		}
+	}
+	text := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	note := dto.ClinicalNoteInputDTO{
+		Subjective: text(in.Subjective),
+		Objective:  text(in.Objective),
+		Assessment: text(in.Assessment),
+		Plan:       text(in.Plan),
+	}
+	if in.DiagnosisCodes != nil {
+		note.DiagnosisCodes = *in.DiagnosisCodes
+	}
+	return ValidateClinicalNoteInputDTO(note)
+}
@@ -12,7 +12,7 @@ var (
 	ErrInvalidPetID           = errors.New("pet_id es obligatorio y debe ser un UUID válido")
 	ErrInvalidVetID           = errors.New("vet_id debe ser un UUID válido")
 	ErrInvalidDateOnly        = errors.New("la fecha es obligatoria y debe tener formato DD-MM-YYYY")
-	ErrInvalidTimeOnly        = errors.New("la hora es obligatoria y debe tener formato HH.MM")
+	ErrInvalidTimeOnly        = errors.New("la hora es obligatoria y debe tener formato HH:MM")
 	ErrInvalidDateTimeInPast  = errors.New("la fecha y hora de la cita no pueden ser en el pasado")
 	ErrInvalidWeight          = errors.New("el peso debe ser un número positivo")
 	ErrInvalidTemperature     = errors.New("la temperatura debe ser un número positivo")
@@ -74,7 +74,7 @@ func ValidateDateTimeNotPast(dateOnly, timeOnly string) error {
 	if err != nil {
 		return ErrInvalidDateOnly
 	}
-	timeParsed, err := time.Parse("15.04", timeOnly)
+	timeParsed, err := time.Parse("15:04", timeOnly)
 	if err != nil {
 		return ErrInvalidTimeOnly
 	}
@@ -88,7 +88,7 @@ func ValidateDateTimeNotPast(dateOnly, timeOnly string) error {
 }
 
 func ValidateStatusID(statusID int) error {
-	if statusID < 1 || statusID > 3 {
+	if statusID < 1 || statusID > 6 {
 		return errors.New("Status_id inválido, debe ser un valor numerico")
 	}
 	return nil
@@ -152,3 +152,32 @@ func ValidateAppointmentDTO(app dto.AppointmentDTO) error {
 	}
 	return nil
 }
+
+func ValidateRescheduleDTO(in dto.RescheduleAppointmentDTO) error {
+	if in.Date == "" {
+		return ErrInvalidDateOnly
+	}
+	if in.Time == "" {
+		return ErrInvalidTimeOnly
+	}
+	if err := ValidateDate(in.Date); err != nil {
+		return err
+	}
+	if err := ValidateTime(in.Time); err != nil {
+		return err
+	}
+	if err := ValidateDateTimeNotPast(in.Date, in.Time); err != nil {
+		return err
+	}
+	return ValidateMaxLen(in.Reason, 300, ErrInvalidReasonLength)
+}
+
+func ValidateWalkInDTO(in dto.WalkInDTO) error {
+	if err := ValidateUUIDRequired(in.PetID); err != nil {
+		return ErrInvalidPetID
+	}
+	if err := ValidateUUIDOptional(in.VetID); err != nil {
+		return ErrInvalidVetID
+	}
+	return ValidateMaxLen(in.Reason, 300, ErrInvalidReasonLength)
+}
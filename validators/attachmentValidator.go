@@ -0,0 +1,30 @@
+package validators
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidAttachmentFile        = errors.New("debe enviar el archivo en el campo file")
+	ErrInvalidAttachmentName        = errors.New("el nombre del archivo debe tener máximo 255 caracteres")
+	ErrInvalidAttachmentDescription = errors.New("la descripción debe tener máximo 300 caracteres")
+	ErrAttachmentTooLarge           = errors.New("el archivo supera el tamaño máximo permitido")
+)
+
+// ValidateAttachmentUpload revisa los datos del formulario; el tipo de archivo
+// lo valida el servicio a partir del contenido.
+func ValidateAttachmentUpload(fileName, description string, appointmentID *string, size, maxBytes int64) error {
+	if fileName == "" {
+		return ErrInvalidAttachmentFile
+	}
+	if err := ValidateMaxLen(fileName, 255, ErrInvalidAttachmentName); err != nil {
+		return err
+	}
+	if err := ValidateMaxLen(description, 300, ErrInvalidAttachmentDescription); err != nil {
+		return err
+	}
+	if size > maxBytes {
+		return ErrAttachmentTooLarge
+	}
+	return ValidateUUIDOptional(appointmentID)
+}
@@ -0,0 +1,25 @@
+package validators
+
+import (
+	"VetiCare/entities/dto"
+	"errors"
+)
+
+var (
+	ErrInvalidBreedName    = errors.New("el nombre de la raza es obligatorio y debe tener máximo 100 caracteres")
+	ErrInvalidBreedAliases = errors.New("los alias de la raza deben tener máximo 300 caracteres")
+)
+
+func ValidateBreedName(name string) error {
+	if len(name) == 0 || len(name) > 100 {
+		return ErrInvalidBreedName
+	}
+	return nil
+}
+
+func ValidateBreedDTO(in dto.BreedDTO) error {
+	if err := ValidateBreedName(in.Name); err != nil {
+		return err
+	}
+	return ValidateMaxLen(in.Aliases, 300, ErrInvalidBreedAliases)
+}
@@ -0,0 +1,45 @@
+package validators
+
+import (
+	"VetiCare/entities/dto"
+	"errors"
+)
+
+var (
+	ErrInvalidTimeOffRange     = errors.New("la fecha final de la ausencia no puede ser anterior a la inicial")
+	ErrInvalidTimeOffReason    = errors.New("el motivo de la ausencia debe tener máximo 200 caracteres")
+	ErrInvalidClosureDesc      = errors.New("la descripción del cierre es obligatoria y debe tener máximo 200 caracteres")
+	ErrInvalidCalendarDateOnly = errors.New("las fechas son obligatorias y deben tener formato DD-MM-YYYY")
+	ErrReassignHashRequired    = errors.New("proposal_hash es obligatorio; se obtiene de la vista previa")
+	ErrInvalidReassignReason   = errors.New("el motivo de la reasignación debe tener máximo 300 caracteres")
+)
+
+func ValidateTimeOffDTO(in dto.TimeOffInputDTO) error {
+	if in.StartDate == "" || in.EndDate == "" {
+		return ErrInvalidCalendarDateOnly
+	}
+	if ValidateDate(in.StartDate) != nil || ValidateDate(in.EndDate) != nil {
+		return ErrInvalidCalendarDateOnly
+	}
+	return ValidateMaxLen(in.Reason, 200, ErrInvalidTimeOffReason)
+}
+
+func ValidateClinicClosureDTO(in dto.ClinicClosureInputDTO) error {
+	if in.Date == "" || ValidateDate(in.Date) != nil {
+		return ErrInvalidCalendarDateOnly
+	}
+	if in.Description == "" || len(in.Description) > 200 {
+		return ErrInvalidClosureDesc
+	}
+	return nil
+}
+
+func ValidateReassignmentDTO(in dto.ReassignmentInputDTO) error {
+	if in.From == "" || in.To == "" {
+		return ErrInvalidCalendarDateOnly
+	}
+	if ValidateDate(in.From) != nil || ValidateDate(in.To) != nil {
+		return ErrInvalidCalendarDateOnly
+	}
+	return ValidateMaxLen(in.Reason, 300, ErrInvalidReassignReason)
+}
@@ -0,0 +1,37 @@
+package validators
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidClinicName    = errors.New("el nombre de la clínica es obligatorio y debe tener máximo 100 caracteres")
+	ErrInvalidClinicAddress = errors.New("la dirección de la clínica debe tener máximo 200 caracteres")
+	ErrInvalidRoomName      = errors.New("el nombre es obligatorio y debe tener máximo 80 caracteres")
+	ErrInvalidCapacity      = errors.New("la capacidad debe ser un número mayor que cero")
+)
+
+func ValidateClinicName(name string) error {
+	if len(name) == 0 || len(name) > 100 {
+		return ErrInvalidClinicName
+	}
+	return nil
+}
+
+func ValidateClinicAddress(address string) error {
+	return ValidateMaxLen(address, 200, ErrInvalidClinicAddress)
+}
+
+func ValidateRoomName(name string) error {
+	if len(name) == 0 || len(name) > 80 {
+		return ErrInvalidRoomName
+	}
+	return nil
+}
+
+func ValidateCapacity(capacity int) error {
+	if capacity < 1 {
+		return ErrInvalidCapacity
+	}
+	return nil
+}
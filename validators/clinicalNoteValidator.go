@@ -0,0 +1,48 @@
+package validators
+
+import (
+	"VetiCare/entities/dto"
+	"errors"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidNoteSection      = errors.New("cada sección de la nota debe tener máximo 20000 caracteres")
+	ErrInvalidDiagnosisCodes   = errors.New("se admiten hasta 20 códigos de diagnóstico de máximo 20 caracteres cada uno")
+	ErrInvalidNoteTemplateName = errors.New("el nombre de la plantilla es obligatorio y debe tener máximo 100 caracteres")
+)
+
+// MaxNoteSectionLength es un tope de seguridad; las secciones se guardan como
+// texto sin límite en la base de datos.
+const MaxNoteSectionLength = 20000
+
+func validateNoteSections(sections ...string) error {
+	for _, s := range sections {
+		if utf8.RuneCountInString(s) > MaxNoteSectionLength {
+			return ErrInvalidNoteSection
+		}
+	}
+	return nil
+}
+
+func ValidateClinicalNoteInputDTO(in dto.ClinicalNoteInputDTO) error {
+	if err := validateNoteSections(in.Subjective, in.Objective, in.Assessment, in.Plan); err != nil {
+		return err
+	}
+	if len(in.DiagnosisCodes) > 20 {
+		return ErrInvalidDiagnosisCodes
+	}
+	for _, code := range in.DiagnosisCodes {
+		if len(code) > 20 {
+			return ErrInvalidDiagnosisCodes
+		}
+	}
+	return nil
+}
+
+func ValidateClinicalNoteTemplateDTO(in dto.ClinicalNoteTemplateDTO) error {
+	if in.Name == "" || len(in.Name) > 100 {
+		return ErrInvalidNoteTemplateName
+	}
+	return validateNoteSections(in.Subjective, in.Objective, in.Assessment, in.Plan)
+}
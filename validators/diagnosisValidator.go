@@ -0,0 +1,43 @@
+package validators
+
+import (
+	"VetiCare/entities/dto"
+	"errors"
+	"regexp"
+)
+
+var (
+	ErrInvalidDiagnosisCode        = errors.New("el código del diagnóstico es obligatorio, debe tener máximo 20 caracteres y solo letras, números, puntos o guiones")
+	ErrInvalidDiagnosisName        = errors.New("el nombre del diagnóstico es obligatorio y debe tener máximo 150 caracteres")
+	ErrInvalidDiagnosisCategory    = errors.New("la categoría del diagnóstico debe tener máximo 50 caracteres")
+	ErrInvalidDiagnosisID          = errors.New("cada diagnóstico necesita diagnosis_id")
+	ErrInvalidDiagnosisNotes       = errors.New("las notas del diagnóstico deben tener máximo 300 caracteres")
+	ErrTooManyAppointmentDiagnoses = errors.New("se admiten hasta 20 diagnósticos por cita")
+)
+
+var diagnosisCodeRegex = regexp.MustCompile(`^[A-Z0-9.\-]{1,20}$`)
+
+func ValidateDiagnosisDTO(in dto.DiagnosisDTO) error {
+	if !diagnosisCodeRegex.MatchString(in.Code) {
+		return ErrInvalidDiagnosisCode
+	}
+	if len(in.Name) == 0 || len(in.Name) > 150 {
+		return ErrInvalidDiagnosisName
+	}
+	return ValidateMaxLen(in.Category, 50, ErrInvalidDiagnosisCategory)
+}
+
+func ValidateAppointmentDiagnosesInputDTO(in dto.AppointmentDiagnosesInputDTO) error {
+	if len(in.Diagnoses) > 20 {
+		return ErrTooManyAppointmentDiagnoses
+	}
+	for _, d := range in.Diagnoses {
+		if d.DiagnosisID <= 0 {
+			return ErrInvalidDiagnosisID
+		}
+		if err := ValidateMaxLen(d.Notes, 300, ErrInvalidDiagnosisNotes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package validators
+
+import (
+	"VetiCare/entities/dto"
+	"errors"
+)
+
+var (
+	ErrInvalidLabTestName    = errors.New("el nombre de la prueba es obligatorio y debe tener máximo 100 caracteres")
+	ErrInvalidLabTestCode    = errors.New("el código de la prueba debe tener máximo 20 caracteres")
+	ErrInvalidLabSampleType  = errors.New("el tipo de muestra debe tener máximo 50 caracteres")
+	ErrInvalidLabAnalyteName = errors.New("el nombre del analito es obligatorio y debe tener máximo 100 caracteres")
+	ErrInvalidLabAnalyteUnit = errors.New("la unidad del analito debe tener máximo 30 caracteres")
+	ErrInvalidLabRange       = errors.New("el rango de referencia necesita species_id y un mínimo menor que el máximo")
+	ErrInvalidLabTestID      = errors.New("lab_test_id es obligatorio")
+	ErrInvalidLaboratory     = errors.New("el laboratorio es obligatorio en los envíos externos y debe tener máximo 100 caracteres")
+	ErrInvalidLabOrderNotes  = errors.New("las notas deben tener máximo 500 caracteres")
+	ErrInvalidLabAnalyteID   = errors.New("cada resultado necesita analyte_id")
+	ErrInvalidLabTextValue   = errors.New("el valor de texto debe tener máximo 100 caracteres")
+	ErrInvalidLabResultNotes = errors.New("las notas del resultado deben tener máximo 1000 caracteres")
+)
+
+func ValidateLabTestName(name string) error {
+	if len(name) == 0 || len(name) > 100 {
+		return ErrInvalidLabTestName
+	}
+	return nil
+}
+
+func ValidateLabTestDTO(in dto.LabTestDTO) error {
+	if err := ValidateLabTestName(in.Name); err != nil {
+		return err
+	}
+	if err := ValidateMaxLen(in.Code, 20, ErrInvalidLabTestCode); err != nil {
+		return err
+	}
+	if err := ValidateMaxLen(in.SampleType, 50, ErrInvalidLabSampleType); err != nil {
+		return err
+	}
+	for _, a := range in.Analytes {
+		if err := ValidateLabAnalyteDTO(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ValidateLabAnalyteDTO(in dto.LabAnalyteDTO) error {
+	if len(in.Name) == 0 || len(in.Name) > 100 {
+		return ErrInvalidLabAnalyteName
+	}
+	if err := ValidateMaxLen(in.Unit, 30, ErrInvalidLabAnalyteUnit); err != nil {
+		return err
+	}
+	seen := map[int]bool{}
+	for _, rng := range in.Ranges {
+		if err := ValidateLabReferenceRangeDTO(rng); err != nil {
+			return err
+		}
+		if seen[rng.SpeciesID] {
+			return ErrInvalidLabRange
+		}
+		seen[rng.SpeciesID] = true
+	}
+	return nil
+}
+
+func ValidateLabReferenceRangeDTO(in dto.LabReferenceRangeDTO) error {
+	if in.SpeciesID <= 0 || in.Min >= in.Max {
+		return ErrInvalidLabRange
+	}
+	return nil
+}
+
+func ValidateLabOrderInputDTO(in dto.LabOrderInputDTO) error {
+	if in.LabTestID <= 0 {
+		return ErrInvalidLabTestID
+	}
+	if (in.External && in.Laboratory == "") || len(in.Laboratory) > 100 {
+		return ErrInvalidLaboratory
+	}
+	return ValidateMaxLen(in.Notes, 500, ErrInvalidLabOrderNotes)
+}
+
+func ValidateLabResultsInputDTO(in dto.LabResultsInputDTO) error {
+	for _, r := range in.Results {
+		if r.AnalyteID <= 0 {
+			return ErrInvalidLabAnalyteID
+		}
+		if err := ValidateMaxLen(r.TextValue, 100, ErrInvalidLabTextValue); err != nil {
+			return err
+		}
+	}
+	return ValidateMaxLen(in.Notes, 1000, ErrInvalidLabResultNotes)
+}
@@ -0,0 +1,55 @@
+package validators
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"errors"
+)
+
+var (
+	ErrInvalidAllergyAgent    = errors.New("el agente de la alergia es obligatorio y debe tener máximo 100 caracteres")
+	ErrInvalidAllergyReaction = errors.New("la reacción debe tener máximo 200 caracteres")
+	ErrInvalidAllergySeverity = errors.New("la severidad debe ser leve, moderada o severa")
+	ErrInvalidConditionName   = errors.New("el nombre de la condición es obligatorio y debe tener máximo 120 caracteres")
+	ErrInvalidDiagnosedOn     = errors.New("la fecha de diagnóstico debe tener el formato DD-MM-YYYY")
+	ErrInvalidBehaviorAlert   = errors.New("la descripción del aviso es obligatoria y debe tener máximo 200 caracteres")
+	ErrInvalidPetHealthNote   = errors.New("las notas deben tener máximo 300 caracteres")
+)
+
+func ValidateAllergySeverity(severity string) error {
+	switch severity {
+	case entities.AllergySeverityMild, entities.AllergySeverityModerate, entities.AllergySeveritySevere:
+		return nil
+	}
+	return ErrInvalidAllergySeverity
+}
+
+func ValidatePetAllergyDTO(in dto.PetAllergyDTO) error {
+	if in.Agent == "" || len(in.Agent) > 100 {
+		return ErrInvalidAllergyAgent
+	}
+	if err := ValidateMaxLen(in.Reaction, 200, ErrInvalidAllergyReaction); err != nil {
+		return err
+	}
+	if err := ValidateAllergySeverity(in.Severity); err != nil {
+		return err
+	}
+	return ValidateMaxLen(in.Notes, 300, ErrInvalidPetHealthNote)
+}
+
+func ValidatePetConditionDTO(in dto.PetConditionDTO) error {
+	if in.Name == "" || len(in.Name) > 120 {
+		return ErrInvalidConditionName
+	}
+	if in.DiagnosedOn != nil && *in.DiagnosedOn != "" && ValidateDate(*in.DiagnosedOn) != nil {
+		return ErrInvalidDiagnosedOn
+	}
+	return ValidateMaxLen(in.Notes, 300, ErrInvalidPetHealthNote)
+}
+
+func ValidatePetBehaviorAlertDTO(in dto.PetBehaviorAlertDTO) error {
+	if in.Description == "" || len(in.Description) > 200 {
+		return ErrInvalidBehaviorAlert
+	}
+	return nil
+}
@@ -15,6 +15,14 @@ var (
 	ErrInvalidBirthDate = errors.New("la fecha de nacimiento debe ser una fecha válida y no futura")
 	ErrInvalidBreed     = errors.New("la raza, si se proporciona, debe tener máximo 50 caracteres")
 	ErrInvalidStatusID  = errors.New("el estado es obligatorio y debe ser un valor válido")
+	ErrInvalidBreedID   = errors.New("el ID de raza debe ser mayor que cero")
+
+	ErrInvalidMicrochip          = errors.New("el microchip debe tener 15 dígitos (ISO 11784/11785) con un código de país o fabricante válido")
+	ErrInvalidMicrochipImplanted = errors.New("la fecha de implante del microchip no puede ser futura ni anterior al nacimiento")
+	ErrInvalidMicrochipLocation  = errors.New("la ubicación del microchip debe tener máximo 100 caracteres")
+
+	ErrInvalidPetPhotoFile = errors.New("debe enviar la foto en el campo photo")
+	ErrPetPhotoTooLarge    = errors.New("la foto supera el tamaño máximo permitido")
 )
 
 func ValidatePetName(name string) error {
@@ -59,6 +67,41 @@ func ValidatePetBreed(breed *string) error {
 	return nil
 }
 
+func ValidatePetBreedID(breedID *int) error {
+	if breedID != nil && *breedID <= 0 {
+		return ErrInvalidBreedID
+	}
+	return nil
+}
+
+// ValidateMicrochip revisa un número ya normalizado. Los tres primeros
+// dígitos son el código de país ISO 3166 (001-899) o de fabricante
+// (900-998); 000 y 999 no se asignan a chips implantados.
+func ValidateMicrochip(number string) error {
+	if len(number) != 15 {
+		return ErrInvalidMicrochip
+	}
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return ErrInvalidMicrochip
+		}
+	}
+	if prefix := number[:3]; prefix == "000" || prefix == "999" {
+		return ErrInvalidMicrochip
+	}
+	return nil
+}
+
+func ValidateMicrochipImplantedAt(implantedAt, birthDate *time.Time) error {
+	if implantedAt == nil {
+		return nil
+	}
+	if implantedAt.After(time.Now()) || (birthDate != nil && implantedAt.Before(*birthDate)) {
+		return ErrInvalidMicrochipImplanted
+	}
+	return nil
+}
+
 func ValidatePetStatusID(statusID int) error {
 	if statusID < 1 {
 		return ErrInvalidStatusID
@@ -82,5 +125,16 @@ func ValidatePetDTO(pet dto.PetDTO) error {
 	if err := ValidatePetBreed(pet.Breed); err != nil {
 		return err
 	}
-	return nil
+	if err := ValidatePetBreedID(pet.BreedID); err != nil {
+		return err
+	}
+	if pet.Microchip != nil {
+		if err := ValidateMicrochip(*pet.Microchip); err != nil {
+			return err
+		}
+	}
+	if err := ValidateMicrochipImplantedAt(pet.MicrochipImplantedAt, pet.BirthDate); err != nil {
+		return err
+	}
+	return ValidateMaxLen(pet.MicrochipLocation, 100, ErrInvalidMicrochipLocation)
 }
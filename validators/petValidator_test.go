@@ -0,0 +1,36 @@
+package validators
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateMicrochip(t *testing.T) {
+	tests := []struct {
+		name   string
+		number string
+		valid  bool
+	}{
+		{"código de país", "222123456789012", true},
+		{"código de fabricante", "900123456789012", true},
+		{"último código de fabricante", "998000000000001", true},
+		{"prefijo 000 no asignado", "000123456789012", false},
+		{"prefijo 999 reservado", "999123456789012", false},
+		{"muy corto", "22212345678901", false},
+		{"muy largo", "2221234567890123", false},
+		{"con letras", "22212345678901A", false},
+		{"sin normalizar", "222 123 456 789", false},
+		{"vacío", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMicrochip(tt.number)
+			if tt.valid && err != nil {
+				t.Errorf("ValidateMicrochip(%q) = %v, se esperaba nil", tt.number, err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidMicrochip) {
+				t.Errorf("ValidateMicrochip(%q) = %v, se esperaba ErrInvalidMicrochip", tt.number, err)
+			}
+		})
+	}
+}
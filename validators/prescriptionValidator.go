@@ -0,0 +1,80 @@
+package validators
+
+import (
+	"VetiCare/entities/dto"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrPrescriptionItemsRequired = errors.New("la receta debe incluir al menos un medicamento")
+	ErrInvalidDrug               = errors.New("el medicamento es obligatorio y debe tener máximo 120 caracteres")
+	ErrInvalidStrength           = errors.New("la concentración debe tener máximo 50 caracteres")
+	ErrInvalidDose               = errors.New("la dosis es obligatoria y debe tener máximo 50 caracteres")
+	ErrInvalidRoute              = errors.New("vía de administración inválida")
+	ErrInvalidFrequency          = errors.New("la frecuencia es obligatoria y debe tener máximo 50 caracteres")
+	ErrInvalidDuration           = errors.New("la duración debe ser de 1 a 365 días")
+	ErrInvalidQuantity           = errors.New("la cantidad debe tener máximo 50 caracteres")
+	ErrInvalidInstructions       = errors.New("las indicaciones deben tener máximo 300 caracteres")
+	ErrInvalidPrescriptionNotes  = errors.New("las notas de la receta deben tener máximo 500 caracteres")
+	ErrInvalidVoidReason         = errors.New("el motivo de anulación es obligatorio y debe tener máximo 300 caracteres")
+)
+
+var PrescriptionRoutes = []string{
+	"oral", "tópica", "oftálmica", "ótica", "subcutánea", "intramuscular",
+	"intravenosa", "inhalada", "rectal", "transdérmica",
+}
+
+func ValidateRoute(route string) error {
+	route = strings.ToLower(strings.TrimSpace(route))
+	for _, r := range PrescriptionRoutes {
+		if r == route {
+			return nil
+		}
+	}
+	return ErrInvalidRoute
+}
+
+func ValidatePrescriptionItemDTO(in dto.PrescriptionItemDTO) error {
+	if in.Drug == "" || len(in.Drug) > 120 {
+		return ErrInvalidDrug
+	}
+	if err := ValidateMaxLen(in.Strength, 50, ErrInvalidStrength); err != nil {
+		return err
+	}
+	if in.Dose == "" || len(in.Dose) > 50 {
+		return ErrInvalidDose
+	}
+	if err := ValidateRoute(in.Route); err != nil {
+		return err
+	}
+	if in.Frequency == "" || len(in.Frequency) > 50 {
+		return ErrInvalidFrequency
+	}
+	if in.DurationDays < 1 || in.DurationDays > 365 {
+		return ErrInvalidDuration
+	}
+	if err := ValidateMaxLen(in.Quantity, 50, ErrInvalidQuantity); err != nil {
+		return err
+	}
+	return ValidateMaxLen(in.Instructions, 300, ErrInvalidInstructions)
+}
+
+func ValidatePrescriptionInputDTO(in dto.PrescriptionInputDTO) error {
+	if len(in.Items) == 0 {
+		return ErrPrescriptionItemsRequired
+	}
+	for _, item := range in.Items {
+		if err := ValidatePrescriptionItemDTO(item); err != nil {
+			return err
+		}
+	}
+	return ValidateMaxLen(in.Notes, 500, ErrInvalidPrescriptionNotes)
+}
+
+func ValidateVoidPrescriptionDTO(in dto.VoidPrescriptionDTO) error {
+	if strings.TrimSpace(in.Reason) == "" || len(in.Reason) > 300 {
+		return ErrInvalidVoidReason
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package validators
+
+import (
+	"VetiCare/entities/dto"
+	"errors"
+)
+
+var (
+	ErrInvalidDewormingProduct = errors.New("el producto es obligatorio y debe tener máximo 120 caracteres")
+	ErrInvalidDewormingDose    = errors.New("la dosis debe tener máximo 50 caracteres")
+	ErrInvalidProtocolName     = errors.New("el nombre del protocolo es obligatorio y debe tener máximo 100 caracteres")
+	ErrInvalidProtocolAges     = errors.New("las edades del protocolo deben ser positivas y la edad máxima mayor que la mínima")
+	ErrInvalidProtocolInterval = errors.New("el intervalo del protocolo debe ser mayor que cero")
+)
+
+func ValidateDewormingInputDTO(in dto.DewormingInputDTO) error {
+	if in.Product == "" || len(in.Product) > 120 {
+		return ErrInvalidDewormingProduct
+	}
+	if err := ValidateMaxLen(in.Dose, 50, ErrInvalidDewormingDose); err != nil {
+		return err
+	}
+	if err := ValidatePositiveFloat(in.WeightKg, ErrInvalidWeight); err != nil {
+		return err
+	}
+	if in.DateGiven == "" || ValidateDate(in.DateGiven) != nil {
+		return ErrInvalidDateGiven
+	}
+	if in.NextDueDate != "" && ValidateDate(in.NextDueDate) != nil {
+		return ErrInvalidNextDueDate
+	}
+	if err := ValidateUUIDOptional(in.AppointmentID); err != nil {
+		return err
+	}
+	if err := ValidateUUIDOptional(in.AdministeredByID); err != nil {
+		return ErrInvalidVetID
+	}
+	return ValidateMaxLen(in.Notes, 500, ErrInvalidVaccinationNote)
+}
+
+func ValidatePreventiveProtocolDTO(in dto.PreventiveProtocolDTO) error {
+	if in.Name == "" || len(in.Name) > 100 {
+		return ErrInvalidProtocolName
+	}
+	if in.MinAgeWeeks < 0 || (in.MaxAgeWeeks != nil && *in.MaxAgeWeeks <= in.MinAgeWeeks) {
+		return ErrInvalidProtocolAges
+	}
+	if in.IntervalDays <= 0 {
+		return ErrInvalidProtocolInterval
+	}
+	return nil
+}
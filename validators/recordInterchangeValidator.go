@@ -0,0 +1,86 @@
+package validators
+
+import (
+	"VetiCare/entities/dto"
+	"VetiCare/utils"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrFHIRPatientCount    = errors.New("el Bundle debe traer exactamente un recurso Patient")
+	ErrFHIRPatientName     = errors.New("el nombre de la mascota es obligatorio y debe tener máximo 80 caracteres")
+	ErrFHIRPatientSpecies  = errors.New("la especie de la mascota es obligatoria (extensión patient-animal)")
+	ErrFHIRPatientBreed    = errors.New("la raza debe tener máximo 50 caracteres")
+	ErrFHIRPatientBirth    = errors.New("birthDate debe tener formato YYYY-MM-DD")
+	ErrFHIRPatientChip     = errors.New("el microchip de la mascota debe tener 15 dígitos (ISO 11784/11785)")
+	ErrFHIROwnerCount      = errors.New("el Bundle debe traer exactamente un RelatedPerson con relación OWN")
+	ErrFHIROwnerName       = errors.New("el nombre del dueño es obligatorio y debe tener máximo 100 caracteres")
+	ErrFHIROwnerDUI        = errors.New("el DUI del dueño es obligatorio: formato esperado ########-#")
+	ErrFHIROwnerPhone      = errors.New("el teléfono del dueño es inválido, formato esperado ####-####")
+	ErrFHIROwnerEmail      = errors.New("el correo del dueño es inválido")
+	ErrFHIRResourceInvalid = errors.New("recurso inválido")
+)
+
+// ValidateFHIRRecord revisa lo indispensable para importar: una mascota con
+// especie y un dueño identificado por DUI, y que las fechas de los encuentros
+// y las vacunas se puedan leer. Los signos vitales o medicamentos que no se
+// entienden no invalidan la importación; se omiten con una advertencia.
+func ValidateFHIRRecord(rec *dto.FHIRRecord) error {
+	if len(rec.Patients) != 1 {
+		return ErrFHIRPatientCount
+	}
+	patient := rec.Patients[0]
+	if name := patient.DisplayName(); name == "" || len(name) > 80 {
+		return ErrFHIRPatientName
+	}
+	if patient.AnimalDetail("species") == "" {
+		return ErrFHIRPatientSpecies
+	}
+	if err := ValidateMaxLen(patient.AnimalDetail("breed"), 50, ErrFHIRPatientBreed); err != nil {
+		return err
+	}
+	if patient.BirthDate != "" {
+		if _, err := dto.ParseFHIRDateTime(patient.BirthDate); err != nil {
+			return ErrFHIRPatientBirth
+		}
+	}
+	if chip := patient.IdentifierValue(dto.FHIRSystemMicrochip); chip != "" && ValidateMicrochip(utils.NormalizeMicrochip(chip)) != nil {
+		return ErrFHIRPatientChip
+	}
+
+	owners := 0
+	for _, person := range rec.RelatedPersons {
+		if !person.IsOwner() {
+			continue
+		}
+		owners++
+		if name := person.DisplayName(); name == "" || len(name) > 100 {
+			return ErrFHIROwnerName
+		}
+		if ValidateDUI(person.IdentifierValue(dto.FHIRSystemDUI)) != nil {
+			return ErrFHIROwnerDUI
+		}
+		if phone := person.TelecomValue("phone"); phone != "" && ValidatePhone(phone) != nil {
+			return ErrFHIROwnerPhone
+		}
+		if email := person.TelecomValue("email"); email != "" && ValidateEmail(email) != nil {
+			return ErrFHIROwnerEmail
+		}
+	}
+	if owners != 1 {
+		return ErrFHIROwnerCount
+	}
+
+	for _, e := range rec.Encounters {
+		if _, err := dto.ParseFHIRDateTime(e.Period.Start); err != nil {
+			return fmt.Errorf("%w: Encounter %s sin period.start válido", ErrFHIRResourceInvalid, e.ID)
+		}
+	}
+	for _, i := range rec.Immunizations {
+		if _, err := dto.ParseFHIRDateTime(i.OccurrenceDateTime); err != nil {
+			return fmt.Errorf("%w: Immunization %s sin occurrenceDateTime válido", ErrFHIRResourceInvalid, i.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package validators
+
+import (
+	"VetiCare/entities"
+	"VetiCare/entities/dto"
+	"errors"
+	"regexp"
+)
+
+var (
+	ErrInvalidShareScope     = errors.New("scope debe ser 'historial' o 'vacunas'")
+	ErrInvalidShareRecipient = errors.New("el destinatario debe tener máximo 150 caracteres")
+	ErrInvalidShareExpiry    = errors.New("expires_in_hours debe estar entre 1 y 720")
+	ErrInvalidSharePin       = errors.New("el PIN debe tener entre 4 y 8 dígitos")
+)
+
+var sharePinRegex = regexp.MustCompile(`^[0-9]{4,8}$`)
+
+// ValidateShareLinkInputDTO valida el enlace; ExpiresInHours en cero toma el
+// valor por defecto en el controlador.
+func ValidateShareLinkInputDTO(in dto.ShareLinkInputDTO) error {
+	if in.Scope != entities.ShareScopeHistory && in.Scope != entities.ShareScopeVaccinations {
+		return ErrInvalidShareScope
+	}
+	if err := ValidateMaxLen(in.Recipient, 150, ErrInvalidShareRecipient); err != nil {
+		return err
+	}
+	if in.ExpiresInHours < 0 || in.ExpiresInHours > 720 {
+		return ErrInvalidShareExpiry
+	}
+	if in.Pin != "" && !sharePinRegex.MatchString(in.Pin) {
+		return ErrInvalidSharePin
+	}
+	return nil
+}
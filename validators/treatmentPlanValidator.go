@@ -0,0 +1,62 @@
+package validators
+
+import (
+	"VetiCare/entities/dto"
+	"errors"
+)
+
+var (
+	ErrInvalidTreatmentTitle       = errors.New("el título del plan es obligatorio y debe tener máximo 150 caracteres")
+	ErrInvalidTreatmentDescription = errors.New("la descripción del plan debe tener máximo 1000 caracteres")
+	ErrInvalidTreatmentStartDate   = errors.New("start_date debe tener formato DD-MM-YYYY")
+	ErrInvalidTreatmentSteps       = errors.New("el plan debe tener entre 1 y 30 pasos")
+	ErrInvalidTreatmentProcedure   = errors.New("el procedimiento de cada paso es obligatorio y debe tener máximo 150 caracteres")
+	ErrInvalidTreatmentStepNotes   = errors.New("las notas del paso deben tener máximo 500 caracteres")
+	ErrInvalidTreatmentInterval    = errors.New("el intervalo de cada paso debe estar entre 0 y 730 días")
+	ErrInvalidTreatmentCost        = errors.New("el costo estimado no puede ser negativo ni mayor a 99999999.99")
+)
+
+func ValidateTreatmentPlanStepInputDTO(in dto.TreatmentPlanStepInputDTO) error {
+	if len(in.Procedure) == 0 || len(in.Procedure) > 150 {
+		return ErrInvalidTreatmentProcedure
+	}
+	if err := ValidateMaxLen(in.Notes, 500, ErrInvalidTreatmentStepNotes); err != nil {
+		return err
+	}
+	if in.IntervalDays < 0 || in.IntervalDays > 730 {
+		return ErrInvalidTreatmentInterval
+	}
+	if in.EstimatedCost < 0 || in.EstimatedCost > 99999999.99 {
+		return ErrInvalidTreatmentCost
+	}
+	return nil
+}
+
+// ValidateTreatmentPlanInputDTO valida el plan; withSteps es false al editar,
+// porque los pasos se agregan con su propia ruta.
+func ValidateTreatmentPlanInputDTO(in dto.TreatmentPlanInputDTO, withSteps bool) error {
+	if len(in.Title) == 0 || len(in.Title) > 150 {
+		return ErrInvalidTreatmentTitle
+	}
+	if err := ValidateMaxLen(in.Description, 1000, ErrInvalidTreatmentDescription); err != nil {
+		return err
+	}
+	if err := ValidateUUIDOptional(in.VetID); err != nil {
+		return err
+	}
+	if !withSteps {
+		return nil
+	}
+	if ValidateDate(in.StartDate) != nil {
+		return ErrInvalidTreatmentStartDate
+	}
+	if len(in.Steps) == 0 || len(in.Steps) > 30 {
+		return ErrInvalidTreatmentSteps
+	}
+	for _, s := range in.Steps {
+		if err := ValidateTreatmentPlanStepInputDTO(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
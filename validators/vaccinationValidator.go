@@ -0,0 +1,64 @@
+package validators
+
+import (
+	"VetiCare/entities/dto"
+	"errors"
+)
+
+var (
+	ErrInvalidVaccineName     = errors.New("el nombre de la vacuna es obligatorio y debe tener máximo 100 caracteres")
+	ErrInvalidVaccineInterval = errors.New("el intervalo de refuerzo debe ser un número de días mayor o igual a cero")
+	ErrInvalidVaccineAliases  = errors.New("los alias de la vacuna deben tener máximo 300 caracteres")
+	ErrInvalidVaccineID       = errors.New("vaccine_id es obligatorio")
+	ErrInvalidDateGiven       = errors.New("date_given es obligatorio y debe tener formato DD-MM-YYYY")
+	ErrInvalidNextDueDate     = errors.New("next_due_date debe tener formato DD-MM-YYYY")
+	ErrInvalidDoseNumber      = errors.New("el número de dosis no puede ser negativo")
+	ErrInvalidManufacturer    = errors.New("el fabricante debe tener máximo 100 caracteres")
+	ErrInvalidLotNumber       = errors.New("el número de lote debe tener máximo 50 caracteres")
+	ErrInvalidVaccinationNote = errors.New("las notas deben tener máximo 500 caracteres")
+)
+
+func ValidateVaccineName(name string) error {
+	if len(name) == 0 || len(name) > 100 {
+		return ErrInvalidVaccineName
+	}
+	return nil
+}
+
+func ValidateVaccineDTO(in dto.VaccineDTO) error {
+	if err := ValidateVaccineName(in.Name); err != nil {
+		return err
+	}
+	if in.DefaultIntervalDays < 0 {
+		return ErrInvalidVaccineInterval
+	}
+	return ValidateMaxLen(in.Aliases, 300, ErrInvalidVaccineAliases)
+}
+
+func ValidateVaccinationInputDTO(in dto.VaccinationInputDTO) error {
+	if in.VaccineID <= 0 {
+		return ErrInvalidVaccineID
+	}
+	if in.DateGiven == "" || ValidateDate(in.DateGiven) != nil {
+		return ErrInvalidDateGiven
+	}
+	if in.NextDueDate != "" && ValidateDate(in.NextDueDate) != nil {
+		return ErrInvalidNextDueDate
+	}
+	if in.DoseNumber < 0 {
+		return ErrInvalidDoseNumber
+	}
+	if err := ValidateUUIDOptional(in.AppointmentID); err != nil {
+		return err
+	}
+	if err := ValidateUUIDOptional(in.AdministeredByID); err != nil {
+		return ErrInvalidVetID
+	}
+	if err := ValidateMaxLen(in.Manufacturer, 100, ErrInvalidManufacturer); err != nil {
+		return err
+	}
+	if err := ValidateMaxLen(in.LotNumber, 50, ErrInvalidLotNumber); err != nil {
+		return err
+	}
+	return ValidateMaxLen(in.Notes, 500, ErrInvalidVaccinationNote)
+}
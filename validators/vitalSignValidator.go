@@ -0,0 +1,56 @@
+package validators
+
+import (
+	"VetiCare/entities/dto"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidHeartRate          = errors.New("la frecuencia cardiaca debe ser un número positivo")
+	ErrInvalidRespiratoryRate    = errors.New("la frecuencia respiratoria debe ser un número positivo")
+	ErrInvalidBodyConditionScore = errors.New("la condición corporal debe estar entre 1 y 9")
+	ErrInvalidRecordedAt         = errors.New("la fecha de la toma debe tener el formato DD-MM-YYYY HH:MM")
+	ErrInvalidVitalNote          = errors.New("las notas deben tener máximo 300 caracteres")
+	ErrInvalidLifeStage          = errors.New("la etapa de vida debe tener máximo 50 caracteres")
+	ErrInvalidRangeAges          = errors.New("las edades del rango deben ser positivas y la edad máxima mayor que la mínima")
+)
+
+const VitalRecordedAtLayout = "02-01-2006 15:04"
+
+func ValidateVitalSignInputDTO(in dto.VitalSignInputDTO) error {
+	if err := ValidatePositiveFloat(in.WeightKg, ErrInvalidWeight); err != nil {
+		return err
+	}
+	if err := ValidatePositiveFloat(in.Temperature, ErrInvalidTemperature); err != nil {
+		return err
+	}
+	if in.HeartRate != nil && *in.HeartRate <= 0 {
+		return ErrInvalidHeartRate
+	}
+	if in.RespiratoryRate != nil && *in.RespiratoryRate <= 0 {
+		return ErrInvalidRespiratoryRate
+	}
+	if in.BodyConditionScore != nil && (*in.BodyConditionScore < 1 || *in.BodyConditionScore > 9) {
+		return ErrInvalidBodyConditionScore
+	}
+	if in.RecordedAt != "" {
+		if _, err := time.ParseInLocation(VitalRecordedAtLayout, in.RecordedAt, time.Local); err != nil {
+			return ErrInvalidRecordedAt
+		}
+	}
+	if err := ValidateUUIDOptional(in.AppointmentID); err != nil {
+		return err
+	}
+	return ValidateMaxLen(in.Notes, 300, ErrInvalidVitalNote)
+}
+
+func ValidateVitalReferenceRangeDTO(in dto.VitalReferenceRangeDTO) error {
+	if err := ValidateMaxLen(in.LifeStage, 50, ErrInvalidLifeStage); err != nil {
+		return err
+	}
+	if in.MinAgeWeeks < 0 || (in.MaxAgeWeeks != nil && *in.MaxAgeWeeks <= in.MinAgeWeeks) {
+		return ErrInvalidRangeAges
+	}
+	return nil
+}